@@ -0,0 +1,355 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+/*
+	List Roles
+******************************************************************/
+
+func ListRoles(listRoles usecases.ListRoles, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := listRoles.Do(infrastructure.NewContext(r.Context()))
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		result := make([]role, len(list))
+		for i := 0; i < len(list); i++ {
+			result[i] = *NewRoleDto(&list[i])
+		}
+		valueResponse(w, result)
+	}
+}
+
+/*
+	Save Role
+******************************************************************/
+
+type saveRoleReq struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func (req *saveRoleReq) Sanitize() {
+	req.Name = StrictSanitize(req.Name)
+	for i := 0; i < len(req.Permissions); i++ {
+		req.Permissions[i] = StrictSanitize(req.Permissions[i])
+	}
+}
+
+func SaveRole(saveRole usecases.SaveRole, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(saveRoleReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		permissions := make([]domain.Permission, len(data.Permissions))
+		for i, p := range data.Permissions {
+			permissions[i] = domain.Permission(p)
+		}
+
+		saved, err := saveRole.Do(infrastructure.NewContext(r.Context()), data.Id, data.Name, permissions)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewRoleDto(saved))
+	}
+}
+
+/*
+	Remove Role
+******************************************************************/
+
+type removeRoleReq struct {
+	Id int `json:"id"`
+}
+
+type removeRoleRes struct {
+	Removed bool `json:"removed"`
+}
+
+func RemoveRole(removeRole usecases.RemoveRole, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeRoleReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		removed, err := removeRole.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &removeRoleRes{Removed: removed})
+	}
+}
+
+/*
+	Grant / Revoke User Role
+******************************************************************/
+
+type userRoleReq struct {
+	UserId string `json:"userId"`
+	RoleId int    `json:"roleId"`
+}
+
+func (req *userRoleReq) Sanitize() {
+	req.UserId = StrictSanitize(req.UserId)
+}
+
+type userChangedRes struct {
+	Changed bool `json:"changed"`
+}
+
+func GrantRole(grantRole usecases.GrantRole, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(userRoleReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		changed, err := grantRole.Do(infrastructure.NewContext(r.Context()), data.UserId, data.RoleId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &userChangedRes{Changed: changed})
+	}
+}
+
+func RevokeRole(revokeRole usecases.RevokeRole, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(userRoleReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		changed, err := revokeRole.Do(infrastructure.NewContext(r.Context()), data.UserId, data.RoleId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &userChangedRes{Changed: changed})
+	}
+}
+
+/*
+	Search Users
+******************************************************************/
+
+type searchUsersReq struct {
+	Query       string `json:"query"`
+	Banned      bool   `json:"banned"`
+	Unconfirmed bool   `json:"unconfirmed"`
+	RoleId      int    `json:"roleId"`
+	Count       int    `json:"count"`
+	Page        int    `json:"page"`
+}
+
+func (req *searchUsersReq) Sanitize() {
+	req.Query = StrictSanitize(req.Query)
+}
+
+func SearchUsers(searchUsers usecases.SearchUsers, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(searchUsersReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		filter := domain.UserFilter{
+			Query:       data.Query,
+			Banned:      data.Banned,
+			Unconfirmed: data.Unconfirmed,
+			RoleId:      data.RoleId,
+		}
+		list, err := searchUsers.Do(infrastructure.NewContext(r.Context()), filter, data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		result := make([]adminUser, len(list))
+		for i := 0; i < len(list); i++ {
+			result[i] = *NewAdminUserDto(&list[i])
+		}
+		valueResponse(w, result)
+	}
+}
+
+/*
+	Ban / Unban User
+******************************************************************/
+
+type banUserReq struct {
+	UserId string `json:"userId"`
+	Reason string `json:"reason"`
+	// empty for permanent ban
+	Until *time.Time `json:"until"`
+}
+
+func (req *banUserReq) Sanitize() {
+	req.UserId = StrictSanitize(req.UserId)
+	req.Reason = StrictSanitize(req.Reason)
+}
+
+func BanUser(banUser usecases.BanUser, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(banUserReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		var until time.Time
+		if data.Until != nil {
+			until = *data.Until
+		}
+
+		changed, err := banUser.Do(infrastructure.NewContext(r.Context()), data.UserId, data.Reason, until)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &userChangedRes{Changed: changed})
+	}
+}
+
+type userIdReq struct {
+	UserId string `json:"userId"`
+}
+
+func (req *userIdReq) Sanitize() {
+	req.UserId = StrictSanitize(req.UserId)
+}
+
+type userAction interface {
+	Do(ctx core.ReqContext, userId string) (bool, error)
+}
+
+func UnbanUser(unbanUser usecases.UnbanUser, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return userActionHandler(unbanUser, log)
+}
+
+/*
+	Confirm User Email
+******************************************************************/
+
+func ConfirmUserEmail(confirmUserEmail usecases.ConfirmUserEmail, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return userActionHandler(confirmUserEmail, log)
+}
+
+/*
+	Logout User
+******************************************************************/
+
+func LogoutUser(logoutUser usecases.LogoutUser, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return userActionHandler(logoutUser, log)
+}
+
+func userActionHandler(action userAction, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(userIdReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		changed, err := action.Do(infrastructure.NewContext(r.Context()), data.UserId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &userChangedRes{Changed: changed})
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type getChangeLogReq struct {
+	UserId string            `json:"userId"`
+	Type   string            `json:"type"`
+	Action domain.ChangeType `json:"action"`
+	Count  int               `json:"count"`
+	Page   int               `json:"page"`
+}
+
+func (req *getChangeLogReq) Sanitize() {
+	req.UserId = StrictSanitize(req.UserId)
+	req.Type = StrictSanitize(req.Type)
+}
+
+func GetChangeLog(getChangeLog usecases.GetChangeLog, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getChangeLogReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		filter := domain.ChangeLogFilter{
+			UserId: data.UserId,
+			Action: data.Action,
+		}
+		if data.Type != "" {
+			var isValidType bool
+			if isValidType, filter.EntityType = domain.EntityTypeFromString(data.Type); !isValidType {
+				statusResponse(w, &status{Code: http.StatusBadRequest})
+				return
+			}
+		}
+
+		list, err := getChangeLog.Do(infrastructure.NewContext(r.Context()), filter, data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewChangeLogDto(list))
+	}
+}
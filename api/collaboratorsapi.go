@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+/*
+	Invite Collaborator
+******************************************************************/
+
+type inviteCollaboratorReq struct {
+	PlanId string                  `json:"planId"`
+	Name   string                  `json:"name"`
+	Role   domain.CollaboratorRole `json:"role"`
+}
+
+func (req *inviteCollaboratorReq) Sanitize() {
+	req.PlanId = StrictSanitize(req.PlanId)
+	req.Name = StrictSanitize(req.Name)
+}
+
+func InvitePlanCollaborator(invitePlanCollaborator usecases.InvitePlanCollaborator, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(inviteCollaboratorReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		planId, err := core.DecodeStringToNum(data.PlanId)
+		if err != nil {
+			badRequest(w, core.ValidationError(map[string]string{"planId": core.InvalidValue.String()}))
+			return
+		}
+
+		collaborator, err := invitePlanCollaborator.Do(infrastructure.NewContext(r.Context()), planId, data.Name, data.Role)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewPlanCollaboratorDto(collaborator))
+	}
+}
+
+/*
+	Collaborators Of Plan
+******************************************************************/
+
+type planCollaboratorsReq struct {
+	PlanId string `json:"planId"`
+}
+
+func (req *planCollaboratorsReq) Sanitize() {
+	req.PlanId = StrictSanitize(req.PlanId)
+}
+
+func GetPlanCollaborators(getPlanCollaborators usecases.GetPlanCollaborators, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(planCollaboratorsReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		planId, err := core.DecodeStringToNum(data.PlanId)
+		if err != nil {
+			badRequest(w, core.ValidationError(map[string]string{"planId": core.InvalidValue.String()}))
+			return
+		}
+
+		list, err := getPlanCollaborators.Do(infrastructure.NewContext(r.Context()), planId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewPlanCollaboratorsDto(list))
+	}
+}
+
+/*
+	Remove Collaborator
+******************************************************************/
+
+type removeCollaboratorReq struct {
+	PlanId string `json:"planId"`
+	UserId string `json:"userId"`
+}
+
+func (req *removeCollaboratorReq) Sanitize() {
+	req.PlanId = StrictSanitize(req.PlanId)
+	req.UserId = StrictSanitize(req.UserId)
+}
+
+type collaboratorRes struct {
+	Success bool `json:"success"`
+}
+
+func RemovePlanCollaborator(removePlanCollaborator usecases.RemovePlanCollaborator, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeCollaboratorReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		planId, err := core.DecodeStringToNum(data.PlanId)
+		if err != nil {
+			badRequest(w, core.ValidationError(map[string]string{"planId": core.InvalidValue.String()}))
+			return
+		}
+
+		removed, err := removePlanCollaborator.Do(infrastructure.NewContext(r.Context()), planId, data.UserId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &collaboratorRes{Success: removed})
+	}
+}
+
+/*
+	Invitations
+******************************************************************/
+
+func GetPlanInvitations(getPlanInvitations usecases.GetPlanInvitations, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := getPlanInvitations.Do(infrastructure.NewContext(r.Context()))
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewPlanCollaboratorsDto(list))
+	}
+}
+
+func AcceptPlanInvitation(acceptPlanInvitation usecases.AcceptPlanInvitation, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(planCollaboratorsReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		planId, err := core.DecodeStringToNum(data.PlanId)
+		if err != nil {
+			badRequest(w, core.ValidationError(map[string]string{"planId": core.InvalidValue.String()}))
+			return
+		}
+
+		accepted, err := acceptPlanInvitation.Do(infrastructure.NewContext(r.Context()), planId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &collaboratorRes{Success: accepted})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/core/usecases"
@@ -21,7 +22,8 @@ type addCommentRequest struct {
 
 func (req *addCommentRequest) Sanitize() {
 	req.Title = StrictSanitize(req.Title)
-	req.Text = SanitizeText(req.Text)
+	// markdown source, it is rendered and sanitized by usecase
+	req.Text = strings.TrimSpace(req.Text)
 	req.EntityId = StrictSanitize(req.EntityId)
 }
 
@@ -78,14 +80,16 @@ func AddComment(addComment usecases.AddComment, log core.AppLogger) func(w http.
 }
 
 type editCommentRequest struct {
-	Id    int64  `json:"id"`
-	Text  string `json:"text"`
-	Title string `json:"title"`
+	Id      int64  `json:"id"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+	Title   string `json:"title"`
 }
 
 func (req *editCommentRequest) Sanitize() {
 	req.Title = StrictSanitize(req.Title)
-	req.Text = SanitizeText(req.Text)
+	// markdown source, it is rendered and sanitized by usecase
+	req.Text = strings.TrimSpace(req.Text)
 }
 
 func EditComment(editComment usecases.EditComment, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
@@ -100,7 +104,11 @@ func EditComment(editComment usecases.EditComment, log core.AppLogger) func(w ht
 			return
 		}
 		data.Sanitize()
-		_, err = editComment.Do(infrastructure.NewContext(r.Context()), data.Id, data.Text, data.Title)
+		_, err = editComment.Do(infrastructure.NewContext(r.Context()), data.Id, data.Version, data.Text, data.Title)
+		if conflict, ok := err.(*core.ConflictError); ok {
+			conflictResponse(w, NewCommentDto(conflict.Current.(*domain.Comment)))
+			return
+		}
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -144,20 +152,158 @@ func DeleteComment(removeComment usecases.RemoveComment, log core.AppLogger) fun
 	}
 }
 
+type getCommentRevisionsRequest struct {
+	Id int64 `json:"id"`
+}
+
+func GetCommentRevisions(getRevisions usecases.GetCommentRevisions, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getCommentRevisionsRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		revisions, err := getRevisions.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		result := make([]commentRevision, len(revisions))
+		for i := 0; i < len(revisions); i++ {
+			result[i] = *NewCommentRevisionDto(&revisions[i])
+		}
+		valueResponse(w, result)
+	}
+}
+
+type toggleReactionRequest struct {
+	Id       int64  `json:"id"`
+	Reaction string `json:"reaction"`
+}
+
+type toggleReactionResponse struct {
+	Reacted   bool       `json:"reacted"`
+	Reactions []reaction `json:"reactions"`
+}
+
+func ToggleReaction(toggleReaction usecases.ToggleReaction, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(toggleReactionRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		reacted, reactions, err := toggleReaction.Do(infrastructure.NewContext(r.Context()), data.Id, domain.Reaction(data.Reaction))
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &toggleReactionResponse{
+			Reacted:   reacted,
+			Reactions: NewReactionsDto(reactions),
+		})
+	}
+}
+
+type pinCommentRequest struct {
+	Id     int64 `json:"id"`
+	Pinned bool  `json:"pinned"`
+}
+
+func PinComment(pinComment usecases.PinComment, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(pinCommentRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		_, err = pinComment.Do(infrastructure.NewContext(r.Context()), data.Id, data.Pinned)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		statusResponse(w, &status{Code: http.StatusOK})
+	}
+}
+
+type acceptAnswerRequest struct {
+	ThreadId int64 `json:"threadId"`
+	AnswerId int64 `json:"answerId"`
+}
+
+func AcceptAnswer(acceptAnswer usecases.AcceptAnswer, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(acceptAnswerRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		_, err = acceptAnswer.Do(infrastructure.NewContext(r.Context()), data.ThreadId, data.AnswerId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		statusResponse(w, &status{Code: http.StatusOK})
+	}
+}
+
 type getCommentThreadsRequest struct {
 	EntityType string `json:"entityType"`
 	EntityId   string `json:"entityId"`
+	Sort       string `json:"sort"`
+	Cursor     string `json:"cursor"`
 	Count      int    `json:"count"`
-	Page       int    `json:"page"`
 }
 
 func (req *getCommentThreadsRequest) Sanitize() {
 	req.EntityId = StrictSanitize(req.EntityId)
+	req.Sort = StrictSanitize(req.Sort)
+	req.Cursor = StrictSanitize(req.Cursor)
 }
 
 type getCommentThreadsResponse struct {
 	HasMore  bool      `json:"hasMore"`
-	Page     int       `json:"page"`
+	Cursor   string    `json:"cursor"`
 	Comments []comment `json:"comments"`
 }
 
@@ -193,7 +339,7 @@ func GetThreads(getThreads usecases.GetCommentsThreads, getPointsList usecases.G
 		}
 
 		ctx := infrastructure.NewContext(r.Context())
-		comments, hasMore, err := getThreads.Do(ctx, entityType, entityId, data.Count, data.Page)
+		comments, next, err := getThreads.Do(ctx, entityType, entityId, domain.CommentSort(data.Sort), data.Cursor, data.Count)
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -211,8 +357,8 @@ func GetThreads(getThreads usecases.GetCommentsThreads, getPointsList usecases.G
 		}
 
 		valueResponse(w, &getCommentThreadsResponse{
-			HasMore:  hasMore,
-			Page:     data.Page,
+			HasMore:  next != "",
+			Cursor:   next,
 			Comments: c,
 		})
 	}
@@ -222,13 +368,20 @@ type getCommentThreadRequest struct {
 	EntityType string `json:"entityType"`
 	EntityId   string `json:"entityId"`
 	ThreadId   int64  `json:"threadId"`
+	Sort       string `json:"sort"`
+	Cursor     string `json:"cursor"`
+	Count      int    `json:"count"`
 }
 
 func (req *getCommentThreadRequest) Sanitize() {
 	req.EntityId = StrictSanitize(req.EntityId)
+	req.Sort = StrictSanitize(req.Sort)
+	req.Cursor = StrictSanitize(req.Cursor)
 }
 
 type getCommentThreadResponse struct {
+	HasMore  bool      `json:"hasMore"`
+	Cursor   string    `json:"cursor"`
 	Comments []comment `json:"comments"`
 }
 
@@ -264,7 +417,7 @@ func GetThread(getThread usecases.GetCommentsThread, getPointsList usecases.GetP
 		}
 
 		ctx := infrastructure.NewContext(r.Context())
-		comments, err := getThread.Do(ctx, entityType, entityId, data.ThreadId)
+		comments, next, err := getThread.Do(ctx, entityType, entityId, data.ThreadId, domain.CommentSort(data.Sort), data.Cursor, data.Count)
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -281,7 +434,11 @@ func GetThread(getThread usecases.GetCommentsThread, getPointsList usecases.GetP
 			c[i] = *NewCommentDto(&comments[i])
 		}
 
-		valueResponse(w, c)
+		valueResponse(w, &getCommentThreadResponse{
+			HasMore:  next != "",
+			Cursor:   next,
+			Comments: c,
+		})
 	}
 }
 
@@ -297,7 +454,7 @@ func attachePoints(comments []domain.Comment, getPointsList usecases.GetPointsLi
 			"reqid", ctx.ReqId(),
 			"error", "see db log")
 	} else {
-		for i := 0; i < len(comments); i++ {
+		for i := 0; i < len(points); i++ {
 			for j := 0; j < len(comments); j++ {
 				if int64(comments[j].Id) == points[i].Id {
 					comments[j].Points = &points[i]
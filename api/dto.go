@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"strconv"
 	"time"
 
@@ -8,25 +9,34 @@ import (
 	"github.com/NeekUP/roadmaps/domain"
 )
 
+// Description is rendered html, DescriptionSource is markdown for editing
 type topic struct {
-	Id          int        `json:"id"`
-	Name        string     `json:"name"`
-	Title       string     `json:"title"`
-	Description string     `json:"desc,omitempty"`
-	Tags        []topicTag `json:"tags"`
-	Plans       []plan     `json:"plans,omitempty"`
-	IsTag       bool       `json:"isTag"`
+	Id                int        `json:"id"`
+	Name              string     `json:"name"`
+	Title             string     `json:"title"`
+	Description       string     `json:"desc,omitempty"`
+	DescriptionSource string     `json:"descSource,omitempty"`
+	Tags              []topicTag `json:"tags"`
+	Plans             []plan     `json:"plans,omitempty"`
+	IsTag             bool       `json:"isTag"`
+	Comments          int        `json:"comments"`
+	Points            *points    `json:"points,omitempty"`
+	Version           int        `json:"version"`
 }
 
 func NewTopicDto(t *domain.Topic) *topic {
 	nt := &topic{
-		Id:          t.Id,
-		Name:        t.Name,
-		Title:       t.Title,
-		Description: t.Description,
-		Tags:        make([]topicTag, len(t.Tags)),
-		Plans:       make([]plan, len(t.Plans)),
-		IsTag:       t.IsTag,
+		Id:                t.Id,
+		Name:              t.Name,
+		Title:             t.Title,
+		Description:       t.DescriptionHtml,
+		DescriptionSource: t.Description,
+		Tags:              make([]topicTag, len(t.Tags)),
+		Plans:             make([]plan, len(t.Plans)),
+		IsTag:             t.IsTag,
+		Comments:          t.Comments,
+		Points:            NewPointsDTO(t.Points),
+		Version:           t.Version,
 	}
 
 	for i := 0; i < len(t.Tags); i++ {
@@ -49,18 +59,41 @@ type plan struct {
 	InFavorites bool    `json:"inFavorites"`
 	Steps       []step  `json:"steps,omitempty"`
 	IsDraft     bool    `json:"isDraft"`
+	IsHidden    bool    `json:"isHidden"`
+	Version     int     `json:"version"`
+	// estimated time of all steps and of not optional steps in minutes, 0 when steps are not loaded
+	EstimatedTime int `json:"estimatedTime"`
+	RequiredTime  int `json:"requiredTime"`
+	// filled by plan/get only
+	Forks      int      `json:"forks,omitempty"`
+	ForkedFrom *planRef `json:"forkedFrom,omitempty"`
+}
+
+// planRef is a link to plan
+type planRef struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
 }
 
 func NewPlanDto(p *domain.Plan, inFavorites bool) *plan {
 	np := &plan{
-		Id:          core.EncodeNumToString(p.Id),
-		Title:       p.Title,
-		Points:      NewPointsDTO(p.Points),
-		TopicName:   p.TopicName,
-		Owner:       NewUserDto(p.Owner),
-		InFavorites: inFavorites,
-		IsDraft:     p.IsDraft,
-		Steps:       make([]step, len(p.Steps)),
+		Id:            core.EncodeNumToString(p.Id),
+		Title:         p.Title,
+		Points:        NewPointsDTO(p.Points),
+		TopicName:     p.TopicName,
+		Owner:         NewUserDto(p.Owner),
+		InFavorites:   inFavorites,
+		IsDraft:       p.IsDraft,
+		IsHidden:      p.Hidden,
+		Steps:         make([]step, len(p.Steps)),
+		Forks:         p.Forks,
+		Version:       p.Version,
+		EstimatedTime: p.EstimatedTime(),
+		RequiredTime:  p.RequiredTime(),
+	}
+
+	if p.Parent != nil {
+		np.ForkedFrom = &planRef{Id: core.EncodeNumToString(p.Parent.Id), Title: p.Parent.Title}
 	}
 
 	for i := 0; i < len(p.Steps); i++ {
@@ -70,12 +103,19 @@ func NewPlanDto(p *domain.Plan, inFavorites bool) *plan {
 	return np
 }
 
+// Title is rendered html, TitleSource is markdown for editing
 type step struct {
 	Id            int64                `json:"id"`
 	ReferenceType domain.ReferenceType `json:"type"`
 	Position      int                  `json:"position"`
 	Source        interface{}          `json:"source"`
 	Title         string               `json:"title"`
+	TitleSource   string               `json:"titleSource"`
+	Duration      int                  `json:"duration"`
+	Difficulty    domain.Difficulty    `json:"difficulty,omitempty"`
+	Optional      bool                 `json:"optional"`
+	Notes         string               `json:"notes,omitempty"`
+	NotesSource   string               `json:"notesSource,omitempty"`
 }
 
 func NewStepDto(s *domain.Step) *step {
@@ -88,7 +128,13 @@ func NewStepDto(s *domain.Step) *step {
 		ReferenceType: s.ReferenceType,
 		Position:      s.Position,
 		Source:        NewSourceDto(s.Source),
-		Title:         s.Title,
+		Title:         s.TitleHtml,
+		TitleSource:   s.Title,
+		Duration:      s.Duration,
+		Difficulty:    s.Difficulty,
+		Optional:      s.Optional,
+		Notes:         s.NotesHtml,
+		NotesSource:   s.Notes,
 	}
 }
 
@@ -99,6 +145,8 @@ type source struct {
 	Properties string            `json:"props,omitempty"`
 	Img        string            `json:"img,omitempty"`
 	Desc       string            `json:"desc,omitempty"`
+	Comments   int               `json:"comments"`
+	Points     *points           `json:"points,omitempty"`
 }
 
 func NewSourceDto(s interface{}) interface{} {
@@ -115,6 +163,8 @@ func NewSourceDto(s interface{}) interface{} {
 			Properties: v.Properties,
 			Img:        ImgManager.GetResourceCoverUrl(v.Img),
 			Desc:       v.Desc,
+			Comments:   v.Comments,
+			Points:     NewPointsDTO(v.Points),
 		}
 
 		if v.Id == -1 {
@@ -126,8 +176,10 @@ func NewSourceDto(s interface{}) interface{} {
 			Id:          v.Id,
 			Title:       v.Title,
 			Name:        v.Name,
-			Description: v.Description,
+			Description: v.DescriptionHtml,
 			IsTag:       v.IsTag,
+			Comments:    v.Comments,
+			Points:      NewPointsDTO(v.Points),
 		}
 		return tpc
 	}
@@ -136,10 +188,11 @@ func NewSourceDto(s interface{}) interface{} {
 }
 
 type user struct {
-	Id    string `json:"id"`
-	Name  string `json:"name"`
-	Img   string `json:"img"`
-	Oauth bool   `json:"oauth"`
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Img        string `json:"img"`
+	Oauth      bool   `json:"oauth"`
+	Reputation int    `json:"reputation"`
 }
 
 func NewUserDto(u *domain.User) *user {
@@ -148,10 +201,11 @@ func NewUserDto(u *domain.User) *user {
 	}
 
 	return &user{
-		Id:    u.Id,
-		Name:  u.Name,
-		Img:   ImgManager.GetAvatarUrl(u.Img),
-		Oauth: u.OAuth,
+		Id:         u.Id,
+		Name:       u.Name,
+		Img:        ImgManager.GetAvatarUrl(u.Img),
+		Oauth:      u.OAuth,
+		Reputation: u.Reputation,
 	}
 }
 
@@ -175,6 +229,23 @@ func NewCommentDto(c *domain.Comment) *comment {
 		childs[i] = *NewCommentDto(&c.Childs[i])
 	}
 
+	text, source, title := c.Html, c.Text, c.Title
+	if c.Hidden || c.Deleted {
+		text, source, title = "", "", ""
+	}
+
+	var editedAt *time.Time
+	editedBy := ""
+	if !c.EditedAt.IsZero() {
+		editedAt = &c.EditedAt
+		editedBy = commentActor(c.EditedByModerator())
+	}
+
+	deletedBy := ""
+	if c.Deleted {
+		deletedBy = commentActor(c.DeletedByModerator())
+	}
+
 	return &comment{
 		Id:         c.Id,
 		EntityType: domain.EntityTypeToString(c.EntityType),
@@ -183,11 +254,21 @@ func NewCommentDto(c *domain.Comment) *comment {
 		ParentId:   c.ParentId,
 		Date:       c.Date,
 		User:       NewUserDto(c.User),
-		Text:       c.Text,
-		Title:      c.Title,
+		Text:       text,
+		Source:     source,
+		Title:      title,
 		Deleted:    c.Deleted,
+		Hidden:     c.Hidden,
+		Pinned:     c.Pinned,
+		AnswerId:   c.AnswerId,
+		Replies:    c.Replies,
+		EditedAt:   editedAt,
+		EditedBy:   editedBy,
+		DeletedBy:  deletedBy,
 		Points:     NewPointsDTO(c.Points),
+		Reactions:  NewReactionsDto(c.Reactions),
 		Childs:     childs,
+		Version:    c.Version,
 	}
 }
 
@@ -199,11 +280,68 @@ type comment struct {
 	ParentId   int64
 	Date       time.Time
 	User       *user
-	Text       string
+	Text       string // rendered html
+	Source     string // markdown for editing
 	Title      string
 	Deleted    bool
+	Hidden     bool
+	Pinned     bool
+	AnswerId   int64
+	Replies    int
+	EditedAt   *time.Time
+	EditedBy   string // author or moderator
+	DeletedBy  string // author or moderator
 	Points     *points
+	Reactions  []reaction
 	Childs     []comment
+	Version    int
+}
+
+func commentActor(moderator bool) string {
+	if moderator {
+		return "moderator"
+	}
+	return "author"
+}
+
+type commentRevision struct {
+	Id          int64     `json:"id"`
+	Text        string    `json:"text"`
+	Source      string    `json:"source"`
+	Title       string    `json:"title"`
+	Editor      *user     `json:"editor"`
+	ByModerator bool      `json:"byModerator"`
+	Date        time.Time `json:"date"`
+}
+
+func NewCommentRevisionDto(r *domain.CommentRevision) *commentRevision {
+	return &commentRevision{
+		Id:          r.Id,
+		Text:        r.Html,
+		Source:      r.Text,
+		Title:       r.Title,
+		Editor:      NewUserDto(r.Editor),
+		ByModerator: r.ByModerator,
+		Date:        r.Date,
+	}
+}
+
+type reaction struct {
+	Reaction string `json:"reaction"`
+	Count    int    `json:"count"`
+	Reacted  bool   `json:"reacted"`
+}
+
+func NewReactionsDto(list []domain.ReactionSummary) []reaction {
+	result := make([]reaction, len(list))
+	for i := 0; i < len(list); i++ {
+		result[i] = reaction{
+			Reaction: string(list[i].Reaction),
+			Count:    list[i].Count,
+			Reacted:  list[i].Reacted,
+		}
+	}
+	return result
 }
 
 func NewTopicTag(t *domain.TopicTag) *topicTag {
@@ -214,12 +352,14 @@ func NewTopicTag(t *domain.TopicTag) *topicTag {
 }
 
 type project struct {
-	Id     int        `json:"id"`
-	Title  string     `json:"title"`
-	Text   string     `json:"text"`
-	Tags   []topicTag `json:"tags"`
-	Owner  *user      `json:"owner"`
-	Points *points    `json:"points"`
+	Id       int        `json:"id"`
+	Title    string     `json:"title"`
+	Text     string     `json:"text"`
+	Tags     []topicTag `json:"tags"`
+	Owner    *user      `json:"owner"`
+	Points   *points    `json:"points"`
+	Comments int        `json:"comments"`
+	Version  int        `json:"version"`
 }
 
 func NewProjectDto(p *domain.Project) *project {
@@ -228,12 +368,14 @@ func NewProjectDto(p *domain.Project) *project {
 	}
 
 	pr := &project{
-		Id:     p.Id,
-		Title:  p.Title,
-		Text:   p.Text,
-		Tags:   make([]topicTag, len(p.Tags)),
-		Owner:  NewUserDto(p.Owner),
-		Points: NewPointsDTO(p.Points),
+		Id:       p.Id,
+		Title:    p.Title,
+		Text:     p.Text,
+		Tags:     make([]topicTag, len(p.Tags)),
+		Owner:    NewUserDto(p.Owner),
+		Points:   NewPointsDTO(p.Points),
+		Comments: p.Comments,
+		Version:  p.Version,
 	}
 
 	for i := 0; i < len(p.Tags); i++ {
@@ -247,7 +389,7 @@ type points struct {
 	Count int     `json:"count"`
 	Avg   float32 `json:"avg"`
 	Value float32 `json:"value"`
-	Voted bool    `json:"voted"`
+	Voted int     `json:"voted"`
 }
 
 func NewPointsDTO(p *domain.Points) *points {
@@ -259,5 +401,437 @@ func NewPointsDTO(p *domain.Points) *points {
 		Count: p.Count,
 		Avg:   p.Avg,
 		Value: p.Value,
+		Voted: p.Voted,
+	}
+}
+
+type role struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func NewRoleDto(r *domain.Role) *role {
+	if r == nil {
+		return nil
+	}
+
+	nr := &role{
+		Id:          r.Id,
+		Name:        r.Name,
+		Permissions: make([]string, len(r.Permissions)),
+	}
+
+	for i := 0; i < len(r.Permissions); i++ {
+		nr.Permissions[i] = string(r.Permissions[i])
+	}
+	return nr
+}
+
+type adminUser struct {
+	Id             string     `json:"id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	EmailConfirmed bool       `json:"emailConfirmed"`
+	Img            string     `json:"img"`
+	Roles          []string   `json:"roles"`
+	Sessions       int        `json:"sessions"`
+	Banned         bool       `json:"banned"`
+	BanReason      string     `json:"banReason,omitempty"`
+	BannedUntil    *time.Time `json:"bannedUntil,omitempty"`
+}
+
+func NewAdminUserDto(u *domain.User) *adminUser {
+	if u == nil {
+		return nil
+	}
+
+	nu := &adminUser{
+		Id:             u.Id,
+		Name:           u.Name,
+		Email:          u.Email,
+		EmailConfirmed: u.EmailConfirmed,
+		Img:            ImgManager.GetAvatarUrl(u.Img),
+		Roles:          u.Roles,
+		Sessions:       len(u.Tokens),
+		Banned:         u.IsBanned(),
+	}
+
+	if nu.Banned {
+		nu.BanReason = u.BanReason
+		if !u.BannedUntil.IsZero() {
+			nu.BannedUntil = &u.BannedUntil
+		}
+	}
+	return nu
+}
+
+type report struct {
+	Id         int64               `json:"id"`
+	EntityType string              `json:"type"`
+	EntityId   string              `json:"entityId"`
+	Status     domain.ReportStatus `json:"status"`
+	Count      int                 `json:"count"`
+	Created    time.Time           `json:"created"`
+	Updated    time.Time           `json:"updated"`
+	ResolvedAt *time.Time          `json:"resolvedAt,omitempty"`
+	Comment    string              `json:"comment,omitempty"`
+	Complaints []complaint         `json:"complaints"`
+}
+
+type complaint struct {
+	UserId string              `json:"userId"`
+	Reason domain.ReportReason `json:"reason"`
+	Text   string              `json:"text,omitempty"`
+	Date   time.Time           `json:"date"`
+}
+
+func NewReportDto(r *domain.Report) *report {
+	if r == nil {
+		return nil
+	}
+
+	entityId := strconv.FormatInt(r.EntityId, 10)
+	if r.EntityType == domain.PlanEntity {
+		entityId = core.EncodeNumToString(int(r.EntityId))
+	}
+
+	nr := &report{
+		Id:         r.Id,
+		EntityType: domain.EntityTypeToString(r.EntityType),
+		EntityId:   entityId,
+		Status:     r.Status,
+		Count:      r.Count,
+		Created:    r.Created,
+		Updated:    r.Updated,
+		Comment:    r.Comment,
+		Complaints: make([]complaint, len(r.Complaints)),
+	}
+
+	if !r.ResolvedAt.IsZero() {
+		nr.ResolvedAt = &r.ResolvedAt
+	}
+
+	for i, c := range r.Complaints {
+		nr.Complaints[i] = complaint{
+			UserId: c.UserId,
+			Reason: c.Reason,
+			Text:   c.Text,
+			Date:   c.Date,
+		}
+	}
+	return nr
+}
+
+func NewReportsDto(list []domain.Report) []report {
+	result := make([]report, len(list))
+	for i := 0; i < len(list); i++ {
+		result[i] = *NewReportDto(&list[i])
+	}
+	return result
+}
+
+type changeLogRecord struct {
+	Id         int64             `json:"id"`
+	Date       time.Time         `json:"date"`
+	Action     domain.ChangeType `json:"action"`
+	UserId     string            `json:"userId"`
+	EntityType string            `json:"type"`
+	EntityId   string            `json:"entityId"`
+	Diff       json.RawMessage   `json:"diff,omitempty"`
+	Snapshot   json.RawMessage   `json:"snapshot,omitempty"`
+	ReqId      string            `json:"reqId,omitempty"`
+	IP         string            `json:"ip,omitempty"`
+	UserAgent  string            `json:"userAgent,omitempty"`
+}
+
+func NewChangeLogDto(list []domain.ChangeLogRecord) []changeLogRecord {
+	result := make([]changeLogRecord, len(list))
+	for i, r := range list {
+		entityId := strconv.FormatInt(r.EntityId, 10)
+		if r.EntityType == domain.PlanEntity {
+			entityId = core.EncodeNumToString(int(r.EntityId))
+		}
+		if r.EntityKey != "" {
+			entityId = r.EntityKey
+		}
+
+		result[i] = changeLogRecord{
+			Id:         r.Id,
+			Date:       r.Date,
+			Action:     r.Action,
+			UserId:     r.UserId,
+			EntityType: domain.EntityTypeToString(r.EntityType),
+			EntityId:   entityId,
+			ReqId:      r.ReqId,
+			IP:         r.IP,
+			UserAgent:  r.UserAgent,
+		}
+		if r.Diff != "" {
+			result[i].Diff = json.RawMessage(r.Diff)
+		}
+		if r.Snapshot != "" {
+			result[i].Snapshot = json.RawMessage(r.Snapshot)
+		}
+	}
+	return result
+}
+
+type notification struct {
+	Id         int64                   `json:"id"`
+	Type       domain.NotificationType `json:"type"`
+	ActorId    string                  `json:"actorId,omitempty"`
+	EntityType string                  `json:"entityType"`
+	EntityId   string                  `json:"entityId"`
+	Title      string                  `json:"title"`
+	Read       bool                    `json:"read"`
+	Created    time.Time               `json:"created"`
+}
+
+func NewNotificationsDto(list []domain.Notification) []notification {
+	result := make([]notification, len(list))
+	for i, n := range list {
+		entityId := strconv.FormatInt(n.EntityId, 10)
+		if n.EntityType == domain.PlanEntity {
+			entityId = core.EncodeNumToString(int(n.EntityId))
+		}
+
+		result[i] = notification{
+			Id:         n.Id,
+			Type:       n.Type,
+			ActorId:    n.ActorId,
+			EntityType: domain.EntityTypeToString(n.EntityType),
+			EntityId:   entityId,
+			Title:      n.Title,
+			Read:       n.Read,
+			Created:    n.Created,
+		}
+	}
+	return result
+}
+
+type notificationPreference struct {
+	Type    domain.NotificationType `json:"type"`
+	Enabled bool                    `json:"enabled"`
+	Email   bool                    `json:"email"`
+}
+
+func NewNotificationPreferencesDto(list []domain.NotificationPreference) []notificationPreference {
+	result := make([]notificationPreference, len(list))
+	for i, p := range list {
+		result[i] = notificationPreference{Type: p.Type, Enabled: p.Enabled, Email: p.Email}
+	}
+	return result
+}
+
+type event struct {
+	Type       domain.EventType `json:"type"`
+	EntityType string           `json:"entityType"`
+	EntityId   string           `json:"entityId"`
+	Comment    *comment         `json:"comment,omitempty"`
+	Points     *points          `json:"points,omitempty"`
+}
+
+func NewEventDto(e *domain.Event) *event {
+	entityId := strconv.FormatInt(e.EntityId, 10)
+	if e.EntityType == domain.PlanEntity {
+		entityId = core.EncodeNumToString(int(e.EntityId))
+	}
+
+	return &event{
+		Type:       e.Type,
+		EntityType: domain.EntityTypeToString(e.EntityType),
+		EntityId:   entityId,
+		Comment:    NewCommentDto(e.Comment),
+		Points:     NewPointsDTO(e.Points),
+	}
+}
+
+type webhook struct {
+	Id         int64               `json:"id"`
+	Url        string              `json:"url"`
+	Secret     string              `json:"secret,omitempty"`
+	Events     []domain.ChangeType `json:"events"`
+	Active     bool                `json:"active"`
+	OwnChanges bool                `json:"ownChanges"`
+	Created    time.Time           `json:"created"`
+}
+
+// NewWebhookDto returns secret only when it is requested, i.e. right after webhook is created
+func NewWebhookDto(w *domain.Webhook, withSecret bool) *webhook {
+	dto := &webhook{
+		Id:         w.Id,
+		Url:        w.Url,
+		Events:     w.Events,
+		Active:     w.Active,
+		OwnChanges: w.OwnChanges,
+		Created:    w.Created,
+	}
+	if withSecret {
+		dto.Secret = w.Secret
+	}
+	return dto
+}
+
+func NewWebhooksDto(list []domain.Webhook) []webhook {
+	result := make([]webhook, len(list))
+	for i := range list {
+		result[i] = *NewWebhookDto(&list[i], false)
+	}
+	return result
+}
+
+type webhookDelivery struct {
+	Id           int64                        `json:"id"`
+	WebhookId    int64                        `json:"webhookId"`
+	Event        domain.ChangeType            `json:"event"`
+	Payload      json.RawMessage              `json:"payload"`
+	Status       domain.WebhookDeliveryStatus `json:"status"`
+	Attempts     int                          `json:"attempts"`
+	NextAttempt  time.Time                    `json:"nextAttempt"`
+	ResponseCode int                          `json:"responseCode,omitempty"`
+	LastError    string                       `json:"lastError,omitempty"`
+	Created      time.Time                    `json:"created"`
+	Delivered    *time.Time                   `json:"delivered,omitempty"`
+	RedeliveryOf int64                        `json:"redeliveryOf,omitempty"`
+}
+
+func NewWebhookDeliveryDto(d *domain.WebhookDelivery) *webhookDelivery {
+	dto := &webhookDelivery{
+		Id:           d.Id,
+		WebhookId:    d.WebhookId,
+		Event:        d.Event,
+		Payload:      json.RawMessage(d.Payload),
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		NextAttempt:  d.NextAttempt,
+		ResponseCode: d.ResponseCode,
+		LastError:    d.LastError,
+		Created:      d.Created,
+		RedeliveryOf: d.RedeliveryOf,
+	}
+	if !d.Delivered.IsZero() {
+		delivered := d.Delivered
+		dto.Delivered = &delivered
+	}
+	return dto
+}
+
+func NewWebhookDeliveriesDto(list []domain.WebhookDelivery) []webhookDelivery {
+	result := make([]webhookDelivery, len(list))
+	for i := range list {
+		result[i] = *NewWebhookDeliveryDto(&list[i])
+	}
+	return result
+}
+
+type suggestedStep struct {
+	ReferenceId   int64                `json:"sourceId"`
+	ReferenceType domain.ReferenceType `json:"type"`
+	Position      int                  `json:"position"`
+	Title         string               `json:"title"`
+	Duration      int                  `json:"duration"`
+	Difficulty    domain.Difficulty    `json:"difficulty,omitempty"`
+	Optional      bool                 `json:"optional"`
+	Notes         string               `json:"notes,omitempty"`
+}
+
+type planSuggestion struct {
+	Id         int64                   `json:"id"`
+	PlanId     string                  `json:"planId"`
+	UserId     string                  `json:"userId"`
+	Title      string                  `json:"title"`
+	Steps      []suggestedStep         `json:"steps"`
+	Message    string                  `json:"message,omitempty"`
+	Status     domain.SuggestionStatus `json:"status"`
+	Date       time.Time               `json:"date"`
+	ReviewedBy string                  `json:"reviewedBy,omitempty"`
+	ReviewedAt *time.Time              `json:"reviewedAt,omitempty"`
+	Comment    string                  `json:"comment,omitempty"`
+	Diff       json.RawMessage         `json:"diff,omitempty"`
+}
+
+func NewPlanSuggestionDto(s *domain.PlanSuggestion) *planSuggestion {
+	if s == nil {
+		return nil
+	}
+
+	dto := &planSuggestion{
+		Id:         s.Id,
+		PlanId:     core.EncodeNumToString(s.PlanId),
+		UserId:     s.UserId,
+		Title:      s.Title,
+		Steps:      make([]suggestedStep, len(s.Steps)),
+		Message:    s.Message,
+		Status:     s.Status,
+		Date:       s.Date,
+		ReviewedBy: s.ReviewedBy,
+		Comment:    s.Comment,
+	}
+
+	for i, step := range s.Steps {
+		dto.Steps[i] = suggestedStep{
+			ReferenceId:   step.ReferenceId,
+			ReferenceType: step.ReferenceType,
+			Position:      step.Position,
+			Title:         step.Title,
+			Duration:      step.Duration,
+			Difficulty:    step.Difficulty,
+			Optional:      step.Optional,
+			Notes:         step.Notes,
+		}
+	}
+
+	if !s.ReviewedAt.IsZero() {
+		reviewedAt := s.ReviewedAt
+		dto.ReviewedAt = &reviewedAt
+	}
+	if s.Diff != "" {
+		dto.Diff = json.RawMessage(s.Diff)
+	}
+	return dto
+}
+
+func NewPlanSuggestionsDto(list []domain.PlanSuggestion) []planSuggestion {
+	result := make([]planSuggestion, len(list))
+	for i := range list {
+		result[i] = *NewPlanSuggestionDto(&list[i])
+	}
+	return result
+}
+
+type planCollaborator struct {
+	PlanId    string                  `json:"planId"`
+	PlanTitle string                  `json:"planTitle,omitempty"`
+	UserId    string                  `json:"userId"`
+	UserName  string                  `json:"name,omitempty"`
+	Role      domain.CollaboratorRole `json:"role"`
+	InvitedBy string                  `json:"invitedBy"`
+	Accepted  bool                    `json:"accepted"`
+	Date      time.Time               `json:"date"`
+}
+
+func NewPlanCollaboratorDto(c *domain.PlanCollaborator) *planCollaborator {
+	if c == nil {
+		return nil
+	}
+
+	return &planCollaborator{
+		PlanId:    core.EncodeNumToString(c.PlanId),
+		PlanTitle: c.PlanTitle,
+		UserId:    c.UserId,
+		UserName:  c.UserName,
+		Role:      c.Role,
+		InvitedBy: c.InvitedBy,
+		Accepted:  c.Accepted,
+		Date:      c.Date,
+	}
+}
+
+func NewPlanCollaboratorsDto(list []domain.PlanCollaborator) []planCollaborator {
+	result := make([]planCollaborator, len(list))
+	for i := range list {
+		result[i] = *NewPlanCollaboratorDto(&list[i])
 	}
+	return result
 }
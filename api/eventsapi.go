@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+const eventsHeartbeat = 20 * time.Second
+
+// Events streams events as server-sent events.
+// Channels are passed in query: ?channel=plan:{id}&channel=topic:{id}&channel=thread:{id}, plan ids are encoded.
+// Stream is closed after maxDuration to fit into server write timeout, browsers reconnect automatically.
+func Events(subscribeEvents usecases.SubscribeEvents, maxDuration time.Duration, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			log.Errorw("Streaming is not supported by response writer")
+			statusResponse(w, &status{Code: 500})
+			return
+		}
+
+		channels, ok := parseChannels(r.URL.Query()["channel"])
+		if !ok {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		ctx := infrastructure.NewContext(r.Context())
+		events, cancel, err := subscribeEvents.Do(ctx, channels)
+		if err != nil {
+			badRequest(w, err)
+			return
+		}
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", time.Second/time.Millisecond)
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(eventsHeartbeat)
+		defer heartbeat.Stop()
+		var timeout <-chan time.Time
+		if maxDuration > 0 {
+			timer := time.NewTimer(maxDuration)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		for {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(NewEventDto(&e))
+				if err != nil {
+					log.Errorw("Fail to serialize event", "type", e.Type, "error", err.Error(), "reqid", ctx.ReqId())
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+				flusher.Flush()
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+				flusher.Flush()
+			case <-timeout:
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+// parseChannels converts channels from client format, encoded plan ids are decoded
+func parseChannels(list []string) ([]string, bool) {
+	channels := make([]string, 0, len(list))
+	for _, ch := range list {
+		parts := strings.SplitN(ch, ":", 2)
+		if len(parts) != 2 {
+			return nil, false
+		}
+
+		if parts[0] == domain.EntityTypeToString(domain.PlanEntity) {
+			planId, err := core.DecodeStringToNum(parts[1])
+			if err != nil {
+				return nil, false
+			}
+			ch = domain.EntityChannel(domain.PlanEntity, int64(planId))
+		}
+		channels = append(channels, ch)
+	}
+	return channels, true
+}
@@ -38,6 +38,18 @@ func badRequest(w http.ResponseWriter, payload interface{}) {
 	json.NewEncoder(w).Encode(payload)
 }
 
+type conflictRes struct {
+	Error   string      `json:"error"`
+	Current interface{} `json:"current"`
+}
+
+// conflictResponse returns current state of entity changed since client read it
+func conflictResponse(w http.ResponseWriter, current interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(&conflictRes{Error: core.Conflict.String(), Current: current})
+}
+
 func HtmlEscape(value string) string {
 	return template.HTMLEscapeString(value)
 }
@@ -8,13 +8,13 @@ import (
 	"net/http"
 )
 
-func Auth(rights domain.Rights, ts core.TokenService, log core.AppLogger) func(next http.Handler) http.Handler {
+func Auth(rights domain.Rights, ts core.TokenService, policy core.AccessPolicy, log core.AppLogger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			ctx := r.Context()
 			if len(authHeader) > 0 {
-				userId, userName, userRights, err := ts.Validate(authHeader[7:])
+				userId, userName, sessionId, userRights, err := ts.Validate(authHeader[7:])
 				if err != nil {
 					log.Errorw("Unauthorized. Error", "path", r.URL.Path, "requiredRights", rights, "error", err.Error())
 					statusResponse(w, &status{Code: http.StatusUnauthorized})
@@ -27,6 +27,12 @@ func Auth(rights domain.Rights, ts core.TokenService, log core.AppLogger) func(n
 					return
 				}
 
+				if !policy.IsActive(infrastructure.NewContext(ctx), userId, sessionId) {
+					log.Infow("Unauthorized. Banned or logged out", "path", r.URL.Path, "requiredRights", rights, "userId", userId)
+					statusResponse(w, &status{Code: http.StatusUnauthorized})
+					return
+				}
+
 				if rights != domain.All && !domain.Rights(userRights).HasFlag(rights) {
 					log.Infow("Forbidden", "path", r.URL.Path, "requiredRights", rights, "userId", userId, "userName", userName, "userRights", userRights)
 					statusResponse(w, &status{Code: http.StatusForbidden})
@@ -38,6 +44,7 @@ func Auth(rights domain.Rights, ts core.TokenService, log core.AppLogger) func(n
 				ctx = context.WithValue(ctx, infrastructure.ReqRights, userRights)
 				ctx = context.WithValue(ctx, infrastructure.ReqUserId, userId)
 				ctx = context.WithValue(ctx, infrastructure.ReqUserName, userName)
+				ctx = context.WithValue(ctx, infrastructure.ReqPermissions, policy.Permissions(infrastructure.NewContext(ctx), userId))
 
 			} else if rights != domain.All {
 				log.Infow("Unauthorized. no auth", "path", r.URL.Path, "requiredRights", rights)
@@ -52,3 +59,32 @@ func Auth(rights domain.Rights, ts core.TokenService, log core.AppLogger) func(n
 		return http.HandlerFunc(fn)
 	}
 }
+
+// QueryToken takes access token from query when Authorization header is missing,
+// browsers can't set headers for EventSource. Should be used before Auth
+func QueryToken(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("token"); token != "" && r.Header.Get("Authorization") == "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// Permit should be used after Auth
+func Permit(permission domain.Permission, log core.AppLogger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := infrastructure.NewContext(r.Context())
+			if !ctx.HasPermission(permission) {
+				log.Infow("Forbidden", "path", r.URL.Path, "requiredPermission", permission, "userId", ctx.UserId())
+				statusResponse(w, &status{Code: http.StatusForbidden})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+/*
+	Notifications list
+******************************************************************/
+
+type getNotificationsReq struct {
+	Unread bool `json:"unread"`
+	Count  int  `json:"count"`
+	Page   int  `json:"page"`
+}
+
+func GetNotifications(getNotifications usecases.GetNotifications, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getNotificationsReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		list, err := getNotifications.Do(infrastructure.NewContext(r.Context()), data.Unread, data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewNotificationsDto(list))
+	}
+}
+
+/*
+	Unread count
+******************************************************************/
+
+type unreadCountRes struct {
+	Count int `json:"count"`
+}
+
+func GetUnreadCount(getUnreadCount usecases.GetUnreadCount, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := infrastructure.NewContext(r.Context())
+		count, err := getUnreadCount.Do(ctx)
+		if err != nil {
+			log.Errorw("unread notifications count", "error", err.Error(), "reqid", ctx.ReqId())
+			statusResponse(w, &status{Code: 500})
+			return
+		}
+
+		valueResponse(w, &unreadCountRes{Count: count})
+	}
+}
+
+/*
+	Mark read
+******************************************************************/
+
+type markReadReq struct {
+	Ids []int64 `json:"ids"`
+}
+
+type markReadRes struct {
+	Marked int64 `json:"marked"`
+}
+
+func MarkNotificationsRead(markRead usecases.MarkNotificationsRead, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(markReadReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		ctx := infrastructure.NewContext(r.Context())
+		marked, err := markRead.Do(ctx, data.Ids)
+		if err != nil {
+			log.Errorw("mark notifications read", "error", err.Error(), "reqid", ctx.ReqId())
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &markReadRes{Marked: marked})
+	}
+}
+
+/*
+	Preferences
+******************************************************************/
+
+func GetNotificationPreferences(getPreferences usecases.GetNotificationPreferences, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := infrastructure.NewContext(r.Context())
+		list, err := getPreferences.Do(ctx)
+		if err != nil {
+			log.Errorw("notification preferences", "error", err.Error(), "reqid", ctx.ReqId())
+			statusResponse(w, &status{Code: 500})
+			return
+		}
+
+		valueResponse(w, NewNotificationPreferencesDto(list))
+	}
+}
+
+type savePreferencesReq struct {
+	Preferences []notificationPreference `json:"preferences"`
+}
+
+type savePreferencesRes struct {
+	Saved bool `json:"saved"`
+}
+
+func SaveNotificationPreferences(savePreferences usecases.SaveNotificationPreferences, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(savePreferencesReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		preferences := make([]domain.NotificationPreference, len(data.Preferences))
+		for i, p := range data.Preferences {
+			preferences[i] = domain.NotificationPreference{Type: p.Type, Enabled: p.Enabled, Email: p.Email}
+		}
+
+		ctx := infrastructure.NewContext(r.Context())
+		saved, err := savePreferences.Do(ctx, preferences)
+		if err != nil {
+			log.Errorw("save notification preferences", "error", err.Error(), "reqid", ctx.ReqId())
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &savePreferencesRes{Saved: saved})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/NeekUP/roadmaps/domain"
 	"github.com/NeekUP/roadmaps/infrastructure"
 	"net/http"
+	"strings"
 )
 
 type addPlanRequest struct {
@@ -21,7 +22,8 @@ func (req *addPlanRequest) Sanitize() {
 	req.Title = StrictSanitize(req.Title)
 
 	for i := 0; i < len(req.Steps); i++ {
-		req.Steps[i].Title = SanitizeText(req.Steps[i].Title)
+		req.Steps[i].Title = strings.TrimSpace(req.Steps[i].Title)
+		req.Steps[i].Notes = strings.TrimSpace(req.Steps[i].Notes)
 	}
 }
 
@@ -31,6 +33,23 @@ type planstep struct {
 	Source struct {
 		Id int64 `json:"id"`
 	}
+	Duration   int               `json:"duration"`
+	Difficulty domain.Difficulty `json:"difficulty"`
+	Optional   bool              `json:"optional"`
+	Notes      string            `json:"notes"`
+}
+
+// toPlanStep converts step of request, title and notes are markdown rendered by usecase
+func (s *planstep) toPlanStep() usecases.PlanStep {
+	return usecases.PlanStep{
+		ReferenceId:   s.Source.Id,
+		ReferenceType: s.Type,
+		Title:         s.Title,
+		Duration:      s.Duration,
+		Difficulty:    s.Difficulty,
+		Optional:      s.Optional,
+		Notes:         s.Notes,
+	}
 }
 
 type addPlanResponse struct {
@@ -58,7 +77,7 @@ func AddPlan(addPlan usecases.AddPlan, log core.AppLogger) func(w http.ResponseW
 		}
 
 		for _, v := range data.Steps {
-			addPlanReq.Steps = append(addPlanReq.Steps, usecases.PlanStep{ReferenceId: v.Source.Id, ReferenceType: v.Type, Title: v.Title})
+			addPlanReq.Steps = append(addPlanReq.Steps, v.toPlanStep())
 		}
 
 		plan, err := addPlan.Do(infrastructure.NewContext(r.Context()), addPlanReq)
@@ -85,6 +104,7 @@ type editPlanRequest struct {
 	Title     string     `json:"title"`
 	IsDraft   bool       `json:"isDraft"`
 	Steps     []planstep `json:"steps"`
+	Version   int        `json:"version"`
 }
 
 func (req *editPlanRequest) Sanitize() {
@@ -92,7 +112,8 @@ func (req *editPlanRequest) Sanitize() {
 	req.Title = StrictSanitize(req.Title)
 	req.TopicName = StrictSanitize(req.TopicName)
 	for i := 0; i < len(req.Steps); i++ {
-		req.Steps[i].Title = SanitizeText(req.Steps[i].Title)
+		req.Steps[i].Title = strings.TrimSpace(req.Steps[i].Title)
+		req.Steps[i].Notes = strings.TrimSpace(req.Steps[i].Notes)
 	}
 }
 
@@ -121,13 +142,18 @@ func EditPlan(editPlan usecases.EditPlan, log core.AppLogger) func(w http.Respon
 			Title:     data.Title,
 			TopicName: data.TopicName,
 			IsDraft:   data.IsDraft,
+			Version:   data.Version,
 		}
 
 		for _, v := range data.Steps {
-			addPlanReq.Steps = append(addPlanReq.Steps, usecases.PlanStep{ReferenceId: v.Source.Id, ReferenceType: v.Type, Title: v.Title})
+			addPlanReq.Steps = append(addPlanReq.Steps, v.toPlanStep())
 		}
 
 		_, err = editPlan.Do(infrastructure.NewContext(r.Context()), addPlanReq)
+		if conflict, ok := err.(*core.ConflictError); ok {
+			conflictResponse(w, NewPlanDto(conflict.Current.(*domain.Plan), false))
+			return
+		}
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -277,8 +303,91 @@ func GetPlan(getPlan usecases.GetPlan, getUsersPlan usecases.GetUsersPlan, getPo
 	}
 }
 
+func ForkPlan(forkPlan usecases.ForkPlan, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getPlanRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		id, err := core.DecodeStringToNum(data.Id)
+		if err != nil {
+			errors := make(map[string]string)
+			errors["id"] = core.InvalidValue.String()
+			badRequest(w, core.ValidationError(errors))
+			return
+		}
+
+		plan, err := forkPlan.Do(infrastructure.NewContext(r.Context()), id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &addPlanResponse{
+			TopicName: plan.TopicName,
+			Title:     plan.Title,
+			Id:        core.EncodeNumToString(plan.Id),
+		})
+	}
+}
+
+type planDiffResponse struct {
+	Id         string          `json:"id"`
+	UpstreamId string          `json:"upstreamId"`
+	Diff       json.RawMessage `json:"diff"`
+}
+
+func GetPlanDiff(getPlanDiff usecases.GetPlanDiff, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getPlanRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		id, err := core.DecodeStringToNum(data.Id)
+		if err != nil {
+			errors := make(map[string]string)
+			errors["id"] = core.InvalidValue.String()
+			badRequest(w, core.ValidationError(errors))
+			return
+		}
+
+		diff, err := getPlanDiff.Do(infrastructure.NewContext(r.Context()), id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &planDiffResponse{
+			Id:         core.EncodeNumToString(diff.Plan.Id),
+			UpstreamId: core.EncodeNumToString(diff.Upstream.Id),
+			Diff:       json.RawMessage(diff.Diff),
+		})
+	}
+}
+
 type getPlanListRequest struct {
-	TopicName string `json:"topicName"`
+	TopicName string          `json:"topicName"`
+	Sort      domain.PlanSort `json:"sort"`
 }
 
 func (req *getPlanListRequest) Sanitize() {
@@ -299,7 +408,7 @@ func GetPlanList(getPlanList usecases.GetPlanList, getUsersPlan usecases.GetUser
 
 		data.Sanitize()
 		ctx := infrastructure.NewContext(r.Context())
-		list, err := getPlanList.Do(ctx, data.TopicName, 100)
+		list, err := getPlanList.Do(ctx, data.TopicName, data.Sort, 100)
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -464,3 +573,41 @@ func RemovePlan(removePlan usecases.RemovePlan, log core.AppLogger) func(w http.
 		valueResponse(w, &removeTopicTagRes{Removed: true})
 	}
 }
+
+type restoreRes struct {
+	Restored bool `json:"restored"`
+}
+
+func RestorePlan(restorePlan usecases.RestorePlan, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removePlanReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		id, err := core.DecodeStringToNum(data.Id)
+		if err != nil {
+			errors := make(map[string]string)
+			errors["id"] = core.InvalidValue.String()
+			badRequest(w, core.ValidationError(errors))
+			return
+		}
+
+		restored, err := restorePlan.Do(infrastructure.NewContext(r.Context()), id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &restoreRes{Restored: restored})
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+/*
+	Add Report
+******************************************************************/
+
+type addReportReq struct {
+	Id     string              `json:"id"`
+	Type   string              `json:"type"`
+	Reason domain.ReportReason `json:"reason"`
+	Text   string              `json:"text"`
+}
+
+func (req *addReportReq) Sanitize() {
+	req.Id = StrictSanitize(req.Id)
+	req.Type = StrictSanitize(req.Type)
+	req.Text = StrictSanitize(req.Text)
+}
+
+type addReportRes struct {
+	Added bool `json:"added"`
+}
+
+func AddReport(addReport usecases.AddReport, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(addReportReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		entityType, entityId, ok := parseEntity(data.Type, data.Id)
+		if !ok {
+			errors := make(map[string]string)
+			errors["id"] = core.InvalidValue.String()
+			badRequest(w, core.ValidationError(errors))
+			return
+		}
+
+		added, err := addReport.Do(infrastructure.NewContext(r.Context()), entityType, entityId, data.Reason, data.Text)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &addReportRes{Added: added})
+	}
+}
+
+/*
+	Moderation Queue
+******************************************************************/
+
+type getReportsReq struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+	Page  int    `json:"page"`
+}
+
+func (req *getReportsReq) Sanitize() {
+	req.Type = StrictSanitize(req.Type)
+}
+
+func GetReports(getReports usecases.GetReports, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getReportsReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		var entityType domain.EntityType
+		if data.Type != "" {
+			var isValidType bool
+			if isValidType, entityType = domain.EntityTypeFromString(data.Type); !isValidType {
+				statusResponse(w, &status{Code: http.StatusBadRequest})
+				return
+			}
+		}
+
+		list, err := getReports.Do(infrastructure.NewContext(r.Context()), entityType, data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewReportsDto(list))
+	}
+}
+
+/*
+	Resolve Report
+******************************************************************/
+
+type resolveReportReq struct {
+	Id         int64               `json:"id"`
+	Resolution domain.ReportStatus `json:"resolution"`
+	Comment    string              `json:"comment"`
+}
+
+func (req *resolveReportReq) Sanitize() {
+	req.Comment = StrictSanitize(req.Comment)
+}
+
+type resolveReportRes struct {
+	Resolved bool `json:"resolved"`
+}
+
+func ResolveReport(resolveReport usecases.ResolveReport, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(resolveReportReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		resolved, err := resolveReport.Do(infrastructure.NewContext(r.Context()), data.Id, data.Resolution, data.Comment)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &resolveReportRes{Resolved: resolved})
+	}
+}
+
+/*
+	Unhide Content
+******************************************************************/
+
+type unhideContentReq struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+func (req *unhideContentReq) Sanitize() {
+	req.Id = StrictSanitize(req.Id)
+	req.Type = StrictSanitize(req.Type)
+}
+
+func UnhideContent(unhideContent usecases.UnhideContent, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(unhideContentReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		entityType, entityId, ok := parseEntity(data.Type, data.Id)
+		if !ok {
+			errors := make(map[string]string)
+			errors["id"] = core.InvalidValue.String()
+			badRequest(w, core.ValidationError(errors))
+			return
+		}
+
+		restored, err := unhideContent.Do(infrastructure.NewContext(r.Context()), entityType, entityId)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &restoreRes{Restored: restored})
+	}
+}
+
+/*
+	User Reports
+******************************************************************/
+
+type getUserReportsReq struct {
+	Count int `json:"count"`
+	Page  int `json:"page"`
+}
+
+func GetUserReports(getUserReports usecases.GetUserReports, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getUserReportsReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		list, err := getUserReports.Do(infrastructure.NewContext(r.Context()), data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewReportsDto(list))
+	}
+}
+
+// parseEntity converts entity type name and id from request, plan ids are encoded
+func parseEntity(entityTypeName string, id string) (domain.EntityType, int64, bool) {
+	ok, entityType := domain.EntityTypeFromString(entityTypeName)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if entityType == domain.PlanEntity {
+		planId, err := core.DecodeStringToNum(id)
+		return entityType, int64(planId), err == nil
+	}
+
+	entityId, err := strconv.ParseInt(id, 10, 64)
+	return entityType, entityId, err == nil
+}
@@ -66,3 +66,59 @@ func AddSource(addSource usecases.AddSource, log core.AppLogger) func(w http.Res
 		})
 	}
 }
+
+type removeSourceReq struct {
+	Id int64 `json:"id"`
+}
+
+func RemoveSource(removeSource usecases.RemoveSource, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeSourceReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		removed, err := removeSource.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &removeTopicTagRes{Removed: removed})
+	}
+}
+
+func RestoreSource(restoreSource usecases.RestoreSource, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeSourceReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		restored, err := restoreSource.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &restoreRes{Restored: restored})
+	}
+}
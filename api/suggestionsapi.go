@@ -0,0 +1,237 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+/*
+	Suggest Plan Edit
+******************************************************************/
+
+type suggestPlanEditReq struct {
+	PlanId  string     `json:"planId"`
+	Title   string     `json:"title"`
+	Steps   []planstep `json:"steps"`
+	Message string     `json:"message"`
+}
+
+func (req *suggestPlanEditReq) Sanitize() {
+	req.PlanId = StrictSanitize(req.PlanId)
+	req.Title = StrictSanitize(req.Title)
+	req.Message = StrictSanitize(req.Message)
+	for i := 0; i < len(req.Steps); i++ {
+		req.Steps[i].Title = strings.TrimSpace(req.Steps[i].Title)
+		req.Steps[i].Notes = strings.TrimSpace(req.Steps[i].Notes)
+	}
+}
+
+func SuggestPlanEdit(suggestPlanEdit usecases.SuggestPlanEdit, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(suggestPlanEditReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+		planId, err := core.DecodeStringToNum(data.PlanId)
+		if err != nil {
+			badRequest(w, core.ValidationError(map[string]string{"planId": core.InvalidValue.String()}))
+			return
+		}
+
+		req := usecases.SuggestPlanEditReq{
+			PlanId:  planId,
+			Title:   data.Title,
+			Message: data.Message,
+		}
+		for _, v := range data.Steps {
+			req.Steps = append(req.Steps, v.toPlanStep())
+		}
+
+		suggestion, err := suggestPlanEdit.Do(infrastructure.NewContext(r.Context()), req)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewPlanSuggestionDto(suggestion))
+	}
+}
+
+/*
+	Pending Suggestions
+******************************************************************/
+
+type getPlanSuggestionsReq struct {
+	PlanId string `json:"planId"`
+	Count  int    `json:"count"`
+	Page   int    `json:"page"`
+}
+
+func (req *getPlanSuggestionsReq) Sanitize() {
+	req.PlanId = StrictSanitize(req.PlanId)
+}
+
+func GetPlanSuggestions(getPlanSuggestions usecases.GetPlanSuggestions, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getPlanSuggestionsReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		// suggestions for all plans of user when plan is not specified
+		planId := 0
+		if data.PlanId != "" {
+			planId, err = core.DecodeStringToNum(data.PlanId)
+			if err != nil {
+				badRequest(w, core.ValidationError(map[string]string{"planId": core.InvalidValue.String()}))
+				return
+			}
+		}
+
+		list, err := getPlanSuggestions.Do(infrastructure.NewContext(r.Context()), planId, data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewPlanSuggestionsDto(list))
+	}
+}
+
+/*
+	Get Suggestion
+******************************************************************/
+
+type planSuggestionReq struct {
+	Id int64 `json:"id"`
+}
+
+func GetPlanSuggestion(getPlanSuggestion usecases.GetPlanSuggestion, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(planSuggestionReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		suggestion, err := getPlanSuggestion.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewPlanSuggestionDto(suggestion))
+	}
+}
+
+/*
+	Accept Suggestion
+******************************************************************/
+
+type reviewSuggestionRes struct {
+	Success bool `json:"success"`
+}
+
+func AcceptPlanSuggestion(acceptPlanSuggestion usecases.AcceptPlanSuggestion, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(planSuggestionReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		accepted, err := acceptPlanSuggestion.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if conflict, ok := err.(*core.ConflictError); ok {
+			conflictResponse(w, NewPlanDto(conflict.Current.(*domain.Plan), false))
+			return
+		}
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &reviewSuggestionRes{Success: accepted})
+	}
+}
+
+/*
+	Reject Suggestion
+******************************************************************/
+
+type rejectPlanSuggestionReq struct {
+	Id      int64  `json:"id"`
+	Comment string `json:"comment"`
+}
+
+func (req *rejectPlanSuggestionReq) Sanitize() {
+	req.Comment = StrictSanitize(req.Comment)
+}
+
+func RejectPlanSuggestion(rejectPlanSuggestion usecases.RejectPlanSuggestion, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(rejectPlanSuggestionReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		rejected, err := rejectPlanSuggestion.Do(infrastructure.NewContext(r.Context()), data.Id, data.Comment)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &reviewSuggestionRes{Success: rejected})
+	}
+}
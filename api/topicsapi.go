@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
 	"github.com/NeekUP/roadmaps/infrastructure"
 	"net/http"
+	"strings"
 )
 
 type addTopicRequest struct {
@@ -17,7 +19,7 @@ type addTopicRequest struct {
 
 func (req *addTopicRequest) Sanitize() {
 	req.Title = StrictSanitize(req.Title)
-	req.Desc = StrictSanitize(req.Desc)
+	req.Desc = strings.TrimSpace(req.Desc)
 	sanitizedTags := make([]string, len(req.Tags))
 	for i, v := range req.Tags {
 		sanitizedTags[i] = StrictSanitize(v)
@@ -55,15 +57,16 @@ func AddTopic(addTopic usecases.AddTopic, log core.AppLogger) func(w http.Respon
 }
 
 type editTopicRequest struct {
-	Id    int    `json:"id"`
-	Title string `json:"title"`
-	Desc  string `json:"desc"`
-	IsTag bool   `json:"istag"`
+	Id      int    `json:"id"`
+	Title   string `json:"title"`
+	Desc    string `json:"desc"`
+	IsTag   bool   `json:"istag"`
+	Version int    `json:"version"`
 }
 
 func (req *editTopicRequest) Sanitize() {
 	req.Title = StrictSanitize(req.Title)
-	req.Desc = StrictSanitize(req.Desc)
+	req.Desc = strings.TrimSpace(req.Desc)
 }
 
 func EditTopic(editTopic usecases.EditTopic, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
@@ -81,7 +84,11 @@ func EditTopic(editTopic usecases.EditTopic, log core.AppLogger) func(w http.Res
 
 		context := infrastructure.NewContext(r.Context())
 		data.Sanitize()
-		_, err = editTopic.Do(context, data.Id, data.Title, data.Desc, data.IsTag)
+		_, err = editTopic.Do(context, data.Id, data.Version, data.Title, data.Desc, data.IsTag)
+		if conflict, ok := err.(*core.ConflictError); ok {
+			conflictResponse(w, NewTopicDto(conflict.Current.(*domain.Topic)))
+			return
+		}
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -260,3 +267,59 @@ func RemoveTopicTag(removeTopicTag usecases.RemoveTopicTag, log core.AppLogger)
 		valueResponse(w, &removeTopicTagRes{Removed: removed})
 	}
 }
+
+type removeTopicReq struct {
+	Id int `json:"id"`
+}
+
+func RemoveTopic(removeTopic usecases.RemoveTopic, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeTopicReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		removed, err := removeTopic.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &removeTopicTagRes{Removed: removed})
+	}
+}
+
+func RestoreTopic(restoreTopic usecases.RestoreTopic, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeTopicReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		restored, err := restoreTopic.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &restoreRes{Restored: restored})
+	}
+}
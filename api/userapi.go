@@ -546,10 +546,81 @@ func EmailConfirmation(confirmation usecases.EmailConfirmation, log core.AppLogg
 		_, err := confirmation.Do(ctx, id[0], secret[0])
 		if err != nil {
 			log.Errorw("Bad request", "error", err.Error(), "url", r.URL.String())
-			statusResponse(w, &status{Code: 403})
+			if appErr, ok := err.(*core.AppError); ok && appErr.Message != core.AccessDenied.String() {
+				statusResponse(w, &status{Code: 403, Message: appErr.Message})
+			} else {
+				statusResponse(w, &status{Code: 403})
+			}
 			return
 		}
 
 		http.Redirect(w, r, "/login", 302)
 	}
 }
+
+/*
+	Resend confirmation
+******************************************************************/
+
+type confirmationRes struct {
+	Sent bool `json:"sent"`
+}
+
+func ResendConfirmation(resendConfirmation usecases.ResendConfirmation, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := infrastructure.NewContext(r.Context())
+		sent, err := resendConfirmation.Do(ctx)
+		if err != nil {
+			log.Errorw("resend confirmation", "error", err.Error(), "reqid", ctx.ReqId())
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &confirmationRes{Sent: sent})
+	}
+}
+
+/*
+	Change email
+******************************************************************/
+
+type changeEmailReq struct {
+	Email string `json:"email"`
+}
+
+func (req *changeEmailReq) Sanitize() {
+	req.Email = StrictSanitize(req.Email)
+}
+
+func ChangeEmail(changeEmail usecases.ChangeEmail, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		decoder := json.NewDecoder(r.Body)
+		data := new(changeEmailReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		data.Sanitize()
+		ctx := infrastructure.NewContext(r.Context())
+		sent, err := changeEmail.Do(ctx, data.Email)
+		if err != nil {
+			log.Errorw("change email", "error", err.Error(), "reqid", ctx.ReqId())
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &confirmationRes{Sent: sent})
+	}
+}
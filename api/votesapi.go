@@ -20,7 +20,7 @@ type addVoteResponse struct {
 	Success bool `json:"success"`
 }
 
-func AddPoints(addSource usecases.AddVote, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+func AddPoints(addVote usecases.AddVote, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		decoder := json.NewDecoder(r.Body)
@@ -33,26 +33,85 @@ func AddPoints(addSource usecases.AddVote, log core.AppLogger) func(w http.Respo
 			return
 		}
 
-		var entityType domain.EntityType
-		var isValidType bool
-		if isValidType, entityType = domain.EntityTypeFromString(data.Type); !isValidType {
+		entityType, entityId, ok := parseVoteTarget(w, data.Type, data.Id)
+		if !ok {
+			return
+		}
+
+		success, err := addVote.Do(infrastructure.NewContext(r.Context()), entityType, entityId, data.Value)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &addVoteResponse{
+			Success: success,
+		})
+	}
+}
+
+type removeVoteRequest struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+func ChangePoints(changeVote usecases.ChangeVote, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		decoder := json.NewDecoder(r.Body)
+		data := new(addVoteRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
 			statusResponse(w, &status{Code: http.StatusBadRequest})
 			return
 		}
 
-		entityId, err := strconv.ParseInt(data.Type, 10, 64)
-		if err != nil || entityType == domain.PlanEntity {
-			id, err := core.DecodeStringToNum(data.Id)
-			if err != nil {
-				errors := make(map[string]string)
-				errors["id"] = core.InvalidValue.String()
-				badRequest(w, core.ValidationError(errors))
-				return
+		entityType, entityId, ok := parseVoteTarget(w, data.Type, data.Id)
+		if !ok {
+			return
+		}
+
+		success, err := changeVote.Do(infrastructure.NewContext(r.Context()), entityType, entityId, data.Value)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
 			}
-			entityId = int64(id)
+			return
+		}
+
+		valueResponse(w, &addVoteResponse{
+			Success: success,
+		})
+	}
+}
+
+func RemovePoints(removeVote usecases.RemoveVote, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeVoteRequest)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		entityType, entityId, ok := parseVoteTarget(w, data.Type, data.Id)
+		if !ok {
+			return
 		}
 
-		success, err := addSource.Do(infrastructure.NewContext(r.Context()), entityType, entityId, data.Value)
+		success, err := removeVote.Do(infrastructure.NewContext(r.Context()), entityType, entityId)
 		if err != nil {
 			if err.Error() != core.InternalError.String() {
 				badRequest(w, err)
@@ -67,3 +126,27 @@ func AddPoints(addSource usecases.AddVote, log core.AppLogger) func(w http.Respo
 		})
 	}
 }
+
+// parseVoteTarget writes bad request response and returns false if type or id is invalid.
+// Plans are identified by encoded id, other entities by number
+func parseVoteTarget(w http.ResponseWriter, typeName string, id string) (domain.EntityType, int64, bool) {
+	var entityType domain.EntityType
+	var isValidType bool
+	if isValidType, entityType = domain.EntityTypeFromString(typeName); !isValidType {
+		statusResponse(w, &status{Code: http.StatusBadRequest})
+		return entityType, 0, false
+	}
+
+	entityId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || entityType == domain.PlanEntity {
+		num, err := core.DecodeStringToNum(id)
+		if err != nil {
+			errors := make(map[string]string)
+			errors["id"] = core.InvalidValue.String()
+			badRequest(w, core.ValidationError(errors))
+			return entityType, 0, false
+		}
+		entityId = int64(num)
+	}
+	return entityType, entityId, true
+}
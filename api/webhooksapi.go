@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+/*
+	Add webhook
+******************************************************************/
+
+// url is validated by usecase, html sanitizer would break query of url
+type addWebhookReq struct {
+	Url    string              `json:"url"`
+	Events []domain.ChangeType `json:"events"`
+}
+
+func (req *addWebhookReq) Sanitize() {
+	req.Url = strings.TrimSpace(req.Url)
+}
+
+func AddWebhook(addWebhook usecases.AddWebhook, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(addWebhookReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		webhook, err := addWebhook.Do(infrastructure.NewContext(r.Context()), data.Url, data.Events)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewWebhookDto(webhook, true))
+	}
+}
+
+/*
+	Edit webhook
+******************************************************************/
+
+type editWebhookReq struct {
+	Id     int64               `json:"id"`
+	Url    string              `json:"url"`
+	Events []domain.ChangeType `json:"events"`
+	Active bool                `json:"active"`
+}
+
+func (req *editWebhookReq) Sanitize() {
+	req.Url = strings.TrimSpace(req.Url)
+}
+
+func EditWebhook(editWebhook usecases.EditWebhook, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(editWebhookReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+		data.Sanitize()
+
+		webhook, err := editWebhook.Do(infrastructure.NewContext(r.Context()), data.Id, data.Url, data.Events, data.Active)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewWebhookDto(webhook, false))
+	}
+}
+
+/*
+	Remove webhook
+******************************************************************/
+
+type removeWebhookReq struct {
+	Id int64 `json:"id"`
+}
+
+type removeWebhookRes struct {
+	Removed bool `json:"removed"`
+}
+
+func RemoveWebhook(removeWebhook usecases.RemoveWebhook, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(removeWebhookReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		removed, err := removeWebhook.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, &removeWebhookRes{Removed: removed})
+	}
+}
+
+/*
+	Webhooks list
+******************************************************************/
+
+func GetWebhooks(getWebhooks usecases.GetWebhooks, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := getWebhooks.Do(infrastructure.NewContext(r.Context()))
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewWebhooksDto(list))
+	}
+}
+
+/*
+	Deliveries log
+******************************************************************/
+
+type getWebhookDeliveriesReq struct {
+	WebhookId int64 `json:"webhookId"`
+	Count     int   `json:"count"`
+	Page      int   `json:"page"`
+}
+
+func GetWebhookDeliveries(getDeliveries usecases.GetWebhookDeliveries, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(getWebhookDeliveriesReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		list, err := getDeliveries.Do(infrastructure.NewContext(r.Context()), data.WebhookId, data.Count, data.Page)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewWebhookDeliveriesDto(list))
+	}
+}
+
+/*
+	Redelivery
+******************************************************************/
+
+type redeliverWebhookReq struct {
+	Id int64 `json:"id"`
+}
+
+func RedeliverWebhook(redeliver usecases.RedeliverWebhook, log core.AppLogger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		data := new(redeliverWebhookReq)
+		err := decoder.Decode(data)
+		defer r.Body.Close()
+
+		if err != nil {
+			statusResponse(w, &status{Code: http.StatusBadRequest})
+			return
+		}
+
+		delivery, err := redeliver.Do(infrastructure.NewContext(r.Context()), data.Id)
+		if err != nil {
+			if err.Error() != core.InternalError.String() {
+				badRequest(w, err)
+			} else {
+				statusResponse(w, &status{Code: 500})
+			}
+			return
+		}
+
+		valueResponse(w, NewWebhookDeliveryDto(delivery))
+	}
+}
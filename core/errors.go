@@ -27,6 +27,12 @@ const (
 	InvalidCount          ErrorCode = "INVALID_COUNT"
 	NotExists             ErrorCode = "NOT_EXISTS"
 	AccessDenied          ErrorCode = "ACCESS_DENIED"
+	InUse                 ErrorCode = "IN_USE"
+	UserBanned            ErrorCode = "USER_BANNED"
+	ConfirmationExpired   ErrorCode = "CONFIRMATION_EXPIRED"
+	AlreadyConfirmed      ErrorCode = "ALREADY_CONFIRMED"
+	TooManyRequests       ErrorCode = "TOO_MANY_REQUESTS"
+	Conflict              ErrorCode = "CONFLICT"
 )
 
 func (e ErrorCode) String() string {
@@ -52,3 +58,14 @@ func ValidationError(validation map[string]string) *AppError {
 		Message:    string(InvalidRequest),
 		Validation: validation}
 }
+
+// ConflictError is returned when entity was changed by somebody else since client read it.
+// Current is the actual state of entity, client merges changes and retries with its version
+type ConflictError struct {
+	*AppError
+	Current interface{}
+}
+
+func NewConflictError(current interface{}) *ConflictError {
+	return &ConflictError{AppError: NewError(Conflict), Current: current}
+}
@@ -10,14 +10,21 @@ type UserRepository interface {
 	Get(ctx ReqContext, id string) *domain.User
 	GetList(ctx ReqContext, id []string) []domain.User
 	Save(ctx ReqContext, user *domain.User) (bool, *AppError)
+	// SaveWithEmail saves user and puts email addressed to the user into outbox in one transaction
+	SaveWithEmail(ctx ReqContext, user *domain.User, email *domain.Email) (bool, *AppError)
 	AddOauth(ctx ReqContext, userid, provider, openid string) (bool, *AppError)
 	Update(ctx ReqContext, user *domain.User) (bool, *AppError)
+	// UpdateWithEmail updates user and puts email addressed to the user into outbox in one transaction
+	UpdateWithEmail(ctx ReqContext, user *domain.User, email *domain.Email) (bool, *AppError)
 	ExistsName(ctx ReqContext, name string) (exists bool, ok bool)
 	ExistsEmail(ctx ReqContext, email string) (exists bool, ok bool)
 	FindByEmail(ctx ReqContext, email string) *domain.User
 	FindByOauth(ctx ReqContext, provider, id string) *domain.User
+	// FindByNames ignores case of names
+	FindByNames(ctx ReqContext, names []string) []domain.User
 	Count(ctx ReqContext) (count int, ok bool)
 	Delete(ctx ReqContext, id string) (bool, *AppError)
+	Search(ctx ReqContext, filter domain.UserFilter, count int, page int) []domain.User
 	//dev
 	All() []domain.User
 }
@@ -27,7 +34,13 @@ type SourceRepository interface {
 	FindByIdentifier(ctx ReqContext, identifier string) *domain.Source
 	Save(ctx ReqContext, source *domain.Source) (bool, *AppError)
 	Update(ctx ReqContext, source *domain.Source) (bool, *AppError)
-	GetOrAddByIdentifier(ctx ReqContext, source *domain.Source) *domain.Source
+	// GetOrAddByIdentifier returns source with the same identifier or saves new one, created is true for new source
+	GetOrAddByIdentifier(ctx ReqContext, source *domain.Source) (s *domain.Source, created bool)
+	// Delete marks source as deleted, it stays in db until purged
+	Delete(ctx ReqContext, id int64) (bool, *AppError)
+	Restore(ctx ReqContext, id int64) (bool, *AppError)
+	// Purge removes sources deleted before the date and not used in any step
+	Purge(before time.Time) (int64, *AppError)
 
 	//dev
 	All() []domain.Source
@@ -37,12 +50,21 @@ type TopicRepository interface {
 	Get(ctx ReqContext, name string) *domain.Topic
 	GetById(ctx ReqContext, id int) *domain.Topic
 	Save(ctx ReqContext, source *domain.Topic) (bool, *AppError)
+	// Update saves topic if its version is the same as source.Version and increments version.
+	// Returns Conflict error if topic was changed since it was read
 	Update(ctx ReqContext, source *domain.Topic) (bool, *AppError)
 	//TitleLike(ctx ReqContext, str string, count int) []domain.Topic
 	Search(ctx ReqContext, str string, tags []string, count int) []domain.Topic
 	AddTag(ctx ReqContext, tagname, topicname string) bool
 	DeleteTag(ctx ReqContext, tagname, topicname string) bool
 	GetTags(ctx ReqContext, topicnames []string) []domain.TopicTag
+	// HasPlans returns true if topic has not deleted plans
+	HasPlans(ctx ReqContext, name string) bool
+	// Delete marks topic as deleted, it stays in db until purged
+	Delete(ctx ReqContext, id int) (bool, *AppError)
+	Restore(ctx ReqContext, id int) (bool, *AppError)
+	// Purge removes topics deleted before the date and not used in any plan
+	Purge(before time.Time) (int64, *AppError)
 
 	//dev
 	All() []domain.Topic
@@ -52,12 +74,29 @@ type PlanRepository interface {
 	SaveWithSteps(ctx ReqContext, plan *domain.Plan) (bool, *AppError)
 	// should includes steps
 	Get(ctx ReqContext, id int) *domain.Plan
+	// GetWithDraft returns also draft or hidden plan when user is its owner or accepted collaborator
 	GetWithDraft(ctx ReqContext, id int, userid string) *domain.Plan
 	GetList(ctx ReqContext, id []int) []domain.Plan
-	GetPopularByTopic(ctx ReqContext, topic string, count int) []domain.Plan
+	// GetPopularByTopic returns plans in order of scores saved by ranking job
+	GetPopularByTopic(ctx ReqContext, topic string, sort domain.PlanSort, count int) []domain.Plan
+	// SaveScores replaces scores of plans for the ranking algorithm
+	SaveScores(sort domain.PlanSort, scores map[int64]float64) *AppError
+	// GetListedIds returns ids of plans shown in lists, i.e. not drafts, not hidden and not deleted
+	GetListedIds() ([]int64, *AppError)
+	// Update replaces title and steps of plan if its version is the same as plan.Version and increments version.
+	// Returns Conflict error if plan was changed since it was read
 	Update(ctx ReqContext, plan *domain.Plan) (bool, *AppError)
+	// Delete marks plan as deleted, it stays in db until purged
 	Delete(ctx ReqContext, planId int) (bool, *AppError)
+	Restore(ctx ReqContext, planId int) (bool, *AppError)
+	// Purge removes plans deleted before the date with their steps and comments
+	Purge(before time.Time) (int64, *AppError)
+	SetHidden(ctx ReqContext, planId int, hidden bool) (bool, *AppError)
+	// GetHidden returns plan hidden by moderator
+	GetHidden(ctx ReqContext, planId int) *domain.Plan
 	GetByUser(ctx ReqContext, userid string, count int, page int) []domain.Plan
+	// CountForks returns number of not deleted plans forked from the plan
+	CountForks(ctx ReqContext, planId int) int
 	//dev
 	All() []domain.Plan
 }
@@ -76,37 +115,201 @@ type UsersPlanRepository interface {
 	Remove(ctx ReqContext, userId string, planId int) (bool, *AppError)
 	GetByTopic(ctx ReqContext, userId, topicName string) *domain.UsersPlan
 	GetByUser(ctx ReqContext, userId string) []domain.UsersPlan
+	// GetUsers returns ids of users who added plan to favorites
+	GetUsers(ctx ReqContext, planId int) []string
 }
 
 type CommentsRepository interface {
 	Add(ctx ReqContext, comment *domain.Comment) (bool, error)
-	Update(ctx ReqContext, id int64, text, title string) (bool, error)
-	Delete(ctx ReqContext, id int64) (bool, error)
+	// Update saves current text of comment as revision before update.
+	// Returns Conflict error if version of comment differs from version
+	Update(ctx ReqContext, id int64, version int, text, html, title string, editorId string) (bool, *AppError)
+	Delete(ctx ReqContext, id int64, deletedBy string) (bool, error)
+	// GetRevisions returns replaced versions of comment, oldest first
+	GetRevisions(ctx ReqContext, commentId int64) []domain.CommentRevision
+	SetHidden(ctx ReqContext, id int64, hidden bool) (bool, error)
 	Get(ctx ReqContext, id int64) *domain.Comment
-	GetThreadList(ctx ReqContext, entityType int, entityId int64, count int, page int) []domain.Comment
-	GetThread(ctx ReqContext, entityType int, entityId int64, threadId int64) []domain.Comment
+	// GetThreadList and GetThread return page of comments after cursor, and cursor of next page if there are more comments.
+	// Pinned threads are always first.
+	GetThreadList(ctx ReqContext, entityType int, entityId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor)
+	GetThread(ctx ReqContext, entityType int, entityId int64, threadId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor)
+	// SetPinned and SetAnswer update first comment of thread only
+	SetPinned(ctx ReqContext, id int64, pinned bool) (bool, error)
+	// SetAnswer with zero answerId removes accepted answer
+	SetAnswer(ctx ReqContext, threadId int64, answerId int64) (bool, error)
+	// ToggleReaction removes reaction of user if exists or adds it, returns true if reaction was added
+	ToggleReaction(ctx ReqContext, commentId int64, userId string, reaction domain.Reaction) (bool, error)
+	// GetReactions returns reactions grouped by comment, Reacted is set for reactions of userId
+	GetReactions(ctx ReqContext, userId string, commentIds []int64) map[int64][]domain.ReactionSummary
+	// Count returns count of visible comments by entity, entities without comments are missing
+	Count(ctx ReqContext, entityType domain.EntityType, entityIds []int64) map[int64]int
+}
+
+type RoleRepository interface {
+	Get(ctx ReqContext, id int) *domain.Role
+	GetByName(ctx ReqContext, name string) *domain.Role
+	GetList(ctx ReqContext) []domain.Role
+	GetByUser(ctx ReqContext, userId string) []domain.Role
+	Save(ctx ReqContext, role *domain.Role) (bool, *AppError)
+	Update(ctx ReqContext, role *domain.Role) (bool, *AppError)
+	Delete(ctx ReqContext, id int) (bool, *AppError)
+	AddToUser(ctx ReqContext, userId string, roleId int) (bool, *AppError)
+	// RemoveFromUser returns InUse error when keepLast is set and user is the last one with the role
+	RemoveFromUser(ctx ReqContext, userId string, roleId int, keepLast bool) (bool, *AppError)
+}
+
+type ReportRepository interface {
+	// Add appends complaint to open report of entity or creates new one.
+	// Returns false if user already complained about this entity.
+	Add(ctx ReqContext, entityType domain.EntityType, entityId int64, complaint *domain.Complaint) (bool, *AppError)
+	Get(ctx ReqContext, id int64) *domain.Report
+	// GetOpen returns open reports ordered by number of complaints, entityType 0 means any type
+	GetOpen(ctx ReqContext, entityType domain.EntityType, count int, page int) []domain.Report
+	// GetByUser returns reports with complaints of user only
+	GetByUser(ctx ReqContext, userId string, count int, page int) []domain.Report
+	// Resolve closes report if it is still open, returns false if it was resolved by other moderator
+	Resolve(ctx ReqContext, report *domain.Report) (bool, *AppError)
+	// Reopen returns report with given resolution back to the queue
+	Reopen(ctx ReqContext, report *domain.Report) (bool, *AppError)
+}
+
+type PlanSuggestionRepository interface {
+	Add(ctx ReqContext, suggestion *domain.PlanSuggestion) (bool, *AppError)
+	Get(ctx ReqContext, id int64) *domain.PlanSuggestion
+	// GetPending returns not reviewed suggestions for plans of owner, newest first. planId 0 means any plan
+	GetPending(ctx ReqContext, ownerId string, planId int, count int, page int) []domain.PlanSuggestion
+	// Review saves status and comment of pending suggestion, returns false if suggestion already reviewed
+	Review(ctx ReqContext, suggestion *domain.PlanSuggestion) (bool, *AppError)
+}
+
+type PlanCollaboratorRepository interface {
+	// Save adds invitation or changes role of existed collaborator keeping its acceptance
+	Save(ctx ReqContext, collaborator *domain.PlanCollaborator) (bool, *AppError)
+	// Accept returns false if there is no invitation
+	Accept(ctx ReqContext, planId int, userId string) (bool, *AppError)
+	Remove(ctx ReqContext, planId int, userId string) (bool, *AppError)
+	Get(ctx ReqContext, planId int, userId string) *domain.PlanCollaborator
+	// GetByPlan returns collaborators and invitations of plan with names of users
+	GetByPlan(ctx ReqContext, planId int) []domain.PlanCollaborator
+	// GetInvitations returns not accepted invitations of user with titles of plans, newest first
+	GetInvitations(ctx ReqContext, userId string) []domain.PlanCollaborator
 }
 
 type ChangeLogRepository interface {
 	Add(record *domain.ChangeLogRecord) bool
+	// Award saves record with points and adds points to reputation of user of record in one transaction
+	Award(record *domain.ChangeLogRecord) bool
+	// CountAwards returns number of awards to user for entities of the type since the time
+	CountAwards(userId string, entityType domain.EntityType, since time.Time) int
+	Search(ctx ReqContext, filter domain.ChangeLogFilter, count int, page int) []domain.ChangeLogRecord
+}
+
+type NotificationRepository interface {
+	// Add saves notifications except those disabled by user preferences, returns count of saved
+	Add(ctx ReqContext, notifications []domain.Notification) (int64, *AppError)
+	GetList(ctx ReqContext, userId string, unreadOnly bool, count int, page int) []domain.Notification
+	UnreadCount(ctx ReqContext, userId string) (count int, ok bool)
+	// MarkRead marks notifications of user as read, all of them when ids is empty
+	MarkRead(ctx ReqContext, userId string, ids []int64) (int64, *AppError)
+	// GetPreferences returns saved preferences only, missing types have default preferences
+	GetPreferences(ctx ReqContext, userId string) []domain.NotificationPreference
+	SavePreferences(ctx ReqContext, userId string, preferences []domain.NotificationPreference) (bool, *AppError)
+	// GetForDigest returns unread notifications not sent yet, of types user wants to get by email
+	GetForDigest(count int) []domain.Notification
+	// SaveDigest puts digest email into outbox and marks notifications as emailed in one transaction,
+	// nil email only marks notifications
+	SaveDigest(email *domain.Email, ids []int64) (bool, *AppError)
 }
 
 type ProjectsRepository interface {
 	Add(ctx ReqContext, project *domain.Project) (bool, error)
+	// Update saves project if its version is the same as project.Version and increments version.
+	// Returns Conflict error if project was changed since it was read
 	Update(ctx ReqContext, project *domain.Project) (bool, error)
 	Get(ctx ReqContext, id int) *domain.Project
 }
 
 type PointsRepository interface {
-	Add(ctx ReqContext, entityType domain.EntityType, entityId int64, userId string, value int) bool
+	// Add saves vote of user, previous vote of the user is replaced and its value is returned, zero if user not voted
+	Add(ctx ReqContext, vote *domain.VoteRecord) (previous int, ok bool)
+	// Remove deletes vote of user and returns its value, returns false if user not voted
+	Remove(ctx ReqContext, entityType domain.EntityType, entityId int64, userId string) (previous int, ok bool)
+	// CountRecent returns number of votes made since the time by user and from ip for all entity types
+	CountRecent(ctx ReqContext, userId string, ip string, since time.Time) (byUser int, byIP int)
+	// GetVotes returns not flagged votes for all entities of the type
+	GetVotes(entityType domain.EntityType) ([]domain.EntityVotes, *AppError)
+	// GetRecent returns not flagged votes for all entity types made since the time
+	GetRecent(since time.Time) ([]domain.VoteRecord, *AppError)
+	// Flag excludes votes from aggregates
+	Flag(votes []domain.VoteRecord) *AppError
 	Get(ctx ReqContext, userid string, entityType domain.EntityType, entityId int64) *domain.Points
 	GetList(ctx ReqContext, userid string, entityType domain.EntityType, entityId []int64) []domain.Points
 }
 
+// Ranker computes score of entity from its votes, entities are sorted by score descending
+type Ranker interface {
+	Sort() domain.PlanSort
+	Score(votes []domain.Vote, now time.Time) float64
+}
+
+// VoteGuard protects aggregates from abuse of votes
+type VoteGuard interface {
+	// Check returns TooManyRequests error if user or ip votes too often,
+	// otherwise sets weight of vote and flags votes which must be ignored
+	Check(ctx ReqContext, vote *domain.VoteRecord) *AppError
+}
+
+// PlanDiffer compares versions of plan
+type PlanDiffer interface {
+	// Diff returns serialized difference of plans in format of change log records
+	Diff(before *domain.Plan, after *domain.Plan) (string, error)
+}
+
+// Reputation awards points to authors of contributions
+type Reputation interface {
+	// VoteChanged is called after user of request context voted, changed or retracted vote.
+	// before and after are values of vote, 0 if there is no vote
+	VoteChanged(ctx ReqContext, entityType domain.EntityType, entityId int64, before int, after int)
+	SourceAdded(ctx ReqContext, sourceId int64)
+}
+
+// ChangeLog records changes made by the user of request context
 type ChangeLog interface {
-	Added(entityType domain.EntityType, entityId int64, userId string)
-	Edited(entityType domain.EntityType, entityId int64, userId string, before interface{}, after interface{})
-	Deleted(entityType domain.EntityType, entityId int64, userId string)
+	Added(ctx ReqContext, entityType domain.EntityType, entityId int64)
+	Edited(ctx ReqContext, entityType domain.EntityType, entityId int64, before interface{}, after interface{})
+	// Deleted saves snapshot of the entity before deletion
+	Deleted(ctx ReqContext, entityType domain.EntityType, entityId int64, before interface{})
+	Restored(ctx ReqContext, entityType domain.EntityType, entityId int64)
+}
+
+// Notifier creates notifications for users interested in the event, errors are logged only
+type Notifier interface {
+	CommentAdded(ctx ReqContext, comment *domain.Comment)
+	VoteAdded(ctx ReqContext, entityType domain.EntityType, entityId int64, value int)
+	PlanChanged(ctx ReqContext, planId int)
+}
+
+// Broadcaster publishes changes interesting to clients watching the entity in real time
+type Broadcaster interface {
+	CommentAdded(ctx ReqContext, comment *domain.Comment)
+	CommentEdited(ctx ReqContext, comment *domain.Comment)
+	CommentDeleted(ctx ReqContext, comment *domain.Comment)
+	ReactionsChanged(ctx ReqContext, comment *domain.Comment)
+	PointsChanged(ctx ReqContext, entityType domain.EntityType, entityId int64)
+}
+
+// EventBus delivers events to subscribers of this instance, or of all instances when backend is used
+type EventBus interface {
+	Publish(event *domain.Event)
+	// Subscribe returns channel of events and function to cancel subscription
+	Subscribe(channels []string) (<-chan domain.Event, func())
+}
+
+// EventBackend transfers events between instances of application
+type EventBackend interface {
+	Publish(payload []byte) error
+	// Listen calls handler for every payload published by any instance, including current one
+	Listen(handler func(payload []byte))
 }
 
 type HashProvider interface {
@@ -114,15 +317,53 @@ type HashProvider interface {
 	CheckPassword(pass string, hash []byte, salt []byte) bool
 }
 
-type EmailSender interface {
-	Send(recipient string, subject string, body string) (bool, error)
-	Registration(recipient, userId, secret string) (bool, error)
+// EmailOutbox keeps emails until they are sent by background sender
+type EmailOutbox interface {
+	Add(ctx ReqContext, email *domain.Email) (bool, *AppError)
+	// Take returns pending emails due to send and postpones them for the lease duration,
+	// so other senders will not take the same emails
+	Take(count int, lease time.Duration) []domain.Email
+	MarkSent(id int64) bool
+	// MarkFailed schedules next attempt or marks email as dead
+	MarkFailed(id int64, lastError string, nextAttempt time.Time, dead bool) bool
+}
+
+// MarkdownRenderer converts markdown to sanitized html, mentions of existing users become links to them
+type MarkdownRenderer interface {
+	Render(ctx ReqContext, source string) string
+	// RenderInline is for one line texts like step titles, single paragraph is not wrapped into <p>
+	RenderInline(ctx ReqContext, source string) string
+}
+
+// WebhookRepository keeps webhooks and log of their deliveries
+type WebhookRepository interface {
+	Add(ctx ReqContext, webhook *domain.Webhook) (bool, *AppError)
+	Update(ctx ReqContext, webhook *domain.Webhook) (bool, *AppError)
+	Get(ctx ReqContext, id int64) *domain.Webhook
+	GetByUser(ctx ReqContext, userId string) []domain.Webhook
+	Delete(ctx ReqContext, id int64) (bool, *AppError)
+	// Enqueue adds delivery of event made by user to every active webhook subscribed to it
+	Enqueue(ctx ReqContext, event domain.ChangeType, userId string, payload string) (int64, *AppError)
+	GetDeliveries(ctx ReqContext, webhookId int64, count int, page int) []domain.WebhookDelivery
+	GetDelivery(ctx ReqContext, id int64) *domain.WebhookDelivery
+	// Redeliver adds copy of delivery which is sent as new one
+	Redeliver(ctx ReqContext, id int64) (*domain.WebhookDelivery, *AppError)
+	// Take returns pending deliveries due to send and postpones them for the lease duration
+	Take(count int, lease time.Duration) []domain.WebhookDelivery
+	MarkDelivered(id int64, responseCode int) bool
+	// MarkFailed schedules next attempt or marks delivery as dead
+	MarkFailed(id int64, responseCode int, lastError string, nextAttempt time.Time, dead bool) bool
+}
+
+// WebhookPublisher sends changes to webhooks subscribed to them
+type WebhookPublisher interface {
+	Publish(ctx ReqContext, record *domain.ChangeLogRecord)
 }
 
 type TokenService interface {
 	Create(ctx ReqContext, user *domain.User, fingerprint, useragent string) (auth string, refresh string, err error)
 	Refresh(ctx ReqContext, authToken, refreshToken, fingerprint, useragent string) (aToken string, rToken string, err error)
-	Validate(authToken string) (userID string, userName string, rights int, err error)
+	Validate(authToken string) (userID string, userName string, sessionId string, rights int, err error)
 }
 
 type ReqContext interface {
@@ -133,10 +374,27 @@ type ReqContext interface {
 	ReqId() string
 	UserId() string
 	UserName() string
+	ClientIP() string
+	UserAgent() string
+	// Language is preferred language of client, e.g. "en"
+	Language() string
+	HasPermission(permission domain.Permission) bool
 	StartTrace(name string, args ...interface{}) *nptrace.Trace
 	StopTrace(t *nptrace.Trace)
 }
 
+type AccessPolicy interface {
+	// Permissions returns union of permissions of all user roles,
+	// some of them are denied until user confirms email
+	Permissions(ctx ReqContext, userId string) domain.PermissionSet
+	// IsActive reports whether user is not banned and session was not closed
+	IsActive(ctx ReqContext, userId string, sessionId string) bool
+	// ResetUser should be called after user roles, ban, sessions or email confirmation was changed
+	ResetUser(userId string)
+	// ResetRoles should be called after any role was changed
+	ResetRoles()
+}
+
 type ImageManager interface {
 	SaveResourceCover(data []byte, name string) error
 	GetResourceCoverUrl(name string) string
@@ -0,0 +1,25 @@
+package core
+
+import "github.com/NeekUP/roadmaps/domain"
+
+// IsAllowed reports whether the user from ctx may perform an action guarded by permission
+// on the entity owned by ownerId. Owners get only permissions marked as owner permissions.
+func IsAllowed(ctx ReqContext, permission domain.Permission, ownerId string) bool {
+	if ctx.HasPermission(permission) {
+		return true
+	}
+
+	userId := ctx.UserId()
+	return userId != "" && userId == ownerId && permission.IsOwnerPermission()
+}
+
+// IsAllowedOnPlan is IsAllowed for plans, editors of plan get the same permissions as its owner.
+// collaborator is the current user's record for the plan or nil
+func IsAllowedOnPlan(ctx ReqContext, permission domain.Permission, plan *domain.Plan, collaborator *domain.PlanCollaborator) bool {
+	if IsAllowed(ctx, permission, plan.OwnerId) {
+		return true
+	}
+
+	userId := ctx.UserId()
+	return userId != "" && collaborator.CanEdit() && collaborator.UserId == userId && permission.IsOwnerPermission()
+}
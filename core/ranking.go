@@ -0,0 +1,134 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// votes are integers from 1 to 10
+const (
+	minVoteValue = 1
+	maxVoteValue = 10
+)
+
+// positive returns vote value in range [0, 1]
+func positive(v domain.Vote) float64 {
+	return float64(v.Value-minVoteValue) / (maxVoteValue - minVoteValue)
+}
+
+// signed returns vote value in range [-1, 1], votes below middle of range pull score down
+func signed(v domain.Vote) float64 {
+	return positive(v)*2 - 1
+}
+
+// NewBayesianRanker ranks by average of votes mixed with minVotes virtual votes of prior value,
+// so a few high votes do not outrank many good ones
+func NewBayesianRanker(prior float64, minVotes int) Ranker {
+	return &bayesianRanker{prior: prior, minVotes: minVotes}
+}
+
+type bayesianRanker struct {
+	prior    float64
+	minVotes int
+}
+
+func (r *bayesianRanker) Sort() domain.PlanSort {
+	return domain.PlanSortRating
+}
+
+func (r *bayesianRanker) Score(votes []domain.Vote, now time.Time) float64 {
+	sum := r.prior * float64(r.minVotes)
+	count := float64(r.minVotes)
+	for _, v := range votes {
+		sum += float64(v.Value) * v.Weight
+		count += v.Weight
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / count
+}
+
+// NewWilsonRanker ranks by lower bound of Wilson score interval of positive share of votes,
+// z is quantile of confidence level, 1.96 for 95%
+func NewWilsonRanker(z float64) Ranker {
+	return &wilsonRanker{z: z}
+}
+
+type wilsonRanker struct {
+	z float64
+}
+
+func (r *wilsonRanker) Sort() domain.PlanSort {
+	return domain.PlanSortWilson
+}
+
+func (r *wilsonRanker) Score(votes []domain.Vote, now time.Time) float64 {
+	n, p := 0.0, 0.0
+	for _, v := range votes {
+		n += v.Weight
+		p += positive(v) * v.Weight
+	}
+	if n == 0 {
+		return 0
+	}
+	p /= n
+
+	z2 := r.z * r.z
+	return (p + z2/(2*n) - r.z*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// NewHotRanker ranks by sum of votes, weight of vote halves every halfLife
+func NewHotRanker(halfLife time.Duration) Ranker {
+	return &hotRanker{halfLife: halfLife}
+}
+
+type hotRanker struct {
+	halfLife time.Duration
+}
+
+func (r *hotRanker) Sort() domain.PlanSort {
+	return domain.PlanSortHot
+}
+
+func (r *hotRanker) Score(votes []domain.Vote, now time.Time) float64 {
+	if r.halfLife <= 0 {
+		return 0
+	}
+
+	score := 0.0
+	for _, v := range votes {
+		age := now.Sub(v.Date)
+		if age < 0 {
+			age = 0
+		}
+		score += signed(v) * v.Weight * math.Pow(0.5, float64(age)/float64(r.halfLife))
+	}
+	return score
+}
+
+// NewTrendingRanker ranks by sum of votes made in last days
+func NewTrendingRanker(days int) Ranker {
+	return &trendingRanker{days: days}
+}
+
+type trendingRanker struct {
+	days int
+}
+
+func (r *trendingRanker) Sort() domain.PlanSort {
+	return domain.PlanSortTrending
+}
+
+func (r *trendingRanker) Score(votes []domain.Vote, now time.Time) float64 {
+	since := now.AddDate(0, 0, -r.days)
+	score := 0.0
+	for _, v := range votes {
+		if v.Date.After(since) {
+			score += signed(v) * v.Weight
+		}
+	}
+	return score
+}
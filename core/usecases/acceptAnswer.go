@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// AcceptAnswer marks reply as accepted answer to question of thread, zero answerId removes the mark
+type AcceptAnswer interface {
+	Do(ctx core.ReqContext, threadId int64, answerId int64) (bool, error)
+}
+
+type acceptAnswer struct {
+	commentsRepo core.CommentsRepository
+	planRepo     core.PlanRepository
+	changeLog    core.ChangeLog
+	broadcaster  core.Broadcaster
+	log          core.AppLogger
+}
+
+func NewAcceptAnswer(commentsRepo core.CommentsRepository, planRepo core.PlanRepository, changeLog core.ChangeLog, broadcaster core.Broadcaster, log core.AppLogger) AcceptAnswer {
+	return &acceptAnswer{commentsRepo: commentsRepo, planRepo: planRepo, changeLog: changeLog, broadcaster: broadcaster, log: log}
+}
+
+func (usecase *acceptAnswer) Do(ctx core.ReqContext, threadId int64, answerId int64) (bool, error) {
+	trace := ctx.StartTrace("acceptAnswer")
+	defer ctx.StopTrace(trace)
+
+	if answerId < 0 {
+		errors := make(map[string]string)
+		errors["answerId"] = core.InvalidValue.String()
+		return false, core.ValidationError(errors)
+	}
+
+	userId := ctx.UserId()
+	thread := usecase.commentsRepo.Get(ctx, threadId)
+	if thread == nil || thread.Deleted || thread.ThreadId > 0 {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+			"error", fmt.Sprintf("thread deleted or not existed. id: %v", threadId),
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if !canManageThread(ctx, usecase.planRepo, thread) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	if answerId > 0 {
+		answer := usecase.commentsRepo.Get(ctx, answerId)
+		if answer == nil || answer.Deleted || answer.Hidden || answer.ThreadId != thread.Id {
+			errors := make(map[string]string)
+			errors["answerId"] = core.InvalidValue.String()
+			return false, core.ValidationError(errors)
+		}
+	}
+
+	if thread.AnswerId == answerId {
+		return true, nil
+	}
+
+	if ok, err := usecase.commentsRepo.SetAnswer(ctx, thread.Id, answerId); !ok {
+		if err != nil {
+			usecase.log.Errorw("invalid request",
+				"reqid", ctx.ReqId(),
+				"error", err.Error(),
+			)
+		}
+		return false, err
+	}
+
+	changedThread := *thread
+	changedThread.AnswerId = answerId
+	usecase.changeLog.Edited(ctx, domain.CommentEntity, thread.Id, thread, &changedThread)
+	usecase.broadcaster.CommentEdited(ctx, &changedThread)
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+)
+
+// AcceptPlanInvitation grants rights of invitation to current user.
+// Invitation is declined by RemovePlanCollaborator
+type AcceptPlanInvitation interface {
+	Do(ctx core.ReqContext, planId int) (bool, error)
+}
+
+type acceptPlanInvitation struct {
+	collaboratorRepo core.PlanCollaboratorRepository
+	log              core.AppLogger
+}
+
+func NewAcceptPlanInvitation(collaboratorRepo core.PlanCollaboratorRepository, log core.AppLogger) AcceptPlanInvitation {
+	return &acceptPlanInvitation{collaboratorRepo: collaboratorRepo, log: log}
+}
+
+func (usecase *acceptPlanInvitation) Do(ctx core.ReqContext, planId int) (bool, error) {
+	trace := ctx.StartTrace("acceptPlanInvitation")
+	defer ctx.StopTrace(trace)
+
+	if ctx.UserId() == "" {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	accepted, err := usecase.collaboratorRepo.Accept(ctx, planId, ctx.UserId())
+	if err != nil {
+		usecase.log.Errorw("invitation not accepted",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+	if !accepted {
+		return false, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+	}
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// AcceptPlanSuggestion applies proposed changes to plan by EditPlan, change is attributed to proposer.
+// Plan edited after suggestion was made is a conflict, owner sees current plan then
+type AcceptPlanSuggestion interface {
+	Do(ctx core.ReqContext, id int64) (bool, error)
+}
+
+type acceptPlanSuggestion struct {
+	suggestionRepo core.PlanSuggestionRepository
+	planRepo       core.PlanRepository
+	editPlan       EditPlan
+	log            core.AppLogger
+}
+
+func NewAcceptPlanSuggestion(suggestionRepo core.PlanSuggestionRepository, planRepo core.PlanRepository, editPlan EditPlan, log core.AppLogger) AcceptPlanSuggestion {
+	return &acceptPlanSuggestion{suggestionRepo: suggestionRepo, planRepo: planRepo, editPlan: editPlan, log: log}
+}
+
+func (usecase *acceptPlanSuggestion) Do(ctx core.ReqContext, id int64) (bool, error) {
+	trace := ctx.StartTrace("acceptPlanSuggestion")
+	defer ctx.StopTrace(trace)
+
+	suggestion, plan, appErr := getSuggestionForReview(ctx, usecase.suggestionRepo, usecase.planRepo, id)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	steps := make([]PlanStep, len(suggestion.Steps))
+	for i, v := range suggestion.Steps {
+		steps[i] = PlanStep{
+			ReferenceId:   v.ReferenceId,
+			ReferenceType: v.ReferenceType,
+			Title:         v.Title,
+			Duration:      v.Duration,
+			Difficulty:    v.Difficulty,
+			Optional:      v.Optional,
+			Notes:         v.Notes,
+		}
+	}
+
+	if _, err := usecase.editPlan.Do(ctx, EditPlanReq{
+		Id:        plan.Id,
+		TopicName: plan.TopicName,
+		Title:     suggestion.Title,
+		IsDraft:   plan.IsDraft,
+		Steps:     steps,
+		Version:   suggestion.PlanVersion,
+		AuthorId:  suggestion.UserId,
+	}); err != nil {
+		return false, err
+	}
+
+	suggestion.Status = domain.SuggestionAccepted
+	suggestion.ReviewedBy = ctx.UserId()
+	reviewed, appErr := usecase.suggestionRepo.Review(ctx, suggestion)
+	if appErr != nil {
+		return false, appErr
+	}
+	return reviewed, nil
+}
+
+// getSuggestionForReview returns pending suggestion and its plan if current user may edit the plan
+func getSuggestionForReview(ctx core.ReqContext, suggestionRepo core.PlanSuggestionRepository, planRepo core.PlanRepository, id int64) (*domain.PlanSuggestion, *domain.Plan, *core.AppError) {
+	suggestion := suggestionRepo.Get(ctx, id)
+	if suggestion == nil {
+		return nil, nil, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+	}
+	if suggestion.Status != domain.SuggestionPending {
+		return nil, nil, core.ValidationError(map[string]string{"id": core.AlreadyExists.String()})
+	}
+
+	plan := planRepo.GetWithDraft(ctx, suggestion.PlanId, ctx.UserId())
+	if plan == nil {
+		return nil, nil, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+	}
+	if !core.IsAllowed(ctx, domain.PlanEdit, plan.OwnerId) {
+		return nil, nil, core.NewError(core.AccessDenied)
+	}
+	return suggestion, plan, nil
+}
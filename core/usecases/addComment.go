@@ -14,17 +14,31 @@ type AddComment interface {
 type addComment struct {
 	commentsRepo core.CommentsRepository
 	planRepo     core.PlanRepository
+	topicRepo    core.TopicRepository
+	sourceRepo   core.SourceRepository
 	log          core.AppLogger
 	changeLog    core.ChangeLog
+	notifier     core.Notifier
+	broadcaster  core.Broadcaster
+	markdown     core.MarkdownRenderer
 }
 
-func NewAddComment(commentsRepo core.CommentsRepository, planRepo core.PlanRepository, changeLog core.ChangeLog, log core.AppLogger) AddComment {
-	return &addComment{commentsRepo: commentsRepo, planRepo: planRepo, changeLog: changeLog, log: log}
+func NewAddComment(commentsRepo core.CommentsRepository, planRepo core.PlanRepository, topicRepo core.TopicRepository, sourceRepo core.SourceRepository, changeLog core.ChangeLog, notifier core.Notifier, broadcaster core.Broadcaster, markdown core.MarkdownRenderer, log core.AppLogger) AddComment {
+	return &addComment{commentsRepo: commentsRepo, planRepo: planRepo, topicRepo: topicRepo, sourceRepo: sourceRepo, changeLog: changeLog, notifier: notifier, broadcaster: broadcaster, markdown: markdown, log: log}
 }
 
 func (usecase *addComment) Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, parentId int64, text string, title string) (*domain.Comment, error) {
 	trace := ctx.StartTrace("addComment")
 	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.CommentAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
 	appErr := usecase.validate(ctx, entityType, entityId, parentId, text, title)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
@@ -56,6 +70,7 @@ func (usecase *addComment) Do(ctx core.ReqContext, entityType domain.EntityType,
 		ParentId:   parentId,
 		UserId:     userId,
 		Text:       text,
+		Html:       usecase.markdown.Render(ctx, text),
 		Title:      title,
 		Date:       time.Now().UTC(),
 		Deleted:    false,
@@ -71,7 +86,9 @@ func (usecase *addComment) Do(ctx core.ReqContext, entityType domain.EntityType,
 		return nil, err
 	}
 
-	usecase.changeLog.Added(domain.CommentEntity, comment.Id, userId)
+	usecase.changeLog.Added(ctx, domain.CommentEntity, comment.Id)
+	usecase.notifier.CommentAdded(ctx, comment)
+	usecase.broadcaster.CommentAdded(ctx, comment)
 	return comment, nil
 }
 
@@ -85,12 +102,7 @@ func (ac *addComment) validate(ctx core.ReqContext, entityType domain.EntityType
 		errors["entityId"] = core.InvalidValue.String()
 	}
 
-	switch entityType {
-	case domain.PlanEntity:
-		if ac.planRepo.Get(ctx, int(entityId)) == nil {
-			errors["entityId"] = core.InvalidValue.String()
-		}
-	default:
+	if !ac.entityExists(ctx, entityType, entityId) {
 		errors["entityId"] = core.InvalidValue.String()
 	}
 
@@ -115,3 +127,17 @@ func (ac *addComment) validate(ctx core.ReqContext, entityType domain.EntityType
 	}
 	return nil
 }
+
+func (ac *addComment) entityExists(ctx core.ReqContext, entityType domain.EntityType, entityId int64) bool {
+	switch entityType {
+	case domain.PlanEntity:
+		return ac.planRepo.Get(ctx, int(entityId)) != nil
+	case domain.TopicEntity:
+		return ac.topicRepo.GetById(ctx, int(entityId)) != nil
+	case domain.ResourceEntity:
+		return ac.sourceRepo.Get(ctx, entityId) != nil
+	default:
+		// projects are not stored yet
+		return false
+	}
+}
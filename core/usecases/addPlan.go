@@ -16,6 +16,7 @@ type addPlan struct {
 	projectsRepo core.ProjectsRepository
 	log          core.AppLogger
 	changeLog    core.ChangeLog
+	markdown     core.MarkdownRenderer
 }
 
 type AddPlanReq struct {
@@ -29,20 +30,35 @@ type PlanStep struct {
 	ReferenceId   int64
 	ReferenceType domain.ReferenceType
 	Title         string
+	// estimated time in minutes, 0 to take duration of source when it is known
+	Duration   int
+	Difficulty domain.Difficulty
+	Optional   bool
+	Notes      string
 }
 
-func NewAddPlan(planRepo core.PlanRepository, sourceRepo core.SourceRepository, topicRepo core.TopicRepository, projectsRepo core.ProjectsRepository, changeLog core.ChangeLog, log core.AppLogger) AddPlan {
+func NewAddPlan(planRepo core.PlanRepository, sourceRepo core.SourceRepository, topicRepo core.TopicRepository, projectsRepo core.ProjectsRepository, changeLog core.ChangeLog, markdown core.MarkdownRenderer, log core.AppLogger) AddPlan {
 	return &addPlan{planRepo: planRepo,
 		sourceRepo:   sourceRepo,
 		topicRepo:    topicRepo,
 		projectsRepo: projectsRepo,
 		changeLog:    changeLog,
+		markdown:     markdown,
 		log:          log}
 }
 
 func (usecase *addPlan) Do(ctx core.ReqContext, req AddPlanReq) (*domain.Plan, error) {
 	trace := ctx.StartTrace("addPlan")
 	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.PlanAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
 	appErr := usecase.validate(ctx, req)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
@@ -53,18 +69,7 @@ func (usecase *addPlan) Do(ctx core.ReqContext, req AddPlanReq) (*domain.Plan, e
 	}
 
 	userId := ctx.UserId()
-	stepsCount := len(req.Steps)
-	steps := make([]domain.Step, 0, stepsCount)
-
-	for i, v := range req.Steps {
-		step := domain.Step{
-			ReferenceId:   v.ReferenceId,
-			ReferenceType: v.ReferenceType,
-			Position:      i,
-			Title:         v.Title,
-		}
-		steps = append(steps, step)
-	}
+	steps := newPlanSteps(ctx, req.Steps, usecase.sourceRepo, usecase.markdown)
 
 	plan := &domain.Plan{
 		TopicName: req.TopicName,
@@ -84,7 +89,7 @@ func (usecase *addPlan) Do(ctx core.ReqContext, req AddPlanReq) (*domain.Plan, e
 		return nil, err
 	}
 
-	usecase.changeLog.Added(domain.PlanEntity, int64(plan.Id), userId)
+	usecase.changeLog.Added(ctx, domain.PlanEntity, int64(plan.Id))
 	return plan, nil
 }
 
@@ -112,6 +117,7 @@ func (usecase *addPlan) validate(ctx core.ReqContext, req AddPlanReq) *core.AppE
 		if !core.IsValidStepTitle(v.Title) {
 			errors["source.title"] = core.InvalidValue.String()
 		}
+		validateStepDetails(v, errors)
 		switch v.ReferenceType {
 		case domain.ResourceReference:
 			if usecase.sourceRepo.Get(ctx, v.ReferenceId) == nil {
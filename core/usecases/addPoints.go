@@ -10,18 +10,31 @@ type AddVote interface {
 }
 
 type addVote struct {
-	pointsRepo core.PointsRepository
-	log        core.AppLogger
+	pointsRepo  core.PointsRepository
+	notifier    core.Notifier
+	broadcaster core.Broadcaster
+	reputation  core.Reputation
+	guard       core.VoteGuard
+	log         core.AppLogger
 }
 
-func NewAddPoints(pointsRepo core.PointsRepository, log core.AppLogger) AddVote {
-	return &addVote{pointsRepo: pointsRepo, log: log}
+func NewAddPoints(pointsRepo core.PointsRepository, notifier core.Notifier, broadcaster core.Broadcaster, reputation core.Reputation, guard core.VoteGuard, log core.AppLogger) AddVote {
+	return &addVote{pointsRepo: pointsRepo, notifier: notifier, broadcaster: broadcaster, reputation: reputation, guard: guard, log: log}
 }
 
 func (usecase addVote) Do(ctx core.ReqContext, entityType domain.EntityType, id int64, value int) (bool, error) {
 	trace := ctx.StartTrace("addVote")
 	defer ctx.StopTrace(trace)
-	appErr := usecase.validate(entityType, id, value)
+
+	if !ctx.HasPermission(domain.PointsAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validateVote(entityType, id, value)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -30,14 +43,26 @@ func (usecase addVote) Do(ctx core.ReqContext, entityType domain.EntityType, id
 		return false, appErr
 	}
 
-	result := usecase.pointsRepo.Add(ctx, entityType, id, ctx.UserId(), value)
+	vote := &domain.VoteRecord{EntityType: entityType, EntityId: id, UserId: ctx.UserId(), IP: ctx.ClientIP(), Value: value}
+	if appErr := usecase.guard.Check(ctx, vote); appErr != nil {
+		return false, appErr
+	}
+
+	before, result := usecase.pointsRepo.Add(ctx, vote)
+	if result {
+		usecase.notifier.VoteAdded(ctx, entityType, id, value)
+		usecase.broadcaster.PointsChanged(ctx, entityType, id)
+		if !vote.Flagged {
+			usecase.reputation.VoteChanged(ctx, entityType, id, before, value)
+		}
+	}
 	return result, nil
 }
 
-func (usecase addVote) validate(entityType domain.EntityType, id int64, value int) *core.AppError {
+func validateVote(entityType domain.EntityType, id int64, value int) *core.AppError {
 	errors := make(map[string]string)
 
-	if !core.IsValidEntityType(entityType) {
+	if !entityType.IsVotable() {
 		errors["type"] = core.InvalidValue.String()
 	}
 
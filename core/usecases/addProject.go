@@ -48,7 +48,7 @@ func (usecase *addProject) Do(ctx core.ReqContext, title, text string, tags []st
 		return nil, err
 	}
 
-	usecase.changeLog.Added(domain.ProjectEntity, int64(project.Id), ctx.UserId())
+	usecase.changeLog.Added(ctx, domain.ProjectEntity, int64(project.Id))
 	return project, nil
 }
 
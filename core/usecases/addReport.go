@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// AddReport adds complaint about content to moderation queue.
+// Complaints about the same entity are aggregated into one report.
+type AddReport interface {
+	Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, reason domain.ReportReason, text string) (bool, error)
+}
+
+type addReport struct {
+	reportRepo   core.ReportRepository
+	planRepo     core.PlanRepository
+	topicRepo    core.TopicRepository
+	sourceRepo   core.SourceRepository
+	commentsRepo core.CommentsRepository
+	log          core.AppLogger
+}
+
+func NewAddReport(reportRepo core.ReportRepository, planRepo core.PlanRepository, topicRepo core.TopicRepository, sourceRepo core.SourceRepository, commentsRepo core.CommentsRepository, log core.AppLogger) AddReport {
+	return &addReport{
+		reportRepo:   reportRepo,
+		planRepo:     planRepo,
+		topicRepo:    topicRepo,
+		sourceRepo:   sourceRepo,
+		commentsRepo: commentsRepo,
+		log:          log,
+	}
+}
+
+func (usecase *addReport) Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, reason domain.ReportReason, text string) (bool, error) {
+	trace := ctx.StartTrace("addReport")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ReportAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	appErr := usecase.validate(ctx, entityType, entityId, reason, text)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	complaint := &domain.Complaint{
+		UserId: ctx.UserId(),
+		Reason: reason,
+		Text:   text,
+	}
+	added, err := usecase.reportRepo.Add(ctx, entityType, entityId, complaint)
+	if err != nil {
+		usecase.log.Errorw("Report not saved",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+	return added, nil
+}
+
+func (usecase *addReport) validate(ctx core.ReqContext, entityType domain.EntityType, entityId int64, reason domain.ReportReason, text string) *core.AppError {
+	errors := make(map[string]string)
+
+	if !reason.IsValid() {
+		errors["reason"] = core.InvalidValue.String()
+	}
+
+	if !core.IsValidReportText(text) {
+		errors["text"] = core.InvalidFormat.String()
+	}
+
+	if entityId <= 0 {
+		errors["id"] = core.InvalidValue.String()
+	} else {
+		exists := false
+		switch entityType {
+		case domain.PlanEntity:
+			exists = usecase.planRepo.Get(ctx, int(entityId)) != nil
+		case domain.TopicEntity:
+			exists = usecase.topicRepo.GetById(ctx, int(entityId)) != nil
+		case domain.ResourceEntity:
+			exists = usecase.sourceRepo.Get(ctx, entityId) != nil
+		case domain.ProjectEntity:
+			// projects are not stored yet, so there is nothing to report
+			exists = false
+		case domain.CommentEntity:
+			comment := usecase.commentsRepo.Get(ctx, entityId)
+			exists = comment != nil && !comment.Deleted
+		default:
+			errors["type"] = core.InvalidValue.String()
+		}
+
+		if !exists && errors["type"] == "" {
+			errors["id"] = core.NotExists.String()
+		}
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"image"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/idna"
@@ -25,6 +27,8 @@ import (
 	"github.com/moraes/isbn"
 )
 
+var isoDurationRegexp = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
 const (
 	googleApiUrl      = "https://www.googleapis.com/books/v1/volumes?q=%s+isbn&fields=items/volumeInfo(title,subtitle,authors,description,industryIdentifiers,imageLinks)"
 	openLibraryApiUrl = "http://openlibrary.org/api/books?bibkeys=ISBN:%s&format=json&jscmd=data"
@@ -34,8 +38,8 @@ type AddSource interface {
 	Do(ctx core.ReqContext, identifier string, props map[string]string, sourceType domain.SourceType) (*domain.Source, error)
 }
 
-func NewAddSource(sr core.SourceRepository, log core.AppLogger, imgSaver core.ImageManager, changelog core.ChangeLog) AddSource {
-	return &addSource{sourceRepo: sr, log: log, imageManager: imgSaver, changeLog: changelog}
+func NewAddSource(sr core.SourceRepository, log core.AppLogger, imgSaver core.ImageManager, changelog core.ChangeLog, reputation core.Reputation) AddSource {
+	return &addSource{sourceRepo: sr, log: log, imageManager: imgSaver, changeLog: changelog, reputation: reputation}
 }
 
 type addSource struct {
@@ -43,12 +47,15 @@ type addSource struct {
 	log          core.AppLogger
 	imageManager core.ImageManager
 	changeLog    core.ChangeLog
+	reputation   core.Reputation
 }
 
 type webPageSummary struct {
 	Title string
 	Img   image.Image
 	Desc  string
+	// duration of video or audio in seconds, 0 if page does not contain it
+	Duration int
 }
 
 type bookSummary struct {
@@ -65,8 +72,15 @@ func (usecase *addSource) Do(ctx core.ReqContext, identifier string, props map[s
 	trace := ctx.StartTrace("addSource")
 	defer ctx.StopTrace(trace)
 
+	if !ctx.HasPermission(domain.SourceAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
 	appErr := usecase.validate(identifier, props, sourceType)
-	userId := ctx.UserId()
 	if props == nil {
 		props = map[string]string{}
 	}
@@ -135,6 +149,9 @@ func (usecase *addSource) Do(ctx core.ReqContext, identifier string, props map[s
 		s.Title = pageMeta.Title
 		s.Desc = pageMeta.Desc
 		img = pageMeta.Img
+		if pageMeta.Duration > 0 && sourceType != domain.Article {
+			props[domain.SourceDurationProperty] = strconv.Itoa((pageMeta.Duration + 59) / 60)
+		}
 	default:
 		return nil, core.ValidationError(map[string]string{"type": core.InvalidSourceType.String()})
 	}
@@ -158,9 +175,16 @@ func (usecase *addSource) Do(ctx core.ReqContext, identifier string, props map[s
 	}
 
 	s.Properties = string(p)
-	s = usecase.sourceRepo.GetOrAddByIdentifier(ctx, s)
+	s, created := usecase.sourceRepo.GetOrAddByIdentifier(ctx, s)
+	if s == nil {
+		return nil, core.NewError(core.InternalError)
+	}
 
-	usecase.changeLog.Added(domain.ResourceEntity, s.Id, userId)
+	// source could be added by concurrent request after it was searched
+	if created {
+		usecase.changeLog.Added(ctx, domain.ResourceEntity, s.Id)
+		usecase.reputation.SourceAdded(ctx, s.Id)
+	}
 	return s, nil
 }
 
@@ -220,21 +244,50 @@ func (usecase *addSource) getWebPageMeta(uri string) (*webPageSummary, error) {
 	}
 
 	summary := usecase.getTwitterMeta(doc)
-	if summary != nil {
-		return summary, nil
+	if summary == nil {
+		summary = usecase.getOpenGraphMeta(doc)
 	}
-
-	summary = usecase.getOpenGraphMeta(doc)
-	if summary != nil {
-		return summary, nil
+	if summary == nil {
+		summary = usecase.getRawHtmlMeta(doc)
+	}
+	if summary == nil {
+		return nil, core.NewError(core.InaccessibleWebPage)
 	}
 
-	summary = usecase.getRawHtmlMeta(doc)
-	if summary != nil {
-		return summary, nil
+	summary.Duration = usecase.getMediaDuration(doc)
+	return summary, nil
+}
+
+// getMediaDuration finds duration of video or audio in open graph or schema.org meta of page
+func (usecase *addSource) getMediaDuration(doc *goquery.Document) int {
+	selections := doc.Find("meta[property=og\\:video\\:duration], meta[property=video\\:duration], meta[property=music\\:duration], meta[itemprop=duration]")
+	duration := 0
+	selections.EachWithBreak(func(i int, s *goquery.Selection) bool {
+		duration = parseMediaDuration(s.AttrOr("content", ""))
+		return duration == 0
+	})
+	return duration
+}
+
+// parseMediaDuration parses duration in seconds or in ISO 8601 format like PT1H2M30S
+func parseMediaDuration(content string) int {
+	content = strings.TrimSpace(content)
+	if seconds, err := strconv.Atoi(content); err == nil && seconds > 0 {
+		return seconds
 	}
 
-	return nil, core.NewError(core.InaccessibleWebPage)
+	m := isoDurationRegexp.FindStringSubmatch(strings.ToUpper(content))
+	if m == nil {
+		return 0
+	}
+	seconds := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if m[i+1] != "" {
+			v, _ := strconv.Atoi(m[i+1])
+			seconds += v * unit
+		}
+	}
+	return seconds
 }
 
 func (usecase *addSource) getBookMeta(isbn13 string) (*bookSummary, error) {
@@ -13,17 +13,26 @@ type addTopic struct {
 	topicRepo core.TopicRepository
 	log       core.AppLogger
 	changeLog core.ChangeLog
+	markdown  core.MarkdownRenderer
 }
 
-func NewAddTopic(topicRepo core.TopicRepository, changelog core.ChangeLog, log core.AppLogger) AddTopic {
-	return &addTopic{topicRepo: topicRepo, changeLog: changelog, log: log}
+func NewAddTopic(topicRepo core.TopicRepository, changelog core.ChangeLog, markdown core.MarkdownRenderer, log core.AppLogger) AddTopic {
+	return &addTopic{topicRepo: topicRepo, changeLog: changelog, markdown: markdown, log: log}
 }
 
 func (usecase *addTopic) Do(ctx core.ReqContext, title, desc string, istag bool, tags []string) (*domain.Topic, error) {
 	trace := ctx.StartTrace("addTopic")
 	defer ctx.StopTrace(trace)
 
-	appErr := usecase.validate(title, tags)
+	if !ctx.HasPermission(domain.TopicAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	appErr := usecase.validate(title, desc, tags)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -34,6 +43,7 @@ func (usecase *addTopic) Do(ctx core.ReqContext, title, desc string, istag bool,
 
 	userId := ctx.UserId()
 	topic := domain.NewTopic(title, desc, userId)
+	topic.DescriptionHtml = usecase.markdown.Render(ctx, desc)
 	topic.IsTag = istag
 	topic.Tags = usecase.topicRepo.GetTags(ctx, tags)
 	saved, err := usecase.topicRepo.Save(ctx, topic)
@@ -51,19 +61,23 @@ func (usecase *addTopic) Do(ctx core.ReqContext, title, desc string, istag bool,
 				usecase.topicRepo.AddTag(ctx, tag.Name, topic.Name)
 			}
 		}
-		usecase.changeLog.Added(domain.TopicEntity, int64(topic.Id), userId)
+		usecase.changeLog.Added(ctx, domain.TopicEntity, int64(topic.Id))
 		return topic, nil
 	}
 
 	return nil, nil
 }
 
-func (usecase *addTopic) validate(title string, tags []string) *core.AppError {
+func (usecase *addTopic) validate(title, desc string, tags []string) *core.AppError {
 	errors := make(map[string]string)
 	if !core.IsValidTopicTitle(title) {
 		errors["title"] = core.InvalidFormat.String()
 	}
 
+	if !core.IsValidDscription(desc) {
+		errors["desc"] = core.InvalidFormat.String()
+	}
+
 	for _, tag := range tags {
 		if !core.IsValidTopicName(tag) {
 			errors["tags"] = core.InvalidFormat.String()
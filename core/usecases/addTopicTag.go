@@ -33,6 +33,14 @@ func (usecase *addTopicTag) Do(ctx core.ReqContext, tagname, topicname string) (
 	}
 
 	userId := ctx.UserId()
+	if !ctx.HasPermission(domain.TagManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
 	topic := usecase.topicRepo.Get(ctx, topicname)
 	if topic == nil {
 		return false, core.NewError(core.NotExists)
@@ -43,7 +51,7 @@ func (usecase *addTopicTag) Do(ctx core.ReqContext, tagname, topicname string) (
 		changedTopic := *topic
 		copy(changedTopic.Tags, topic.Tags)
 		changedTopic.Tags = append(changedTopic.Tags, domain.TopicTag{Name: topicname})
-		usecase.changeLog.Edited(domain.TopicEntity, int64(topic.Id), userId, topic, &changedTopic)
+		usecase.changeLog.Edited(ctx, domain.TopicEntity, int64(topic.Id), topic, &changedTopic)
 	}
 	return hasChanges, nil
 }
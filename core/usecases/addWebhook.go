@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+const maxWebhooksPerUser = 20
+
+// AddWebhook registers endpoint of current user, secret to check signature of payloads is generated
+type AddWebhook interface {
+	Do(ctx core.ReqContext, url string, events []domain.ChangeType) (*domain.Webhook, error)
+}
+
+type addWebhook struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewAddWebhook(webhookRepo core.WebhookRepository, log core.AppLogger) AddWebhook {
+	return &addWebhook{webhookRepo: webhookRepo, log: log}
+}
+
+func (usecase *addWebhook) Do(ctx core.ReqContext, url string, events []domain.ChangeType) (*domain.Webhook, error) {
+	trace := ctx.StartTrace("addWebhook")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.WebhookOwn) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validateWebhook(url, events)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	if len(usecase.webhookRepo.GetByUser(ctx, ctx.UserId())) >= maxWebhooksPerUser {
+		return nil, core.ValidationError(map[string]string{"url": core.InvalidCount.String()})
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		usecase.log.Errorw("Fail to generate webhook secret",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return nil, core.NewError(core.InternalError)
+	}
+
+	webhook := &domain.Webhook{
+		UserId:     ctx.UserId(),
+		Url:        url,
+		Secret:     secret,
+		Events:     events,
+		Active:     true,
+		OwnChanges: !ctx.HasPermission(domain.WebhookManage),
+		Created:    time.Now(),
+	}
+	if _, appErr := usecase.webhookRepo.Add(ctx, webhook); appErr != nil {
+		return nil, appErr
+	}
+	return webhook, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validateWebhook(url string, events []domain.ChangeType) *core.AppError {
+	errors := make(map[string]string)
+
+	if !core.IsValidWebhookUrl(url) {
+		errors["url"] = core.InvalidUrl.String()
+	}
+
+	if len(events) == 0 || len(events) > 32 {
+		errors["events"] = core.InvalidCount.String()
+	}
+
+	for _, e := range events {
+		if !e.IsWebhookEvent() {
+			errors["events"] = core.InvalidValue.String()
+			break
+		}
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
+
+// getOwnWebhook returns webhook only to its owner with permission to manage webhooks
+func getOwnWebhook(ctx core.ReqContext, webhookRepo core.WebhookRepository, id int64, log core.AppLogger) (*domain.Webhook, *core.AppError) {
+	if !ctx.HasPermission(domain.WebhookOwn) {
+		log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	if id <= 0 {
+		return nil, core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+	}
+
+	webhook := webhookRepo.Get(ctx, id)
+	if webhook == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	if webhook.UserId != ctx.UserId() {
+		log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+			"webhookId", id,
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+	return webhook, nil
+}
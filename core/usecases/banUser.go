@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// BanUser bans user until given time, zero time means permanent ban.
+// All sessions of user are closed.
+type BanUser interface {
+	Do(ctx core.ReqContext, userId string, reason string, until time.Time) (bool, error)
+}
+
+type banUser struct {
+	userRepo  core.UserRepository
+	policy    core.AccessPolicy
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewBanUser(userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger) BanUser {
+	return &banUser{userRepo: userRepo, policy: policy, changeLog: changeLog, log: log}
+}
+
+func (usecase *banUser) Do(ctx core.ReqContext, userId string, reason string, until time.Time) (bool, error) {
+	trace := ctx.StartTrace("banUser")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.UserManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	user := usecase.userRepo.Get(ctx, userId)
+	appErr := usecase.validate(ctx, user, reason, until)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	old := *user
+	user.Banned = true
+	user.BanReason = reason
+	user.BannedUntil = until
+	user.Tokens = []domain.UserToken{}
+
+	return updateUser(ctx, usecase.userRepo, usecase.policy, usecase.changeLog, usecase.log, &old, user)
+}
+
+func (usecase *banUser) validate(ctx core.ReqContext, user *domain.User, reason string, until time.Time) *core.AppError {
+	errors := make(map[string]string)
+
+	if user == nil {
+		errors["userId"] = core.NotExists.String()
+	} else if user.Id == ctx.UserId() {
+		errors["userId"] = core.AccessDenied.String()
+	}
+
+	if !core.IsValidBanReason(reason) {
+		errors["reason"] = core.InvalidFormat.String()
+	}
+
+	if !until.IsZero() && until.Before(time.Now()) {
+		errors["until"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
+
+func updateUser(ctx core.ReqContext, userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger, old *domain.User, user *domain.User) (bool, error) {
+	saved, err := userRepo.Update(ctx, user)
+	if err != nil {
+		log.Errorw("User not updated",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+
+	policy.ResetUser(user.Id)
+	// id of user is not a number, change log takes it from user as entity key
+	changeLog.Edited(ctx, domain.UserEntity, 0, old, user)
+	return saved, nil
+}
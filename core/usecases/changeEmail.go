@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ChangeEmail sends confirmation link to new email of current user,
+// email of user is changed only after the link is opened.
+type ChangeEmail interface {
+	Do(ctx core.ReqContext, email string) (bool, error)
+}
+
+type changeEmail struct {
+	userRepo core.UserRepository
+	lifetime time.Duration
+	interval time.Duration
+	log      core.AppLogger
+}
+
+func NewChangeEmail(userRepo core.UserRepository, lifetime time.Duration, resendInterval time.Duration, log core.AppLogger) ChangeEmail {
+	return &changeEmail{userRepo: userRepo, lifetime: lifetime, interval: resendInterval, log: log}
+}
+
+func (usecase *changeEmail) Do(ctx core.ReqContext, email string) (bool, error) {
+	trace := ctx.StartTrace("changeEmail")
+	defer ctx.StopTrace(trace)
+
+	user := usecase.userRepo.Get(ctx, ctx.UserId())
+	if user == nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "user not exists",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	email = strings.TrimSpace(email)
+	appErr := usecase.validate(ctx, user, email)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	// prevents using the form to send emails to arbitrary addresses
+	if !canSendConfirmation(user, usecase.interval) {
+		usecase.log.Errorw("too many requests",
+			"reqid", ctx.ReqId(),
+			"UserId", user.Id,
+			"sent", user.EmailConfirmationSent,
+		)
+		return false, core.NewError(core.TooManyRequests)
+	}
+
+	user.PendingEmail = email
+	return sendConfirmation(ctx, usecase.userRepo, usecase.log, user, usecase.lifetime)
+}
+
+func (usecase *changeEmail) validate(ctx core.ReqContext, user *domain.User, email string) *core.AppError {
+	errors := make(map[string]string)
+
+	if !core.IsValidEmail(email) {
+		errors["email"] = core.InvalidFormat.String()
+	} else if strings.EqualFold(email, user.Email) {
+		errors["email"] = core.InvalidValue.String()
+	} else if !core.IsValidEmailHost(email) {
+		errors["email"] = core.BadEmail.String()
+	} else if exists, ok := usecase.userRepo.ExistsEmail(ctx, email); !ok || exists {
+		errors["email"] = core.AlreadyExists.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
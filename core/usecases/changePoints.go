@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ChangeVote replaces value of vote made by user before
+type ChangeVote interface {
+	Do(ctx core.ReqContext, entityType domain.EntityType, id int64, value int) (bool, error)
+}
+
+type changeVote struct {
+	pointsRepo  core.PointsRepository
+	broadcaster core.Broadcaster
+	reputation  core.Reputation
+	guard       core.VoteGuard
+	log         core.AppLogger
+}
+
+func NewChangePoints(pointsRepo core.PointsRepository, broadcaster core.Broadcaster, reputation core.Reputation, guard core.VoteGuard, log core.AppLogger) ChangeVote {
+	return &changeVote{pointsRepo: pointsRepo, broadcaster: broadcaster, reputation: reputation, guard: guard, log: log}
+}
+
+func (usecase changeVote) Do(ctx core.ReqContext, entityType domain.EntityType, id int64, value int) (bool, error) {
+	trace := ctx.StartTrace("changeVote")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.PointsAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validateVote(entityType, id, value)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	points := usecase.pointsRepo.Get(ctx, ctx.UserId(), entityType, id)
+	if points == nil || points.Voted == 0 {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+			"error", "vote not existed",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if points.Voted == value {
+		return true, nil
+	}
+
+	vote := &domain.VoteRecord{EntityType: entityType, EntityId: id, UserId: ctx.UserId(), IP: ctx.ClientIP(), Value: value}
+	if appErr := usecase.guard.Check(ctx, vote); appErr != nil {
+		return false, appErr
+	}
+
+	before, result := usecase.pointsRepo.Add(ctx, vote)
+	if result {
+		usecase.broadcaster.PointsChanged(ctx, entityType, id)
+		if !vote.Flagged {
+			usecase.reputation.VoteChanged(ctx, entityType, id, before, value)
+		}
+	}
+	return result, nil
+}
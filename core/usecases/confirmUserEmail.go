@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ConfirmUserEmail marks email of user as confirmed without confirmation link
+type ConfirmUserEmail interface {
+	Do(ctx core.ReqContext, userId string) (bool, error)
+}
+
+type confirmUserEmail struct {
+	userRepo  core.UserRepository
+	policy    core.AccessPolicy
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewConfirmUserEmail(userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger) ConfirmUserEmail {
+	return &confirmUserEmail{userRepo: userRepo, policy: policy, changeLog: changeLog, log: log}
+}
+
+func (usecase *confirmUserEmail) Do(ctx core.ReqContext, userId string) (bool, error) {
+	trace := ctx.StartTrace("confirmUserEmail")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.UserManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	user := usecase.userRepo.Get(ctx, userId)
+	if user == nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "user not exists",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if user.EmailConfirmed {
+		return false, nil
+	}
+
+	old := *user
+	user.EmailConfirmed = true
+	user.EmailConfirmation = ""
+
+	return updateUser(ctx, usecase.userRepo, usecase.policy, usecase.changeLog, usecase.log, &old, user)
+}
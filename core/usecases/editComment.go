@@ -3,29 +3,32 @@ package usecases
 import (
 	"fmt"
 	"github.com/NeekUP/roadmaps/domain"
+	"time"
 
 	"github.com/NeekUP/roadmaps/core"
 )
 
 type EditComment interface {
-	Do(ctx core.ReqContext, id int64, text string, title string) (bool, error)
+	Do(ctx core.ReqContext, id int64, version int, text string, title string) (bool, error)
 }
 
 type editComment struct {
 	commentsRepo core.CommentsRepository
 	log          core.AppLogger
 	changeLog    core.ChangeLog
+	broadcaster  core.Broadcaster
+	markdown     core.MarkdownRenderer
 }
 
-func NewEditComment(commentsRepo core.CommentsRepository, changeLog core.ChangeLog, log core.AppLogger) EditComment {
-	return &editComment{commentsRepo: commentsRepo, changeLog: changeLog, log: log}
+func NewEditComment(commentsRepo core.CommentsRepository, changeLog core.ChangeLog, broadcaster core.Broadcaster, markdown core.MarkdownRenderer, log core.AppLogger) EditComment {
+	return &editComment{commentsRepo: commentsRepo, changeLog: changeLog, broadcaster: broadcaster, markdown: markdown, log: log}
 }
 
-func (usecase *editComment) Do(ctx core.ReqContext, id int64, text string, title string) (bool, error) {
+func (usecase *editComment) Do(ctx core.ReqContext, id int64, version int, text string, title string) (bool, error) {
 	trace := ctx.StartTrace("editComment")
 	defer ctx.StopTrace(trace)
 
-	appErr := usecase.validate(id, text, title)
+	appErr := usecase.validate(id, version, text, title)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -45,7 +48,7 @@ func (usecase *editComment) Do(ctx core.ReqContext, id int64, text string, title
 		return false, core.NewError(core.NotExists)
 	}
 
-	if comment.UserId != userId {
+	if !core.IsAllowed(ctx, domain.CommentModerate, comment.UserId) {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
 			"UserId", userId,
@@ -54,7 +57,15 @@ func (usecase *editComment) Do(ctx core.ReqContext, id int64, text string, title
 		return false, core.NewError(core.AccessDenied)
 	}
 
-	if ok, err := usecase.commentsRepo.Update(ctx, id, text, title); !ok {
+	if comment.Version != version {
+		return false, usecase.conflict(ctx, comment)
+	}
+
+	html := usecase.markdown.Render(ctx, text)
+	if ok, err := usecase.commentsRepo.Update(ctx, id, version, text, html, title, userId); !ok {
+		if err != nil && err.Message == core.Conflict.String() {
+			return false, usecase.conflict(ctx, usecase.commentsRepo.Get(ctx, id))
+		}
 		if err != nil {
 			usecase.log.Errorw("invalid request",
 				"reqid", ctx.ReqId(),
@@ -66,18 +77,39 @@ func (usecase *editComment) Do(ctx core.ReqContext, id int64, text string, title
 
 	changedComment := *comment
 	changedComment.Text = text
+	changedComment.Html = html
 	changedComment.Title = title
-	usecase.changeLog.Edited(domain.CommentEntity, comment.Id, userId, comment, &changedComment)
+	changedComment.EditedAt = time.Now().UTC()
+	changedComment.EditedBy = userId
+	changedComment.Version = version + 1
+	usecase.changeLog.Edited(ctx, domain.CommentEntity, comment.Id, comment, &changedComment)
+	usecase.broadcaster.CommentEdited(ctx, &changedComment)
 	return true, nil
 }
 
-func (usecase *editComment) validate(id int64, text string, title string) *core.AppError {
+// conflict returns current state of comment changed by somebody else
+func (usecase *editComment) conflict(ctx core.ReqContext, current *domain.Comment) error {
+	if current == nil || current.Deleted {
+		return core.NewError(core.NotExists)
+	}
+	usecase.log.Infow("comment changed concurrently",
+		"reqid", ctx.ReqId(),
+		"id", current.Id,
+	)
+	return core.NewConflictError(current)
+}
+
+func (usecase *editComment) validate(id int64, version int, text string, title string) *core.AppError {
 	errors := make(map[string]string)
 
 	if id <= 0 {
 		errors["id"] = core.InvalidValue.String()
 	}
 
+	if version <= 0 {
+		errors["version"] = core.InvalidValue.String()
+	}
+
 	if !core.IsValidCommentText(text) {
 		errors["text"] = core.InvalidValue.String()
 	}
@@ -10,12 +10,15 @@ type EditPlan interface {
 }
 
 type editPlan struct {
-	planRepo     core.PlanRepository
-	sourceRepo   core.SourceRepository
-	topicRepo    core.TopicRepository
-	projectsRepo core.ProjectsRepository
-	log          core.AppLogger
-	changeLog    core.ChangeLog
+	planRepo         core.PlanRepository
+	collaboratorRepo core.PlanCollaboratorRepository
+	stepRepo         core.StepRepository
+	sourceRepo       core.SourceRepository
+	topicRepo        core.TopicRepository
+	projectsRepo     core.ProjectsRepository
+	log              core.AppLogger
+	changeLog        core.ChangeLog
+	markdown         core.MarkdownRenderer
 }
 
 type EditPlanReq struct {
@@ -24,15 +27,22 @@ type EditPlanReq struct {
 	Title     string
 	IsDraft   bool
 	Steps     []PlanStep
+	// version of plan which client has changed
+	Version int
+	// proposer of accepted suggestion, changes are attributed to proposer instead of current user
+	AuthorId string
 }
 
-func NewEditPlan(planRepo core.PlanRepository, sourceRepo core.SourceRepository, topicRepo core.TopicRepository, projectsRepo core.ProjectsRepository, changeLog core.ChangeLog, log core.AppLogger) EditPlan {
+func NewEditPlan(planRepo core.PlanRepository, collaboratorRepo core.PlanCollaboratorRepository, stepRepo core.StepRepository, sourceRepo core.SourceRepository, topicRepo core.TopicRepository, projectsRepo core.ProjectsRepository, changeLog core.ChangeLog, markdown core.MarkdownRenderer, log core.AppLogger) EditPlan {
 	return &editPlan{planRepo: planRepo,
-		sourceRepo:   sourceRepo,
-		topicRepo:    topicRepo,
-		projectsRepo: projectsRepo,
-		changeLog:    changeLog,
-		log:          log}
+		collaboratorRepo: collaboratorRepo,
+		stepRepo:         stepRepo,
+		sourceRepo:       sourceRepo,
+		topicRepo:        topicRepo,
+		projectsRepo:     projectsRepo,
+		changeLog:        changeLog,
+		markdown:         markdown,
+		log:              log}
 }
 
 func (usecase *editPlan) Do(ctx core.ReqContext, req EditPlanReq) (bool, error) {
@@ -40,8 +50,7 @@ func (usecase *editPlan) Do(ctx core.ReqContext, req EditPlanReq) (bool, error)
 	defer ctx.StopTrace(trace)
 
 	old := usecase.planRepo.GetWithDraft(ctx, req.Id, ctx.UserId())
-	userId := ctx.UserId()
-	appErr := usecase.validate(ctx, req, userId, old)
+	appErr := usecase.validate(ctx, req, old)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -50,29 +59,26 @@ func (usecase *editPlan) Do(ctx core.ReqContext, req EditPlanReq) (bool, error)
 		return false, appErr
 	}
 
-	stepsCount := len(req.Steps)
-	steps := make([]domain.Step, 0, stepsCount)
-
-	for i, v := range req.Steps {
-		step := domain.Step{
-			ReferenceId:   v.ReferenceId,
-			ReferenceType: v.ReferenceType,
-			Position:      i,
-			Title:         v.Title,
-		}
-		steps = append(steps, step)
+	if old.Version != req.Version {
+		return false, usecase.conflict(ctx, req.Id)
 	}
 
+	steps := newPlanSteps(ctx, req.Steps, usecase.sourceRepo, usecase.markdown)
+
 	plan := &domain.Plan{
 		Id:        req.Id,
 		TopicName: req.TopicName,
 		Title:     req.Title,
 		IsDraft:   req.IsDraft,
-		OwnerId:   userId,
+		OwnerId:   old.OwnerId,
 		Steps:     steps,
+		Version:   req.Version,
 	}
 
 	if ok, err := usecase.planRepo.Update(ctx, plan); !ok {
+		if err != nil && err.Message == core.Conflict.String() {
+			return false, usecase.conflict(ctx, req.Id)
+		}
 		if err != nil {
 			usecase.log.Errorw("invalid request",
 				"reqid", ctx.ReqId(),
@@ -82,11 +88,39 @@ func (usecase *editPlan) Do(ctx core.ReqContext, req EditPlanReq) (bool, error)
 		return false, err
 	}
 
-	usecase.changeLog.Edited(domain.PlanEntity, int64(plan.Id), userId, old, plan)
+	changedBy := ctx
+	if req.AuthorId != "" {
+		changedBy = &authorContext{ReqContext: ctx, authorId: req.AuthorId}
+	}
+	usecase.changeLog.Edited(changedBy, domain.PlanEntity, int64(plan.Id), old, plan)
 	return true, nil
 }
 
-func (usecase *editPlan) validate(ctx core.ReqContext, req EditPlanReq, userId string, plan *domain.Plan) *core.AppError {
+// conflict returns current state of plan changed by somebody else
+func (usecase *editPlan) conflict(ctx core.ReqContext, id int) error {
+	usecase.log.Infow("plan changed concurrently",
+		"reqid", ctx.ReqId(),
+		"id", id,
+	)
+	current := usecase.planRepo.GetWithDraft(ctx, id, ctx.UserId())
+	if current == nil {
+		return core.NewError(core.NotExists)
+	}
+	current.Steps = usecase.stepRepo.GetByPlan(ctx, current.Id)
+	return core.NewConflictError(current)
+}
+
+// authorContext attributes changes to other user, permissions stay of current user
+type authorContext struct {
+	core.ReqContext
+	authorId string
+}
+
+func (ctx *authorContext) UserId() string {
+	return ctx.authorId
+}
+
+func (usecase *editPlan) validate(ctx core.ReqContext, req EditPlanReq, plan *domain.Plan) *core.AppError {
 	errors := make(map[string]string)
 	if !core.IsValidTopicName(req.TopicName) {
 		errors["topic"] = core.InvalidFormat.String()
@@ -96,11 +130,32 @@ func (usecase *editPlan) validate(ctx core.ReqContext, req EditPlanReq, userId s
 		errors["title"] = core.InvalidFormat.String()
 	}
 
-	if len(req.Steps) == 0 {
+	validatePlanSteps(ctx, req.Steps, usecase.sourceRepo, usecase.topicRepo, usecase.projectsRepo, errors)
+
+	if req.Version <= 0 {
+		errors["version"] = core.InvalidValue.String()
+	}
+
+	if plan == nil {
+		errors["id"] = core.NotExists.String()
+	} else if !core.IsAllowedOnPlan(ctx, domain.PlanEdit, plan, usecase.collaboratorRepo.Get(ctx, plan.Id, ctx.UserId())) {
+		errors["id"] = core.AccessDenied.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+
+	return nil
+}
+
+// validatePlanSteps adds errors of steps and not existed references of steps
+func validatePlanSteps(ctx core.ReqContext, steps []PlanStep, sourceRepo core.SourceRepository, topicRepo core.TopicRepository, projectsRepo core.ProjectsRepository, errors map[string]string) {
+	if len(steps) == 0 {
 		errors["steps"] = core.InvalidCount.String()
 	}
 
-	for _, v := range req.Steps {
+	for _, v := range steps {
 		if v.ReferenceId == 0 {
 			errors["source.id"] = core.InvalidValue.String()
 		}
@@ -110,33 +165,61 @@ func (usecase *editPlan) validate(ctx core.ReqContext, req EditPlanReq, userId s
 		if !core.IsValidStepTitle(v.Title) {
 			errors["title"] = core.InvalidValue.String()
 		}
+		validateStepDetails(v, errors)
 		switch v.ReferenceType {
 		case domain.ResourceReference:
-			if usecase.sourceRepo.Get(ctx, v.ReferenceId) == nil {
+			if sourceRepo.Get(ctx, v.ReferenceId) == nil {
 				errors["source.id"] = core.NotExists.String()
 			}
 		case domain.ProjectReference:
-			if usecase.projectsRepo.Get(ctx, int(v.ReferenceId)) == nil {
+			if projectsRepo.Get(ctx, int(v.ReferenceId)) == nil {
 				errors["source.id"] = core.NotExists.String()
 			}
 		case domain.TopicReference:
-			if usecase.topicRepo.GetById(ctx, int(v.ReferenceId)) == nil {
+			if topicRepo.GetById(ctx, int(v.ReferenceId)) == nil {
 				errors["source.id"] = core.NotExists.String()
 			}
 		}
 	}
+}
 
-	if plan == nil {
-		errors["id"] = core.NotExists.String()
+// validateStepDetails adds errors of estimated time, difficulty and notes of step
+func validateStepDetails(step PlanStep, errors map[string]string) {
+	if !core.IsValidStepDuration(step.Duration) {
+		errors["duration"] = core.InvalidValue.String()
 	}
-
-	if plan.OwnerId != userId {
-		errors["id"] = core.AccessDenied.String()
+	if !core.IsValidDifficulty(step.Difficulty) {
+		errors["difficulty"] = core.InvalidValue.String()
 	}
-
-	if len(errors) > 0 {
-		return core.ValidationError(errors)
+	if !core.IsValidStepNotes(step.Notes) {
+		errors["notes"] = core.InvalidValue.String()
 	}
+}
 
-	return nil
+// newPlanSteps renders steps of request, duration of resource is taken from source when it is not set
+func newPlanSteps(ctx core.ReqContext, reqSteps []PlanStep, sourceRepo core.SourceRepository, markdown core.MarkdownRenderer) []domain.Step {
+	steps := make([]domain.Step, 0, len(reqSteps))
+	for i, v := range reqSteps {
+		step := domain.Step{
+			ReferenceId:   v.ReferenceId,
+			ReferenceType: v.ReferenceType,
+			Position:      i,
+			Title:         v.Title,
+			TitleHtml:     markdown.RenderInline(ctx, v.Title),
+			Duration:      v.Duration,
+			Difficulty:    v.Difficulty,
+			Optional:      v.Optional,
+			Notes:         v.Notes,
+		}
+		if v.Notes != "" {
+			step.NotesHtml = markdown.Render(ctx, v.Notes)
+		}
+		if step.Duration == 0 && step.ReferenceType == domain.ResourceReference {
+			if source := sourceRepo.Get(ctx, v.ReferenceId); source != nil {
+				step.Duration = source.Duration()
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
 }
@@ -6,7 +6,7 @@ import (
 )
 
 type EditProject interface {
-	Do(ctx core.ReqContext, id int, title, text string, tags []string) (*domain.Project, error)
+	Do(ctx core.ReqContext, id int, version int, title, text string, tags []string) (*domain.Project, error)
 }
 
 type editProject struct {
@@ -20,11 +20,11 @@ func NewEditProject(projectRepo core.ProjectsRepository, topicRepo core.TopicRep
 	return &editProject{projectRepo: projectRepo, topicRepo: topicRepo, changeLog: changeLog, log: log}
 }
 
-func (usecase *editProject) Do(ctx core.ReqContext, id int, title, text string, tags []string) (*domain.Project, error) {
+func (usecase *editProject) Do(ctx core.ReqContext, id int, version int, title, text string, tags []string) (*domain.Project, error) {
 	trace := ctx.StartTrace("editProject")
 	defer ctx.StopTrace(trace)
 
-	appErr := usecase.validate(title, text, tags)
+	appErr := usecase.validate(version, title, text, tags)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -41,9 +41,17 @@ func (usecase *editProject) Do(ctx core.ReqContext, id int, title, text string,
 	if userId != projectBefore.OwnerId {
 		return nil, core.NewError(core.AccessDenied)
 	}
+	if projectBefore.Version != version {
+		usecase.log.Infow("project changed concurrently",
+			"reqid", ctx.ReqId(),
+			"id", id,
+		)
+		return nil, core.NewConflictError(projectBefore)
+	}
 
 	projectAfter := *projectBefore
 	projectAfter.Tags = usecase.topicRepo.GetTags(ctx, tags)
+	projectAfter.Version = version
 	_, err := usecase.projectRepo.Update(ctx, &projectAfter)
 
 	if err != nil {
@@ -53,13 +61,17 @@ func (usecase *editProject) Do(ctx core.ReqContext, id int, title, text string,
 		)
 		return nil, err
 	}
-	usecase.changeLog.Edited(domain.ProjectEntity, int64(projectBefore.Id), userId, projectBefore, &projectAfter)
+	usecase.changeLog.Edited(ctx, domain.ProjectEntity, int64(projectBefore.Id), projectBefore, &projectAfter)
 	return &projectAfter, nil
 }
 
-func (usecase *editProject) validate(title, text string, tags []string) *core.AppError {
+func (usecase *editProject) validate(version int, title, text string, tags []string) *core.AppError {
 	errors := make(map[string]string)
 
+	if version <= 0 {
+		errors["version"] = core.InvalidValue.String()
+	}
+
 	if !core.IsValidProjectTitle(title) {
 		errors["title"] = core.InvalidFormat.String()
 	}
@@ -6,26 +6,27 @@ import (
 )
 
 type EditTopic interface {
-	Do(ctx core.ReqContext, id int, title, desc string, istag bool) (bool, error)
+	Do(ctx core.ReqContext, id int, version int, title, desc string, istag bool) (bool, error)
 }
 
 type editTopic struct {
 	repo      core.TopicRepository
 	log       core.AppLogger
 	changeLog core.ChangeLog
+	markdown  core.MarkdownRenderer
 }
 
-func NewEditTopic(topicRepo core.TopicRepository, changelog core.ChangeLog, log core.AppLogger) EditTopic {
-	return &editTopic{repo: topicRepo, changeLog: changelog, log: log}
+func NewEditTopic(topicRepo core.TopicRepository, changelog core.ChangeLog, markdown core.MarkdownRenderer, log core.AppLogger) EditTopic {
+	return &editTopic{repo: topicRepo, changeLog: changelog, markdown: markdown, log: log}
 }
 
-func (usecase *editTopic) Do(ctx core.ReqContext, id int, title, desc string, istag bool) (bool, error) {
+func (usecase *editTopic) Do(ctx core.ReqContext, id int, version int, title, desc string, istag bool) (bool, error) {
 	trace := ctx.StartTrace("editTopic")
 	defer ctx.StopTrace(trace)
 
 	userId := ctx.UserId()
 	old := usecase.repo.GetById(ctx, id)
-	appErr := usecase.validate(id, title, old, userId)
+	appErr := usecase.validate(ctx, id, version, title, desc, old)
 
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
@@ -35,10 +36,19 @@ func (usecase *editTopic) Do(ctx core.ReqContext, id int, title, desc string, is
 		return false, appErr
 	}
 
+	if old.Version != version {
+		return false, usecase.conflict(ctx, old)
+	}
+
 	topic := domain.NewTopic(title, desc, userId)
+	topic.DescriptionHtml = usecase.markdown.Render(ctx, desc)
 	topic.Id = id
 	topic.IsTag = istag
+	topic.Version = version
 	saved, err := usecase.repo.Update(ctx, topic)
+	if err != nil && err.Message == core.Conflict.String() {
+		return false, usecase.conflict(ctx, usecase.repo.GetById(ctx, id))
+	}
 	if err != nil {
 		usecase.log.Errorw("Topic not updated",
 			"reqid", ctx.ReqId(),
@@ -47,18 +57,28 @@ func (usecase *editTopic) Do(ctx core.ReqContext, id int, title, desc string, is
 		return false, err
 	}
 
-	usecase.changeLog.Edited(domain.TopicEntity, int64(topic.Id), ctx.UserId(), old, topic)
+	usecase.changeLog.Edited(ctx, domain.TopicEntity, int64(topic.Id), old, topic)
 	return saved, nil
 }
 
-func (usecase *editTopic) validate(id int, title string, topic *domain.Topic, userId string) *core.AppError {
+// conflict returns current state of topic changed by somebody else
+func (usecase *editTopic) conflict(ctx core.ReqContext, current *domain.Topic) error {
+	if current == nil {
+		return core.NewError(core.NotExists)
+	}
+	usecase.log.Infow("topic changed concurrently",
+		"reqid", ctx.ReqId(),
+		"id", current.Id,
+	)
+	return core.NewConflictError(current)
+}
+
+func (usecase *editTopic) validate(ctx core.ReqContext, id int, version int, title, desc string, topic *domain.Topic) *core.AppError {
 	errors := make(map[string]string)
 
 	if topic == nil {
 		errors["id"] = core.NotExists.String()
-	}
-
-	if topic.Creator != userId {
+	} else if !ctx.HasPermission(domain.TopicEdit) {
 		errors["id"] = core.AccessDenied.String()
 	}
 
@@ -66,10 +86,18 @@ func (usecase *editTopic) validate(id int, title string, topic *domain.Topic, us
 		errors["title"] = core.InvalidFormat.String()
 	}
 
+	if !core.IsValidDscription(desc) {
+		errors["desc"] = core.InvalidFormat.String()
+	}
+
 	if id <= 0 {
 		errors["id"] = core.InvalidValue.String()
 	}
 
+	if version <= 0 {
+		errors["version"] = core.InvalidValue.String()
+	}
+
 	if len(errors) > 0 {
 		return core.ValidationError(errors)
 	}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// EditWebhook changes url and events of webhook, disabled webhook keeps deliveries until it is enabled
+type EditWebhook interface {
+	Do(ctx core.ReqContext, id int64, url string, events []domain.ChangeType, active bool) (*domain.Webhook, error)
+}
+
+type editWebhook struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewEditWebhook(webhookRepo core.WebhookRepository, log core.AppLogger) EditWebhook {
+	return &editWebhook{webhookRepo: webhookRepo, log: log}
+}
+
+func (usecase *editWebhook) Do(ctx core.ReqContext, id int64, url string, events []domain.ChangeType, active bool) (*domain.Webhook, error) {
+	trace := ctx.StartTrace("editWebhook")
+	defer ctx.StopTrace(trace)
+
+	webhook, appErr := getOwnWebhook(ctx, usecase.webhookRepo, id, usecase.log)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	appErr = validateWebhook(url, events)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	webhook.Url = url
+	webhook.Events = events
+	webhook.Active = active
+	webhook.OwnChanges = !ctx.HasPermission(domain.WebhookManage)
+	if _, appErr := usecase.webhookRepo.Update(ctx, webhook); appErr != nil {
+		return nil, appErr
+	}
+	return webhook, nil
+}
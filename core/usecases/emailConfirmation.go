@@ -5,18 +5,21 @@ import (
 	"github.com/NeekUP/roadmaps/domain"
 )
 
+// EmailConfirmation confirms email of new user or new email of existing user by secret from link
 type EmailConfirmation interface {
 	Do(ctx core.ReqContext, id, secret string) (*domain.User, error)
 }
 
 type emailConfirmation struct {
 	userRepo core.UserRepository
+	policy   core.AccessPolicy
 	log      core.AppLogger
 }
 
-func NewEmailConfirmation(userRepo core.UserRepository, log core.AppLogger) EmailConfirmation {
+func NewEmailConfirmation(userRepo core.UserRepository, policy core.AccessPolicy, log core.AppLogger) EmailConfirmation {
 	return &emailConfirmation{
 		userRepo: userRepo,
+		policy:   policy,
 		log:      log,
 	}
 }
@@ -30,13 +33,40 @@ func (usecase *emailConfirmation) Do(ctx core.ReqContext, id, secret string) (*d
 		return nil, core.NewError(core.AccessDenied)
 	}
 
-	if user.EmailConfirmation != secret {
+	if user.EmailConfirmation == "" || user.EmailConfirmation != secret {
 		return nil, core.NewError(core.AccessDenied)
 	}
 
-	user.EmailConfirmed = true
-	user.EmailConfirmation = ""
+	if user.IsConfirmationExpired() {
+		usecase.log.Errorw("confirmation expired",
+			"reqid", ctx.ReqId(),
+			"UserId", user.Id,
+			"expires", user.EmailConfirmationExpires,
+		)
+		return nil, core.NewError(core.ConfirmationExpired)
+	}
+
+	// email could be taken by another user while change was waiting for confirmation
+	if user.PendingEmail != "" {
+		if exists, ok := usecase.userRepo.ExistsEmail(ctx, user.PendingEmail); !ok || exists {
+			usecase.log.Errorw("invalid request",
+				"reqid", ctx.ReqId(),
+				"UserId", user.Id,
+				"error", "email already exists",
+			)
+			return nil, core.NewError(core.AlreadyExists)
+		}
+	}
+
+	user.ConfirmEmail()
+	if _, err := usecase.userRepo.Update(ctx, user); err != nil {
+		usecase.log.Errorw("User not updated",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
 
-	usecase.userRepo.Update(ctx, user)
+	usecase.policy.ResetUser(user.Id)
 	return user, nil
 }
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ForkPlan copies plan with its steps to current user as a draft, copy keeps id of original plan
+type ForkPlan interface {
+	Do(ctx core.ReqContext, id int) (*domain.Plan, error)
+}
+
+type forkPlan struct {
+	planRepo  core.PlanRepository
+	stepRepo  core.StepRepository
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewForkPlan(planRepo core.PlanRepository, stepRepo core.StepRepository, changeLog core.ChangeLog, log core.AppLogger) ForkPlan {
+	return &forkPlan{planRepo: planRepo, stepRepo: stepRepo, changeLog: changeLog, log: log}
+}
+
+func (usecase *forkPlan) Do(ctx core.ReqContext, id int) (*domain.Plan, error) {
+	trace := ctx.StartTrace("forkPlan")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.PlanAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	if id <= 0 {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	// own drafts can be forked too
+	upstream := usecase.planRepo.GetWithDraft(ctx, id, ctx.UserId())
+	if upstream == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	upstreamSteps := usecase.stepRepo.GetByPlan(ctx, upstream.Id)
+	steps := make([]domain.Step, len(upstreamSteps))
+	for i, v := range upstreamSteps {
+		steps[i] = domain.Step{
+			ReferenceId:   v.ReferenceId,
+			ReferenceType: v.ReferenceType,
+			Position:      v.Position,
+			Title:         v.Title,
+			TitleHtml:     v.TitleHtml,
+			Duration:      v.Duration,
+			Difficulty:    v.Difficulty,
+			Optional:      v.Optional,
+			Notes:         v.Notes,
+			NotesHtml:     v.NotesHtml,
+		}
+	}
+
+	plan := &domain.Plan{
+		TopicName: upstream.TopicName,
+		Title:     upstream.Title,
+		IsDraft:   true,
+		OwnerId:   ctx.UserId(),
+		ParentId:  upstream.Id,
+		Steps:     steps,
+	}
+
+	if ok, err := usecase.planRepo.SaveWithSteps(ctx, plan); !ok {
+		if err != nil {
+			usecase.log.Errorw("invalid request",
+				"reqid", ctx.ReqId(),
+				"error", err.Error(),
+			)
+		}
+		return nil, err
+	}
+
+	usecase.changeLog.Added(ctx, domain.PlanEntity, int64(plan.Id))
+	return plan, nil
+}
@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetChangeLog returns global activity, newest records go first
+type GetChangeLog interface {
+	Do(ctx core.ReqContext, filter domain.ChangeLogFilter, count int, page int) ([]domain.ChangeLogRecord, error)
+}
+
+type getChangeLog struct {
+	changeLogRepo core.ChangeLogRepository
+	log           core.AppLogger
+}
+
+func NewGetChangeLog(changeLogRepo core.ChangeLogRepository, log core.AppLogger) GetChangeLog {
+	return &getChangeLog{changeLogRepo: changeLogRepo, log: log}
+}
+
+func (usecase *getChangeLog) Do(ctx core.ReqContext, filter domain.ChangeLogFilter, count int, page int) ([]domain.ChangeLogRecord, error) {
+	trace := ctx.StartTrace("getChangeLog")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ChangeLogView) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	appErr := usecase.validate(filter, count, page)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	return usecase.changeLogRepo.Search(ctx, filter, count, page), nil
+}
+
+func (usecase *getChangeLog) validate(filter domain.ChangeLogFilter, count int, page int) *core.AppError {
+	if appErr := validatePaging(count, page); appErr != nil {
+		return appErr
+	}
+
+	errors := make(map[string]string)
+	if len(filter.UserId) > 36 {
+		errors["userId"] = core.InvalidFormat.String()
+	}
+
+	if filter.EntityType != 0 && !filter.EntityType.IsValid() {
+		errors["type"] = core.InvalidValue.String()
+	}
+
+	if filter.Action != 0 && !filter.Action.IsValid() {
+		errors["action"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
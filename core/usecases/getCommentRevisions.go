@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetCommentRevisions returns replaced versions of comment to moderators and author, oldest first
+type GetCommentRevisions interface {
+	Do(ctx core.ReqContext, id int64) ([]domain.CommentRevision, error)
+}
+
+type getCommentRevisions struct {
+	commentsRepo core.CommentsRepository
+	usersRepo    core.UserRepository
+	log          core.AppLogger
+}
+
+func NewGetCommentRevisions(commentsRepo core.CommentsRepository, usersRepo core.UserRepository, log core.AppLogger) GetCommentRevisions {
+	return &getCommentRevisions{commentsRepo: commentsRepo, usersRepo: usersRepo, log: log}
+}
+
+func (usecase *getCommentRevisions) Do(ctx core.ReqContext, id int64) ([]domain.CommentRevision, error) {
+	trace := ctx.StartTrace("getCommentRevisions")
+	defer ctx.StopTrace(trace)
+
+	userId := ctx.UserId()
+	comment := usecase.commentsRepo.Get(ctx, id)
+	if comment == nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+			"error", fmt.Sprintf("comment not existed. id: %v", id),
+		)
+		return nil, core.NewError(core.NotExists)
+	}
+
+	if !core.IsAllowed(ctx, domain.CommentModerate, comment.UserId) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	revisions := usecase.commentsRepo.GetRevisions(ctx, id)
+	idList := make([]string, 0, len(revisions))
+	for i := 0; i < len(revisions); i++ {
+		idList = append(idList, revisions[i].EditorId)
+	}
+
+	users := make(map[string]*domain.User)
+	for _, v := range usecase.usersRepo.GetList(ctx, idList) {
+		user := v
+		users[v.Id] = &user
+	}
+
+	for i := 0; i < len(revisions); i++ {
+		revisions[i].Editor = users[revisions[i].EditorId]
+		revisions[i].ByModerator = revisions[i].EditorId != comment.UserId
+	}
+	return revisions, nil
+}
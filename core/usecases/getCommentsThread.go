@@ -1,14 +1,15 @@
 package usecases
 
 import (
-	"sort"
-
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
 )
 
+// GetCommentsThread returns page of replies in thread, replies are nested into parents found on the same page.
+// Oldest replies are first by default, up to maxThreadReplies are returned when count is zero.
+// Next is a cursor of the next page or empty if there are no more replies.
 type GetCommentsThread interface {
-	Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, threadId int64) ([]domain.Comment, error)
+	Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, threadId int64, sort domain.CommentSort, cursor string, count int) (comments []domain.Comment, next string, err error)
 }
 
 type getCommentsThread struct {
@@ -21,89 +22,93 @@ func NewGetCommentsThread(commentsRepo core.CommentsRepository, usersRepo core.U
 	return &getCommentsThread{commentsRepo: commentsRepo, usersRepo: usersRepo, log: log}
 }
 
-func (usecase *getCommentsThread) Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, threadId int64) ([]domain.Comment, error) {
+func (usecase *getCommentsThread) Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, threadId int64, sort domain.CommentSort, cursor string, count int) ([]domain.Comment, string, error) {
 	trace := ctx.StartTrace("getCommentsThread")
 	defer ctx.StopTrace(trace)
 
-	appErr := usecase.validate(entityType, entityId, threadId)
+	if sort == "" {
+		sort = domain.CommentSortOldest
+	}
+	if count == 0 {
+		count = maxThreadReplies
+	}
+
+	after, appErr := usecase.validate(entityType, entityId, threadId, sort, cursor, count)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
 			"error", appErr.Error(),
 		)
-		return nil, appErr
+		return nil, "", appErr
 	}
 
-	allcomments := usecase.commentsRepo.GetThread(ctx, int(entityType), entityId, threadId)
+	allcomments, next := usecase.commentsRepo.GetThread(ctx, int(entityType), entityId, threadId, sort, after, count)
 	if len(allcomments) == 0 {
-		return []domain.Comment{}, nil
-	}
-
-	// attache users
-	userIds := make(map[string]*domain.User)
-	for i := 0; i < len(allcomments); i++ {
-		if _, ok := userIds[allcomments[i].UserId]; !ok {
-			userIds[allcomments[i].UserId] = nil
-		}
+		return []domain.Comment{}, "", nil
 	}
 
-	idList := make([]string, 0, len(userIds))
-	for k := range userIds {
-		idList = append(idList, k)
-	}
+	hideDeleted(allcomments)
+	attachUsers(ctx, usecase.usersRepo, allcomments)
+	attachReactions(ctx, usecase.commentsRepo, allcomments)
+	return nestReplies(allcomments), core.EncodeCommentCursor(next), nil
+}
 
-	for _, v := range usecase.usersRepo.GetList(ctx, idList) {
-		userIds[v.Id] = &v
+func (usecase *getCommentsThread) validate(entityType domain.EntityType, entityId int64, threadId int64, sort domain.CommentSort, cursor string, count int) (*domain.CommentCursor, *core.AppError) {
+	errors := make(map[string]string)
+	if !entityType.IsValid() {
+		errors["entityType"] = core.InvalidValue.String()
 	}
 
-	for i := 0; i < len(allcomments); i++ {
-		allcomments[i].User = userIds[allcomments[i].UserId]
+	if entityId < 0 {
+		errors["entityId"] = core.InvalidValue.String()
 	}
 
-	// sort
-	m := make(map[int64]domain.Comment)
-	for _, v := range allcomments {
-		m[v.Id] = v
+	if threadId <= 0 {
+		errors["threadId"] = core.InvalidValue.String()
 	}
 
-	for key, val := range m {
-		if v, ok := m[val.ParentId]; ok {
-			if v.Childs == nil {
-				v.Childs = []domain.Comment{val}
-			} else {
-				v.Childs = append(v.Childs, val)
-				sort.Slice(v.Childs, func(i, j int) bool { return v.Childs[i].Id < v.Childs[j].Id })
-			}
-			m[val.ParentId] = v
-			delete(m, key)
-		}
+	if count < 0 || count > maxThreadReplies {
+		errors["count"] = core.InvalidCount.String()
 	}
 
-	comments := make([]domain.Comment, len(m))
-	var i int = 0
-	for _, val := range m {
-		comments[i] = val
-		i++
+	after := validateCommentsCursor(errors, sort, cursor)
+	if len(errors) > 0 {
+		return nil, core.ValidationError(errors)
 	}
-	return comments, nil
+	return after, nil
 }
 
-func (usecase *getCommentsThread) validate(entityType domain.EntityType, entityId int64, threadId int64) *core.AppError {
-	errors := make(map[string]string)
-	if !entityType.IsValid() {
-		errors["entityType"] = core.InvalidValue.String()
+// nestReplies puts replies into childs of their parents keeping order of the list,
+// replies to comments missing in the list stay on top level
+func nestReplies(list []domain.Comment) []domain.Comment {
+	index := make(map[int64]bool, len(list))
+	for i := 0; i < len(list); i++ {
+		index[list[i].Id] = true
 	}
 
-	if entityId < 0 {
-		errors["entityId"] = core.InvalidValue.String()
+	childs := make(map[int64][]int)
+	roots := make([]int, 0, len(list))
+	for i := 0; i < len(list); i++ {
+		if index[list[i].ParentId] {
+			childs[list[i].ParentId] = append(childs[list[i].ParentId], i)
+		} else {
+			roots = append(roots, i)
+		}
 	}
 
-	if threadId <= 0 {
-		errors["threadId"] = core.InvalidValue.String()
+	var nest func(i int) domain.Comment
+	nest = func(i int) domain.Comment {
+		c := list[i]
+		c.Childs = make([]domain.Comment, len(childs[c.Id]))
+		for j, child := range childs[c.Id] {
+			c.Childs[j] = nest(child)
+		}
+		return c
 	}
 
-	if len(errors) > 0 {
-		return core.ValidationError(errors)
+	result := make([]domain.Comment, len(roots))
+	for i, root := range roots {
+		result[i] = nest(root)
 	}
-	return nil
+	return result
 }
@@ -5,8 +5,15 @@ import (
 	"github.com/NeekUP/roadmaps/domain"
 )
 
+const (
+	maxThreadsCount  = 100
+	maxThreadReplies = 500
+)
+
+// GetCommentsThreads returns page of first comments of threads, pinned threads are first.
+// Newest threads are first by default, next is a cursor of the next page or empty if there are no more threads.
 type GetCommentsThreads interface {
-	Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, count int, page int) (comments []domain.Comment, hasMore bool, err error)
+	Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, sort domain.CommentSort, cursor string, count int) (comments []domain.Comment, next string, err error)
 }
 
 type getCommentsThreads struct {
@@ -19,63 +26,100 @@ func NewGetCommentsThreads(commentsRepo core.CommentsRepository, usersRepo core.
 	return &getCommentsThreads{commentsRepo: commentsRepo, usersRepo: usersRepo, log: log}
 }
 
-func (usecase *getCommentsThreads) Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, count int, page int) (comments []domain.Comment, hasMore bool, err error) {
+func (usecase *getCommentsThreads) Do(ctx core.ReqContext, entityType domain.EntityType, entityId int64, sort domain.CommentSort, cursor string, count int) (comments []domain.Comment, next string, err error) {
 	trace := ctx.StartTrace("getCommentsThreads")
 	defer ctx.StopTrace(trace)
 
-	appErr := usecase.validate(entityType, entityId, count, page)
+	if sort == "" {
+		sort = domain.CommentSortNewest
+	}
+
+	after, appErr := usecase.validate(entityType, entityId, sort, cursor, count)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
 			"error", appErr.Error(),
 		)
-		return nil, false, appErr
+		return nil, "", appErr
 	}
 
-	list := usecase.commentsRepo.GetThreadList(ctx, int(entityType), entityId, count, page)
-	userIds := make(map[string]*domain.User)
-	for i := 0; i < len(list); i++ {
-		if _, ok := userIds[list[i].UserId]; !ok {
-			userIds[list[i].UserId] = nil
-		}
+	list, nextCursor := usecase.commentsRepo.GetThreadList(ctx, int(entityType), entityId, sort, after, count)
+	hideDeleted(list)
+	attachUsers(ctx, usecase.usersRepo, list)
+	attachReactions(ctx, usecase.commentsRepo, list)
+	return list, core.EncodeCommentCursor(nextCursor), nil
+}
+
+func (usecase *getCommentsThreads) validate(entityType domain.EntityType, entityId int64, sort domain.CommentSort, cursor string, count int) (*domain.CommentCursor, *core.AppError) {
+	errors := make(map[string]string)
+	if !entityType.IsValid() {
+		errors["entityType"] = core.InvalidValue.String()
 	}
 
-	idList := make([]string, 0, len(userIds))
-	for k := range userIds {
-		idList = append(idList, k)
+	if entityId < 0 {
+		errors["entityId"] = core.InvalidValue.String()
+	}
+
+	if count <= 0 || count > maxThreadsCount {
+		errors["count"] = core.InvalidCount.String()
 	}
 
-	for _, v := range usecase.usersRepo.GetList(ctx, idList) {
-		userIds[v.Id] = &v
+	after := validateCommentsCursor(errors, sort, cursor)
+	if len(errors) > 0 {
+		return nil, core.ValidationError(errors)
 	}
+	return after, nil
+}
 
-	for i := 0; i < len(list); i++ {
-		list[i].User = userIds[list[i].UserId]
+// validateCommentsCursor checks sort mode and decodes cursor made for the same sort mode
+func validateCommentsCursor(errors map[string]string, sort domain.CommentSort, cursor string) *domain.CommentCursor {
+	if !sort.IsValid() {
+		errors["sort"] = core.InvalidValue.String()
+		return nil
 	}
 
-	return list, len(list) == count, nil
+	if cursor == "" {
+		return nil
+	}
+
+	after, err := core.DecodeCommentCursor(cursor)
+	if err != nil || after.Sort != sort {
+		errors["cursor"] = core.InvalidValue.String()
+		return nil
+	}
+	return after
 }
 
-func (usecase *getCommentsThreads) validate(entityType domain.EntityType, entityId int64, count int, page int) *core.AppError {
-	errors := make(map[string]string)
-	if !entityType.IsValid() {
-		errors["entityType"] = core.InvalidValue.String()
+// hideDeleted removes text of deleted comments, client shows placeholder depending on who deleted comment
+func hideDeleted(comments []domain.Comment) {
+	for i := 0; i < len(comments); i++ {
+		if comments[i].Deleted {
+			comments[i].Text = ""
+			comments[i].Html = ""
+			comments[i].Title = ""
+		}
 	}
+}
 
-	if entityId < 0 {
-		errors["entityId"] = core.InvalidValue.String()
+func attachUsers(ctx core.ReqContext, usersRepo core.UserRepository, comments []domain.Comment) {
+	userIds := make(map[string]*domain.User)
+	for i := 0; i < len(comments); i++ {
+		if _, ok := userIds[comments[i].UserId]; !ok {
+			userIds[comments[i].UserId] = nil
+		}
 	}
 
-	if count <= 0 {
-		errors["count"] = core.InvalidValue.String()
+	idList := make([]string, 0, len(userIds))
+	for k := range userIds {
+		idList = append(idList, k)
 	}
 
-	if page < 0 {
-		errors["page"] = core.InvalidValue.String()
+	for _, v := range usersRepo.GetList(ctx, idList) {
+		user := v
+		userIds[v.Id] = &user
 	}
 
-	if len(errors) > 0 {
-		return core.ValidationError(errors)
+	for i := 0; i < len(comments); i++ {
+		comments[i].User = userIds[comments[i].UserId]
 	}
-	return nil
 }
@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetNotificationPreferences returns preferences of current user for every type of notifications
+type GetNotificationPreferences interface {
+	Do(ctx core.ReqContext) ([]domain.NotificationPreference, error)
+}
+
+type getNotificationPreferences struct {
+	notificationRepo core.NotificationRepository
+	log              core.AppLogger
+}
+
+func NewGetNotificationPreferences(notificationRepo core.NotificationRepository, log core.AppLogger) GetNotificationPreferences {
+	return &getNotificationPreferences{notificationRepo: notificationRepo, log: log}
+}
+
+func (usecase *getNotificationPreferences) Do(ctx core.ReqContext) ([]domain.NotificationPreference, error) {
+	trace := ctx.StartTrace("getNotificationPreferences")
+	defer ctx.StopTrace(trace)
+
+	preferences := domain.DefaultNotificationPreferences()
+	for _, saved := range usecase.notificationRepo.GetPreferences(ctx, ctx.UserId()) {
+		for i := range preferences {
+			if preferences[i].Type == saved.Type {
+				preferences[i] = saved
+			}
+		}
+	}
+	return preferences, nil
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetNotifications returns notifications of current user, newest go first
+type GetNotifications interface {
+	Do(ctx core.ReqContext, unreadOnly bool, count int, page int) ([]domain.Notification, error)
+}
+
+type getNotifications struct {
+	notificationRepo core.NotificationRepository
+	log              core.AppLogger
+}
+
+func NewGetNotifications(notificationRepo core.NotificationRepository, log core.AppLogger) GetNotifications {
+	return &getNotifications{notificationRepo: notificationRepo, log: log}
+}
+
+func (usecase *getNotifications) Do(ctx core.ReqContext, unreadOnly bool, count int, page int) ([]domain.Notification, error) {
+	trace := ctx.StartTrace("getNotifications")
+	defer ctx.StopTrace(trace)
+
+	appErr := validatePaging(count, page)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	return usecase.notificationRepo.GetList(ctx, ctx.UserId(), unreadOnly, count, page), nil
+}
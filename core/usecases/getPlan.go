@@ -14,25 +14,31 @@ func NewGetPlan(plans core.PlanRepository,
 	steps core.StepRepository,
 	sources core.SourceRepository,
 	topics core.TopicRepository,
+	comments core.CommentsRepository,
+	points core.PointsRepository,
 	//projectRepo core.ProjectsRepository,
 	logger core.AppLogger) GetPlan {
 	return &getPlan{
-		planRepo:   plans,
-		stepRepo:   steps,
-		userRepo:   users,
-		sourceRepo: sources,
-		topicRepo:  topics,
+		planRepo:     plans,
+		stepRepo:     steps,
+		userRepo:     users,
+		sourceRepo:   sources,
+		topicRepo:    topics,
+		commentsRepo: comments,
+		pointsRepo:   points,
 		//projectRepo: projectRepo,
 		log: logger,
 	}
 }
 
 type getPlan struct {
-	planRepo   core.PlanRepository
-	stepRepo   core.StepRepository
-	sourceRepo core.SourceRepository
-	topicRepo  core.TopicRepository
-	userRepo   core.UserRepository
+	planRepo     core.PlanRepository
+	stepRepo     core.StepRepository
+	sourceRepo   core.SourceRepository
+	topicRepo    core.TopicRepository
+	userRepo     core.UserRepository
+	commentsRepo core.CommentsRepository
+	pointsRepo   core.PointsRepository
 	//projectRepo core.ProjectsRepository
 	log core.AppLogger
 }
@@ -54,6 +60,10 @@ func (usecase *getPlan) Do(ctx core.ReqContext, id int) (*domain.Plan, error) {
 	if plan != nil {
 		plan.Steps = usecase.stepRepo.GetByPlan(ctx, plan.Id)
 		plan.Owner = usecase.userRepo.Get(ctx, plan.OwnerId)
+		plan.Forks = usecase.planRepo.CountForks(ctx, plan.Id)
+		if plan.ParentId > 0 {
+			plan.Parent = usecase.planRepo.Get(ctx, plan.ParentId)
+		}
 		usecase.fillSteps(ctx, plan)
 		return plan, nil
 	}
@@ -65,14 +75,16 @@ func (usecase *getPlan) fillSteps(ctx core.ReqContext, plan *domain.Plan) {
 	for i := 0; i < len(plan.Steps); i++ {
 		if plan.Steps[i].ReferenceType == domain.TopicReference {
 			t := usecase.topicRepo.GetById(ctx, int(plan.Steps[i].ReferenceId))
-			plan.Steps[i].Source = t
+			if t != nil {
+				plan.Steps[i].Source = t
+			}
 		} else if plan.Steps[i].ReferenceType == domain.ProjectReference {
 			//p := usecase.projectRepo.Get(ctx, int(plan.Steps[i].ReferenceId))
 			//plan.Steps[i].Source = p
 		} else {
 			s := usecase.sourceRepo.Get(ctx, plan.Steps[i].ReferenceId)
 			if s == nil {
-				break
+				continue
 			}
 			plan.Steps[i].Source = &domain.Source{
 				Id:                   s.Id,
@@ -86,6 +98,69 @@ func (usecase *getPlan) fillSteps(ctx core.ReqContext, plan *domain.Plan) {
 			}
 		}
 	}
+	usecase.attachCommentsCount(ctx, plan)
+	usecase.attachPoints(ctx, plan)
+}
+
+// attachCommentsCount sets count of comments to topics and sources of steps
+func (usecase *getPlan) attachCommentsCount(ctx core.ReqContext, plan *domain.Plan) {
+	var topicIds, sourceIds []int64
+	for i := 0; i < len(plan.Steps); i++ {
+		switch v := plan.Steps[i].Source.(type) {
+		case *domain.Topic:
+			topicIds = append(topicIds, int64(v.Id))
+		case *domain.Source:
+			sourceIds = append(sourceIds, v.Id)
+		}
+	}
+
+	topics := usecase.commentsRepo.Count(ctx, domain.TopicEntity, topicIds)
+	sources := usecase.commentsRepo.Count(ctx, domain.ResourceEntity, sourceIds)
+	for i := 0; i < len(plan.Steps); i++ {
+		switch v := plan.Steps[i].Source.(type) {
+		case *domain.Topic:
+			v.Comments = topics[int64(v.Id)]
+		case *domain.Source:
+			v.Comments = sources[v.Id]
+		}
+	}
+}
+
+// attachPoints sets votes of topics and sources of steps
+func (usecase *getPlan) attachPoints(ctx core.ReqContext, plan *domain.Plan) {
+	var topicIds, sourceIds []int64
+	for i := 0; i < len(plan.Steps); i++ {
+		switch v := plan.Steps[i].Source.(type) {
+		case *domain.Topic:
+			topicIds = append(topicIds, int64(v.Id))
+		case *domain.Source:
+			sourceIds = append(sourceIds, v.Id)
+		}
+	}
+
+	topics := usecase.getPoints(ctx, domain.TopicEntity, topicIds)
+	sources := usecase.getPoints(ctx, domain.ResourceEntity, sourceIds)
+	for i := 0; i < len(plan.Steps); i++ {
+		switch v := plan.Steps[i].Source.(type) {
+		case *domain.Topic:
+			v.Points = topics[int64(v.Id)]
+		case *domain.Source:
+			v.Points = sources[v.Id]
+		}
+	}
+}
+
+func (usecase *getPlan) getPoints(ctx core.ReqContext, entityType domain.EntityType, idList []int64) map[int64]*domain.Points {
+	result := make(map[int64]*domain.Points)
+	if len(idList) == 0 {
+		return result
+	}
+
+	points := usecase.pointsRepo.GetList(ctx, ctx.UserId(), entityType, idList)
+	for i := 0; i < len(points); i++ {
+		result[points[i].Id] = &points[i]
+	}
+	return result
 }
 
 func (usecase *getPlan) validate(id int) *core.AppError {
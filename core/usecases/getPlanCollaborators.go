@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetPlanCollaborators returns collaborators and not accepted invitations of plan.
+// List is available to owner and collaborators of plan
+type GetPlanCollaborators interface {
+	Do(ctx core.ReqContext, planId int) ([]domain.PlanCollaborator, error)
+}
+
+type getPlanCollaborators struct {
+	collaboratorRepo core.PlanCollaboratorRepository
+	planRepo         core.PlanRepository
+	log              core.AppLogger
+}
+
+func NewGetPlanCollaborators(collaboratorRepo core.PlanCollaboratorRepository, planRepo core.PlanRepository, log core.AppLogger) GetPlanCollaborators {
+	return &getPlanCollaborators{collaboratorRepo: collaboratorRepo, planRepo: planRepo, log: log}
+}
+
+func (usecase *getPlanCollaborators) Do(ctx core.ReqContext, planId int) ([]domain.PlanCollaborator, error) {
+	trace := ctx.StartTrace("getPlanCollaborators")
+	defer ctx.StopTrace(trace)
+
+	plan := usecase.planRepo.GetWithDraft(ctx, planId, ctx.UserId())
+	if plan == nil {
+		appErr := core.ValidationError(map[string]string{"id": core.NotExists.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	if !core.IsAllowed(ctx, domain.PlanEdit, plan.OwnerId) {
+		if c := usecase.collaboratorRepo.Get(ctx, plan.Id, ctx.UserId()); c == nil || !c.Accepted {
+			usecase.log.Errorw("access denied",
+				"reqid", ctx.ReqId(),
+				"UserId", ctx.UserId(),
+			)
+			return nil, core.NewError(core.AccessDenied)
+		}
+	}
+
+	return usecase.collaboratorRepo.GetByPlan(ctx, plan.Id), nil
+}
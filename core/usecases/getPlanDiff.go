@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetPlanDiff compares fork with the plan it was forked from
+type GetPlanDiff interface {
+	Do(ctx core.ReqContext, id int) (*domain.PlanDiff, error)
+}
+
+type getPlanDiff struct {
+	planRepo core.PlanRepository
+	stepRepo core.StepRepository
+	differ   core.PlanDiffer
+	log      core.AppLogger
+}
+
+func NewGetPlanDiff(planRepo core.PlanRepository, stepRepo core.StepRepository, differ core.PlanDiffer, log core.AppLogger) GetPlanDiff {
+	return &getPlanDiff{planRepo: planRepo, stepRepo: stepRepo, differ: differ, log: log}
+}
+
+func (usecase *getPlanDiff) Do(ctx core.ReqContext, id int) (*domain.PlanDiff, error) {
+	trace := ctx.StartTrace("getPlanDiff")
+	defer ctx.StopTrace(trace)
+
+	if id <= 0 {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	fork := usecase.planRepo.GetWithDraft(ctx, id, ctx.UserId())
+	if fork == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	if fork.ParentId == 0 {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "plan is not a fork",
+		)
+		return nil, appErr
+	}
+
+	upstream := usecase.planRepo.GetWithDraft(ctx, fork.ParentId, ctx.UserId())
+	if upstream == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	fork.Steps = usecase.stepRepo.GetByPlan(ctx, fork.Id)
+	upstream.Steps = usecase.stepRepo.GetByPlan(ctx, upstream.Id)
+	diff, err := usecase.differ.Diff(upstream, fork)
+	if err != nil {
+		usecase.log.Errorw("plans not compared",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return nil, core.NewError(core.InternalError)
+	}
+
+	return &domain.PlanDiff{Plan: fork, Upstream: upstream, Diff: diff}, nil
+}
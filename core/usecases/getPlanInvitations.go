@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetPlanInvitations returns not accepted invitations of current user to collaborate on plans
+type GetPlanInvitations interface {
+	Do(ctx core.ReqContext) ([]domain.PlanCollaborator, error)
+}
+
+type getPlanInvitations struct {
+	collaboratorRepo core.PlanCollaboratorRepository
+	log              core.AppLogger
+}
+
+func NewGetPlanInvitations(collaboratorRepo core.PlanCollaboratorRepository, log core.AppLogger) GetPlanInvitations {
+	return &getPlanInvitations{collaboratorRepo: collaboratorRepo, log: log}
+}
+
+func (usecase *getPlanInvitations) Do(ctx core.ReqContext) ([]domain.PlanCollaborator, error) {
+	trace := ctx.StartTrace("getPlanInvitations")
+	defer ctx.StopTrace(trace)
+
+	if ctx.UserId() == "" {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	return usecase.collaboratorRepo.GetInvitations(ctx, ctx.UserId()), nil
+}
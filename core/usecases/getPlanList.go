@@ -6,7 +6,8 @@ import (
 )
 
 type GetPlanList interface {
-	Do(ctx core.ReqContext, topicName string, count int) ([]domain.Plan, error)
+	// Do returns plans of topic in order of ranking algorithm, rating by default
+	Do(ctx core.ReqContext, topicName string, sort domain.PlanSort, count int) ([]domain.Plan, error)
 }
 
 func NewGetPlanList(plans core.PlanRepository, users core.UserRepository, logger core.AppLogger) GetPlanList {
@@ -23,11 +24,15 @@ type getPlanList struct {
 	log      core.AppLogger
 }
 
-func (usecase *getPlanList) Do(ctx core.ReqContext, topicName string, count int) ([]domain.Plan, error) {
+func (usecase *getPlanList) Do(ctx core.ReqContext, topicName string, sort domain.PlanSort, count int) ([]domain.Plan, error) {
 	trace := ctx.StartTrace("getPlanList")
 	defer ctx.StopTrace(trace)
 
-	appErr := usecase.validate(topicName, count)
+	if sort == "" {
+		sort = domain.PlanSortRating
+	}
+
+	appErr := usecase.validate(topicName, sort, count)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -36,7 +41,7 @@ func (usecase *getPlanList) Do(ctx core.ReqContext, topicName string, count int)
 		return nil, appErr
 	}
 
-	list := usecase.planRepo.GetPopularByTopic(ctx, topicName, count)
+	list := usecase.planRepo.GetPopularByTopic(ctx, topicName, sort, count)
 
 	for i := 0; i < len(list); i++ {
 		list[i].Owner = usecase.userRepo.Get(ctx, list[i].OwnerId)
@@ -44,12 +49,16 @@ func (usecase *getPlanList) Do(ctx core.ReqContext, topicName string, count int)
 	return list, nil
 }
 
-func (usecase *getPlanList) validate(topicName string, count int) *core.AppError {
+func (usecase *getPlanList) validate(topicName string, sort domain.PlanSort, count int) *core.AppError {
 	errors := make(map[string]string)
 	if !core.IsValidTopicName(topicName) {
 		errors["topicName"] = core.InvalidFormat.String()
 	}
 
+	if !sort.IsValid() {
+		errors["sort"] = core.InvalidValue.String()
+	}
+
 	if count <= 0 {
 		errors["count"] = core.InvalidCount.String()
 	}
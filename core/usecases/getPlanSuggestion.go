@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetPlanSuggestion returns suggestion with difference between current plan and proposed changes.
+// Suggestion is visible to its proposer and to users allowed to edit the plan
+type GetPlanSuggestion interface {
+	Do(ctx core.ReqContext, id int64) (*domain.PlanSuggestion, error)
+}
+
+type getPlanSuggestion struct {
+	suggestionRepo core.PlanSuggestionRepository
+	planRepo       core.PlanRepository
+	stepRepo       core.StepRepository
+	differ         core.PlanDiffer
+	log            core.AppLogger
+}
+
+func NewGetPlanSuggestion(suggestionRepo core.PlanSuggestionRepository, planRepo core.PlanRepository, stepRepo core.StepRepository, differ core.PlanDiffer, log core.AppLogger) GetPlanSuggestion {
+	return &getPlanSuggestion{suggestionRepo: suggestionRepo, planRepo: planRepo, stepRepo: stepRepo, differ: differ, log: log}
+}
+
+func (usecase *getPlanSuggestion) Do(ctx core.ReqContext, id int64) (*domain.PlanSuggestion, error) {
+	trace := ctx.StartTrace("getPlanSuggestion")
+	defer ctx.StopTrace(trace)
+
+	suggestion := usecase.suggestionRepo.Get(ctx, id)
+	if suggestion == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	plan := usecase.planRepo.GetWithDraft(ctx, suggestion.PlanId, ctx.UserId())
+	if plan == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	if suggestion.UserId != ctx.UserId() && !core.IsAllowed(ctx, domain.PlanEdit, plan.OwnerId) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	plan.Steps = usecase.stepRepo.GetByPlan(ctx, plan.Id)
+	proposed := *plan
+	proposed.Title = suggestion.Title
+	proposed.Steps = suggestion.Steps
+
+	diff, err := usecase.differ.Diff(plan, &proposed)
+	if err != nil {
+		usecase.log.Errorw("suggestion not compared",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return nil, core.NewError(core.InternalError)
+	}
+	suggestion.Diff = diff
+	return suggestion, nil
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetPlanSuggestions returns pending suggestions for plans of current user, newest first.
+// planId 0 means suggestions for all plans of user
+type GetPlanSuggestions interface {
+	Do(ctx core.ReqContext, planId int, count int, page int) ([]domain.PlanSuggestion, error)
+}
+
+type getPlanSuggestions struct {
+	suggestionRepo core.PlanSuggestionRepository
+	log            core.AppLogger
+}
+
+func NewGetPlanSuggestions(suggestionRepo core.PlanSuggestionRepository, log core.AppLogger) GetPlanSuggestions {
+	return &getPlanSuggestions{suggestionRepo: suggestionRepo, log: log}
+}
+
+func (usecase *getPlanSuggestions) Do(ctx core.ReqContext, planId int, count int, page int) ([]domain.PlanSuggestion, error) {
+	trace := ctx.StartTrace("getPlanSuggestions")
+	defer ctx.StopTrace(trace)
+
+	if ctx.UserId() == "" {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validatePaging(count, page)
+	if planId < 0 {
+		appErr = core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+	}
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	return usecase.suggestionRepo.GetPending(ctx, ctx.UserId(), planId, count, page), nil
+}
@@ -76,10 +76,12 @@ func (usecase *getPlanTree) Do(ctx core.ReqContext, ids []int) ([]TreeNode, erro
 				t := usecase.topicRepo.GetById(ctx, int(plans[i].Steps[j].ReferenceId))
 				if t != nil {
 					if userFavorits[t.Name] != 0 {
-						plan := usecase.planRepo.Get(ctx, userFavorits[t.Name])
-						t.Plans = []domain.Plan{*plan}
-					} else {
-						t.Plans = usecase.planRepo.GetPopularByTopic(ctx, t.Name, 1)
+						if plan := usecase.planRepo.Get(ctx, userFavorits[t.Name]); plan != nil {
+							t.Plans = []domain.Plan{*plan}
+						}
+					}
+					if len(t.Plans) == 0 {
+						t.Plans = usecase.planRepo.GetPopularByTopic(ctx, t.Name, domain.PlanSortRating, 1)
 					}
 					chPlanId := -1
 					chPlanTitle := ""
@@ -134,7 +136,7 @@ func (usecase *getPlanTree) DoByTopic(ctx core.ReqContext, name string) ([]TreeN
 		p := usecase.planRepo.Get(ctx, up.PlanId)
 		topic.Plans = []domain.Plan{*p}
 	} else {
-		topic.Plans = usecase.planRepo.GetPopularByTopic(ctx, topic.Name, 1)
+		topic.Plans = usecase.planRepo.GetPopularByTopic(ctx, topic.Name, domain.PlanSortRating, 1)
 	}
 
 	if len(topic.Plans) == 0 {
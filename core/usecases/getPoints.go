@@ -41,7 +41,7 @@ func (usecase *getPoints) Do(ctx core.ReqContext, entityType domain.EntityType,
 func (usecase *getPoints) validate(entityType domain.EntityType, id int64) *core.AppError {
 	errors := make(map[string]string)
 
-	if !core.IsValidEntityType(entityType) {
+	if !entityType.IsVotable() {
 		errors["type"] = core.InvalidValue.String()
 	}
 
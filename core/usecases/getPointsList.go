@@ -41,7 +41,7 @@ func (usecase *getPointsList) Do(ctx core.ReqContext, entityType domain.EntityTy
 func (usecase *getPointsList) validate(entityType domain.EntityType, idList []int64) *core.AppError {
 	errors := make(map[string]string)
 
-	if !core.IsValidEntityType(entityType) {
+	if !entityType.IsVotable() {
 		errors["type"] = core.InvalidValue.String()
 	}
 
@@ -10,14 +10,16 @@ type GetProject interface {
 }
 
 type getProject struct {
-	projectRepo core.ProjectsRepository
-	log         core.AppLogger
+	projectRepo  core.ProjectsRepository
+	commentsRepo core.CommentsRepository
+	log          core.AppLogger
 }
 
-func NewGetProject(projectRepo core.ProjectsRepository, log core.AppLogger) GetProject {
+func NewGetProject(projectRepo core.ProjectsRepository, commentsRepo core.CommentsRepository, log core.AppLogger) GetProject {
 	return &getProject{
-		projectRepo: projectRepo,
-		log:         log,
+		projectRepo:  projectRepo,
+		commentsRepo: commentsRepo,
+		log:          log,
 	}
 }
 
@@ -38,6 +40,7 @@ func (usecase *getProject) Do(ctx core.ReqContext, id int) (*domain.Project, err
 	if p == nil {
 		return nil, core.NewError(core.NotExists)
 	}
+	p.Comments = usecase.commentsRepo.Count(ctx, domain.ProjectEntity, []int64{int64(p.Id)})[int64(p.Id)]
 
 	return p, nil
 }
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetReports returns moderation queue, reports with more complaints go first
+type GetReports interface {
+	Do(ctx core.ReqContext, entityType domain.EntityType, count int, page int) ([]domain.Report, error)
+}
+
+type getReports struct {
+	reportRepo core.ReportRepository
+	log        core.AppLogger
+}
+
+func NewGetReports(reportRepo core.ReportRepository, log core.AppLogger) GetReports {
+	return &getReports{reportRepo: reportRepo, log: log}
+}
+
+func (usecase *getReports) Do(ctx core.ReqContext, entityType domain.EntityType, count int, page int) ([]domain.Report, error) {
+	trace := ctx.StartTrace("getReports")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ReportModerate) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validatePaging(count, page)
+	if entityType != 0 && !entityType.IsValid() {
+		appErr = core.ValidationError(map[string]string{"type": core.InvalidValue.String()})
+	}
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	return usecase.reportRepo.GetOpen(ctx, entityType, count, page), nil
+}
+
+func validatePaging(count int, page int) *core.AppError {
+	errors := make(map[string]string)
+
+	if count <= 0 || count > 100 {
+		errors["count"] = core.InvalidCount.String()
+	}
+
+	if page < 0 {
+		errors["page"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
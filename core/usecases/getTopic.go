@@ -14,11 +14,13 @@ type getTopic struct {
 	topicRepo core.TopicRepository
 	planRepo  core.PlanRepository
 	usersPlan core.UsersPlanRepository
+	comments  core.CommentsRepository
+	points    core.PointsRepository
 	log       core.AppLogger
 }
 
-func NewGetTopic(topicRepo core.TopicRepository, planRepo core.PlanRepository, userPlans core.UsersPlanRepository, log core.AppLogger) GetTopic {
-	return &getTopic{topicRepo: topicRepo, planRepo: planRepo, usersPlan: userPlans, log: log}
+func NewGetTopic(topicRepo core.TopicRepository, planRepo core.PlanRepository, userPlans core.UsersPlanRepository, commentsRepo core.CommentsRepository, pointsRepo core.PointsRepository, log core.AppLogger) GetTopic {
+	return &getTopic{topicRepo: topicRepo, planRepo: planRepo, usersPlan: userPlans, comments: commentsRepo, points: pointsRepo, log: log}
 }
 
 func (usecase *getTopic) Do(ctx core.ReqContext, name string, planCount int) (*domain.Topic, error) {
@@ -38,6 +40,8 @@ func (usecase *getTopic) Do(ctx core.ReqContext, name string, planCount int) (*d
 	if topic == nil {
 		return nil, core.NewError(core.NotExists)
 	}
+	topic.Comments = usecase.comments.Count(ctx, domain.TopicEntity, []int64{int64(topic.Id)})[int64(topic.Id)]
+	topic.Points = usecase.points.Get(ctx, ctx.UserId(), domain.TopicEntity, int64(topic.Id))
 	return topic, nil
 }
 
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+)
+
+// GetUnreadCount returns count of unread notifications of current user
+type GetUnreadCount interface {
+	Do(ctx core.ReqContext) (int, error)
+}
+
+type getUnreadCount struct {
+	notificationRepo core.NotificationRepository
+	log              core.AppLogger
+}
+
+func NewGetUnreadCount(notificationRepo core.NotificationRepository, log core.AppLogger) GetUnreadCount {
+	return &getUnreadCount{notificationRepo: notificationRepo, log: log}
+}
+
+func (usecase *getUnreadCount) Do(ctx core.ReqContext) (int, error) {
+	trace := ctx.StartTrace("getUnreadCount")
+	defer ctx.StopTrace(trace)
+
+	count, ok := usecase.notificationRepo.UnreadCount(ctx, ctx.UserId())
+	if !ok {
+		return 0, core.NewError(core.InternalError)
+	}
+	return count, nil
+}
@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetUserReports returns reports of current user with outcome of moderation
+type GetUserReports interface {
+	Do(ctx core.ReqContext, count int, page int) ([]domain.Report, error)
+}
+
+type getUserReports struct {
+	reportRepo core.ReportRepository
+	log        core.AppLogger
+}
+
+func NewGetUserReports(reportRepo core.ReportRepository, log core.AppLogger) GetUserReports {
+	return &getUserReports{reportRepo: reportRepo, log: log}
+}
+
+func (usecase *getUserReports) Do(ctx core.ReqContext, count int, page int) ([]domain.Report, error) {
+	trace := ctx.StartTrace("getUserReports")
+	defer ctx.StopTrace(trace)
+
+	appErr := validatePaging(count, page)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	return usecase.reportRepo.GetByUser(ctx, ctx.UserId(), count, page), nil
+}
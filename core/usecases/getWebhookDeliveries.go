@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetWebhookDeliveries returns delivery log of webhook, newest deliveries go first
+type GetWebhookDeliveries interface {
+	Do(ctx core.ReqContext, webhookId int64, count int, page int) ([]domain.WebhookDelivery, error)
+}
+
+type getWebhookDeliveries struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewGetWebhookDeliveries(webhookRepo core.WebhookRepository, log core.AppLogger) GetWebhookDeliveries {
+	return &getWebhookDeliveries{webhookRepo: webhookRepo, log: log}
+}
+
+func (usecase *getWebhookDeliveries) Do(ctx core.ReqContext, webhookId int64, count int, page int) ([]domain.WebhookDelivery, error) {
+	trace := ctx.StartTrace("getWebhookDeliveries")
+	defer ctx.StopTrace(trace)
+
+	if appErr := validatePaging(count, page); appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	if _, appErr := getOwnWebhook(ctx, usecase.webhookRepo, webhookId, usecase.log); appErr != nil {
+		return nil, appErr
+	}
+
+	return usecase.webhookRepo.GetDeliveries(ctx, webhookId, count, page), nil
+}
@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// GetWebhooks returns webhooks of current user
+type GetWebhooks interface {
+	Do(ctx core.ReqContext) ([]domain.Webhook, error)
+}
+
+type getWebhooks struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewGetWebhooks(webhookRepo core.WebhookRepository, log core.AppLogger) GetWebhooks {
+	return &getWebhooks{webhookRepo: webhookRepo, log: log}
+}
+
+func (usecase *getWebhooks) Do(ctx core.ReqContext) ([]domain.Webhook, error) {
+	trace := ctx.StartTrace("getWebhooks")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.WebhookOwn) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	return usecase.webhookRepo.GetByUser(ctx, ctx.UserId()), nil
+}
@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type GrantRole interface {
+	Do(ctx core.ReqContext, userId string, roleId int) (bool, error)
+}
+
+type grantRole struct {
+	roleRepo  core.RoleRepository
+	userRepo  core.UserRepository
+	policy    core.AccessPolicy
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewGrantRole(roleRepo core.RoleRepository, userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger) GrantRole {
+	return &grantRole{roleRepo: roleRepo, userRepo: userRepo, policy: policy, changeLog: changeLog, log: log}
+}
+
+func (usecase *grantRole) Do(ctx core.ReqContext, userId string, roleId int) (bool, error) {
+	trace := ctx.StartTrace("grantRole")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.RoleManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validateUserRole(ctx, usecase.roleRepo, usecase.userRepo, userId, roleId)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	before := roleNames(usecase.roleRepo.GetByUser(ctx, userId))
+	changed, err := usecase.roleRepo.AddToUser(ctx, userId, roleId)
+	if err != nil {
+		usecase.log.Errorw("Role not granted",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+
+	usecase.policy.ResetUser(userId)
+	if changed {
+		logUserRoles(ctx, usecase.changeLog, usecase.roleRepo, usecase.userRepo, userId, before)
+	}
+	return changed, nil
+}
+
+func validateUserRole(ctx core.ReqContext, roleRepo core.RoleRepository, userRepo core.UserRepository, userId string, roleId int) *core.AppError {
+	errors := make(map[string]string)
+
+	if userId == "" {
+		errors["userId"] = core.InvalidValue.String()
+	} else if userRepo.Get(ctx, userId) == nil {
+		errors["userId"] = core.NotExists.String()
+	}
+
+	if roleId <= 0 {
+		errors["roleId"] = core.InvalidValue.String()
+	} else if role := roleRepo.Get(ctx, roleId); role == nil {
+		errors["roleId"] = core.NotExists.String()
+	} else if role.Name == domain.UserRole {
+		// granted implicitly, should not be stored
+		errors["roleId"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
+
+func logUserRoles(ctx core.ReqContext, changeLog core.ChangeLog, roleRepo core.RoleRepository, userRepo core.UserRepository, userId string, before []string) {
+	user := userRepo.Get(ctx, userId)
+	if user == nil {
+		return
+	}
+
+	old := *user
+	old.Roles = before
+	user.Roles = roleNames(roleRepo.GetByUser(ctx, userId))
+	// id of user is not a number, change log takes it from user as entity key
+	changeLog.Edited(ctx, domain.UserEntity, 0, &old, user)
+}
+
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.Name
+	}
+	return names
+}
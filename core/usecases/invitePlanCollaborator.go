@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// InvitePlanCollaborator invites user by name to edit plan or to see its draft.
+// Invitation of existed collaborator changes its role
+type InvitePlanCollaborator interface {
+	Do(ctx core.ReqContext, planId int, userName string, role domain.CollaboratorRole) (*domain.PlanCollaborator, error)
+}
+
+type invitePlanCollaborator struct {
+	collaboratorRepo core.PlanCollaboratorRepository
+	planRepo         core.PlanRepository
+	userRepo         core.UserRepository
+	log              core.AppLogger
+}
+
+func NewInvitePlanCollaborator(collaboratorRepo core.PlanCollaboratorRepository, planRepo core.PlanRepository, userRepo core.UserRepository, log core.AppLogger) InvitePlanCollaborator {
+	return &invitePlanCollaborator{collaboratorRepo: collaboratorRepo, planRepo: planRepo, userRepo: userRepo, log: log}
+}
+
+func (usecase *invitePlanCollaborator) Do(ctx core.ReqContext, planId int, userName string, role domain.CollaboratorRole) (*domain.PlanCollaborator, error) {
+	trace := ctx.StartTrace("invitePlanCollaborator")
+	defer ctx.StopTrace(trace)
+
+	plan := usecase.planRepo.GetWithDraft(ctx, planId, ctx.UserId())
+	var user *domain.User
+	if core.IsValidUserName(userName) {
+		if users := usecase.userRepo.FindByNames(ctx, []string{userName}); len(users) > 0 {
+			user = &users[0]
+		}
+	}
+
+	appErr := usecase.validate(ctx, role, plan, user)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	// editors do not invite other users, only owner decides who works on plan
+	if !core.IsAllowed(ctx, domain.PlanEdit, plan.OwnerId) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	collaborator := &domain.PlanCollaborator{
+		PlanId:    plan.Id,
+		UserId:    user.Id,
+		UserName:  user.Name,
+		Role:      role,
+		InvitedBy: ctx.UserId(),
+	}
+	if ok, err := usecase.collaboratorRepo.Save(ctx, collaborator); !ok {
+		if err != nil {
+			usecase.log.Errorw("collaborator not saved",
+				"reqid", ctx.ReqId(),
+				"error", err.Error(),
+			)
+		}
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+func (usecase *invitePlanCollaborator) validate(ctx core.ReqContext, role domain.CollaboratorRole, plan *domain.Plan, user *domain.User) *core.AppError {
+	errors := make(map[string]string)
+	if !role.IsValid() {
+		errors["role"] = core.InvalidValue.String()
+	}
+
+	if plan == nil {
+		errors["id"] = core.NotExists.String()
+	}
+
+	if user == nil {
+		errors["name"] = core.NotExists.String()
+	} else if plan != nil && user.Id == plan.OwnerId {
+		errors["name"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
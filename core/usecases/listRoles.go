@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type ListRoles interface {
+	Do(ctx core.ReqContext) ([]domain.Role, error)
+}
+
+type listRoles struct {
+	roleRepo core.RoleRepository
+	log      core.AppLogger
+}
+
+func NewListRoles(roleRepo core.RoleRepository, log core.AppLogger) ListRoles {
+	return &listRoles{roleRepo: roleRepo, log: log}
+}
+
+func (usecase *listRoles) Do(ctx core.ReqContext) ([]domain.Role, error) {
+	trace := ctx.StartTrace("listRoles")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.RoleManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	return usecase.roleRepo.GetList(ctx), nil
+}
@@ -58,6 +58,13 @@ func (usecase *loginUser) Do(ctx core.ReqContext, email, password, fingerprint,
 		return nil, "", "", core.NewError(core.AuthenticationError)
 	}
 
+	if user.IsBanned() {
+		usecase.log.Infow("User banned",
+			"reqid", ctx.ReqId(),
+			"email", email)
+		return nil, "", "", core.NewError(core.UserBanned)
+	}
+
 	trace.Point("validation")
 	aToken, rToken, err := usecase.tokenService.Create(ctx, user, fingerprint, useragent)
 	if err != nil {
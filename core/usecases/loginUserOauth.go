@@ -34,6 +34,14 @@ func (usecase *loginUserOauth) Do(ctx core.ReqContext, provider, openid, fingerp
 		return nil, "", "", core.NewError(core.AuthenticationError)
 	}
 
+	if user.IsBanned() {
+		usecase.log.Infow("User banned",
+			"reqid", ctx.ReqId(),
+			"provider", provider,
+			"openid", openid)
+		return nil, "", "", core.NewError(core.UserBanned)
+	}
+
 	aToken, rToken, err := usecase.tokenService.Create(ctx, user, fingerprint, useragent)
 	if err != nil {
 		usecase.log.Errorw("Fail to create token pair",
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// LogoutUser closes all sessions of user
+type LogoutUser interface {
+	Do(ctx core.ReqContext, userId string) (bool, error)
+}
+
+type logoutUser struct {
+	userRepo  core.UserRepository
+	policy    core.AccessPolicy
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewLogoutUser(userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger) LogoutUser {
+	return &logoutUser{userRepo: userRepo, policy: policy, changeLog: changeLog, log: log}
+}
+
+func (usecase *logoutUser) Do(ctx core.ReqContext, userId string) (bool, error) {
+	trace := ctx.StartTrace("logoutUser")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.UserManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	user := usecase.userRepo.Get(ctx, userId)
+	if user == nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "user not exists",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if len(user.Tokens) == 0 {
+		return false, nil
+	}
+
+	old := *user
+	user.Tokens = []domain.UserToken{}
+
+	return updateUser(ctx, usecase.userRepo, usecase.policy, usecase.changeLog, usecase.log, &old, user)
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+)
+
+// MarkNotificationsRead marks notifications of current user as read, all of them if no ids passed
+type MarkNotificationsRead interface {
+	Do(ctx core.ReqContext, ids []int64) (int64, error)
+}
+
+type markNotificationsRead struct {
+	notificationRepo core.NotificationRepository
+	log              core.AppLogger
+}
+
+func NewMarkNotificationsRead(notificationRepo core.NotificationRepository, log core.AppLogger) MarkNotificationsRead {
+	return &markNotificationsRead{notificationRepo: notificationRepo, log: log}
+}
+
+func (usecase *markNotificationsRead) Do(ctx core.ReqContext, ids []int64) (int64, error) {
+	trace := ctx.StartTrace("markNotificationsRead")
+	defer ctx.StopTrace(trace)
+
+	appErr := usecase.validate(ids)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return 0, appErr
+	}
+
+	marked, err := usecase.notificationRepo.MarkRead(ctx, ctx.UserId(), ids)
+	if err != nil {
+		usecase.log.Errorw("Notifications not marked",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return 0, err
+	}
+	return marked, nil
+}
+
+func (usecase *markNotificationsRead) validate(ids []int64) *core.AppError {
+	errors := make(map[string]string)
+
+	if len(ids) > 100 {
+		errors["ids"] = core.InvalidCount.String()
+	}
+
+	for _, id := range ids {
+		if id <= 0 {
+			errors["ids"] = core.InvalidValue.String()
+			break
+		}
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type PinComment interface {
+	Do(ctx core.ReqContext, id int64, pinned bool) (bool, error)
+}
+
+type pinComment struct {
+	commentsRepo core.CommentsRepository
+	planRepo     core.PlanRepository
+	changeLog    core.ChangeLog
+	broadcaster  core.Broadcaster
+	log          core.AppLogger
+}
+
+func NewPinComment(commentsRepo core.CommentsRepository, planRepo core.PlanRepository, changeLog core.ChangeLog, broadcaster core.Broadcaster, log core.AppLogger) PinComment {
+	return &pinComment{commentsRepo: commentsRepo, planRepo: planRepo, changeLog: changeLog, broadcaster: broadcaster, log: log}
+}
+
+func (usecase *pinComment) Do(ctx core.ReqContext, id int64, pinned bool) (bool, error) {
+	trace := ctx.StartTrace("pinComment")
+	defer ctx.StopTrace(trace)
+
+	userId := ctx.UserId()
+	comment := usecase.commentsRepo.Get(ctx, id)
+	if comment == nil || comment.Deleted || comment.ThreadId > 0 {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+			"error", fmt.Sprintf("thread deleted or not existed. id: %v", id),
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if !canManageThread(ctx, usecase.planRepo, comment) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	if comment.Pinned == pinned {
+		return true, nil
+	}
+
+	if ok, err := usecase.commentsRepo.SetPinned(ctx, id, pinned); !ok {
+		if err != nil {
+			usecase.log.Errorw("invalid request",
+				"reqid", ctx.ReqId(),
+				"error", err.Error(),
+			)
+		}
+		return false, err
+	}
+
+	changedComment := *comment
+	changedComment.Pinned = pinned
+	usecase.changeLog.Edited(ctx, domain.CommentEntity, comment.Id, comment, &changedComment)
+	usecase.broadcaster.CommentEdited(ctx, &changedComment)
+	return true, nil
+}
+
+// canManageThread allows moderators and owner of the plan to pin threads and accept answers
+func canManageThread(ctx core.ReqContext, planRepo core.PlanRepository, thread *domain.Comment) bool {
+	ownerId := ""
+	if thread.EntityType == domain.PlanEntity {
+		if plan := planRepo.Get(ctx, int(thread.EntityId)); plan != nil {
+			ownerId = plan.OwnerId
+		}
+	}
+	return core.IsAllowed(ctx, domain.CommentModerate, ownerId)
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RedeliverWebhook queues payload of delivery once again, original delivery stays in log as is
+type RedeliverWebhook interface {
+	Do(ctx core.ReqContext, deliveryId int64) (*domain.WebhookDelivery, error)
+}
+
+type redeliverWebhook struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewRedeliverWebhook(webhookRepo core.WebhookRepository, log core.AppLogger) RedeliverWebhook {
+	return &redeliverWebhook{webhookRepo: webhookRepo, log: log}
+}
+
+func (usecase *redeliverWebhook) Do(ctx core.ReqContext, deliveryId int64) (*domain.WebhookDelivery, error) {
+	trace := ctx.StartTrace("redeliverWebhook")
+	defer ctx.StopTrace(trace)
+
+	if deliveryId <= 0 {
+		return nil, core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+	}
+
+	delivery := usecase.webhookRepo.GetDelivery(ctx, deliveryId)
+	if delivery == nil {
+		return nil, core.NewError(core.NotExists)
+	}
+
+	if _, appErr := getOwnWebhook(ctx, usecase.webhookRepo, delivery.WebhookId, usecase.log); appErr != nil {
+		return nil, appErr
+	}
+
+	redelivery, appErr := usecase.webhookRepo.Redeliver(ctx, deliveryId)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return redelivery, nil
+}
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"strings"
+	"time"
 
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
@@ -14,20 +15,21 @@ type RegisterUser interface {
 }
 
 type registerUser struct {
-	userRepo     core.UserRepository
-	log          core.AppLogger
-	hash         core.HashProvider
-	emailService core.EmailSender
-	imgManage    core.ImageManager
+	userRepo  core.UserRepository
+	log       core.AppLogger
+	hash      core.HashProvider
+	imgManage core.ImageManager
+	// confirmation link lifetime
+	lifetime time.Duration
 }
 
-func NewRegisterUser(userRepo core.UserRepository, emailService core.EmailSender, hash core.HashProvider, imgManager core.ImageManager, log core.AppLogger) RegisterUser {
+func NewRegisterUser(userRepo core.UserRepository, hash core.HashProvider, imgManager core.ImageManager, confirmationLifetime time.Duration, log core.AppLogger) RegisterUser {
 	return &registerUser{
-		userRepo:     userRepo,
-		emailService: emailService,
-		hash:         hash,
-		imgManage:    imgManager,
-		log:          log,
+		userRepo:  userRepo,
+		hash:      hash,
+		imgManage: imgManager,
+		lifetime:  confirmationLifetime,
+		log:       log,
 	}
 }
 
@@ -70,18 +72,22 @@ func (usecase *registerUser) Do(ctx core.ReqContext, name string, email string,
 
 	hash, salt := usecase.hash.HashPassword(password)
 	user := &domain.User{
-		Id:                name,
-		Name:              name,
-		NormalizedName:    strings.ToUpper(name),
-		Email:             email,
-		Rights:            domain.U,
-		Pass:              hash,
-		Salt:              salt,
-		Img:               avatarName,
-		EmailConfirmation: uuid.New().String(),
+		Id:             name,
+		Name:           name,
+		NormalizedName: strings.ToUpper(name),
+		Email:          email,
+		Rights:         domain.U,
+		Pass:           hash,
+		Salt:           salt,
+		Img:            avatarName,
 	}
+	user.NewEmailConfirmation(uuid.New().String(), usecase.lifetime)
 
-	if _, err := usecase.userRepo.Save(ctx, user); err != nil {
+	// confirmation email is sent in background, user id is added to email by repository
+	confirmation := domain.NewEmail(email, domain.RegistrationEmail, ctx.Language(), map[string]string{
+		"Secret": user.EmailConfirmation,
+	})
+	if _, err := usecase.userRepo.SaveWithEmail(ctx, user, confirmation); err != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
 			"error", err.Error(),
@@ -101,8 +107,6 @@ func (usecase *registerUser) Do(ctx core.ReqContext, name string, email string,
 		}
 	}
 
-	go usecase.emailService.Registration(email, user.Id, user.EmailConfirmation)
-
 	return user, nil
 }
 
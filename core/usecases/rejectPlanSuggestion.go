@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RejectPlanSuggestion closes suggestion without changes of plan, comment explains the decision to proposer
+type RejectPlanSuggestion interface {
+	Do(ctx core.ReqContext, id int64, comment string) (bool, error)
+}
+
+type rejectPlanSuggestion struct {
+	suggestionRepo core.PlanSuggestionRepository
+	planRepo       core.PlanRepository
+	log            core.AppLogger
+}
+
+func NewRejectPlanSuggestion(suggestionRepo core.PlanSuggestionRepository, planRepo core.PlanRepository, log core.AppLogger) RejectPlanSuggestion {
+	return &rejectPlanSuggestion{suggestionRepo: suggestionRepo, planRepo: planRepo, log: log}
+}
+
+func (usecase *rejectPlanSuggestion) Do(ctx core.ReqContext, id int64, comment string) (bool, error) {
+	trace := ctx.StartTrace("rejectPlanSuggestion")
+	defer ctx.StopTrace(trace)
+
+	if !core.IsValidReportText(comment) {
+		appErr := core.ValidationError(map[string]string{"comment": core.InvalidFormat.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	suggestion, _, appErr := getSuggestionForReview(ctx, usecase.suggestionRepo, usecase.planRepo, id)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	suggestion.Status = domain.SuggestionRejected
+	suggestion.ReviewedBy = ctx.UserId()
+	suggestion.Comment = comment
+	reviewed, appErr := usecase.suggestionRepo.Review(ctx, suggestion)
+	if appErr != nil {
+		return false, appErr
+	}
+	return reviewed, nil
+}
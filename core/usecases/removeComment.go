@@ -15,10 +15,11 @@ type removeComment struct {
 	commentsRepo core.CommentsRepository
 	log          core.AppLogger
 	changeLog    core.ChangeLog
+	broadcaster  core.Broadcaster
 }
 
-func NewRemoveComments(commentsRepo core.CommentsRepository, changeLog core.ChangeLog, log core.AppLogger) RemoveComment {
-	return &removeComment{commentsRepo: commentsRepo, changeLog: changeLog, log: log}
+func NewRemoveComments(commentsRepo core.CommentsRepository, changeLog core.ChangeLog, broadcaster core.Broadcaster, log core.AppLogger) RemoveComment {
+	return &removeComment{commentsRepo: commentsRepo, changeLog: changeLog, broadcaster: broadcaster, log: log}
 }
 
 func (usecase removeComment) Do(ctx core.ReqContext, id int64) (bool, error) {
@@ -27,7 +28,7 @@ func (usecase removeComment) Do(ctx core.ReqContext, id int64) (bool, error) {
 
 	userId := ctx.UserId()
 	comment := usecase.commentsRepo.Get(ctx, id)
-	if comment == nil || comment.UserId != userId {
+	if comment == nil || !core.IsAllowed(ctx, domain.CommentModerate, comment.UserId) {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
 			"UserId", userId,
@@ -36,11 +37,18 @@ func (usecase removeComment) Do(ctx core.ReqContext, id int64) (bool, error) {
 		return false, core.NewError(core.AccessDenied)
 	}
 
-	ok, err := usecase.commentsRepo.Delete(ctx, id)
+	ok, err := usecase.commentsRepo.Delete(ctx, id, userId)
 	if ok {
-		changedComment := *comment
-		changedComment.Deleted = true
-		usecase.changeLog.Edited(domain.CommentEntity, comment.Id, userId, comment, &changedComment)
+		// deleted reply is not an answer anymore
+		if comment.ThreadId > 0 {
+			if thread := usecase.commentsRepo.Get(ctx, comment.ThreadId); thread != nil && thread.AnswerId == comment.Id {
+				usecase.commentsRepo.SetAnswer(ctx, thread.Id, 0)
+			}
+		}
+		usecase.changeLog.Deleted(ctx, domain.CommentEntity, comment.Id, comment)
+		deleted := *comment
+		deleted.DeletedBy = userId
+		usecase.broadcaster.CommentDeleted(ctx, &deleted)
 	}
 	return ok, err
 }
@@ -10,13 +10,15 @@ type RemovePlan interface {
 }
 
 type removePlan struct {
-	repo      core.PlanRepository
-	log       core.AppLogger
-	changeLog core.ChangeLog
+	repo             core.PlanRepository
+	collaboratorRepo core.PlanCollaboratorRepository
+	stepsRepo        core.StepRepository
+	log              core.AppLogger
+	changeLog        core.ChangeLog
 }
 
-func NewRemovePlan(planRepo core.PlanRepository, changeLog core.ChangeLog, log core.AppLogger) RemovePlan {
-	return &removePlan{repo: planRepo, changeLog: changeLog, log: log}
+func NewRemovePlan(planRepo core.PlanRepository, collaboratorRepo core.PlanCollaboratorRepository, stepsRepo core.StepRepository, changeLog core.ChangeLog, log core.AppLogger) RemovePlan {
+	return &removePlan{repo: planRepo, collaboratorRepo: collaboratorRepo, stepsRepo: stepsRepo, changeLog: changeLog, log: log}
 }
 
 func (usecase *removePlan) Do(ctx core.ReqContext, id int) (bool, error) {
@@ -24,8 +26,7 @@ func (usecase *removePlan) Do(ctx core.ReqContext, id int) (bool, error) {
 	defer ctx.StopTrace(trace)
 
 	plan := usecase.repo.GetWithDraft(ctx, id, ctx.UserId())
-	userId := ctx.UserId()
-	appErr := usecase.validate(id, userId, plan)
+	appErr := usecase.validate(ctx, id, plan)
 	if appErr != nil {
 		usecase.log.Errorw("invalid request",
 			"reqid", ctx.ReqId(),
@@ -40,12 +41,13 @@ func (usecase *removePlan) Do(ctx core.ReqContext, id int) (bool, error) {
 	}
 
 	if deleted {
-		usecase.changeLog.Deleted(domain.PlanEntity, int64(id), userId)
+		plan.Steps = usecase.stepsRepo.GetByPlan(ctx, id)
+		usecase.changeLog.Deleted(ctx, domain.PlanEntity, int64(id), plan)
 	}
 	return true, nil
 }
 
-func (usecase *removePlan) validate(id int, userid string, plan *domain.Plan) *core.AppError {
+func (usecase *removePlan) validate(ctx core.ReqContext, id int, plan *domain.Plan) *core.AppError {
 	errors := make(map[string]string)
 	if id <= 0 {
 		errors["id"] = core.InvalidFormat.String()
@@ -53,11 +55,12 @@ func (usecase *removePlan) validate(id int, userid string, plan *domain.Plan) *c
 
 	if plan == nil {
 		errors["id"] = core.NotExists.String()
-	}
-
-	if plan.OwnerId != userid {
+	} else if !core.IsAllowedOnPlan(ctx, domain.PlanRemove, plan, usecase.collaboratorRepo.Get(ctx, plan.Id, ctx.UserId())) {
 		errors["id"] = core.AccessDenied.String()
 	}
 
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
 	return nil
 }
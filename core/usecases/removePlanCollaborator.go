@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RemovePlanCollaborator takes rights on plan from collaborator or cancels invitation.
+// Owner removes anybody, collaborator leaves plan or declines invitation by removing self
+type RemovePlanCollaborator interface {
+	Do(ctx core.ReqContext, planId int, userId string) (bool, error)
+}
+
+type removePlanCollaborator struct {
+	collaboratorRepo core.PlanCollaboratorRepository
+	planRepo         core.PlanRepository
+	log              core.AppLogger
+}
+
+func NewRemovePlanCollaborator(collaboratorRepo core.PlanCollaboratorRepository, planRepo core.PlanRepository, log core.AppLogger) RemovePlanCollaborator {
+	return &removePlanCollaborator{collaboratorRepo: collaboratorRepo, planRepo: planRepo, log: log}
+}
+
+func (usecase *removePlanCollaborator) Do(ctx core.ReqContext, planId int, userId string) (bool, error) {
+	trace := ctx.StartTrace("removePlanCollaborator")
+	defer ctx.StopTrace(trace)
+
+	if userId != ctx.UserId() {
+		plan := usecase.planRepo.GetWithDraft(ctx, planId, ctx.UserId())
+		if plan == nil {
+			appErr := core.ValidationError(map[string]string{"id": core.NotExists.String()})
+			usecase.log.Errorw("invalid request",
+				"reqid", ctx.ReqId(),
+				"error", appErr.Error(),
+			)
+			return false, appErr
+		}
+
+		if !core.IsAllowed(ctx, domain.PlanEdit, plan.OwnerId) {
+			usecase.log.Errorw("access denied",
+				"reqid", ctx.ReqId(),
+				"UserId", ctx.UserId(),
+			)
+			return false, core.NewError(core.AccessDenied)
+		}
+	}
+
+	removed, err := usecase.collaboratorRepo.Remove(ctx, planId, userId)
+	if err != nil {
+		usecase.log.Errorw("collaborator not removed",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+	return removed, nil
+}
@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RemoveVote retracts vote made by user
+type RemoveVote interface {
+	Do(ctx core.ReqContext, entityType domain.EntityType, id int64) (bool, error)
+}
+
+type removeVote struct {
+	pointsRepo  core.PointsRepository
+	broadcaster core.Broadcaster
+	reputation  core.Reputation
+	log         core.AppLogger
+}
+
+func NewRemovePoints(pointsRepo core.PointsRepository, broadcaster core.Broadcaster, reputation core.Reputation, log core.AppLogger) RemoveVote {
+	return &removeVote{pointsRepo: pointsRepo, broadcaster: broadcaster, reputation: reputation, log: log}
+}
+
+func (usecase removeVote) Do(ctx core.ReqContext, entityType domain.EntityType, id int64) (bool, error) {
+	trace := ctx.StartTrace("removeVote")
+	defer ctx.StopTrace(trace)
+
+	errors := make(map[string]string)
+	if !entityType.IsVotable() {
+		errors["type"] = core.InvalidValue.String()
+	}
+	if id <= 0 {
+		errors["id"] = core.InvalidValue.String()
+	}
+	if len(errors) > 0 {
+		appErr := core.ValidationError(errors)
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	before, removed := usecase.pointsRepo.Remove(ctx, entityType, id, ctx.UserId())
+	if !removed {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+			"error", "vote not existed",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	usecase.broadcaster.PointsChanged(ctx, entityType, id)
+	usecase.reputation.VoteChanged(ctx, entityType, id, before, 0)
+	return true, nil
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type RemoveRole interface {
+	Do(ctx core.ReqContext, id int) (bool, error)
+}
+
+type removeRole struct {
+	roleRepo core.RoleRepository
+	policy   core.AccessPolicy
+	log      core.AppLogger
+}
+
+func NewRemoveRole(roleRepo core.RoleRepository, policy core.AccessPolicy, log core.AppLogger) RemoveRole {
+	return &removeRole{roleRepo: roleRepo, policy: policy, log: log}
+}
+
+func (usecase *removeRole) Do(ctx core.ReqContext, id int) (bool, error) {
+	trace := ctx.StartTrace("removeRole")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.RoleManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	role := usecase.roleRepo.Get(ctx, id)
+	appErr := usecase.validate(id, role)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	deleted, err := usecase.roleRepo.Delete(ctx, id)
+	if err != nil {
+		usecase.log.Errorw("Role not deleted",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+
+	usecase.policy.ResetRoles()
+	return deleted, nil
+}
+
+func (usecase *removeRole) validate(id int, role *domain.Role) *core.AppError {
+	errors := make(map[string]string)
+
+	if id <= 0 {
+		errors["id"] = core.InvalidValue.String()
+	} else if role == nil {
+		errors["id"] = core.NotExists.String()
+	} else if role.IsBuiltIn() {
+		errors["id"] = core.InUse.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
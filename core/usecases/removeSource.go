@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type RemoveSource interface {
+	Do(ctx core.ReqContext, id int64) (bool, error)
+}
+
+type removeSource struct {
+	repo      core.SourceRepository
+	log       core.AppLogger
+	changeLog core.ChangeLog
+}
+
+func NewRemoveSource(sourceRepo core.SourceRepository, changeLog core.ChangeLog, log core.AppLogger) RemoveSource {
+	return &removeSource{repo: sourceRepo, changeLog: changeLog, log: log}
+}
+
+func (usecase *removeSource) Do(ctx core.ReqContext, id int64) (bool, error) {
+	trace := ctx.StartTrace("removeSource")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.SourceRemove) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	source := usecase.repo.Get(ctx, id)
+	appErr := usecase.validate(id, source)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	deleted, err := usecase.repo.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if deleted {
+		usecase.changeLog.Deleted(ctx, domain.ResourceEntity, id, source)
+	}
+	return true, nil
+}
+
+func (usecase *removeSource) validate(id int64, source *domain.Source) *core.AppError {
+	errors := make(map[string]string)
+	if id <= 0 {
+		errors["id"] = core.InvalidFormat.String()
+	} else if source == nil {
+		errors["id"] = core.NotExists.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RemoveTopic marks topic as deleted. Topic with plans can't be removed until its plans removed.
+type RemoveTopic interface {
+	Do(ctx core.ReqContext, id int) (bool, error)
+}
+
+type removeTopic struct {
+	repo      core.TopicRepository
+	log       core.AppLogger
+	changeLog core.ChangeLog
+}
+
+func NewRemoveTopic(topicRepo core.TopicRepository, changeLog core.ChangeLog, log core.AppLogger) RemoveTopic {
+	return &removeTopic{repo: topicRepo, changeLog: changeLog, log: log}
+}
+
+func (usecase *removeTopic) Do(ctx core.ReqContext, id int) (bool, error) {
+	trace := ctx.StartTrace("removeTopic")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.TopicRemove) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	topic := usecase.repo.GetById(ctx, id)
+	appErr := usecase.validate(ctx, id, topic)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	deleted, err := usecase.repo.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if deleted {
+		usecase.changeLog.Deleted(ctx, domain.TopicEntity, int64(id), topic)
+	}
+	return true, nil
+}
+
+func (usecase *removeTopic) validate(ctx core.ReqContext, id int, topic *domain.Topic) *core.AppError {
+	errors := make(map[string]string)
+	if id <= 0 {
+		errors["id"] = core.InvalidFormat.String()
+	} else if topic == nil {
+		errors["id"] = core.NotExists.String()
+	} else if usecase.repo.HasPlans(ctx, topic.Name) {
+		errors["id"] = core.InUse.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+)
+
+// RemoveWebhook deletes webhook with log of its deliveries
+type RemoveWebhook interface {
+	Do(ctx core.ReqContext, id int64) (bool, error)
+}
+
+type removeWebhook struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewRemoveWebhook(webhookRepo core.WebhookRepository, log core.AppLogger) RemoveWebhook {
+	return &removeWebhook{webhookRepo: webhookRepo, log: log}
+}
+
+func (usecase *removeWebhook) Do(ctx core.ReqContext, id int64) (bool, error) {
+	trace := ctx.StartTrace("removeWebhook")
+	defer ctx.StopTrace(trace)
+
+	if _, appErr := getOwnWebhook(ctx, usecase.webhookRepo, id, usecase.log); appErr != nil {
+		return false, appErr
+	}
+
+	removed, appErr := usecase.webhookRepo.Delete(ctx, id)
+	if appErr != nil {
+		return false, appErr
+	}
+	return removed, nil
+}
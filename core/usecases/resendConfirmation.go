@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/google/uuid"
+)
+
+// ResendConfirmation sends new confirmation link to unconfirmed or pending email of current user.
+// Previous link stops working.
+type ResendConfirmation interface {
+	Do(ctx core.ReqContext) (bool, error)
+}
+
+type resendConfirmation struct {
+	userRepo core.UserRepository
+	lifetime time.Duration
+	interval time.Duration
+	log      core.AppLogger
+}
+
+func NewResendConfirmation(userRepo core.UserRepository, lifetime time.Duration, resendInterval time.Duration, log core.AppLogger) ResendConfirmation {
+	return &resendConfirmation{userRepo: userRepo, lifetime: lifetime, interval: resendInterval, log: log}
+}
+
+func (usecase *resendConfirmation) Do(ctx core.ReqContext) (bool, error) {
+	trace := ctx.StartTrace("resendConfirmation")
+	defer ctx.StopTrace(trace)
+
+	user := usecase.userRepo.Get(ctx, ctx.UserId())
+	if user == nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "user not exists",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if user.EmailConfirmed && user.PendingEmail == "" {
+		return false, core.NewError(core.AlreadyConfirmed)
+	}
+
+	if !canSendConfirmation(user, usecase.interval) {
+		usecase.log.Errorw("too many requests",
+			"reqid", ctx.ReqId(),
+			"UserId", user.Id,
+			"sent", user.EmailConfirmationSent,
+		)
+		return false, core.NewError(core.TooManyRequests)
+	}
+
+	return sendConfirmation(ctx, usecase.userRepo, usecase.log, user, usecase.lifetime)
+}
+
+func canSendConfirmation(user *domain.User, interval time.Duration) bool {
+	return user.EmailConfirmationSent.Add(interval).Before(time.Now())
+}
+
+// sendConfirmation generates new confirmation secret and sends it to pending email if any, otherwise to current email
+func sendConfirmation(ctx core.ReqContext, userRepo core.UserRepository, log core.AppLogger, user *domain.User, lifetime time.Duration) (bool, error) {
+	user.NewEmailConfirmation(uuid.New().String(), lifetime)
+
+	recipient, template := user.Email, domain.RegistrationEmail
+	if user.PendingEmail != "" {
+		recipient, template = user.PendingEmail, domain.EmailChangeEmail
+	}
+	email := domain.NewEmail(recipient, template, ctx.Language(), map[string]string{
+		"Secret": user.EmailConfirmation,
+	})
+
+	saved, err := userRepo.UpdateWithEmail(ctx, user, email)
+	if err != nil {
+		log.Errorw("User not updated",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+	return saved, nil
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ResolveReport closes open report by hiding reported content, deleting it or dismissing report.
+// Hiding is supported for plans and comments, deleting for plans, comments, topics and sources.
+type ResolveReport interface {
+	Do(ctx core.ReqContext, id int64, resolution domain.ReportStatus, comment string) (bool, error)
+}
+
+type resolveReport struct {
+	reportRepo    core.ReportRepository
+	planRepo      core.PlanRepository
+	commentsRepo  core.CommentsRepository
+	removePlan    RemovePlan
+	removeComment RemoveComment
+	removeTopic   RemoveTopic
+	removeSource  RemoveSource
+	changeLog     core.ChangeLog
+	log           core.AppLogger
+}
+
+func NewResolveReport(reportRepo core.ReportRepository, planRepo core.PlanRepository, commentsRepo core.CommentsRepository, removePlan RemovePlan, removeComment RemoveComment, removeTopic RemoveTopic, removeSource RemoveSource, changeLog core.ChangeLog, log core.AppLogger) ResolveReport {
+	return &resolveReport{
+		reportRepo:    reportRepo,
+		planRepo:      planRepo,
+		commentsRepo:  commentsRepo,
+		removePlan:    removePlan,
+		removeComment: removeComment,
+		removeTopic:   removeTopic,
+		removeSource:  removeSource,
+		changeLog:     changeLog,
+		log:           log,
+	}
+}
+
+func (usecase *resolveReport) Do(ctx core.ReqContext, id int64, resolution domain.ReportStatus, comment string) (bool, error) {
+	trace := ctx.StartTrace("resolveReport")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ReportModerate) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	report := usecase.reportRepo.Get(ctx, id)
+	appErr := usecase.validate(report, resolution, comment)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	// report is claimed first, so content is changed once when moderators resolve it at the same time
+	report.Status = resolution
+	report.ResolvedBy = ctx.UserId()
+	report.Comment = comment
+	resolved, appErr := usecase.reportRepo.Resolve(ctx, report)
+	if appErr != nil {
+		return false, appErr
+	}
+	if !resolved {
+		return false, core.ValidationError(map[string]string{"id": core.AlreadyExists.String()})
+	}
+
+	var err error
+	switch resolution {
+	case domain.ReportHidden:
+		err = usecase.hide(ctx, report)
+	case domain.ReportDeleted:
+		err = usecase.delete(ctx, report)
+	}
+
+	if err != nil {
+		usecase.log.Errorw("Report not resolved",
+			"reqid", ctx.ReqId(),
+			"reportId", id,
+			"error", err.Error(),
+		)
+		if _, appErr := usecase.reportRepo.Reopen(ctx, report); appErr != nil {
+			usecase.log.Errorw("Report not reopened",
+				"reqid", ctx.ReqId(),
+				"reportId", id,
+				"error", appErr.Error(),
+			)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (usecase *resolveReport) hide(ctx core.ReqContext, report *domain.Report) error {
+	switch report.EntityType {
+	case domain.PlanEntity:
+		plan := usecase.planRepo.Get(ctx, int(report.EntityId))
+		if plan == nil {
+			return core.NewError(core.NotExists)
+		}
+		if _, err := usecase.planRepo.SetHidden(ctx, plan.Id, true); err != nil {
+			return err
+		}
+		hidden := *plan
+		hidden.Hidden = true
+		usecase.changeLog.Edited(ctx, domain.PlanEntity, int64(plan.Id), plan, &hidden)
+	case domain.CommentEntity:
+		comment := usecase.commentsRepo.Get(ctx, report.EntityId)
+		if comment == nil {
+			return core.NewError(core.NotExists)
+		}
+		if _, err := usecase.commentsRepo.SetHidden(ctx, comment.Id, true); err != nil {
+			return err
+		}
+		hidden := *comment
+		hidden.Hidden = true
+		usecase.changeLog.Edited(ctx, domain.CommentEntity, comment.Id, comment, &hidden)
+	}
+	return nil
+}
+
+func (usecase *resolveReport) delete(ctx core.ReqContext, report *domain.Report) error {
+	var err error
+	switch report.EntityType {
+	case domain.PlanEntity:
+		_, err = usecase.removePlan.Do(ctx, int(report.EntityId))
+	case domain.CommentEntity:
+		_, err = usecase.removeComment.Do(ctx, report.EntityId)
+	case domain.TopicEntity:
+		_, err = usecase.removeTopic.Do(ctx, int(report.EntityId))
+	case domain.ResourceEntity:
+		_, err = usecase.removeSource.Do(ctx, report.EntityId)
+	}
+	return err
+}
+
+func (usecase *resolveReport) validate(report *domain.Report, resolution domain.ReportStatus, comment string) *core.AppError {
+	errors := make(map[string]string)
+
+	if report == nil {
+		errors["id"] = core.NotExists.String()
+	} else if report.Status != domain.ReportOpen {
+		errors["id"] = core.AlreadyExists.String()
+	}
+
+	if !resolution.IsResolution() {
+		errors["resolution"] = core.InvalidValue.String()
+	} else if report != nil && !canResolve(report.EntityType, resolution) {
+		errors["resolution"] = core.InvalidValue.String()
+	}
+
+	if !core.IsValidReportText(comment) {
+		errors["comment"] = core.InvalidFormat.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
+
+func canResolve(entityType domain.EntityType, resolution domain.ReportStatus) bool {
+	switch resolution {
+	case domain.ReportHidden:
+		return entityType == domain.PlanEntity || entityType == domain.CommentEntity
+	case domain.ReportDeleted:
+		return entityType == domain.PlanEntity || entityType == domain.CommentEntity ||
+			entityType == domain.TopicEntity || entityType == domain.ResourceEntity
+	}
+	return true
+}
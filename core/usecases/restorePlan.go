@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RestorePlan returns deleted plan back until it purged by retention job
+type RestorePlan interface {
+	Do(ctx core.ReqContext, id int) (bool, error)
+}
+
+type restorePlan struct {
+	repo      core.PlanRepository
+	log       core.AppLogger
+	changeLog core.ChangeLog
+}
+
+func NewRestorePlan(repo core.PlanRepository, changeLog core.ChangeLog, log core.AppLogger) RestorePlan {
+	return &restorePlan{repo: repo, changeLog: changeLog, log: log}
+}
+
+func (usecase *restorePlan) Do(ctx core.ReqContext, id int) (bool, error) {
+	trace := ctx.StartTrace("restorePlan")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ContentRestore) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	if id <= 0 {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidFormat.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	restored, err := usecase.repo.Restore(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if !restored {
+		return false, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+	}
+
+	usecase.changeLog.Restored(ctx, domain.PlanEntity, int64(id))
+	return true, nil
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RestoreSource returns deleted source back until it purged by retention job
+type RestoreSource interface {
+	Do(ctx core.ReqContext, id int64) (bool, error)
+}
+
+type restoreSource struct {
+	repo      core.SourceRepository
+	log       core.AppLogger
+	changeLog core.ChangeLog
+}
+
+func NewRestoreSource(repo core.SourceRepository, changeLog core.ChangeLog, log core.AppLogger) RestoreSource {
+	return &restoreSource{repo: repo, changeLog: changeLog, log: log}
+}
+
+func (usecase *restoreSource) Do(ctx core.ReqContext, id int64) (bool, error) {
+	trace := ctx.StartTrace("restoreSource")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ContentRestore) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	if id <= 0 {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidFormat.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	restored, err := usecase.repo.Restore(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if !restored {
+		return false, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+	}
+
+	usecase.changeLog.Restored(ctx, domain.ResourceEntity, id)
+	return true, nil
+}
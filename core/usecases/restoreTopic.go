@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RestoreTopic returns deleted topic back until it purged by retention job
+type RestoreTopic interface {
+	Do(ctx core.ReqContext, id int) (bool, error)
+}
+
+type restoreTopic struct {
+	repo      core.TopicRepository
+	log       core.AppLogger
+	changeLog core.ChangeLog
+}
+
+func NewRestoreTopic(repo core.TopicRepository, changeLog core.ChangeLog, log core.AppLogger) RestoreTopic {
+	return &restoreTopic{repo: repo, changeLog: changeLog, log: log}
+}
+
+func (usecase *restoreTopic) Do(ctx core.ReqContext, id int) (bool, error) {
+	trace := ctx.StartTrace("restoreTopic")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ContentRestore) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	if id <= 0 {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidFormat.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	restored, err := usecase.repo.Restore(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if !restored {
+		return false, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+	}
+
+	usecase.changeLog.Restored(ctx, domain.TopicEntity, int64(id))
+	return true, nil
+}
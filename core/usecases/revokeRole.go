@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type RevokeRole interface {
+	Do(ctx core.ReqContext, userId string, roleId int) (bool, error)
+}
+
+type revokeRole struct {
+	roleRepo  core.RoleRepository
+	userRepo  core.UserRepository
+	policy    core.AccessPolicy
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewRevokeRole(roleRepo core.RoleRepository, userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger) RevokeRole {
+	return &revokeRole{roleRepo: roleRepo, userRepo: userRepo, policy: policy, changeLog: changeLog, log: log}
+}
+
+func (usecase *revokeRole) Do(ctx core.ReqContext, userId string, roleId int) (bool, error) {
+	trace := ctx.StartTrace("revokeRole")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.RoleManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	appErr := validateUserRole(ctx, usecase.roleRepo, usecase.userRepo, userId, roleId)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	// somebody has to manage roles
+	keepLast := usecase.roleRepo.Get(ctx, roleId).Name == domain.AdminRole
+	before := roleNames(usecase.roleRepo.GetByUser(ctx, userId))
+	changed, err := usecase.roleRepo.RemoveFromUser(ctx, userId, roleId, keepLast)
+	if err != nil && err.Message == core.InUse.String() {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "last admin",
+		)
+		return false, core.ValidationError(map[string]string{"roleId": core.InUse.String()})
+	}
+	if err != nil {
+		usecase.log.Errorw("Role not revoked",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+
+	usecase.policy.ResetUser(userId)
+	if changed {
+		logUserRoles(ctx, usecase.changeLog, usecase.roleRepo, usecase.userRepo, userId, before)
+	}
+	return changed, nil
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// SaveNotificationPreferences saves preferences of current user, types not passed stay unchanged
+type SaveNotificationPreferences interface {
+	Do(ctx core.ReqContext, preferences []domain.NotificationPreference) (bool, error)
+}
+
+type saveNotificationPreferences struct {
+	notificationRepo core.NotificationRepository
+	log              core.AppLogger
+}
+
+func NewSaveNotificationPreferences(notificationRepo core.NotificationRepository, log core.AppLogger) SaveNotificationPreferences {
+	return &saveNotificationPreferences{notificationRepo: notificationRepo, log: log}
+}
+
+func (usecase *saveNotificationPreferences) Do(ctx core.ReqContext, preferences []domain.NotificationPreference) (bool, error) {
+	trace := ctx.StartTrace("saveNotificationPreferences")
+	defer ctx.StopTrace(trace)
+
+	appErr := usecase.validate(preferences)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	saved, err := usecase.notificationRepo.SavePreferences(ctx, ctx.UserId(), preferences)
+	if err != nil {
+		usecase.log.Errorw("Preferences not saved",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, err
+	}
+	return saved, nil
+}
+
+func (usecase *saveNotificationPreferences) validate(preferences []domain.NotificationPreference) *core.AppError {
+	errors := make(map[string]string)
+
+	if len(preferences) == 0 {
+		errors["preferences"] = core.InvalidCount.String()
+	}
+
+	types := make(map[domain.NotificationType]bool, len(preferences))
+	for _, p := range preferences {
+		if !p.Type.IsValid() || types[p.Type] {
+			errors["type"] = core.InvalidValue.String()
+		}
+		types[p.Type] = true
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// SaveRole creates new role when id is 0, otherwise updates existing one
+type SaveRole interface {
+	Do(ctx core.ReqContext, id int, name string, permissions []domain.Permission) (*domain.Role, error)
+}
+
+type saveRole struct {
+	roleRepo core.RoleRepository
+	policy   core.AccessPolicy
+	log      core.AppLogger
+}
+
+func NewSaveRole(roleRepo core.RoleRepository, policy core.AccessPolicy, log core.AppLogger) SaveRole {
+	return &saveRole{roleRepo: roleRepo, policy: policy, log: log}
+}
+
+func (usecase *saveRole) Do(ctx core.ReqContext, id int, name string, permissions []domain.Permission) (*domain.Role, error) {
+	trace := ctx.StartTrace("saveRole")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.RoleManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	var old *domain.Role
+	if id != 0 {
+		old = usecase.roleRepo.Get(ctx, id)
+	}
+
+	appErr := usecase.validate(id, name, permissions, old)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	role := &domain.Role{
+		Id:          id,
+		Name:        name,
+		Permissions: domain.NewPermissionSet(permissions...).List(),
+	}
+
+	var err *core.AppError
+	if id == 0 {
+		_, err = usecase.roleRepo.Save(ctx, role)
+	} else {
+		_, err = usecase.roleRepo.Update(ctx, role)
+	}
+
+	if err != nil {
+		usecase.log.Errorw("Role not saved",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+
+	usecase.policy.ResetRoles()
+	return role, nil
+}
+
+func (usecase *saveRole) validate(id int, name string, permissions []domain.Permission, old *domain.Role) *core.AppError {
+	errors := make(map[string]string)
+
+	if id < 0 {
+		errors["id"] = core.InvalidValue.String()
+	} else if id > 0 && old == nil {
+		errors["id"] = core.NotExists.String()
+	} else if old != nil && old.IsBuiltIn() && old.Name != name {
+		errors["name"] = core.AccessDenied.String()
+	}
+
+	if !core.IsValidRoleName(name) {
+		errors["name"] = core.InvalidFormat.String()
+	}
+
+	for _, p := range permissions {
+		if !p.IsValid() {
+			errors["permissions"] = core.InvalidValue.String()
+		}
+	}
+
+	// admins could lose access to role management forever
+	if old != nil && old.Name == domain.AdminRole && !domain.NewPermissionSet(permissions...).Has(domain.RoleManage) {
+		errors["permissions"] = core.AccessDenied.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
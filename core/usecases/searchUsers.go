@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type SearchUsers interface {
+	Do(ctx core.ReqContext, filter domain.UserFilter, count int, page int) ([]domain.User, error)
+}
+
+type searchUsers struct {
+	userRepo core.UserRepository
+	roleRepo core.RoleRepository
+	log      core.AppLogger
+}
+
+func NewSearchUsers(userRepo core.UserRepository, roleRepo core.RoleRepository, log core.AppLogger) SearchUsers {
+	return &searchUsers{userRepo: userRepo, roleRepo: roleRepo, log: log}
+}
+
+func (usecase *searchUsers) Do(ctx core.ReqContext, filter domain.UserFilter, count int, page int) ([]domain.User, error) {
+	trace := ctx.StartTrace("searchUsers")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.UserManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	appErr := usecase.validate(filter, count, page)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	users := usecase.userRepo.Search(ctx, filter, count, page)
+	for i := 0; i < len(users); i++ {
+		users[i].Roles = roleNames(usecase.roleRepo.GetByUser(ctx, users[i].Id))
+	}
+	return users, nil
+}
+
+func (usecase *searchUsers) validate(filter domain.UserFilter, count int, page int) *core.AppError {
+	errors := make(map[string]string)
+
+	if len(filter.Query) > 128 {
+		errors["query"] = core.InvalidFormat.String()
+	}
+
+	if filter.RoleId < 0 {
+		errors["roleId"] = core.InvalidValue.String()
+	}
+
+	if count <= 0 || count > 100 {
+		errors["count"] = core.InvalidCount.String()
+	}
+
+	if page < 0 {
+		errors["page"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
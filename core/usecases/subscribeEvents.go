@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+const maxSubscriptionChannels = 20
+
+// SubscribeEvents subscribes current user to events of plans, topics and comment threads.
+// Returned function must be called when events are not needed anymore.
+type SubscribeEvents interface {
+	Do(ctx core.ReqContext, channels []string) (<-chan domain.Event, func(), error)
+}
+
+type subscribeEvents struct {
+	bus          core.EventBus
+	planRepo     core.PlanRepository
+	commentsRepo core.CommentsRepository
+	topicRepo    core.TopicRepository
+	sourceRepo   core.SourceRepository
+	log          core.AppLogger
+}
+
+func NewSubscribeEvents(bus core.EventBus, planRepo core.PlanRepository, commentsRepo core.CommentsRepository, topicRepo core.TopicRepository, sourceRepo core.SourceRepository, log core.AppLogger) SubscribeEvents {
+	return &subscribeEvents{
+		bus:          bus,
+		planRepo:     planRepo,
+		commentsRepo: commentsRepo,
+		topicRepo:    topicRepo,
+		sourceRepo:   sourceRepo,
+		log:          log,
+	}
+}
+
+func (usecase *subscribeEvents) Do(ctx core.ReqContext, channels []string) (<-chan domain.Event, func(), error) {
+	trace := ctx.StartTrace("subscribeEvents")
+	defer ctx.StopTrace(trace)
+
+	appErr := usecase.validate(channels)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, nil, appErr
+	}
+
+	for _, ch := range channels {
+		if !usecase.isVisible(ctx, ch) {
+			return nil, nil, core.NewError(core.NotExists)
+		}
+	}
+
+	events, cancel := usecase.bus.Subscribe(channels)
+	return events, cancel, nil
+}
+
+// isVisible checks that entity of the channel exists and could be read by current user.
+// Drafts and hidden plans are visible to owner and collaborators only, so are threads of their comments
+func (usecase *subscribeEvents) isVisible(ctx core.ReqContext, channel string) bool {
+	parts := strings.SplitN(channel, ":", 2)
+	id, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	if parts[0] == domain.ThreadChannelPrefix {
+		root := usecase.commentsRepo.Get(ctx, id)
+		if root == nil || root.ThreadId != 0 || root.Hidden {
+			return false
+		}
+		return usecase.isEntityVisible(ctx, root.EntityType, root.EntityId)
+	}
+
+	_, entityType := domain.EntityTypeFromString(parts[0])
+	return usecase.isEntityVisible(ctx, entityType, id)
+}
+
+func (usecase *subscribeEvents) isEntityVisible(ctx core.ReqContext, entityType domain.EntityType, id int64) bool {
+	switch entityType {
+	case domain.PlanEntity:
+		return usecase.planRepo.GetWithDraft(ctx, int(id), ctx.UserId()) != nil
+	case domain.TopicEntity:
+		return usecase.topicRepo.GetById(ctx, int(id)) != nil
+	case domain.ResourceEntity:
+		return usecase.sourceRepo.Get(ctx, id) != nil
+	}
+	return true
+}
+
+func (usecase *subscribeEvents) validate(channels []string) *core.AppError {
+	errors := make(map[string]string)
+
+	if len(channels) == 0 || len(channels) > maxSubscriptionChannels {
+		errors["channel"] = core.InvalidCount.String()
+	}
+
+	for _, ch := range channels {
+		if !domain.IsValidChannel(ch) {
+			errors["channel"] = core.InvalidValue.String()
+			break
+		}
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// SuggestPlanEdit saves changes of title and steps proposed for plan of other user.
+// Owner of plan reviews suggestion and accepts or rejects it
+type SuggestPlanEdit interface {
+	Do(ctx core.ReqContext, req SuggestPlanEditReq) (*domain.PlanSuggestion, error)
+}
+
+type SuggestPlanEditReq struct {
+	PlanId  int
+	Title   string
+	Steps   []PlanStep
+	Message string
+}
+
+type suggestPlanEdit struct {
+	suggestionRepo core.PlanSuggestionRepository
+	planRepo       core.PlanRepository
+	sourceRepo     core.SourceRepository
+	topicRepo      core.TopicRepository
+	projectsRepo   core.ProjectsRepository
+	log            core.AppLogger
+}
+
+func NewSuggestPlanEdit(suggestionRepo core.PlanSuggestionRepository, planRepo core.PlanRepository, sourceRepo core.SourceRepository, topicRepo core.TopicRepository, projectsRepo core.ProjectsRepository, log core.AppLogger) SuggestPlanEdit {
+	return &suggestPlanEdit{
+		suggestionRepo: suggestionRepo,
+		planRepo:       planRepo,
+		sourceRepo:     sourceRepo,
+		topicRepo:      topicRepo,
+		projectsRepo:   projectsRepo,
+		log:            log,
+	}
+}
+
+func (usecase *suggestPlanEdit) Do(ctx core.ReqContext, req SuggestPlanEditReq) (*domain.PlanSuggestion, error) {
+	trace := ctx.StartTrace("suggestPlanEdit")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.PlanSuggest) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return nil, core.NewError(core.AccessDenied)
+	}
+
+	plan := usecase.planRepo.Get(ctx, req.PlanId)
+	appErr := usecase.validate(ctx, req, plan)
+	if appErr != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return nil, appErr
+	}
+
+	steps := make([]domain.Step, len(req.Steps))
+	for i, v := range req.Steps {
+		steps[i] = domain.Step{
+			PlanId:        plan.Id,
+			ReferenceId:   v.ReferenceId,
+			ReferenceType: v.ReferenceType,
+			Position:      i,
+			Title:         v.Title,
+			Duration:      v.Duration,
+			Difficulty:    v.Difficulty,
+			Optional:      v.Optional,
+			Notes:         v.Notes,
+		}
+	}
+
+	suggestion := &domain.PlanSuggestion{
+		PlanId:      plan.Id,
+		PlanVersion: plan.Version,
+		UserId:      ctx.UserId(),
+		Title:       req.Title,
+		Steps:       steps,
+		Message:     req.Message,
+		Status:      domain.SuggestionPending,
+	}
+
+	if ok, err := usecase.suggestionRepo.Add(ctx, suggestion); !ok {
+		if err != nil {
+			usecase.log.Errorw("suggestion not saved",
+				"reqid", ctx.ReqId(),
+				"error", err.Error(),
+			)
+		}
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+func (usecase *suggestPlanEdit) validate(ctx core.ReqContext, req SuggestPlanEditReq, plan *domain.Plan) *core.AppError {
+	errors := make(map[string]string)
+
+	if !core.IsValidPlanTitle(req.Title) {
+		errors["title"] = core.InvalidFormat.String()
+	}
+
+	validatePlanSteps(ctx, req.Steps, usecase.sourceRepo, usecase.topicRepo, usecase.projectsRepo, errors)
+
+	if !core.IsValidReportText(req.Message) {
+		errors["message"] = core.InvalidFormat.String()
+	}
+
+	// owner edits plan directly
+	if plan == nil {
+		errors["id"] = core.NotExists.String()
+	} else if plan.OwnerId == ctx.UserId() {
+		errors["id"] = core.InvalidValue.String()
+	}
+
+	if len(errors) > 0 {
+		return core.ValidationError(errors)
+	}
+	return nil
+}
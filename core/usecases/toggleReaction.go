@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ToggleReaction adds reaction of current user to comment or removes it if user already reacted so
+type ToggleReaction interface {
+	Do(ctx core.ReqContext, commentId int64, reaction domain.Reaction) (reacted bool, reactions []domain.ReactionSummary, err error)
+}
+
+type toggleReaction struct {
+	commentsRepo core.CommentsRepository
+	broadcaster  core.Broadcaster
+	log          core.AppLogger
+}
+
+func NewToggleReaction(commentsRepo core.CommentsRepository, broadcaster core.Broadcaster, log core.AppLogger) ToggleReaction {
+	return &toggleReaction{commentsRepo: commentsRepo, broadcaster: broadcaster, log: log}
+}
+
+func (usecase *toggleReaction) Do(ctx core.ReqContext, commentId int64, reaction domain.Reaction) (bool, []domain.ReactionSummary, error) {
+	trace := ctx.StartTrace("toggleReaction")
+	defer ctx.StopTrace(trace)
+
+	userId := ctx.UserId()
+	if userId == "" || !ctx.HasPermission(domain.CommentAdd) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+		)
+		return false, nil, core.NewError(core.AccessDenied)
+	}
+
+	if !reaction.IsValid() {
+		errors := make(map[string]string)
+		errors["reaction"] = core.InvalidValue.String()
+		return false, nil, core.ValidationError(errors)
+	}
+
+	comment := usecase.commentsRepo.Get(ctx, commentId)
+	if comment == nil || comment.Deleted || comment.Hidden {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"UserId", userId,
+			"error", fmt.Sprintf("comment deleted or not existed. id: %v", commentId),
+		)
+		return false, nil, core.NewError(core.NotExists)
+	}
+
+	reacted, err := usecase.commentsRepo.ToggleReaction(ctx, commentId, userId, reaction)
+	if err != nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", err.Error(),
+		)
+		return false, nil, err
+	}
+
+	usecase.broadcaster.ReactionsChanged(ctx, comment)
+	reactions := usecase.commentsRepo.GetReactions(ctx, userId, []int64{commentId})[commentId]
+	return reacted, reactions, nil
+}
+
+// attachReactions sets reactions to comments, reactions of current user are marked
+func attachReactions(ctx core.ReqContext, commentsRepo core.CommentsRepository, comments []domain.Comment) {
+	ids := make([]int64, len(comments))
+	for i := 0; i < len(comments); i++ {
+		ids[i] = comments[i].Id
+	}
+
+	reactions := commentsRepo.GetReactions(ctx, ctx.UserId(), ids)
+	for i := 0; i < len(comments); i++ {
+		comments[i].Reactions = reactions[comments[i].Id]
+	}
+}
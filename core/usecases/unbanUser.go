@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type UnbanUser interface {
+	Do(ctx core.ReqContext, userId string) (bool, error)
+}
+
+type unbanUser struct {
+	userRepo  core.UserRepository
+	policy    core.AccessPolicy
+	changeLog core.ChangeLog
+	log       core.AppLogger
+}
+
+func NewUnbanUser(userRepo core.UserRepository, policy core.AccessPolicy, changeLog core.ChangeLog, log core.AppLogger) UnbanUser {
+	return &unbanUser{userRepo: userRepo, policy: policy, changeLog: changeLog, log: log}
+}
+
+func (usecase *unbanUser) Do(ctx core.ReqContext, userId string) (bool, error) {
+	trace := ctx.StartTrace("unbanUser")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.UserManage) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	user := usecase.userRepo.Get(ctx, userId)
+	if user == nil {
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", "user not exists",
+		)
+		return false, core.NewError(core.NotExists)
+	}
+
+	if !user.Banned {
+		return false, nil
+	}
+
+	old := *user
+	user.Banned = false
+	user.BanReason = ""
+	user.BannedUntil = time.Time{}
+
+	return updateUser(ctx, usecase.userRepo, usecase.policy, usecase.changeLog, usecase.log, &old, user)
+}
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// UnhideContent shows plan or comment hidden by moderator on report
+type UnhideContent interface {
+	Do(ctx core.ReqContext, entityType domain.EntityType, id int64) (bool, error)
+}
+
+type unhideContent struct {
+	planRepo     core.PlanRepository
+	commentsRepo core.CommentsRepository
+	changeLog    core.ChangeLog
+	log          core.AppLogger
+}
+
+func NewUnhideContent(planRepo core.PlanRepository, commentsRepo core.CommentsRepository, changeLog core.ChangeLog, log core.AppLogger) UnhideContent {
+	return &unhideContent{
+		planRepo:     planRepo,
+		commentsRepo: commentsRepo,
+		changeLog:    changeLog,
+		log:          log,
+	}
+}
+
+func (usecase *unhideContent) Do(ctx core.ReqContext, entityType domain.EntityType, id int64) (bool, error) {
+	trace := ctx.StartTrace("unhideContent")
+	defer ctx.StopTrace(trace)
+
+	if !ctx.HasPermission(domain.ContentRestore) {
+		usecase.log.Errorw("access denied",
+			"reqid", ctx.ReqId(),
+			"UserId", ctx.UserId(),
+		)
+		return false, core.NewError(core.AccessDenied)
+	}
+
+	if id <= 0 || (entityType != domain.PlanEntity && entityType != domain.CommentEntity) {
+		appErr := core.ValidationError(map[string]string{"id": core.InvalidValue.String()})
+		usecase.log.Errorw("invalid request",
+			"reqid", ctx.ReqId(),
+			"error", appErr.Error(),
+		)
+		return false, appErr
+	}
+
+	switch entityType {
+	case domain.PlanEntity:
+		plan := usecase.planRepo.GetHidden(ctx, int(id))
+		if plan == nil {
+			return false, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+		}
+		if _, err := usecase.planRepo.SetHidden(ctx, plan.Id, false); err != nil {
+			return false, err
+		}
+		shown := *plan
+		shown.Hidden = false
+		usecase.changeLog.Edited(ctx, domain.PlanEntity, int64(plan.Id), plan, &shown)
+	case domain.CommentEntity:
+		comment := usecase.commentsRepo.Get(ctx, id)
+		if comment == nil || !comment.Hidden {
+			return false, core.ValidationError(map[string]string{"id": core.NotExists.String()})
+		}
+		if _, err := usecase.commentsRepo.SetHidden(ctx, comment.Id, false); err != nil {
+			return false, err
+		}
+		shown := *comment
+		shown.Hidden = false
+		usecase.changeLog.Edited(ctx, domain.CommentEntity, comment.Id, comment, &shown)
+	}
+	return true, nil
+}
@@ -1,7 +1,11 @@
 package core
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+
+	"github.com/NeekUP/roadmaps/domain"
 )
 
 const Alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -49,12 +53,28 @@ func DecodeStringToNum(s string) (int, error) {
 	return num, nil
 }
 
-// func RenderMarkdown(text string) []byte {
-// 	extentions := blackfriday.NoIntraEmphasis
-// 	extentions |= blackfriday.Tables
-// 	extentions |= blackfriday.FencedCode
-// 	extentions |= blackfriday.DefinitionLists
+// EncodeCommentCursor makes opaque string of cursor for clients
+func EncodeCommentCursor(cursor *domain.CommentCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCommentCursor(s string) (*domain.CommentCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
 
-// 	options := blackfriday.WithExtensions(extentions)
-// 	return blackfriday.Run([]byte(text), options)
-// }
+	cursor := &domain.CommentCursor{}
+	if err := json.Unmarshal(b, cursor); err != nil {
+		return nil, err
+	}
+
+	if !cursor.Sort.IsValid() || cursor.Id <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return cursor, nil
+}
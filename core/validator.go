@@ -6,6 +6,7 @@ import (
 	"github.com/NeekUP/roadmaps/domain"
 	"github.com/badoux/checkmail"
 	"net"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -107,6 +108,19 @@ func IsValidStepTitle(name string) bool {
 	return l > 0 && l < 200
 }
 
+// IsValidStepDuration checks estimated time of step in minutes, 0 means unknown
+func IsValidStepDuration(minutes int) bool {
+	return minutes >= 0 && minutes <= 100000
+}
+
+func IsValidStepNotes(notes string) bool {
+	return utf8.RuneCountInString(notes) < 2000
+}
+
+func IsValidDifficulty(difficulty domain.Difficulty) bool {
+	return difficulty.IsValid()
+}
+
 func IsValidReferenceType(str domain.ReferenceType) bool {
 	return str.IsValid()
 }
@@ -140,3 +154,75 @@ func IsValidCommentTitle(text string) bool {
 	length := utf8.RuneCountInString(text)
 	return length < 256
 }
+
+func IsValidRoleName(name string) bool {
+	positivePattern := regexp.MustCompile("^[a-z0-9_-]+$")
+
+	l := utf8.RuneCountInString(name)
+	return l > 1 && l <= 64 && positivePattern.MatchString(name)
+}
+
+func IsValidBanReason(reason string) bool {
+	l := utf8.RuneCountInString(reason)
+	return l > 0 && l <= 512
+}
+
+func IsValidReportText(text string) bool {
+	return utf8.RuneCountInString(text) <= 512
+}
+
+func IsValidWebhookUrl(uri string) bool {
+	if len(uri) == 0 || len(uri) > 2048 {
+		return false
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return false
+	}
+	// names are checked again by dispatcher after resolving, it also protects from dns rebinding
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return false
+	}
+	return true
+}
+
+var privateNetworks = mustParseNetworks(
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"100.64.0.0/10",
+	"fc00::/7",
+)
+
+// IsPublicIP returns false for loopback, private, link-local, unspecified and multicast addresses
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// FindVoteRings returns votes of accounts voting together. Two accounts are linked when they voted
+// in the same direction for at least minShared entities within window of each other, linked accounts
+// form a ring if there are at least minSize of them. Returned are votes of ring members for entities
+// where another member of the same ring voted alike
+func FindVoteRings(votes []domain.VoteRecord, window time.Duration, minShared int, minSize int) []domain.VoteRecord {
+	type entityKey struct {
+		entityType domain.EntityType
+		entityId   int64
+	}
+	type pairKey struct {
+		a, b string
+	}
+
+	byEntity := make(map[entityKey][]int)
+	for i, v := range votes {
+		key := entityKey{v.EntityType, v.EntityId}
+		byEntity[key] = append(byEntity[key], i)
+	}
+
+	// forEachPair calls f for every two votes of entity made alike within window
+	forEachPair := func(f func(i, j int, key pairKey)) {
+		for _, list := range byEntity {
+			sort.Slice(list, func(x, y int) bool { return votes[list[x]].Date.Before(votes[list[y]].Date) })
+			for x := 0; x < len(list); x++ {
+				for y := x + 1; y < len(list); y++ {
+					i, j := list[x], list[y]
+					if votes[j].Date.Sub(votes[i].Date) > window {
+						break
+					}
+					if votes[i].UserId == votes[j].UserId || upvote(votes[i]) != upvote(votes[j]) {
+						continue
+					}
+					key := pairKey{votes[i].UserId, votes[j].UserId}
+					if key.a > key.b {
+						key.a, key.b = key.b, key.a
+					}
+					f(i, j, key)
+				}
+			}
+		}
+	}
+
+	// user has one vote per entity, so pair is counted once per entity
+	shared := make(map[pairKey]int)
+	forEachPair(func(i, j int, key pairKey) {
+		shared[key]++
+	})
+
+	rings := newUnionFind()
+	for key, count := range shared {
+		if count >= minShared {
+			rings.union(key.a, key.b)
+		}
+	}
+
+	size := make(map[string]int)
+	for user := range rings.parent {
+		size[rings.find(user)]++
+	}
+
+	flagged := make(map[int]bool)
+	forEachPair(func(i, j int, key pairKey) {
+		if shared[key] < minShared || size[rings.find(key.a)] < minSize {
+			return
+		}
+		flagged[i] = true
+		flagged[j] = true
+	})
+
+	result := make([]domain.VoteRecord, 0, len(flagged))
+	for i := range votes {
+		if flagged[i] {
+			result = append(result, votes[i])
+		}
+	}
+	return result
+}
+
+func upvote(v domain.VoteRecord) bool {
+	return signed(domain.Vote{Value: v.Value}) > 0
+}
+
+type unionFind struct {
+	parent map[string]string
+}
+
+func newUnionFind() *unionFind {
+	return &unionFind{parent: make(map[string]string)}
+}
+
+func (u *unionFind) find(x string) string {
+	if _, ok := u.parent[x]; !ok {
+		u.parent[x] = x
+	}
+	for u.parent[x] != x {
+		u.parent[x] = u.parent[u.parent[x]]
+		x = u.parent[x]
+	}
+	return x
+}
+
+func (u *unionFind) union(a, b string) {
+	ra, rb := u.find(a), u.find(b)
+	if ra != rb {
+		u.parent[ra] = rb
+	}
+}
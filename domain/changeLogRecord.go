@@ -9,6 +9,20 @@ type ChangeLogRecord struct {
 	UserId     string
 	EntityType EntityType
 	EntityId   int64
-	Diff       string
-	Points     int
+	// id of entity which is not a number, like user id, EntityId is 0 then
+	EntityKey string
+	Diff      string
+	// Snapshot of deleted entity
+	Snapshot  string
+	Points    int
+	ReqId     string
+	IP        string
+	UserAgent string
+}
+
+// ChangeLogFilter zero values mean any value
+type ChangeLogFilter struct {
+	UserId     string
+	EntityType EntityType
+	Action     ChangeType
 }
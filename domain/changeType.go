@@ -27,4 +27,15 @@ const (
 	AddUser    ChangeType = 16
 	EditUser   ChangeType = 17
 	DeleteUser ChangeType = 18
+
+	RestorePlan     ChangeType = 19
+	RestoreTopic    ChangeType = 20
+	RestoreResource ChangeType = 21
+
+	// reputation points awarded to UserId for the entity
+	AwardReputation ChangeType = 22
 )
+
+func (ct ChangeType) IsValid() bool {
+	return ct >= AddPlan && ct <= AwardReputation
+}
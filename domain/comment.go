@@ -11,9 +11,67 @@ type Comment struct {
 	Date       time.Time
 	UserId     string
 	User       *User
-	Text       string
+	Text       string // markdown source
+	Html       string // rendered and sanitized Text
 	Title      string
 	Deleted    bool
+	Hidden     bool
+	Pinned     bool      // thread is shown before others
+	AnswerId   int64     // reply accepted as answer to thread
+	Replies    int       // count of replies in thread for first comment of thread, of direct replies for others
+	EditedAt   time.Time // zero if comment was not edited
+	EditedBy   string    // user made the last edit
+	DeletedBy  string
+	Version    int // incremented on each edit
 	Points     *Points
+	Reactions  []ReactionSummary
 	Childs     []Comment
 }
+
+// EditedByModerator is true if the last edit was made not by author
+func (c *Comment) EditedByModerator() bool {
+	return c.EditedBy != "" && c.EditedBy != c.UserId
+}
+
+// DeletedByModerator is true if comment was deleted not by author
+func (c *Comment) DeletedByModerator() bool {
+	return c.Deleted && c.DeletedBy != "" && c.DeletedBy != c.UserId
+}
+
+// CommentRevision is a replaced version of comment text, EditorId is a user wrote this version at Date
+type CommentRevision struct {
+	Id          int64
+	CommentId   int64
+	Text        string
+	Html        string
+	Title       string
+	EditorId    string
+	Editor      *User
+	ByModerator bool // editor is not the author of comment
+	Date        time.Time
+}
+
+type CommentSort string
+
+const (
+	CommentSortNewest  CommentSort = "newest"
+	CommentSortOldest  CommentSort = "oldest"
+	CommentSortTop     CommentSort = "top"
+	CommentSortReplies CommentSort = "replies"
+)
+
+func (s CommentSort) IsValid() bool {
+	switch s {
+	case CommentSortNewest, CommentSortOldest, CommentSortTop, CommentSortReplies:
+		return true
+	}
+	return false
+}
+
+// CommentCursor is a position after the last comment of page, Key is points or replies count of the comment
+type CommentCursor struct {
+	Sort   CommentSort
+	Pinned bool
+	Key    int64
+	Id     int64
+}
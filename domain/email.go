@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type EmailStatus int
+
+const (
+	EmailPending EmailStatus = 0
+	EmailSent    EmailStatus = 1
+	// EmailDead is set when all attempts to send email failed
+	EmailDead EmailStatus = 2
+)
+
+// Names of email templates
+const (
+	RegistrationEmail = "registration"
+	EmailChangeEmail  = "emailChange"
+	DigestEmail       = "notificationDigest"
+)
+
+// Email is a message in outbox, it is rendered from template and sent in background
+type Email struct {
+	Id          int64
+	UserId      string
+	Recipient   string
+	Template    string
+	Lang        string
+	Data        map[string]string
+	Status      EmailStatus
+	Attempts    int
+	NextAttempt time.Time
+	LastError   string
+	Created     time.Time
+	Sent        time.Time
+}
+
+func NewEmail(recipient, template, lang string, data map[string]string) *Email {
+	if data == nil {
+		data = map[string]string{}
+	}
+	return &Email{
+		Recipient: recipient,
+		Template:  template,
+		Lang:      lang,
+		Data:      data,
+		Status:    EmailPending,
+	}
+}
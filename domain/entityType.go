@@ -17,20 +17,26 @@ func (et EntityType) IsValid() bool {
 	return et >= 1 && et <= 6
 }
 
+// IsVotable returns true if users can vote for entities of the type.
+// Users are not votable, points are kept by numeric entity id and user id is not a number.
+func (et EntityType) IsVotable() bool {
+	return et.IsValid() && et != UserEntity
+}
+
 func EntityTypeFromString(entityType string) (bool, EntityType) {
 	switch strings.ToLower(entityType) {
 	case "plan":
 		return true, PlanEntity
 	case "topic":
-		return true, PlanEntity
+		return true, TopicEntity
 	case "project":
-		return true, PlanEntity
+		return true, ProjectEntity
 	case "resource":
-		return true, PlanEntity
+		return true, ResourceEntity
 	case "comment":
-		return true, PlanEntity
+		return true, CommentEntity
 	case "user":
-		return true, PlanEntity
+		return true, UserEntity
 	default:
 		return false, 0
 	}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+type EventType string
+
+const (
+	CommentAddedEvent   EventType = "comment.added"
+	CommentEditedEvent  EventType = "comment.edited"
+	CommentDeletedEvent EventType = "comment.deleted"
+	// PointsChangedEvent carries new aggregate of votes for entity
+	PointsChangedEvent EventType = "points.changed"
+	// ReactionsChangedEvent carries all reactions to comment, none if the last one was removed
+	ReactionsChangedEvent EventType = "reactions.changed"
+)
+
+// ThreadChannelPrefix starts channels of comment threads
+const ThreadChannelPrefix = "thread"
+
+// Event is delivered to clients subscribed to any of its channels
+type Event struct {
+	Type       EventType
+	Channels   []string
+	EntityType EntityType
+	EntityId   int64
+	Comment    *Comment          `json:",omitempty"`
+	Points     *Points           `json:",omitempty"`
+	Reactions  []ReactionSummary `json:",omitempty"`
+}
+
+// EntityChannel is a channel of all events related to entity, i.e. plan:42
+func EntityChannel(entityType EntityType, id int64) string {
+	return EntityTypeToString(entityType) + ":" + strconv.FormatInt(id, 10)
+}
+
+// ThreadChannel is a channel of events related to comments in thread
+func ThreadChannel(threadId int64) string {
+	return ThreadChannelPrefix + ":" + strconv.FormatInt(threadId, 10)
+}
+
+// IsValidChannel checks that clients subscribe to commented entity or thread
+func IsValidChannel(channel string) bool {
+	parts := strings.SplitN(channel, ":", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	switch parts[0] {
+	case ThreadChannelPrefix, EntityTypeToString(PlanEntity), EntityTypeToString(TopicEntity),
+		EntityTypeToString(ResourceEntity), EntityTypeToString(ProjectEntity):
+	default:
+		return false
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	return err == nil && id > 0
+}
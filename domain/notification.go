@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+type NotificationType int
+
+const (
+	// CommentReplyNotification is sent to author of comment when somebody replies to it
+	CommentReplyNotification NotificationType = 1
+	// PlanCommentNotification is sent to owner of plan when somebody comments it
+	PlanCommentNotification NotificationType = 2
+	// PlanVoteNotification is sent to owner of plan when somebody votes for it
+	PlanVoteNotification NotificationType = 3
+	// FavoritePlanChangedNotification is sent to users who added plan to favorites when it is edited
+	FavoritePlanChangedNotification NotificationType = 4
+)
+
+var notificationTypes = []NotificationType{
+	CommentReplyNotification, PlanCommentNotification, PlanVoteNotification, FavoritePlanChangedNotification,
+}
+
+func (t NotificationType) IsValid() bool {
+	for _, v := range notificationTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+type Notification struct {
+	Id     int64
+	UserId string
+	Type   NotificationType
+	// user caused notification
+	ActorId    string
+	EntityType EntityType
+	EntityId   int64
+	// title of plan or part of comment at the moment of notification
+	Title   string
+	Read    bool
+	Emailed bool
+	Created time.Time
+}
+
+// NotificationPreference of user for one type of notifications,
+// preferences are saved only when they differ from default
+type NotificationPreference struct {
+	Type NotificationType
+	// notifications are not created when disabled
+	Enabled bool
+	// unread notifications are sent in email digest
+	Email bool
+}
+
+func DefaultNotificationPreferences() []NotificationPreference {
+	list := make([]NotificationPreference, len(notificationTypes))
+	for i, t := range notificationTypes {
+		list[i] = NotificationPreference{Type: t, Enabled: true, Email: false}
+	}
+	return list
+}
@@ -0,0 +1,92 @@
+package domain
+
+type Permission string
+
+const (
+	TopicAdd        Permission = "topic.add"
+	TopicEdit       Permission = "topic.edit"
+	TopicRemove     Permission = "topic.remove"
+	TagManage       Permission = "tag.manage"
+	SourceAdd       Permission = "source.add"
+	SourceRemove    Permission = "source.remove"
+	PlanAdd         Permission = "plan.add"
+	PlanEdit        Permission = "plan.edit"
+	PlanRemove      Permission = "plan.remove"
+	PlanSuggest     Permission = "plan.suggest"
+	CommentAdd      Permission = "comment.add"
+	CommentModerate Permission = "comment.moderate"
+	PointsAdd       Permission = "points.add"
+	ReportAdd       Permission = "report.add"
+	ReportModerate  Permission = "report.moderate"
+	ContentRestore  Permission = "content.restore"
+	ChangeLogView   Permission = "changelog.view"
+	RoleManage      Permission = "role.manage"
+	UserManage      Permission = "user.manage"
+	DevTools        Permission = "dev.tools"
+	WebhookOwn      Permission = "webhook.own"
+	WebhookManage   Permission = "webhook.manage"
+)
+
+var allPermissions = []Permission{
+	TopicAdd, TopicEdit, TopicRemove, TagManage, SourceAdd, SourceRemove,
+	PlanAdd, PlanEdit, PlanRemove, PlanSuggest,
+	CommentAdd, CommentModerate, PointsAdd,
+	ReportAdd, ReportModerate, ContentRestore, ChangeLogView,
+	RoleManage, UserManage, DevTools, WebhookOwn, WebhookManage,
+}
+
+// Permissions granted to the owner of an entity regardless of their roles
+var ownerPermissions = NewPermissionSet(PlanEdit, PlanRemove, CommentModerate)
+
+func AllPermissions() []Permission {
+	list := make([]Permission, len(allPermissions))
+	copy(list, allPermissions)
+	return list
+}
+
+func (p Permission) IsValid() bool {
+	for _, v := range allPermissions {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Permission) IsOwnerPermission() bool {
+	return ownerPermissions.Has(p)
+}
+
+type PermissionSet map[Permission]bool
+
+func NewPermissionSet(permissions ...Permission) PermissionSet {
+	set := make(PermissionSet, len(permissions))
+	set.Add(permissions...)
+	return set
+}
+
+func (set PermissionSet) Add(permissions ...Permission) {
+	for _, p := range permissions {
+		set[p] = true
+	}
+}
+
+func (set PermissionSet) Remove(permissions ...Permission) {
+	for _, p := range permissions {
+		delete(set, p)
+	}
+}
+
+func (set PermissionSet) Has(p Permission) bool {
+	return set[p]
+}
+
+func (set PermissionSet) List() []Permission {
+	list := make([]Permission, 0, len(set))
+	for _, p := range allPermissions {
+		if set[p] {
+			list = append(list, p)
+		}
+	}
+	return list
+}
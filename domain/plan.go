@@ -9,4 +9,42 @@ type Plan struct {
 	Owner     *User
 	Points    *Points
 	IsDraft   bool
+	// hidden by moderator, visible to owner only
+	Hidden bool
+	// id of plan this plan was forked from, 0 if plan is not a fork
+	ParentId int
+	// not deleted forks of plan, filled only when needed
+	Forks int
+	// plan this plan was forked from, filled only when needed
+	Parent *Plan
+	// incremented on each edit, client sends it back to detect concurrent edits
+	Version int
+}
+
+// EstimatedTime is total estimated time of steps in minutes
+func (p *Plan) EstimatedTime() int {
+	total := 0
+	for _, step := range p.Steps {
+		total += step.Duration
+	}
+	return total
+}
+
+// RequiredTime is estimated time of steps which can not be skipped in minutes
+func (p *Plan) RequiredTime() int {
+	total := 0
+	for _, step := range p.Steps {
+		if !step.Optional {
+			total += step.Duration
+		}
+	}
+	return total
+}
+
+// PlanDiff is difference between fork and the plan it was forked from
+type PlanDiff struct {
+	Plan     *Plan
+	Upstream *Plan
+	// serialized difference in format of change log records
+	Diff string
 }
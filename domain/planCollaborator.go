@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type CollaboratorRole int
+
+const (
+	// CollaboratorViewer sees plan when it is a draft
+	CollaboratorViewer CollaboratorRole = 1
+	// CollaboratorEditor has the same rights on plan as its owner
+	CollaboratorEditor CollaboratorRole = 2
+)
+
+func (r CollaboratorRole) IsValid() bool {
+	return r == CollaboratorViewer || r == CollaboratorEditor
+}
+
+// PlanCollaborator is a user invited by owner of plan, rights are granted only after invitation is accepted
+type PlanCollaborator struct {
+	PlanId    int
+	UserId    string
+	Role      CollaboratorRole
+	InvitedBy string
+	Accepted  bool
+	Date      time.Time
+	// filled in lists only
+	UserName  string
+	PlanTitle string
+}
+
+// CanEdit reports whether collaborator may edit and remove plan like owner
+func (c *PlanCollaborator) CanEdit() bool {
+	return c != nil && c.Accepted && c.Role == CollaboratorEditor
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type SuggestionStatus int
+
+const (
+	SuggestionPending  SuggestionStatus = 0
+	SuggestionAccepted SuggestionStatus = 1
+	SuggestionRejected SuggestionStatus = 2
+)
+
+// PlanSuggestion is a change of plan proposed by other user, owner of plan accepts or rejects it
+type PlanSuggestion struct {
+	Id     int64
+	PlanId int
+	// proposer of changes
+	UserId  string
+	Title   string
+	Steps   []Step
+	Message string
+	Status  SuggestionStatus
+	Date    time.Time
+	// owner or moderator reviewed suggestion and its comment on review
+	ReviewedBy string
+	ReviewedAt time.Time
+	Comment    string
+	// difference between current plan and proposed changes, filled only when needed
+	Diff string
+	// version of plan the changes were proposed to, plan edited since then is not overwritten on accept
+	PlanVersion int
+}
@@ -5,5 +5,5 @@ type Points struct {
 	Count int
 	Avg   float32
 	Value float32
-	Voted bool
+	Voted int // value of vote of current user, 0 if not voted
 }
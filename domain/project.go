@@ -1,11 +1,13 @@
 package domain
 
 type Project struct {
-	Id      int
-	Title   string
-	Text    string
-	Tags    []TopicTag
-	OwnerId string
-	Owner   *User
-	Points  *Points
+	Id       int
+	Title    string
+	Text     string
+	Tags     []TopicTag
+	OwnerId  string
+	Owner    *User
+	Points   *Points
+	Comments int
+	Version  int // incremented on each edit
 }
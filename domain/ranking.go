@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// PlanSort is a ranking algorithm of plans, scores of every algorithm are recomputed by ranking job
+type PlanSort string
+
+const (
+	PlanSortRating   PlanSort = "rating"   // bayesian average of votes
+	PlanSortWilson   PlanSort = "wilson"   // lower bound of Wilson score interval
+	PlanSortHot      PlanSort = "hot"      // votes decayed by age
+	PlanSortTrending PlanSort = "trending" // votes of last days
+)
+
+func (s PlanSort) IsValid() bool {
+	switch s {
+	case PlanSortRating, PlanSortWilson, PlanSortHot, PlanSortTrending:
+		return true
+	}
+	return false
+}
+
+type Vote struct {
+	Value int
+	Date  time.Time
+	// share of vote in aggregates, see VoteRecord
+	Weight float64
+}
+
+// EntityVotes are all votes for entity, input of rankers
+type EntityVotes struct {
+	EntityId int64
+	Votes    []Vote
+}
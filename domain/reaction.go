@@ -0,0 +1,36 @@
+package domain
+
+// Reaction is an emoji reaction to comment
+type Reaction string
+
+const (
+	ReactionLike     Reaction = "+1"
+	ReactionDislike  Reaction = "-1"
+	ReactionLaugh    Reaction = "laugh"
+	ReactionHooray   Reaction = "hooray"
+	ReactionConfused Reaction = "confused"
+	ReactionHeart    Reaction = "heart"
+	ReactionRocket   Reaction = "rocket"
+	ReactionEyes     Reaction = "eyes"
+)
+
+var allReactions = []Reaction{
+	ReactionLike, ReactionDislike, ReactionLaugh, ReactionHooray,
+	ReactionConfused, ReactionHeart, ReactionRocket, ReactionEyes,
+}
+
+func (r Reaction) IsValid() bool {
+	for _, v := range allReactions {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
+
+// ReactionSummary is a count of users reacted to comment, Reacted is true if current user is one of them
+type ReactionSummary struct {
+	Reaction Reaction
+	Count    int
+	Reacted  bool
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+type ReportStatus int
+
+const (
+	ReportOpen      ReportStatus = 0
+	ReportHidden    ReportStatus = 1
+	ReportDeleted   ReportStatus = 2
+	ReportDismissed ReportStatus = 3
+)
+
+func (s ReportStatus) IsResolution() bool {
+	return s == ReportHidden || s == ReportDeleted || s == ReportDismissed
+}
+
+type ReportReason int
+
+const (
+	SpamReason      ReportReason = 1
+	AbuseReason     ReportReason = 2
+	CopyrightReason ReportReason = 3
+	IncorrectReason ReportReason = 4
+	OtherReason     ReportReason = 5
+)
+
+func (r ReportReason) IsValid() bool {
+	return r >= SpamReason && r <= OtherReason
+}
+
+// Report aggregates all complaints about one entity until it is resolved by moderator
+type Report struct {
+	Id         int64
+	EntityType EntityType
+	EntityId   int64
+	Status     ReportStatus
+	Count      int
+	Created    time.Time
+	Updated    time.Time
+	ResolvedBy string
+	ResolvedAt time.Time
+	Comment    string
+	Complaints []Complaint
+}
+
+type Complaint struct {
+	ReportId int64
+	UserId   string
+	Reason   ReportReason
+	Text     string
+	Date     time.Time
+}
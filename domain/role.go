@@ -0,0 +1,19 @@
+package domain
+
+// Names of roles created by migration.
+// UserRole is granted implicitly to every authenticated user.
+const (
+	UserRole      = "user"
+	ModeratorRole = "moderator"
+	AdminRole     = "admin"
+)
+
+type Role struct {
+	Id          int
+	Name        string
+	Permissions []Permission
+}
+
+func (role *Role) IsBuiltIn() bool {
+	return role.Name == UserRole || role.Name == ModeratorRole || role.Name == AdminRole
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 type Source struct {
 	Id                   int64
 	Title                string
@@ -9,4 +14,22 @@ type Source struct {
 	Properties           string // json
 	Img                  string
 	Desc                 string
+	Comments             int
+	Points               *Points
+}
+
+// SourceDurationProperty is name of property with duration of source in minutes, known for some videos and audios
+const SourceDurationProperty = "duration"
+
+// Duration returns duration of source in minutes from its properties, 0 if unknown
+func (s *Source) Duration() int {
+	props := make(map[string]string)
+	if err := json.Unmarshal([]byte(s.Properties), &props); err != nil {
+		return 0
+	}
+	minutes, err := strconv.Atoi(props[SourceDurationProperty])
+	if err != nil || minutes < 0 {
+		return 0
+	}
+	return minutes
 }
@@ -7,5 +7,30 @@ type Step struct {
 	ReferenceType ReferenceType
 	Position      int
 	Source        interface{}
-	Title         string
+	Title         string // markdown source
+	TitleHtml     string // rendered and sanitized Title
+	// estimated time to complete step in minutes, 0 if unknown
+	Duration   int
+	Difficulty Difficulty
+	// step can be skipped, it is not counted in required time of plan
+	Optional  bool
+	Notes     string // markdown source
+	NotesHtml string // rendered and sanitized Notes
+}
+
+type Difficulty string
+
+const (
+	// DifficultyNotSet is used when author of plan did not estimate difficulty of step
+	DifficultyNotSet Difficulty = ""
+	Beginner         Difficulty = "Beginner"
+	Intermediate     Difficulty = "Intermediate"
+	Advanced         Difficulty = "Advanced"
+)
+
+func (d Difficulty) IsValid() bool {
+	return d == DifficultyNotSet ||
+		d == Beginner ||
+		d == Intermediate ||
+		d == Advanced
 }
@@ -3,14 +3,18 @@ package domain
 import "github.com/gosimple/slug"
 
 type Topic struct {
-	Id          int
-	Name        string
-	Title       string
-	Description string
-	Creator     string
-	IsTag       bool
-	Tags        []TopicTag
-	Plans       []Plan
+	Id              int
+	Name            string
+	Title           string
+	Description     string // markdown source
+	DescriptionHtml string // rendered and sanitized Description
+	Creator         string
+	IsTag           bool
+	Tags            []TopicTag
+	Plans           []Plan
+	Comments        int
+	Points          *Points
+	Version         int // incremented on each edit
 }
 
 func NewTopic(title, desc, userID string) *Topic {
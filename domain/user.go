@@ -9,12 +9,36 @@ type User struct {
 	Email             string
 	EmailConfirmed    bool
 	EmailConfirmation string
-	Img               string
-	Tokens            []UserToken
-	Rights            Rights
-	Pass              []byte
-	Salt              []byte
-	OAuth             bool
+	// time after which EmailConfirmation secret is not accepted
+	EmailConfirmationExpires time.Time
+	// time of last sent confirmation email, used to limit resending
+	EmailConfirmationSent time.Time
+	// new email waiting for confirmation, replaces Email after confirmation
+	PendingEmail string
+	Img          string
+	Tokens       []UserToken
+	Rights       Rights
+	Pass         []byte
+	Salt         []byte
+	OAuth        bool
+	Banned       bool
+	BanReason    string
+	// zero value means permanent ban
+	BannedUntil time.Time
+	// names of assigned roles, filled only when needed
+	Roles []string
+	// sum of points awarded for contributions
+	Reputation int
+	// zero value means account registered before creation date was tracked
+	Created time.Time
+}
+
+type UserFilter struct {
+	// part of name or email
+	Query       string
+	Banned      bool
+	Unconfirmed bool
+	RoleId      int
 }
 
 type UserToken struct {
@@ -32,3 +56,39 @@ func (this *User) RemoveToken(i int) {
 func (this *User) HasRights(r Rights) bool {
 	return Rights(this.Rights).HasFlag(r)
 }
+
+func (this *User) IsBanned() bool {
+	return this.Banned && (this.BannedUntil.IsZero() || this.BannedUntil.After(time.Now()))
+}
+
+// NewEmailConfirmation generates confirmation secret valid for lifetime
+func (this *User) NewEmailConfirmation(secret string, lifetime time.Duration) {
+	now := time.Now()
+	this.EmailConfirmation = secret
+	this.EmailConfirmationExpires = now.Add(lifetime)
+	this.EmailConfirmationSent = now
+}
+
+func (this *User) IsConfirmationExpired() bool {
+	return this.EmailConfirmationExpires.Before(time.Now())
+}
+
+// ConfirmEmail marks email as confirmed, pending email replaces current one
+func (this *User) ConfirmEmail() {
+	if this.PendingEmail != "" {
+		this.Email = this.PendingEmail
+		this.PendingEmail = ""
+	}
+	this.EmailConfirmed = true
+	this.EmailConfirmation = ""
+	this.EmailConfirmationExpires = time.Time{}
+}
+
+func (this *User) HasSession(id string) bool {
+	for _, t := range this.Tokens {
+		if t.Id == id {
+			return true
+		}
+	}
+	return false
+}
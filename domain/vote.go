@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// VoteRecord is a single vote of user with data used to detect abuse
+type VoteRecord struct {
+	EntityType EntityType
+	EntityId   int64
+	UserId     string
+	IP         string
+	Value      int
+	// votes of new and unconfirmed accounts count less in aggregates
+	Weight float64
+	// flagged votes are kept but excluded from aggregates
+	Flagged bool
+	Date    time.Time
+}
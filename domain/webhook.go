@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// Webhook is an endpoint which receives changes of plans, topics and sources
+type Webhook struct {
+	Id      int64
+	UserId  string
+	Url     string
+	Secret  string
+	Events  []ChangeType
+	Active  bool
+	Created time.Time
+	// OwnChanges webhook receives only changes made by its owner, webhooks of users without webhook.manage are limited to them
+	OwnChanges bool
+}
+
+func (w *Webhook) IsSubscribed(event ChangeType) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWebhookEvent reports whether change could be sent to webhooks.
+// Only changes of plans, topics and sources are sent.
+func (ct ChangeType) IsWebhookEvent() bool {
+	switch ct {
+	case AddPlan, EditPlan, DeletePlan, RestorePlan,
+		AddTopic, EditTopic, DeleteTopic, RestoreTopic,
+		AddResource, EditResource, DeleteResource, RestoreResource:
+		return true
+	}
+	return false
+}
+
+type WebhookDeliveryStatus int
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = 0
+	WebhookDeliveryDelivered WebhookDeliveryStatus = 1
+	WebhookDeliveryDead      WebhookDeliveryStatus = 2
+)
+
+// WebhookDelivery is one attempt series to send event to webhook,
+// Url and Secret of webhook are filled when delivery is taken for sending
+type WebhookDelivery struct {
+	Id           int64
+	WebhookId    int64
+	Url          string
+	Secret       string
+	Event        ChangeType
+	Payload      string
+	Status       WebhookDeliveryStatus
+	Attempts     int
+	NextAttempt  time.Time
+	ResponseCode int
+	LastError    string
+	Created      time.Time
+	Delivered    time.Time
+	// RedeliveryOf is id of delivery which was sent again
+	RedeliveryOf int64
+}
@@ -20,6 +20,7 @@ require (
 	github.com/nullrocks/identicon v0.0.0-20180626043057-7875f45b0022
 	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/r3labs/diff v0.0.0-20191120142937-b4ed99a31f5a
+	github.com/russross/blackfriday/v2 v2.1.0
 	github.com/sergi/go-diff v1.1.0
 	go.uber.org/zap v1.13.0
 	golang.org/x/net v0.0.0-20191119073136-fc4aabc6c914
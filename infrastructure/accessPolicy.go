@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+const (
+	rolesCacheKey      = "policy:roles"
+	userRolesKeyPrefix = "policy:user:"
+	userStateKeyPrefix = "policy:state:"
+	noSessionKeyPrefix = "policy:nosession:"
+	policyCacheTime    = time.Minute
+	// unknown sessions are not read from db again for this time
+	noSessionCacheTime = 10 * time.Second
+)
+
+type rolePolicy struct {
+	roleRepo core.RoleRepository
+	userRepo core.UserRepository
+	cache    core.DistributedCache
+	// permissions denied to users with unconfirmed email
+	unconfirmed []domain.Permission
+	// permissions granted to users with reputation not less than value
+	unlocks map[domain.Permission]int
+	log     core.AppLogger
+}
+
+// state of user required on every authenticated request
+type userState struct {
+	banned     bool
+	until      time.Time
+	confirmed  bool
+	reputation int
+	sessions   map[string]bool
+}
+
+func NewRolePolicy(roleRepo core.RoleRepository, userRepo core.UserRepository, cache core.DistributedCache, unconfirmed []domain.Permission, unlocks map[domain.Permission]int, log core.AppLogger) core.AccessPolicy {
+	return &rolePolicy{roleRepo: roleRepo, userRepo: userRepo, cache: cache, unconfirmed: unconfirmed, unlocks: unlocks, log: log}
+}
+
+func (policy *rolePolicy) Permissions(ctx core.ReqContext, userId string) domain.PermissionSet {
+	permissions := domain.NewPermissionSet()
+	if userId == "" {
+		return permissions
+	}
+
+	roles := policy.roles(ctx)
+	userRoles := policy.userRoles(ctx, userId)
+	for _, role := range roles {
+		if role.Name == domain.UserRole || userRoles[role.Id] {
+			permissions.Add(role.Permissions...)
+		}
+	}
+
+	if len(policy.unconfirmed) == 0 && len(policy.unlocks) == 0 {
+		return permissions
+	}
+
+	state := policy.userState(ctx, userId)
+	if state == nil {
+		return permissions
+	}
+	for permission, reputation := range policy.unlocks {
+		if state.reputation >= reputation {
+			permissions.Add(permission)
+		}
+	}
+	if !state.confirmed {
+		permissions.Remove(policy.unconfirmed...)
+	}
+	return permissions
+}
+
+func (policy *rolePolicy) IsActive(ctx core.ReqContext, userId string, sessionId string) bool {
+	noSessionKey := noSessionKeyPrefix + userId + ":" + sessionId
+	if _, ok := policy.cache.Get(noSessionKey); ok {
+		return false
+	}
+
+	state := policy.userState(ctx, userId)
+	if state != nil && !state.sessions[sessionId] {
+		// session could be created after state was cached
+		policy.cache.Delete(userStateKeyPrefix + userId)
+		state = policy.userState(ctx, userId)
+	}
+
+	if state == nil || !state.sessions[sessionId] {
+		if err := policy.cache.Save(noSessionKey, true, noSessionCacheTime); err != nil {
+			policy.log.Errorw("Fail to cache access policy", "key", noSessionKey, "error", err.Error())
+		}
+		return false
+	}
+
+	if state.banned && (state.until.IsZero() || state.until.After(time.Now())) {
+		return false
+	}
+	return state.sessions[sessionId]
+}
+
+func (policy *rolePolicy) ResetUser(userId string) {
+	policy.cache.Delete(userRolesKeyPrefix + userId)
+	policy.cache.Delete(userStateKeyPrefix + userId)
+}
+
+func (policy *rolePolicy) ResetRoles() {
+	policy.cache.Delete(rolesCacheKey)
+}
+
+func (policy *rolePolicy) roles(ctx core.ReqContext) []domain.Role {
+	if cached, ok := policy.cache.Get(rolesCacheKey); ok {
+		return cached.([]domain.Role)
+	}
+
+	roles := policy.roleRepo.GetList(ctx)
+	policy.save(rolesCacheKey, roles)
+	return roles
+}
+
+func (policy *rolePolicy) userRoles(ctx core.ReqContext, userId string) map[int]bool {
+	key := userRolesKeyPrefix + userId
+	if cached, ok := policy.cache.Get(key); ok {
+		return cached.(map[int]bool)
+	}
+
+	ids := make(map[int]bool)
+	for _, role := range policy.roleRepo.GetByUser(ctx, userId) {
+		ids[role.Id] = true
+	}
+	policy.save(key, ids)
+	return ids
+}
+
+func (policy *rolePolicy) userState(ctx core.ReqContext, userId string) *userState {
+	key := userStateKeyPrefix + userId
+	if cached, ok := policy.cache.Get(key); ok {
+		return cached.(*userState)
+	}
+
+	user := policy.userRepo.Get(ctx, userId)
+	if user == nil {
+		return nil
+	}
+
+	state := &userState{
+		banned:     user.Banned,
+		until:      user.BannedUntil,
+		confirmed:  user.EmailConfirmed,
+		reputation: user.Reputation,
+		sessions:   make(map[string]bool, len(user.Tokens)),
+	}
+	for _, t := range user.Tokens {
+		state.sessions[t.Id] = true
+	}
+	policy.save(key, state)
+	return state
+}
+
+func (policy *rolePolicy) save(key string, item interface{}) {
+	policy.cache.Delete(key)
+	if err := policy.cache.Save(key, item, policyCacheTime); err != nil {
+		policy.log.Errorw("Fail to cache access policy", "key", key, "error", err.Error())
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
@@ -13,67 +15,86 @@ import (
 )
 
 const (
-	Add    = 1
-	Edit   = 2
-	Delete = 3
+	Add     = 1
+	Edit    = 2
+	Delete  = 3
+	Restore = 4
 )
 
 type ChangesCollector struct {
 	changeLogRepo core.ChangeLogRepository
+	notifier      core.Notifier
+	webhooks      core.WebhookPublisher
 	log           core.AppLogger
 }
 
-func NewChangesCollector(repo core.ChangeLogRepository, logger core.AppLogger) core.ChangeLog {
-	return &ChangesCollector{changeLogRepo: repo, log: logger}
+func NewChangesCollector(repo core.ChangeLogRepository, notifier core.Notifier, webhooks core.WebhookPublisher, logger core.AppLogger) core.ChangeLog {
+	return &ChangesCollector{changeLogRepo: repo, notifier: notifier, webhooks: webhooks, log: logger}
 }
 
-func (collector *ChangesCollector) Added(entityType domain.EntityType, entityId int64, userId string) {
-	action, err := getActionType(entityType, Add)
+func (collector *ChangesCollector) Added(ctx core.ReqContext, entityType domain.EntityType, entityId int64) {
+	collector.saveRecord(ctx, Add, entityType, entityId, "", "", "")
+}
+
+func (collector *ChangesCollector) Edited(ctx core.ReqContext, entityType domain.EntityType, entityId int64, before interface{}, after interface{}) {
+	difference, err := diffEntities(before, after)
 	if err != nil {
-		collector.log.Errorw("Changes not logged", "error", err, "entityType", entityType, "entityId", entityId, "userId", userId, "action", Add)
+		collector.log.Errorw("Fail to get deff between entities", "error", err, "reqid", ctx.ReqId(), "entityType", entityType, "entityId", entityId, "userId", ctx.UserId(), "action", Edit)
 		return
 	}
 
-	collector.saveRecord(action, entityType, entityId, userId, "")
+	collector.saveRecord(ctx, Edit, entityType, entityId, entityKey(after), string(difference), "")
+	if entityType == domain.PlanEntity {
+		collector.notifier.PlanChanged(ctx, int(entityId))
+	}
 }
 
-func (collector *ChangesCollector) Edited(entityType domain.EntityType, entityId int64, userId string, before interface{}, after interface{}) {
-	action, err := getActionType(entityType, Edit)
+func (collector *ChangesCollector) Deleted(ctx core.ReqContext, entityType domain.EntityType, entityId int64, before interface{}) {
+	snapshot, err := snapshotEntity(before)
 	if err != nil {
-		collector.log.Errorw("Changes not logged", "error", err, "entityType", entityType, "entityId", entityId, "userId", userId, "action", Add)
-		return
+		// deletion is logged anyway, snapshot is only for history
+		collector.log.Errorw("Fail to get snapshot of entity", "error", err, "reqid", ctx.ReqId(), "entityType", entityType, "entityId", entityId, "userId", ctx.UserId(), "action", Delete)
 	}
 
-	difference, err := diffEntities(before, after)
-	if err != nil {
-		collector.log.Errorw("Fail to get deff between entities", "error", err, "entityType", entityType, "entityId", entityId, "userId", userId, "action", Add)
-		return
-	}
+	collector.saveRecord(ctx, Delete, entityType, entityId, entityKey(before), "", string(snapshot))
+}
 
-	collector.saveRecord(action, entityType, entityId, userId, string(difference))
+func (collector *ChangesCollector) Restored(ctx core.ReqContext, entityType domain.EntityType, entityId int64) {
+	collector.saveRecord(ctx, Restore, entityType, entityId, "", "", "")
+}
+
+// entityKey returns id of entity which can not be stored as number
+func entityKey(entity interface{}) string {
+	if user, ok := entity.(*domain.User); ok && user != nil {
+		return user.Id
+	}
+	return ""
 }
 
-func (collector *ChangesCollector) Deleted(entityType domain.EntityType, entityId int64, userId string) {
-	action, err := getActionType(entityType, Add)
+func (collector *ChangesCollector) saveRecord(ctx core.ReqContext, actionType int, entityType domain.EntityType, entityId int64, key string, diff string, snapshot string) {
+	action, err := getActionType(entityType, actionType)
 	if err != nil {
-		collector.log.Errorw("Changes not logged", "error", err, "entityType", entityType, "entityId", entityId, "userId", userId, "action", Add)
+		collector.log.Errorw("Changes not logged", "error", err, "reqid", ctx.ReqId(), "entityType", entityType, "entityId", entityId, "userId", ctx.UserId(), "action", actionType)
 		return
 	}
-	collector.saveRecord(action, entityType, entityId, userId, "")
-}
 
-func (collector *ChangesCollector) saveRecord(action domain.ChangeType, entityType domain.EntityType, entityId int64, userId string, diff string) {
 	record := &domain.ChangeLogRecord{
 		Action:     action,
-		UserId:     userId,
+		UserId:     ctx.UserId(),
 		EntityType: entityType,
 		EntityId:   entityId,
+		EntityKey:  key,
 		Diff:       diff,
+		Snapshot:   snapshot,
+		ReqId:      ctx.ReqId(),
+		IP:         ctx.ClientIP(),
+		UserAgent:  ctx.UserAgent(),
 	}
 
 	if !collector.changeLogRepo.Add(record) {
-		collector.log.Errorw("Changes not saved into db", "entityType", entityType, "entityId", entityId, "userId", userId, "action", Add)
+		collector.log.Errorw("Changes not saved into db", "reqid", ctx.ReqId(), "entityType", entityType, "entityId", entityId, "userId", ctx.UserId(), "action", action)
 	}
+	collector.webhooks.Publish(ctx, record)
 }
 
 func getActionType(eType domain.EntityType, action int) (domain.ChangeType, error) {
@@ -86,6 +107,8 @@ func getActionType(eType domain.EntityType, action int) (domain.ChangeType, erro
 			return domain.EditPlan, nil
 		case Delete:
 			return domain.DeletePlan, nil
+		case Restore:
+			return domain.RestorePlan, nil
 		}
 	case domain.TopicEntity:
 		switch action {
@@ -95,6 +118,8 @@ func getActionType(eType domain.EntityType, action int) (domain.ChangeType, erro
 			return domain.EditTopic, nil
 		case Delete:
 			return domain.DeleteTopic, nil
+		case Restore:
+			return domain.RestoreTopic, nil
 		}
 	case domain.ProjectEntity:
 		switch action {
@@ -113,6 +138,8 @@ func getActionType(eType domain.EntityType, action int) (domain.ChangeType, erro
 			return domain.EditResource, nil
 		case Delete:
 			return domain.DeleteResource, nil
+		case Restore:
+			return domain.RestoreResource, nil
 		}
 	case domain.CommentEntity:
 		switch action {
@@ -158,6 +185,77 @@ func diffEntities(before interface{}, after interface{}) ([]byte, error) {
 	return difference, err
 }
 
+func snapshotEntity(entity interface{}) ([]byte, error) {
+	var snapshot interface{}
+
+	switch v := entity.(type) {
+	case *domain.Plan:
+		steps := make([]stepSnapshot, len(v.Steps))
+		for i, step := range v.Steps {
+			steps[i] = stepSnapshot{
+				Id:            step.Id,
+				ReferenceId:   step.ReferenceId,
+				ReferenceType: step.ReferenceType,
+				Position:      step.Position,
+				Title:         step.Title,
+				Duration:      step.Duration,
+				Difficulty:    step.Difficulty,
+				Optional:      step.Optional,
+				Notes:         step.Notes,
+			}
+		}
+		snapshot = &planSnapshot{
+			Id:        v.Id,
+			Title:     v.Title,
+			TopicName: v.TopicName,
+			OwnerId:   v.OwnerId,
+			IsDraft:   v.IsDraft,
+			Hidden:    v.Hidden,
+			Steps:     steps,
+		}
+	case *domain.Topic:
+		tags := make([]string, len(v.Tags))
+		for i, tag := range v.Tags {
+			tags[i] = tag.Name
+		}
+		snapshot = &topicSnapshot{
+			Id:          v.Id,
+			Name:        v.Name,
+			Title:       v.Title,
+			Description: v.Description,
+			Creator:     v.Creator,
+			IsTag:       v.IsTag,
+			Tags:        tags,
+		}
+	case *domain.Source:
+		snapshot = &sourceSnapshot{
+			Id:         v.Id,
+			Title:      v.Title,
+			Identifier: v.Identifier,
+			Type:       v.Type,
+			Properties: v.Properties,
+			Img:        v.Img,
+			Desc:       v.Desc,
+		}
+	case *domain.Comment:
+		snapshot = &commentSnapshot{
+			Id:         v.Id,
+			EntityType: v.EntityType,
+			EntityId:   v.EntityId,
+			ThreadId:   v.ThreadId,
+			ParentId:   v.ParentId,
+			Date:       v.Date,
+			UserId:     v.UserId,
+			Text:       v.Text,
+			Title:      v.Title,
+		}
+	default:
+		return nil, fmt.Errorf("Unexpected type: %#v", entity)
+	}
+
+	return json.Marshal(snapshot)
+}
+
 func diffPlans(before *domain.Plan, after *domain.Plan) ([]byte, error) {
 	dmp := diffmatchpatch.New()
 
@@ -183,6 +281,7 @@ func diffPlans(before *domain.Plan, after *domain.Plan) ([]byte, error) {
 	d := &planDiff{
 		Title:   diffStrings(dmp, before.Title, after.Title),
 		OwnerId: diffStrings(dmp, before.OwnerId, after.OwnerId),
+		Hidden:  diffStrings(dmp, strconv.FormatBool(before.Hidden), strconv.FormatBool(after.Hidden)),
 		Steps:   stepsDiff,
 	}
 
@@ -251,6 +350,7 @@ func diffComment(before *domain.Comment, after *domain.Comment) ([]byte, error)
 		Text:    diffStrings(dmp, before.Text, after.Text),
 		Title:   diffStrings(dmp, before.Title, after.Title),
 		Deleted: diffStrings(dmp, strconv.FormatBool(before.Deleted), strconv.FormatBool(after.Deleted)),
+		Hidden:  diffStrings(dmp, strconv.FormatBool(before.Hidden), strconv.FormatBool(after.Hidden)),
 	}
 
 	b, err := json.Marshal(d)
@@ -260,14 +360,32 @@ func diffComment(before *domain.Comment, after *domain.Comment) ([]byte, error)
 	return b, nil
 }
 
+// sortedCopy sorts copy of list, list of caller stays unchanged
+func sortedCopy(list []string) []string {
+	result := make([]string, len(list))
+	copy(result, list)
+	sort.Strings(result)
+	return result
+}
+
 func diffUser(before *domain.User, after *domain.User) ([]byte, error) {
 	dmp := diffmatchpatch.New()
 
+	beforeRoles := sortedCopy(before.Roles)
+	afterRoles := sortedCopy(after.Roles)
+
 	d := &userDiff{
-		Name:   diffStrings(dmp, before.Name, after.Name),
-		Email:  diffStrings(dmp, before.Email, after.Email),
-		Img:    diffStrings(dmp, before.Img, after.Img),
-		Rights: diffStrings(dmp, strconv.Itoa(int(before.Rights)), strconv.Itoa(int(after.Rights))),
+		Id:             diffStrings(dmp, before.Id, after.Id),
+		Name:           diffStrings(dmp, before.Name, after.Name),
+		Email:          diffStrings(dmp, before.Email, after.Email),
+		EmailConfirmed: diffStrings(dmp, strconv.FormatBool(before.EmailConfirmed), strconv.FormatBool(after.EmailConfirmed)),
+		Img:            diffStrings(dmp, before.Img, after.Img),
+		Rights:         diffStrings(dmp, strconv.Itoa(int(before.Rights)), strconv.Itoa(int(after.Rights))),
+		Roles:          diffStrings(dmp, strings.Join(beforeRoles, ","), strings.Join(afterRoles, ",")),
+		Banned:         diffStrings(dmp, strconv.FormatBool(before.Banned), strconv.FormatBool(after.Banned)),
+		BanReason:      diffStrings(dmp, before.BanReason, after.BanReason),
+		BannedUntil:    diffStrings(dmp, formatTime(before.BannedUntil), formatTime(after.BannedUntil)),
+		Sessions:       diffStrings(dmp, strconv.Itoa(len(before.Tokens)), strconv.Itoa(len(after.Tokens))),
 	}
 
 	b, err := json.Marshal(d)
@@ -277,6 +395,13 @@ func diffUser(before *domain.User, after *domain.User) ([]byte, error) {
 	return b, nil
 }
 
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func diffStrings(dmp *diffmatchpatch.DiffMatchPatch, one, two string) string {
 	diffs := dmp.DiffMain(one, two, false)
 	return dmp.DiffPrettyText(diffs)
@@ -285,6 +410,7 @@ func diffStrings(dmp *diffmatchpatch.DiffMatchPatch, one, two string) string {
 type planDiff struct {
 	Title   string
 	OwnerId string
+	Hidden  string
 	Steps   []sliceDiff
 }
 
@@ -309,11 +435,74 @@ type commentDiff struct {
 	Text    string
 	Title   string
 	Deleted string
+	Hidden  string
 }
 
+// user ids are not numeric, so changed user is identified by Id
 type userDiff struct {
-	Name   string
-	Email  string
-	Img    string
-	Rights string
+	Id             string
+	Name           string
+	Email          string
+	EmailConfirmed string
+	Img            string
+	Rights         string
+	Roles          string
+	Banned         string
+	BanReason      string
+	BannedUntil    string
+	Sessions       string
+}
+
+type planSnapshot struct {
+	Id        int
+	Title     string
+	TopicName string
+	OwnerId   string
+	IsDraft   bool
+	Hidden    bool
+	Steps     []stepSnapshot
+}
+
+type stepSnapshot struct {
+	Id            int64
+	ReferenceId   int64
+	ReferenceType domain.ReferenceType
+	Position      int
+	Title         string
+	Duration      int
+	Difficulty    domain.Difficulty
+	Optional      bool
+	Notes         string
+}
+
+type topicSnapshot struct {
+	Id          int
+	Name        string
+	Title       string
+	Description string
+	Creator     string
+	IsTag       bool
+	Tags        []string
+}
+
+type sourceSnapshot struct {
+	Id         int64
+	Title      string
+	Identifier string
+	Type       domain.SourceType
+	Properties string
+	Img        string
+	Desc       string
+}
+
+type commentSnapshot struct {
+	Id         int64
+	EntityType domain.EntityType
+	EntityId   int64
+	ThreadId   int64
+	ParentId   int64
+	Date       time.Time
+	UserId     string
+	Text       string
+	Title      string
 }
@@ -0,0 +1,49 @@
+package infrastructure
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const maxUserAgentLength = 512
+
+// ClientInfo puts client ip, user agent and preferred language into request context.
+// Should be used after RealIP middleware to get ip behind proxy.
+func ClientInfo(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		userAgent := []rune(r.UserAgent())
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+
+		ctx := context.WithValue(r.Context(), ReqClientIP, ip)
+		ctx = context.WithValue(ctx, ReqUserAgent, string(userAgent))
+		ctx = context.WithValue(ctx, ReqLanguage, ParseLanguage(r.Header.Get("Accept-Language")))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// ParseLanguage returns primary language of the first tag in Accept-Language header,
+// "ru-RU,ru;q=0.9,en;q=0.8" gives "ru". Returns empty string if header is not valid.
+func ParseLanguage(header string) string {
+	tag := strings.TrimSpace(strings.SplitN(header, ",", 2)[0])
+	tag = strings.SplitN(tag, ";", 2)[0]
+	lang := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+	if len(lang) < 2 || len(lang) > 3 {
+		return ""
+	}
+	for _, c := range lang {
+		if c < 'a' || c > 'z' {
+			return ""
+		}
+	}
+	return lang
+}
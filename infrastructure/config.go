@@ -29,10 +29,93 @@ type Config struct {
 		Port        int    `json:"port"`
 		Pass        string `json:"pass"`
 	}
+	Email struct {
+		TemplatesPath   string `json:"templatesPath"`
+		DefaultLang     string `json:"defaultLang"`
+		SendIntervalSec int    `json:"sendIntervalSec"`
+		BatchSize       int    `json:"batchSize"`
+		MaxAttempts     int    `json:"maxAttempts"`
+		// if set, emails are sent to local smtp sink instead of SMTP server, for development only
+		SinkAddr string `json:"sinkAddr"`
+	}
 	OAuth struct {
 		ReturnUrl string           `json:"returnUrl"`
 		Providers []OauthProviders `json:"providers"`
 	}
+	// Confirmation links are valid LifetimeHours, a new link could be requested once in ResendIntervalSec.
+	// Restrict lists permissions which are not granted until email is confirmed. Votes of unconfirmed
+	// accounts are weighted by AntiFraud.UnconfirmedWeight, so points.add should not be restricted
+	Confirmation struct {
+		LifetimeHours     int      `json:"lifetimeHours"`
+		ResendIntervalSec int      `json:"resendIntervalSec"`
+		Restrict          []string `json:"restrict"`
+	}
+	// Mentions in markdown link to MentionUrl, {id} and {name} are replaced with id and name of the user
+	Markdown struct {
+		MentionUrl string `json:"mentionUrl"`
+	}
+	// Events are pushed to clients by server-sent events. Backend "postgres" delivers events between instances,
+	// empty backend keeps them in process. Stream is closed after StreamDurationSec and before server write timeout
+	Events struct {
+		Backend           string `json:"backend"`
+		StreamDurationSec int    `json:"streamDurationSec"`
+	}
+	// Changes of plans, topics and sources are sent to webhooks, failed delivery is retried up to MaxAttempts
+	Webhooks struct {
+		SendIntervalSec int `json:"sendIntervalSec"`
+		TimeoutSec      int `json:"timeoutSec"`
+		BatchSize       int `json:"batchSize"`
+		MaxAttempts     int `json:"maxAttempts"`
+		// allows webhooks to local and private networks, for development only
+		AllowPrivateNetworks bool `json:"allowPrivateNetworks"`
+	}
+	// Unread notifications are emailed once in DigestIntervalMin to users who enabled it, zero disables digest
+	Notifications struct {
+		DigestIntervalMin int `json:"digestIntervalMin"`
+		DigestBatchSize   int `json:"digestBatchSize"`
+	}
+	// Scores of plans are recomputed once in IntervalMin, zero disables ranking job.
+	// Prior and MinVotes are virtual votes of bayesian rating, WilsonZ is confidence quantile,
+	// weight of vote in hot list halves every HotHalfLifeHours, trending list counts votes of TrendingDays
+	Ranking struct {
+		IntervalMin      int     `json:"intervalMin"`
+		Prior            float64 `json:"prior"`
+		MinVotes         int     `json:"minVotes"`
+		WilsonZ          float64 `json:"wilsonZ"`
+		HotHalfLifeHours int     `json:"hotHalfLifeHours"`
+		TrendingDays     int     `json:"trendingDays"`
+	}
+	// Points of reputation awarded to authors for upvotes of plans and comments and for added sources, up to SourcesPerDay a day.
+	// Vote not less than UpvoteValue is upvote. Unlock grants permissions to users with reputation not less than value
+	Reputation struct {
+		UpvoteValue   int            `json:"upvoteValue"`
+		PlanUpvote    int            `json:"planUpvote"`
+		CommentUpvote int            `json:"commentUpvote"`
+		SourceAdded   int            `json:"sourceAdded"`
+		SourcesPerDay int            `json:"sourcesPerDay"`
+		Unlock        map[string]int `json:"unlock"`
+	}
+	// Votes made by user and from ip within WindowMin are limited, votes of accounts younger than NewAccountDays
+	// and of unconfirmed accounts are multiplied by weights. Ring job flags votes of at least RingMinSize accounts
+	// voting alike for RingMinShared entities within RingWindowMin of each other during RingLookbackDays
+	AntiFraud struct {
+		WindowMin         int     `json:"windowMin"`
+		MaxVotesPerUser   int     `json:"maxVotesPerUser"`
+		MaxVotesPerIP     int     `json:"maxVotesPerIp"`
+		NewAccountDays    int     `json:"newAccountDays"`
+		NewAccountWeight  float64 `json:"newAccountWeight"`
+		UnconfirmedWeight float64 `json:"unconfirmedWeight"`
+		RingIntervalMin   int     `json:"ringIntervalMin"`
+		RingLookbackDays  int     `json:"ringLookbackDays"`
+		RingWindowMin     int     `json:"ringWindowMin"`
+		RingMinShared     int     `json:"ringMinShared"`
+		RingMinSize       int     `json:"ringMinSize"`
+	}
+	// Deleted plans, topics and sources are kept DeletedDays before purge
+	Retention struct {
+		DeletedDays      int `json:"deletedDays"`
+		PurgeIntervalMin int `json:"purgeIntervalMin"`
+	}
 	//Cache struct {
 	//	Enable    bool   `json:"enable"`
 	//	host      string `json:"host"`
@@ -5,3 +5,7 @@ const ReqRights int = 1
 const ReqUserId int = 2
 const ReqUserName int = 3
 const Tracer int = 4
+const ReqPermissions int = 5
+const ReqClientIP int = 6
+const ReqUserAgent int = 7
+const ReqLanguage int = 8
@@ -1,10 +1,14 @@
 package db
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
 )
 
 type changeLogRepo struct {
@@ -15,16 +19,112 @@ func NewChangeLogRepository(db *DbConnection) core.ChangeLogRepository {
 	return &changeLogRepo{Db: db}
 }
 
-// TODO: add indexes int db!
 func (r *changeLogRepo) Add(record *domain.ChangeLogRecord) bool {
 	dbo := &ChangeLogRecordDBO{}
 	dbo.FromChangeLogRecord(record)
-	query := `INSERT INTO changelog(date, action, userid, entitytype, entityid, diff, points)
-		VALUES (now(), $1, $2, $3, $4, $5, 0);`
-	tag, err := r.Db.Conn.Exec(context.Background(), query, dbo.Action, dbo.UserId, dbo.EntityType, dbo.EntityId, dbo.Diff)
+	query := `INSERT INTO changelog(date, action, userid, entitytype, entityid, entitykey, diff, snapshot, points, reqid, ip, useragent)
+		VALUES (now(), $1, $2, $3, $4, $5, $6, $7, 0, $8, $9, $10);`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, dbo.Action, dbo.UserId, dbo.EntityType, dbo.EntityId, dbo.EntityKey, dbo.Diff, dbo.Snapshot, dbo.ReqId, dbo.IP, dbo.UserAgent)
 	if err != nil {
 		r.Db.LogError(err, query)
 		return false
 	}
 	return tag.RowsAffected() > 0
 }
+
+func (r *changeLogRepo) Award(record *domain.ChangeLogRecord) bool {
+	dbo := &ChangeLogRecordDBO{}
+	dbo.FromChangeLogRecord(record)
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		r.Db.LogError(err, "")
+		return false
+	}
+	defer tx.Rollback(context.Background())
+
+	query := `INSERT INTO changelog(date, action, userid, entitytype, entityid, entitykey, diff, snapshot, points, reqid, ip, useragent)
+		VALUES (now(), $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`
+	if _, err := tx.Exec(context.Background(), query, dbo.Action, dbo.UserId, dbo.EntityType, dbo.EntityId, dbo.EntityKey, dbo.Diff, dbo.Snapshot, dbo.Points, dbo.ReqId, dbo.IP, dbo.UserAgent); err != nil {
+		r.Db.LogError(err, query)
+		return false
+	}
+
+	reputationQuery := `UPDATE users SET reputation = reputation + $1 WHERE id = $2`
+	tag, err := tx.Exec(context.Background(), reputationQuery, dbo.Points, dbo.UserId)
+	if err != nil {
+		r.Db.LogError(err, reputationQuery)
+		return false
+	}
+	if tag.RowsAffected() == 0 {
+		return false
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		r.Db.LogError(err, "")
+		return false
+	}
+	return true
+}
+
+func (r *changeLogRepo) CountAwards(userId string, entityType domain.EntityType, since time.Time) int {
+	query := `SELECT count(*) FROM changelog WHERE action = $1 AND userid = $2 AND entitytype = $3 AND date > $4;`
+	var count int
+	if err := r.Db.Conn.QueryRow(context.Background(), query, int(domain.AwardReputation), userId, int(entityType), since).Scan(&count); err != nil {
+		r.Db.LogError(err, query)
+		return 0
+	}
+	return count
+}
+
+func (r *changeLogRepo) Search(ctx core.ReqContext, filter domain.ChangeLogFilter, count int, page int) []domain.ChangeLogRecord {
+	tr := ctx.StartTrace("ChangeLogRepository.Search")
+	defer ctx.StopTrace(tr)
+
+	var buffer bytes.Buffer
+	buffer.WriteString("SELECT id, date, action, userid, entitytype, entityid, entitykey, diff, snapshot, points, reqid, ip, useragent FROM changelog WHERE true ")
+	params := make([]interface{}, 0, 5)
+	if filter.UserId != "" {
+		params = append(params, filter.UserId)
+		buffer.WriteString(fmt.Sprintf(" AND userid = $%d ", len(params)))
+	}
+	if filter.EntityType != 0 {
+		params = append(params, int(filter.EntityType))
+		buffer.WriteString(fmt.Sprintf(" AND entitytype = $%d ", len(params)))
+	}
+	if filter.Action != 0 {
+		params = append(params, int(filter.Action))
+		buffer.WriteString(fmt.Sprintf(" AND action = $%d ", len(params)))
+	}
+	params = append(params, count, page*count)
+	buffer.WriteString(fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", len(params)-1, len(params)))
+
+	query := buffer.String()
+	rows, err := r.Db.Conn.Query(context.Background(), query, params...)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.ChangeLogRecord{}
+	}
+	defer rows.Close()
+
+	records := make([]domain.ChangeLogRecord, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			r.Db.LogError(err, query)
+			return []domain.ChangeLogRecord{}
+		}
+		records = append(records, *dbo.ToChangeLogRecord())
+	}
+	return records
+}
+
+func (r *changeLogRepo) scanRow(row pgx.Row) (*ChangeLogRecordDBO, error) {
+	dbo := ChangeLogRecordDBO{}
+	err := row.Scan(&dbo.Id, &dbo.Date, &dbo.Action, &dbo.UserId, &dbo.EntityType, &dbo.EntityId, &dbo.EntityKey, &dbo.Diff, &dbo.Snapshot, &dbo.Points, &dbo.ReqId, &dbo.IP, &dbo.UserAgent)
+	return &dbo, err
+}
@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
@@ -20,35 +22,140 @@ func NewCommentsRepository(db *DbConnection) core.CommentsRepository {
 func (r *commentsRepo) Add(ctx core.ReqContext, comment *domain.Comment) (bool, error) {
 	dbo := &CommentDBO{}
 	dbo.FromComment(comment)
-	query := `INSERT INTO comments (entitytype, entityid, date, parentid, threadid, userid, text, title, deleted)
-		VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;`
+	query := `INSERT INTO comments (entitytype, entityid, date, parentid, threadid, userid, text, html, title, deleted)
+		VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, version;`
 	tr := ctx.StartTrace("CommentsRepository.Add")
 	defer ctx.StopTrace(tr)
-	row := r.Db.Conn.QueryRow(context.Background(), query, dbo.EntityType, dbo.EntityId, dbo.Date, dbo.ParentId, dbo.ThreadId, dbo.UserId, dbo.Text, dbo.Title, dbo.Deleted)
-	err := row.Scan(&comment.Id)
+	row := r.Db.Conn.QueryRow(context.Background(), query, dbo.EntityType, dbo.EntityId, dbo.Date, dbo.ParentId, dbo.ThreadId, dbo.UserId, dbo.Text, dbo.Html, dbo.Title, dbo.Deleted)
+	err := row.Scan(&comment.Id, &comment.Version)
 	if err != nil {
 		return false, r.Db.LogError(err, query)
 	}
 	return true, nil
 }
 
-func (r *commentsRepo) Update(ctx core.ReqContext, id int64, text, title string) (bool, error) {
-	query := `UPDATE comments SET text=$1, title=$2, WHERE id=$3;`
+func (r *commentsRepo) Update(ctx core.ReqContext, id int64, version int, text, html, title string, editorId string) (bool, *core.AppError) {
 	tr := ctx.StartTrace("CommentsRepository.Update")
 	defer ctx.StopTrace(tr)
-	tag, err := r.Db.Conn.Exec(context.Background(), query, text, ToNullString(title), id)
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	// current version is saved as revision written by the last editor
+	revisionQuery := `INSERT INTO comment_revisions (commentid, text, html, title, editorid, date)
+	SELECT id, text, html, title, COALESCE(editedby, userid), COALESCE(editedat, date) FROM comments WHERE id=$1 AND version=$2;`
+	tag, err := tx.Exec(context.Background(), revisionQuery, id, version)
+	if err != nil {
+		return false, r.Db.LogError(err, revisionQuery)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, core.NewError(core.Conflict)
+	}
+
+	// version is checked again, comment could be changed after revision was read
+	query := `UPDATE comments SET text=$1, html=$2, title=$3, editedat=$4, editedby=$5, version=version+1 WHERE id=$6 AND version=$7;`
+	tag, err = tx.Exec(context.Background(), query, text, ToNullString(html), ToNullString(title), time.Now().UTC(), editorId, id, version)
 	if err != nil {
 		return false, r.Db.LogError(err, query)
 	}
+	if tag.RowsAffected() == 0 {
+		return false, core.NewError(core.Conflict)
+	}
 
-	return tag.RowsAffected() > 0, nil
+	if err := tx.Commit(context.Background()); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	return true, nil
 }
 
-func (r *commentsRepo) Delete(ctx core.ReqContext, id int64) (bool, error) {
-	query := `UPDATE comments SET deleted=$1 WHERE id=$2;`
+func (r *commentsRepo) Delete(ctx core.ReqContext, id int64, deletedBy string) (bool, error) {
+	query := `UPDATE comments SET deleted=$1, deletedby=$2 WHERE id=$3;`
 	tr := ctx.StartTrace("CommentsRepository.Delete")
 	defer ctx.StopTrace(tr)
-	tag, err := r.Db.Conn.Exec(context.Background(), query, true, id)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, true, deletedBy, id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *commentsRepo) GetRevisions(ctx core.ReqContext, commentId int64) []domain.CommentRevision {
+	query := `SELECT id, commentid, text, html, title, editorid, date FROM comment_revisions WHERE commentid=$1 ORDER BY id;`
+	tr := ctx.StartTrace("CommentsRepository.GetRevisions")
+	defer ctx.StopTrace(tr)
+	rows, err := r.Db.Conn.Query(context.Background(), query, commentId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.CommentRevision{}
+	}
+	defer rows.Close()
+
+	revisions := make([]domain.CommentRevision, 0)
+	for rows.Next() {
+		dbo := CommentRevisionDBO{}
+		if err := rows.Scan(&dbo.Id, &dbo.CommentId, &dbo.Text, &dbo.Html, &dbo.Title, &dbo.EditorId, &dbo.Date); err != nil {
+			r.Db.LogError(err, query)
+			return []domain.CommentRevision{}
+		}
+		revisions = append(revisions, *dbo.ToCommentRevision())
+	}
+	return revisions
+}
+
+func (r *commentsRepo) SetHidden(ctx core.ReqContext, id int64, hidden bool) (bool, error) {
+	query := `UPDATE comments SET hidden=$1 WHERE id=$2;`
+	tr := ctx.StartTrace("CommentsRepository.SetHidden")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, hidden, id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *commentsRepo) SetPinned(ctx core.ReqContext, id int64, pinned bool) (bool, error) {
+	query := `UPDATE comments SET pinned=$1 WHERE id=$2 AND threadid IS NULL;`
+	tr := ctx.StartTrace("CommentsRepository.SetPinned")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, pinned, id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *commentsRepo) SetAnswer(ctx core.ReqContext, threadId int64, answerId int64) (bool, error) {
+	query := `UPDATE comments SET answerid=$1 WHERE id=$2 AND threadid IS NULL;`
+	tr := ctx.StartTrace("CommentsRepository.SetAnswer")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, ToNullInt64(answerId), threadId)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *commentsRepo) ToggleReaction(ctx core.ReqContext, commentId int64, userId string, reaction domain.Reaction) (bool, error) {
+	// reaction is removed if exists, otherwise added
+	query := `WITH removed AS (
+		DELETE FROM comment_reactions WHERE commentid=$1 AND userid=$2 AND reaction=$3 RETURNING commentid
+	)
+	INSERT INTO comment_reactions (commentid, userid, reaction, date)
+	SELECT $1, $2, $3, $4 WHERE NOT EXISTS (SELECT 1 FROM removed)
+	ON CONFLICT DO NOTHING;`
+	tr := ctx.StartTrace("CommentsRepository.ToggleReaction")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, commentId, userId, string(reaction), time.Now().UTC())
 	if err != nil {
 		return false, r.Db.LogError(err, query)
 	}
@@ -56,8 +163,72 @@ func (r *commentsRepo) Delete(ctx core.ReqContext, id int64) (bool, error) {
 	return tag.RowsAffected() > 0, nil
 }
 
+func (r *commentsRepo) GetReactions(ctx core.ReqContext, userId string, commentIds []int64) map[int64][]domain.ReactionSummary {
+	result := make(map[int64][]domain.ReactionSummary)
+	if len(commentIds) == 0 {
+		return result
+	}
+
+	query := `SELECT commentid, reaction, count(*), bool_or(userid = $2)
+	FROM comment_reactions
+	WHERE commentid = ANY($1)
+	GROUP BY commentid, reaction
+	ORDER BY commentid, min(date);`
+	tr := ctx.StartTrace("CommentsRepository.GetReactions")
+	defer ctx.StopTrace(tr)
+	rows, err := r.Db.Conn.Query(context.Background(), query, commentIds, userId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentId int64
+		var reaction string
+		summary := domain.ReactionSummary{}
+		if err := rows.Scan(&commentId, &reaction, &summary.Count, &summary.Reacted); err != nil {
+			r.Db.LogError(err, query)
+			return make(map[int64][]domain.ReactionSummary)
+		}
+		summary.Reaction = domain.Reaction(reaction)
+		result[commentId] = append(result[commentId], summary)
+	}
+	return result
+}
+
+func (r *commentsRepo) Count(ctx core.ReqContext, entityType domain.EntityType, entityIds []int64) map[int64]int {
+	result := make(map[int64]int)
+	if len(entityIds) == 0 {
+		return result
+	}
+
+	query := `SELECT entityid, count(*) FROM comments
+	WHERE entitytype = $1 AND entityid = ANY($2) AND deleted = false AND hidden = false
+	GROUP BY entityid;`
+	tr := ctx.StartTrace("CommentsRepository.Count")
+	defer ctx.StopTrace(tr)
+	rows, err := r.Db.Conn.Query(context.Background(), query, int(entityType), entityIds)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return result
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entityId int64
+		var count int
+		if err := rows.Scan(&entityId, &count); err != nil {
+			r.Db.LogError(err, query)
+			return make(map[int64]int)
+		}
+		result[entityId] = count
+	}
+	return result
+}
+
 func (r *commentsRepo) Get(ctx core.ReqContext, id int64) *domain.Comment {
-	query := `SELECT id, entitytype, entityid, date, parentid, threadid, userid, text, title, deleted FROM comments WHERE id=$1;`
+	query := `SELECT id, entitytype, entityid, date, parentid, threadid, userid, text, html, title, deleted, hidden, pinned, answerid, editedat, editedby, deletedby, version FROM comments WHERE id=$1;`
 	tr := ctx.StartTrace("CommentsRepository.Get")
 	defer ctx.StopTrace(tr)
 	row := r.Db.Conn.QueryRow(context.Background(), query, id)
@@ -72,58 +243,102 @@ func (r *commentsRepo) Get(ctx core.ReqContext, id int64) *domain.Comment {
 	return p.ToComment()
 }
 
-func (r *commentsRepo) GetThreadList(ctx core.ReqContext, entityType int, entityId int64, count int, page int) []domain.Comment {
-	query := `SELECT id, entitytype, entityid, date, parentid, threadid, userid, text, title, deleted 
-	FROM comments 
-	WHERE entitytype=$1 
-		AND entityid=$2 
-		AND threadId is null
-	ORDER BY id DESC
-	LIMIT $3 OFFSET $4;`
+func (r *commentsRepo) GetThreadList(ctx core.ReqContext, entityType int, entityId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor) {
 	tr := ctx.StartTrace("CommentsRepository.GetThreadList")
 	defer ctx.StopTrace(tr)
-	rows, err := r.Db.Conn.Query(context.Background(), query, entityType, entityId, count, page*count)
-	if err != nil {
-		r.Db.LogError(err, query)
-		return []domain.Comment{}
-	}
-	defer rows.Close()
-	return r.scanRows(rows)
+	return r.getPage("c.threadid IS NULL", "threadid", []interface{}{entityType, entityId}, sort, after, count)
 }
 
-func (r *commentsRepo) GetThread(ctx core.ReqContext, entityType int, entityId int64, threadId int64) []domain.Comment {
-	query := `SELECT id, entitytype, entityid, date, parentid, threadid, userid, text, title, deleted 
-	FROM comments 
-	WHERE entitytype = $1 
-		AND entityid = $2 
-		AND threadId = $3
-	ORDER BY id;`
+func (r *commentsRepo) GetThread(ctx core.ReqContext, entityType int, entityId int64, threadId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor) {
 	tr := ctx.StartTrace("CommentsRepository.GetThread")
 	defer ctx.StopTrace(tr)
-	rows, err := r.Db.Conn.Query(context.Background(), query, entityType, entityId, threadId)
+	return r.getPage("c.threadid = $3", "parentid", []interface{}{entityType, entityId, threadId}, sort, after, count)
+}
+
+// getPage selects comments of entity matching filter, in order of sort and after cursor.
+// Replies of comment are counted by repliesColumn. Cursor is returned if there are more comments.
+func (r *commentsRepo) getPage(filter string, repliesColumn string, args []interface{}, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor) {
+	keyColumn := "0"
+	switch sort {
+	case domain.CommentSortTop:
+		keyColumn = "points"
+	case domain.CommentSortReplies:
+		keyColumn = "replies"
+	}
+
+	order := "pinned DESC, id DESC"
+	switch sort {
+	case domain.CommentSortOldest:
+		order = "pinned DESC, id ASC"
+	case domain.CommentSortTop, domain.CommentSortReplies:
+		order = fmt.Sprintf("pinned DESC, %s DESC, id DESC", keyColumn)
+	}
+
+	condition := "TRUE"
+	if after != nil {
+		n := len(args)
+		switch sort {
+		case domain.CommentSortOldest:
+			condition = fmt.Sprintf("(pinned < $%d OR (pinned = $%d AND id > $%d))", n+1, n+1, n+2)
+			args = append(args, after.Pinned, after.Id)
+		case domain.CommentSortTop, domain.CommentSortReplies:
+			condition = fmt.Sprintf("(pinned, %s, id) < ($%d, $%d, $%d)", keyColumn, n+1, n+2, n+3)
+			args = append(args, after.Pinned, after.Key, after.Id)
+		default:
+			condition = fmt.Sprintf("(pinned, id) < ($%d, $%d)", n+1, n+2)
+			args = append(args, after.Pinned, after.Id)
+		}
+	}
+	args = append(args, count+1)
+
+	query := fmt.Sprintf(`SELECT id, entitytype, entityid, date, parentid, threadid, userid, text, html, title, deleted, hidden, pinned, answerid, editedat, editedby, deletedby, version, replies, %s
+	FROM (
+		SELECT c.id, c.entitytype, c.entityid, c.date, c.parentid, c.threadid, c.userid, c.text, c.html, c.title, c.deleted, c.hidden, c.pinned, c.answerid, c.editedat, c.editedby, c.deletedby, c.version,
+			(SELECT count(*) FROM comments r WHERE r.%s = c.id AND r.deleted = false) AS replies,
+			COALESCE(ps.value, 0) AS points
+		FROM comments c
+		LEFT JOIN points_aggregated_comments ps ON ps.entityid = c.id
+		WHERE c.entitytype = $1
+			AND c.entityid = $2
+			AND %s
+	) t
+	WHERE %s
+	ORDER BY %s
+	LIMIT $%d;`, keyColumn, repliesColumn, filter, condition, order, len(args))
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, args...)
 	if err != nil {
 		r.Db.LogError(err, query)
-		return []domain.Comment{}
+		return []domain.Comment{}, nil
 	}
 	defer rows.Close()
-	return r.scanRows(rows)
-}
 
-func (r *commentsRepo) scanRows(rows pgx.Rows) []domain.Comment {
-	comments := make([]domain.Comment, 0)
+	comments := make([]domain.Comment, 0, count)
+	var last domain.CommentCursor
 	for rows.Next() {
-		dbo, err := r.scanRow(rows)
+		if len(comments) == count {
+			return comments, &last
+		}
+
+		dbo := CommentDBO{}
+		var replies, key int64
+		err := rows.Scan(&dbo.Id, &dbo.EntityType, &dbo.EntityId, &dbo.Date, &dbo.ParentId, &dbo.ThreadId, &dbo.UserId, &dbo.Text, &dbo.Html, &dbo.Title, &dbo.Deleted, &dbo.Hidden, &dbo.Pinned, &dbo.AnswerId, &dbo.EditedAt, &dbo.EditedBy, &dbo.DeletedBy, &dbo.Version, &replies, &key)
 		if err != nil {
-			return []domain.Comment{}
+			r.Db.LogError(err, query)
+			return []domain.Comment{}, nil
 		}
-		comments = append(comments, *dbo.ToComment())
+
+		comment := dbo.ToComment()
+		comment.Replies = int(replies)
+		comments = append(comments, *comment)
+		last = domain.CommentCursor{Sort: sort, Pinned: dbo.Pinned, Key: key, Id: dbo.Id}
 	}
-	return comments
+	return comments, nil
 }
 
 func (r *commentsRepo) scanRow(row pgx.Row) (*CommentDBO, error) {
 	dbo := CommentDBO{}
-	err := row.Scan(&dbo.Id, &dbo.EntityType, &dbo.EntityId, &dbo.Date, &dbo.ParentId, &dbo.ThreadId, &dbo.UserId, &dbo.Text, &dbo.Title, &dbo.Deleted)
+	err := row.Scan(&dbo.Id, &dbo.EntityType, &dbo.EntityId, &dbo.Date, &dbo.ParentId, &dbo.ThreadId, &dbo.UserId, &dbo.Text, &dbo.Html, &dbo.Title, &dbo.Deleted, &dbo.Hidden, &dbo.Pinned, &dbo.AnswerId, &dbo.EditedAt, &dbo.EditedBy, &dbo.DeletedBy, &dbo.Version)
 	if err != nil && err.Error() == "no rows in result set" {
 		return &dbo, sql.ErrNoRows
 	}
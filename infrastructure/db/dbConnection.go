@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/infrastructure"
@@ -13,6 +14,12 @@ import (
 	"github.com/jackc/pgx/v4"
 )
 
+// errVersionConflict rolls back transaction when version of updated entity differs from expected one
+var errVersionConflict = errors.New("version of entity changed")
+
+// txAttempts is how many times transaction is run when it fails because of concurrent transactions
+const txAttempts = 3
+
 type DbConnection struct {
 	Conn *pgxpool.Pool
 	Log  core.AppLogger
@@ -97,3 +104,38 @@ func (db *DbConnection) LogError(err error, query string) *core.AppError {
 	return core.NewError(core.InternalError)
 
 }
+
+// InTx runs fn in serializable transaction and repeats it when transaction fails
+// because of concurrent transactions. fn should have no side effects outside of tx
+func (db *DbConnection) InTx(fn func(tx pgx.Tx) error) error {
+	var err error
+	for attempt := 0; attempt < txAttempts; attempt++ {
+		err = db.runTx(fn)
+		if !isSerializationFailure(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (db *DbConnection) runTx(fn func(tx pgx.Tx) error) error {
+	tx, err := db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.Serializable,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func isSerializationFailure(err error) bool {
+	pgerr, ok := err.(*pgconn.PgError)
+	return ok && (pgerr.Code == "40001" || pgerr.Code == "40P01")
+}
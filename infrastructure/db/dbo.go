@@ -12,24 +12,28 @@ import (
 	Topic
  ******************/
 type TopicDBO struct {
-	Id          int
-	Name        string
-	Title       string
-	Description sql.NullString
-	Creator     string
-	Tags        []string
-	IsTag       bool
+	Id              int
+	Name            string
+	Title           string
+	Description     sql.NullString
+	DescriptionHtml sql.NullString
+	Creator         string
+	Tags            []string
+	IsTag           bool
+	Version         int
 }
 
 func (dbo *TopicDBO) ToTopic(tags []domain.TopicTag) *domain.Topic {
 	t := &domain.Topic{
-		Id:          dbo.Id,
-		Name:        dbo.Name,
-		Title:       dbo.Title,
-		Description: dbo.Description.String,
-		Creator:     dbo.Creator,
-		Tags:        tags,
-		IsTag:       dbo.IsTag,
+		Id:              dbo.Id,
+		Name:            dbo.Name,
+		Title:           dbo.Title,
+		Description:     dbo.Description.String,
+		DescriptionHtml: dbo.DescriptionHtml.String,
+		Creator:         dbo.Creator,
+		Tags:            tags,
+		IsTag:           dbo.IsTag,
+		Version:         dbo.Version,
 	}
 	return t
 }
@@ -39,8 +43,10 @@ func (dbo *TopicDBO) FromTopic(d *domain.Topic) {
 	dbo.Name = d.Name
 	dbo.Title = d.Title
 	dbo.Description = ToNullString(d.Description)
+	dbo.DescriptionHtml = ToNullString(d.DescriptionHtml)
 	dbo.Creator = d.Creator
 	dbo.IsTag = d.IsTag
+	dbo.Version = d.Version
 	dbo.Tags = make([]string, len(d.Tags))
 
 	for i, tag := range d.Tags {
@@ -57,6 +63,9 @@ type PlanDBO struct {
 	TopicName string
 	OwnerId   string
 	IsDraft   bool
+	Hidden    bool
+	ParentId  sql.NullInt64
+	Version   int
 }
 
 func (dbo *PlanDBO) ToPlan() *domain.Plan {
@@ -66,6 +75,9 @@ func (dbo *PlanDBO) ToPlan() *domain.Plan {
 		TopicName: dbo.TopicName,
 		OwnerId:   dbo.OwnerId,
 		IsDraft:   dbo.IsDraft,
+		Hidden:    dbo.Hidden,
+		ParentId:  int(dbo.ParentId.Int64),
+		Version:   dbo.Version,
 	}
 }
 
@@ -75,6 +87,9 @@ func (dbo *PlanDBO) FromPlan(plan *domain.Plan) {
 	dbo.TopicName = plan.TopicName
 	dbo.OwnerId = plan.OwnerId
 	dbo.IsDraft = plan.IsDraft
+	dbo.Hidden = plan.Hidden
+	dbo.ParentId = ToNullInt64(int64(plan.ParentId))
+	dbo.Version = plan.Version
 }
 
 /*
@@ -125,6 +140,12 @@ type StepDBO struct {
 	ReferenceType string
 	Position      int
 	Title         string
+	TitleHtml     sql.NullString
+	Duration      int
+	Difficulty    sql.NullString
+	Optional      bool
+	Notes         sql.NullString
+	NotesHtml     sql.NullString
 }
 
 func (dbo *StepDBO) FromStep(step *domain.Step) {
@@ -134,6 +155,12 @@ func (dbo *StepDBO) FromStep(step *domain.Step) {
 	dbo.ReferenceId = step.ReferenceId
 	dbo.ReferenceType = string(step.ReferenceType)
 	dbo.Title = step.Title
+	dbo.TitleHtml = ToNullString(step.TitleHtml)
+	dbo.Duration = step.Duration
+	dbo.Difficulty = ToNullString(string(step.Difficulty))
+	dbo.Optional = step.Optional
+	dbo.Notes = ToNullString(step.Notes)
+	dbo.NotesHtml = ToNullString(step.NotesHtml)
 }
 
 func (dbo *StepDBO) ToStep() *domain.Step {
@@ -144,6 +171,12 @@ func (dbo *StepDBO) ToStep() *domain.Step {
 		ReferenceType: domain.ReferenceType(dbo.ReferenceType),
 		Position:      dbo.Position,
 		Title:         dbo.Title,
+		TitleHtml:     dbo.TitleHtml.String,
+		Duration:      dbo.Duration,
+		Difficulty:    domain.Difficulty(dbo.Difficulty.String),
+		Optional:      dbo.Optional,
+		Notes:         dbo.Notes.String,
+		NotesHtml:     dbo.NotesHtml.String,
 	}
 }
 
@@ -162,13 +195,21 @@ type UserDBO struct {
 	Rights            int
 	Pass              []byte
 	Salt              []byte
+	Banned            bool
+	BanReason         sql.NullString
+	BannedUntil       *time.Time
+	ConfirmationExp   *time.Time
+	ConfirmationSent  *time.Time
+	PendingEmail      sql.NullString
+	Reputation        int
+	Created           *time.Time
 }
 
 func (dbo *UserDBO) ToUser() *domain.User {
 	tokens := make([]domain.UserToken, 0)
 	json.Unmarshal([]byte(dbo.Tokens.String), &tokens)
 
-	return &domain.User{
+	user := &domain.User{
 		Id:                dbo.Id,
 		Name:              dbo.Name,
 		NormalizedName:    dbo.NormalizedName,
@@ -180,7 +221,25 @@ func (dbo *UserDBO) ToUser() *domain.User {
 		Rights:            domain.Rights(dbo.Rights),
 		Pass:              dbo.Pass,
 		Salt:              dbo.Salt,
+		Banned:            dbo.Banned,
+		BanReason:         dbo.BanReason.String,
+		PendingEmail:      dbo.PendingEmail.String,
+		Reputation:        dbo.Reputation,
+	}
+
+	if dbo.BannedUntil != nil {
+		user.BannedUntil = *dbo.BannedUntil
+	}
+	if dbo.ConfirmationExp != nil {
+		user.EmailConfirmationExpires = *dbo.ConfirmationExp
 	}
+	if dbo.ConfirmationSent != nil {
+		user.EmailConfirmationSent = *dbo.ConfirmationSent
+	}
+	if dbo.Created != nil {
+		user.Created = *dbo.Created
+	}
+	return user
 }
 
 func (dbo *UserDBO) FromUser(u *domain.User) {
@@ -196,6 +255,22 @@ func (dbo *UserDBO) FromUser(u *domain.User) {
 	dbo.Rights = int(u.Rights)
 	dbo.Pass = u.Pass
 	dbo.Salt = u.Salt
+	dbo.Banned = u.Banned
+	dbo.BanReason = ToNullString(u.BanReason)
+	if !u.BannedUntil.IsZero() {
+		bannedUntil := u.BannedUntil
+		dbo.BannedUntil = &bannedUntil
+	}
+	if !u.EmailConfirmationExpires.IsZero() {
+		expires := u.EmailConfirmationExpires
+		dbo.ConfirmationExp = &expires
+	}
+	if !u.EmailConfirmationSent.IsZero() {
+		sent := u.EmailConfirmationSent
+		dbo.ConfirmationSent = &sent
+	}
+	dbo.PendingEmail = ToNullString(u.PendingEmail)
+	dbo.Reputation = u.Reputation
 }
 
 /*
@@ -243,7 +318,7 @@ func (dbo *TopicTagDBO) FromTopicTag(tag *domain.TopicTag) {
 
 /*
 	Comments
-*******************/
+ ******************/
 type CommentDBO struct {
 	Id         int64
 	EntityType int
@@ -253,12 +328,20 @@ type CommentDBO struct {
 	Date       time.Time
 	UserId     string
 	Text       string
+	Html       sql.NullString
 	Title      sql.NullString
 	Deleted    bool
+	Hidden     bool
+	Pinned     bool
+	AnswerId   sql.NullInt64
+	EditedAt   *time.Time
+	EditedBy   sql.NullString
+	DeletedBy  sql.NullString
+	Version    int
 }
 
 func (dbo *CommentDBO) ToComment() *domain.Comment {
-	return &domain.Comment{
+	comment := &domain.Comment{
 		Id:         dbo.Id,
 		EntityType: domain.EntityType(dbo.EntityType),
 		EntityId:   dbo.EntityId,
@@ -267,10 +350,21 @@ func (dbo *CommentDBO) ToComment() *domain.Comment {
 		Date:       dbo.Date,
 		UserId:     dbo.UserId,
 		Text:       dbo.Text,
+		Html:       dbo.Html.String,
 		Title:      dbo.Title.String,
 		Deleted:    dbo.Deleted,
+		Hidden:     dbo.Hidden,
+		Pinned:     dbo.Pinned,
+		AnswerId:   dbo.AnswerId.Int64,
+		EditedBy:   dbo.EditedBy.String,
+		DeletedBy:  dbo.DeletedBy.String,
+		Version:    dbo.Version,
 		Childs:     []domain.Comment{},
 	}
+	if dbo.EditedAt != nil {
+		comment.EditedAt = *dbo.EditedAt
+	}
+	return comment
 }
 
 func (dbo *CommentDBO) FromComment(c *domain.Comment) {
@@ -282,13 +376,47 @@ func (dbo *CommentDBO) FromComment(c *domain.Comment) {
 	dbo.Date = c.Date
 	dbo.UserId = c.UserId
 	dbo.Text = c.Text
+	dbo.Html = ToNullString(c.Html)
 	dbo.Title = ToNullString(c.Title)
 	dbo.Deleted = c.Deleted
+	dbo.Pinned = c.Pinned
+	dbo.AnswerId = ToNullInt64(c.AnswerId)
+	dbo.EditedBy = ToNullString(c.EditedBy)
+	dbo.DeletedBy = ToNullString(c.DeletedBy)
+	dbo.Version = c.Version
+	if !c.EditedAt.IsZero() {
+		dbo.EditedAt = &c.EditedAt
+	}
+}
+
+/*
+	CommentRevision
+ ******************/
+type CommentRevisionDBO struct {
+	Id        int64
+	CommentId int64
+	Text      string
+	Html      sql.NullString
+	Title     sql.NullString
+	EditorId  string
+	Date      time.Time
+}
+
+func (dbo *CommentRevisionDBO) ToCommentRevision() *domain.CommentRevision {
+	return &domain.CommentRevision{
+		Id:        dbo.Id,
+		CommentId: dbo.CommentId,
+		Text:      dbo.Text,
+		Html:      dbo.Html.String,
+		Title:     dbo.Title.String,
+		EditorId:  dbo.EditorId,
+		Date:      dbo.Date,
+	}
 }
 
 /*
 	ChangeLogRecord
-*******************/
+ ******************/
 type ChangeLogRecordDBO struct {
 	Id         int64
 	Date       time.Time
@@ -296,8 +424,13 @@ type ChangeLogRecordDBO struct {
 	UserId     string
 	EntityType int
 	EntityId   int64
+	EntityKey  sql.NullString
 	Diff       sql.NullString
+	Snapshot   sql.NullString
 	Points     int
+	ReqId      sql.NullString
+	IP         sql.NullString
+	UserAgent  sql.NullString
 }
 
 func (dbo *ChangeLogRecordDBO) ToChangeLogRecord() *domain.ChangeLogRecord {
@@ -308,8 +441,13 @@ func (dbo *ChangeLogRecordDBO) ToChangeLogRecord() *domain.ChangeLogRecord {
 		UserId:     dbo.UserId,
 		EntityType: domain.EntityType(dbo.EntityType),
 		EntityId:   dbo.EntityId,
+		EntityKey:  dbo.EntityKey.String,
 		Diff:       dbo.Diff.String,
+		Snapshot:   dbo.Snapshot.String,
 		Points:     dbo.Points,
+		ReqId:      dbo.ReqId.String,
+		IP:         dbo.IP.String,
+		UserAgent:  dbo.UserAgent.String,
 	}
 }
 
@@ -320,13 +458,18 @@ func (dbo *ChangeLogRecordDBO) FromChangeLogRecord(c *domain.ChangeLogRecord) {
 	dbo.UserId = c.UserId
 	dbo.EntityType = int(c.EntityType)
 	dbo.EntityId = c.EntityId
+	dbo.EntityKey = ToNullString(c.EntityKey)
 	dbo.Diff = ToNullString(c.Diff)
+	dbo.Snapshot = ToNullString(c.Snapshot)
 	dbo.Points = c.Points
+	dbo.ReqId = ToNullString(c.ReqId)
+	dbo.IP = ToNullString(c.IP)
+	dbo.UserAgent = ToNullString(c.UserAgent)
 }
 
 /*
 	Project
-*******************/
+ ******************/
 
 type ProjectDBO struct {
 	Id      int
@@ -334,6 +477,7 @@ type ProjectDBO struct {
 	Text    string
 	Tags    []string
 	OwnerId string
+	Version int
 }
 
 func (dbo *ProjectDBO) ToProject(tags []domain.TopicTag) *domain.Project {
@@ -343,6 +487,7 @@ func (dbo *ProjectDBO) ToProject(tags []domain.TopicTag) *domain.Project {
 		Text:    dbo.Text,
 		Tags:    tags,
 		OwnerId: dbo.OwnerId,
+		Version: dbo.Version,
 	}
 }
 
@@ -352,6 +497,7 @@ func (dbo *ProjectDBO) FromProject(p *domain.Project) {
 	dbo.Text = p.Text
 	dbo.Tags = make([]string, len(p.Tags))
 	dbo.OwnerId = p.OwnerId
+	dbo.Version = p.Version
 
 	for i, tag := range p.Tags {
 		dbo.Tags[i] = tag.Name
@@ -364,7 +510,7 @@ type PointsDBO struct {
 	Count  int
 	Avg    float32
 	Value  float32
-	Voted  bool
+	Voted  int
 }
 
 func (dbo *PointsDBO) ToPoints() *domain.Points {
@@ -385,6 +531,37 @@ func (dbo *PointsDBO) FromPoints(p *domain.Points) {
 	dbo.Voted = p.Voted
 }
 
+/*
+	Role
+ ******************/
+type RoleDBO struct {
+	Id          int
+	Name        string
+	Permissions []string
+}
+
+func (dbo *RoleDBO) ToRole() *domain.Role {
+	permissions := make([]domain.Permission, len(dbo.Permissions))
+	for i, p := range dbo.Permissions {
+		permissions[i] = domain.Permission(p)
+	}
+
+	return &domain.Role{
+		Id:          dbo.Id,
+		Name:        dbo.Name,
+		Permissions: permissions,
+	}
+}
+
+func (dbo *RoleDBO) FromRole(r *domain.Role) {
+	dbo.Id = r.Id
+	dbo.Name = r.Name
+	dbo.Permissions = make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		dbo.Permissions[i] = string(p)
+	}
+}
+
 func ToNullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != ""}
 }
@@ -392,3 +569,370 @@ func ToNullString(s string) sql.NullString {
 func ToNullInt64(s int64) sql.NullInt64 {
 	return sql.NullInt64{Int64: s, Valid: s != 0}
 }
+
+/*
+	Report
+ ******************/
+type ReportDBO struct {
+	Id         int64
+	EntityType int
+	EntityId   int64
+	Status     int
+	Count      int
+	Created    time.Time
+	Updated    time.Time
+	ResolvedBy sql.NullString
+	ResolvedAt *time.Time
+	Comment    sql.NullString
+}
+
+func (dbo *ReportDBO) ToReport() *domain.Report {
+	report := &domain.Report{
+		Id:         dbo.Id,
+		EntityType: domain.EntityType(dbo.EntityType),
+		EntityId:   dbo.EntityId,
+		Status:     domain.ReportStatus(dbo.Status),
+		Count:      dbo.Count,
+		Created:    dbo.Created,
+		Updated:    dbo.Updated,
+		ResolvedBy: dbo.ResolvedBy.String,
+		Comment:    dbo.Comment.String,
+		Complaints: []domain.Complaint{},
+	}
+
+	if dbo.ResolvedAt != nil {
+		report.ResolvedAt = *dbo.ResolvedAt
+	}
+	return report
+}
+
+type ComplaintDBO struct {
+	ReportId int64
+	UserId   string
+	Reason   int
+	Text     sql.NullString
+	Date     time.Time
+}
+
+func (dbo *ComplaintDBO) ToComplaint() *domain.Complaint {
+	return &domain.Complaint{
+		ReportId: dbo.ReportId,
+		UserId:   dbo.UserId,
+		Reason:   domain.ReportReason(dbo.Reason),
+		Text:     dbo.Text.String,
+		Date:     dbo.Date,
+	}
+}
+
+/*
+	Email
+ ******************/
+
+type EmailDBO struct {
+	Id          int64
+	UserId      sql.NullString
+	Recipient   string
+	Template    string
+	Lang        sql.NullString
+	Data        []byte
+	Status      int
+	Attempts    int
+	NextAttempt time.Time
+	LastError   sql.NullString
+	Created     time.Time
+	Sent        *time.Time
+}
+
+func (dbo *EmailDBO) ToEmail() *domain.Email {
+	email := &domain.Email{
+		Id:          dbo.Id,
+		UserId:      dbo.UserId.String,
+		Recipient:   dbo.Recipient,
+		Template:    dbo.Template,
+		Lang:        dbo.Lang.String,
+		Data:        map[string]string{},
+		Status:      domain.EmailStatus(dbo.Status),
+		Attempts:    dbo.Attempts,
+		NextAttempt: dbo.NextAttempt,
+		LastError:   dbo.LastError.String,
+		Created:     dbo.Created,
+	}
+	if len(dbo.Data) > 0 {
+		json.Unmarshal(dbo.Data, &email.Data)
+	}
+	if dbo.Sent != nil {
+		email.Sent = *dbo.Sent
+	}
+	return email
+}
+
+func (dbo *EmailDBO) FromEmail(email *domain.Email) error {
+	data, err := json.Marshal(email.Data)
+	if err != nil {
+		return err
+	}
+	dbo.Id = email.Id
+	dbo.UserId = ToNullString(email.UserId)
+	dbo.Recipient = email.Recipient
+	dbo.Template = email.Template
+	dbo.Lang = ToNullString(email.Lang)
+	dbo.Data = data
+	dbo.Status = int(email.Status)
+	dbo.Attempts = email.Attempts
+	dbo.NextAttempt = email.NextAttempt
+	dbo.LastError = ToNullString(email.LastError)
+	dbo.Created = email.Created
+	return nil
+}
+
+/*
+	Notification
+ ******************/
+
+type NotificationDBO struct {
+	Id         int64
+	UserId     string
+	Type       int
+	ActorId    sql.NullString
+	EntityType int
+	EntityId   int64
+	Title      sql.NullString
+	Read       bool
+	Emailed    bool
+	Created    time.Time
+}
+
+func (dbo *NotificationDBO) ToNotification() *domain.Notification {
+	return &domain.Notification{
+		Id:         dbo.Id,
+		UserId:     dbo.UserId,
+		Type:       domain.NotificationType(dbo.Type),
+		ActorId:    dbo.ActorId.String,
+		EntityType: domain.EntityType(dbo.EntityType),
+		EntityId:   dbo.EntityId,
+		Title:      dbo.Title.String,
+		Read:       dbo.Read,
+		Emailed:    dbo.Emailed,
+		Created:    dbo.Created,
+	}
+}
+
+func (dbo *NotificationDBO) FromNotification(n *domain.Notification) {
+	dbo.Id = n.Id
+	dbo.UserId = n.UserId
+	dbo.Type = int(n.Type)
+	dbo.ActorId = ToNullString(n.ActorId)
+	dbo.EntityType = int(n.EntityType)
+	dbo.EntityId = n.EntityId
+	dbo.Title = ToNullString(n.Title)
+	dbo.Read = n.Read
+	dbo.Emailed = n.Emailed
+	dbo.Created = n.Created
+}
+
+/*
+	Webhook
+ ******************/
+
+type WebhookDBO struct {
+	Id         int64
+	UserId     string
+	Url        string
+	Secret     string
+	Events     []int32
+	Active     bool
+	OwnChanges bool
+	Created    time.Time
+}
+
+func (dbo *WebhookDBO) ToWebhook() *domain.Webhook {
+	events := make([]domain.ChangeType, len(dbo.Events))
+	for i, e := range dbo.Events {
+		events[i] = domain.ChangeType(e)
+	}
+	return &domain.Webhook{
+		Id:         dbo.Id,
+		UserId:     dbo.UserId,
+		Url:        dbo.Url,
+		Secret:     dbo.Secret,
+		Events:     events,
+		Active:     dbo.Active,
+		OwnChanges: dbo.OwnChanges,
+		Created:    dbo.Created,
+	}
+}
+
+func (dbo *WebhookDBO) FromWebhook(w *domain.Webhook) {
+	dbo.Id = w.Id
+	dbo.UserId = w.UserId
+	dbo.Url = w.Url
+	dbo.Secret = w.Secret
+	dbo.Events = make([]int32, len(w.Events))
+	for i, e := range w.Events {
+		dbo.Events[i] = int32(e)
+	}
+	dbo.Active = w.Active
+	dbo.OwnChanges = w.OwnChanges
+	dbo.Created = w.Created
+}
+
+type WebhookDeliveryDBO struct {
+	Id           int64
+	WebhookId    int64
+	Url          string
+	Secret       string
+	Event        int
+	Payload      string
+	Status       int
+	Attempts     int
+	NextAttempt  time.Time
+	ResponseCode sql.NullInt64
+	LastError    sql.NullString
+	Created      time.Time
+	Delivered    *time.Time
+	RedeliveryOf sql.NullInt64
+}
+
+func (dbo *WebhookDeliveryDBO) ToWebhookDelivery() *domain.WebhookDelivery {
+	delivery := &domain.WebhookDelivery{
+		Id:           dbo.Id,
+		WebhookId:    dbo.WebhookId,
+		Url:          dbo.Url,
+		Secret:       dbo.Secret,
+		Event:        domain.ChangeType(dbo.Event),
+		Payload:      dbo.Payload,
+		Status:       domain.WebhookDeliveryStatus(dbo.Status),
+		Attempts:     dbo.Attempts,
+		NextAttempt:  dbo.NextAttempt,
+		ResponseCode: int(dbo.ResponseCode.Int64),
+		LastError:    dbo.LastError.String,
+		Created:      dbo.Created,
+		RedeliveryOf: dbo.RedeliveryOf.Int64,
+	}
+	if dbo.Delivered != nil {
+		delivery.Delivered = *dbo.Delivered
+	}
+	return delivery
+}
+
+/*
+	Plan suggestion
+ ******************/
+
+type PlanSuggestionDBO struct {
+	Id          int64
+	PlanId      int
+	UserId      string
+	Title       string
+	Steps       []byte
+	Message     sql.NullString
+	Status      int
+	Date        time.Time
+	ReviewedBy  sql.NullString
+	ReviewedAt  *time.Time
+	Comment     sql.NullString
+	PlanVersion int
+}
+
+// suggestedStepDBO is proposed step stored as json, position is index in list
+type suggestedStepDBO struct {
+	ReferenceId   int64                `json:"referenceId"`
+	ReferenceType domain.ReferenceType `json:"referenceType"`
+	Title         string               `json:"title"`
+	Duration      int                  `json:"duration,omitempty"`
+	Difficulty    domain.Difficulty    `json:"difficulty,omitempty"`
+	Optional      bool                 `json:"optional,omitempty"`
+	Notes         string               `json:"notes,omitempty"`
+}
+
+func (dbo *PlanSuggestionDBO) ToPlanSuggestion() *domain.PlanSuggestion {
+	suggestion := &domain.PlanSuggestion{
+		Id:          dbo.Id,
+		PlanId:      dbo.PlanId,
+		UserId:      dbo.UserId,
+		Title:       dbo.Title,
+		Steps:       []domain.Step{},
+		Message:     dbo.Message.String,
+		Status:      domain.SuggestionStatus(dbo.Status),
+		Date:        dbo.Date,
+		ReviewedBy:  dbo.ReviewedBy.String,
+		Comment:     dbo.Comment.String,
+		PlanVersion: dbo.PlanVersion,
+	}
+
+	steps := make([]suggestedStepDBO, 0)
+	json.Unmarshal(dbo.Steps, &steps)
+	for i, v := range steps {
+		suggestion.Steps = append(suggestion.Steps, domain.Step{
+			PlanId:        dbo.PlanId,
+			ReferenceId:   v.ReferenceId,
+			ReferenceType: v.ReferenceType,
+			Position:      i,
+			Title:         v.Title,
+			Duration:      v.Duration,
+			Difficulty:    v.Difficulty,
+			Optional:      v.Optional,
+			Notes:         v.Notes,
+		})
+	}
+
+	if dbo.ReviewedAt != nil {
+		suggestion.ReviewedAt = *dbo.ReviewedAt
+	}
+	return suggestion
+}
+
+func (dbo *PlanSuggestionDBO) FromPlanSuggestion(suggestion *domain.PlanSuggestion) error {
+	steps := make([]suggestedStepDBO, len(suggestion.Steps))
+	for i, v := range suggestion.Steps {
+		steps[i] = suggestedStepDBO{
+			ReferenceId:   v.ReferenceId,
+			ReferenceType: v.ReferenceType,
+			Title:         v.Title,
+			Duration:      v.Duration,
+			Difficulty:    v.Difficulty,
+			Optional:      v.Optional,
+			Notes:         v.Notes,
+		}
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		return err
+	}
+	dbo.Id = suggestion.Id
+	dbo.PlanId = suggestion.PlanId
+	dbo.UserId = suggestion.UserId
+	dbo.Title = suggestion.Title
+	dbo.Steps = data
+	dbo.Message = ToNullString(suggestion.Message)
+	dbo.Status = int(suggestion.Status)
+	dbo.Date = suggestion.Date
+	dbo.ReviewedBy = ToNullString(suggestion.ReviewedBy)
+	dbo.Comment = ToNullString(suggestion.Comment)
+	dbo.PlanVersion = suggestion.PlanVersion
+	return nil
+}
+
+type PlanCollaboratorDBO struct {
+	PlanId    int
+	UserId    string
+	Role      int
+	InvitedBy string
+	Accepted  bool
+	Date      time.Time
+	UserName  sql.NullString
+	PlanTitle sql.NullString
+}
+
+func (dbo *PlanCollaboratorDBO) ToPlanCollaborator() *domain.PlanCollaborator {
+	return &domain.PlanCollaborator{
+		PlanId:    dbo.PlanId,
+		UserId:    dbo.UserId,
+		Role:      domain.CollaboratorRole(dbo.Role),
+		InvitedBy: dbo.InvitedBy,
+		Accepted:  dbo.Accepted,
+		Date:      dbo.Date,
+		UserName:  dbo.UserName.String,
+		PlanTitle: dbo.PlanTitle.String,
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type emailOutboxRepo struct {
+	Db *DbConnection
+}
+
+func NewEmailOutboxRepository(db *DbConnection) core.EmailOutbox {
+	return &emailOutboxRepo{Db: db}
+}
+
+// queryRower is implemented by both pool and transaction,
+// so email can be added to outbox within transaction of other repository
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func insertEmail(conn queryRower, email *domain.Email) (string, error) {
+	dbo := &EmailDBO{}
+	if err := dbo.FromEmail(email); err != nil {
+		return "", err
+	}
+	query := `INSERT INTO emails (userid, recipient, template, lang, data, status, attempts, nextattempt, created)
+	VALUES ($1, $2, $3, $4, $5::jsonb, $6, 0, now(), now())
+	RETURNING id;`
+	err := conn.QueryRow(context.Background(), query, dbo.UserId, dbo.Recipient, dbo.Template, dbo.Lang, string(dbo.Data), int(domain.EmailPending)).Scan(&email.Id)
+	return query, err
+}
+
+func (r *emailOutboxRepo) Add(ctx core.ReqContext, email *domain.Email) (bool, *core.AppError) {
+	tr := ctx.StartTrace("EmailOutbox.Add")
+	defer ctx.StopTrace(tr)
+
+	if query, err := insertEmail(r.Db.Conn, email); err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return true, nil
+}
+
+func (r *emailOutboxRepo) Take(count int, lease time.Duration) []domain.Email {
+	query := `UPDATE emails SET nextattempt = $2 
+WHERE id IN (
+	SELECT id FROM emails 
+	WHERE status = $3 AND nextattempt <= now() 
+	ORDER BY nextattempt 
+	LIMIT $1 
+	FOR UPDATE SKIP LOCKED)
+RETURNING id, userid, recipient, template, lang, data, status, attempts, nextattempt, lasterror, created, sent;`
+	rows, err := r.Db.Conn.Query(context.Background(), query, count, time.Now().Add(lease), int(domain.EmailPending))
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Email{}
+	}
+	defer rows.Close()
+
+	emails := make([]domain.Email, 0)
+	for rows.Next() {
+		dbo := EmailDBO{}
+		err := rows.Scan(&dbo.Id, &dbo.UserId, &dbo.Recipient, &dbo.Template, &dbo.Lang, &dbo.Data, &dbo.Status, &dbo.Attempts, &dbo.NextAttempt, &dbo.LastError, &dbo.Created, &dbo.Sent)
+		if err != nil {
+			r.Db.LogError(err, query)
+			return []domain.Email{}
+		}
+		emails = append(emails, *dbo.ToEmail())
+	}
+	return emails
+}
+
+func (r *emailOutboxRepo) MarkSent(id int64) bool {
+	query := `UPDATE emails SET status = $2, attempts = attempts + 1, sent = now(), lasterror = NULL WHERE id = $1;`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, id, int(domain.EmailSent))
+	if err != nil {
+		r.Db.LogError(err, query)
+		return false
+	}
+	return tag.RowsAffected() > 0
+}
+
+func (r *emailOutboxRepo) MarkFailed(id int64, lastError string, nextAttempt time.Time, dead bool) bool {
+	status := domain.EmailPending
+	if dead {
+		status = domain.EmailDead
+	}
+	query := `UPDATE emails SET status = $2, attempts = attempts + 1, nextattempt = $3, lasterror = $4 WHERE id = $1;`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, id, int(status), nextAttempt, lastError)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return false
+	}
+	return tag.RowsAffected() > 0
+}
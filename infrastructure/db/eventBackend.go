@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+)
+
+const (
+	eventsChannel = "roadmaps_events"
+	// delay before listening is restarted after connection error
+	listenRetryDelay = 5 * time.Second
+)
+
+// pgEventBackend transfers events between instances with postgres LISTEN/NOTIFY.
+// Payload of notification is limited by 8000 bytes.
+type pgEventBackend struct {
+	Db *DbConnection
+}
+
+func NewEventBackend(db *DbConnection) core.EventBackend {
+	return &pgEventBackend{Db: db}
+}
+
+func (b *pgEventBackend) Publish(payload []byte) error {
+	query := "SELECT pg_notify($1, $2);"
+	if _, err := b.Db.Conn.Exec(context.Background(), query, eventsChannel, string(payload)); err != nil {
+		return b.Db.LogError(err, query)
+	}
+	return nil
+}
+
+func (b *pgEventBackend) Listen(handler func(payload []byte)) {
+	go func() {
+		for {
+			if err := b.listen(handler); err != nil {
+				b.Db.LogError(err, "LISTEN "+eventsChannel)
+			}
+			time.Sleep(listenRetryDelay)
+		}
+	}()
+}
+
+func (b *pgEventBackend) listen(handler func(payload []byte)) error {
+	conn, err := b.Db.Conn.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(context.Background(), "LISTEN "+eventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(context.Background())
+		if err != nil {
+			return err
+		}
+		handler([]byte(notification.Payload))
+	}
+}
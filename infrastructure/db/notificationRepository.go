@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type notificationRepo struct {
+	Db *DbConnection
+}
+
+func NewNotificationRepository(db *DbConnection) core.NotificationRepository {
+	return &notificationRepo{Db: db}
+}
+
+func (r *notificationRepo) Add(ctx core.ReqContext, notifications []domain.Notification) (int64, *core.AppError) {
+	// notification is not saved when user disabled notifications of this type
+	query := `INSERT INTO notifications (userid, type, actorid, entitytype, entityid, title, read, emailed, created) 
+	SELECT $1, $2, $3, $4, $5, $6, false, false, $7 
+	WHERE NOT EXISTS (SELECT 1 FROM notification_preferences WHERE userid = $1 AND type = $2 AND NOT enabled);`
+	tr := ctx.StartTrace("NotificationRepository.Add")
+	defer ctx.StopTrace(tr)
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	var added int64
+	for i := range notifications {
+		dbo := &NotificationDBO{}
+		dbo.FromNotification(&notifications[i])
+		tag, err := tx.Exec(context.Background(), query, dbo.UserId, dbo.Type, dbo.ActorId, dbo.EntityType, dbo.EntityId, dbo.Title, dbo.Created)
+		if err != nil {
+			return 0, r.Db.LogError(err, query)
+		}
+		added += tag.RowsAffected()
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return 0, r.Db.LogError(err, "")
+	}
+	return added, nil
+}
+
+func (r *notificationRepo) GetList(ctx core.ReqContext, userId string, unreadOnly bool, count int, page int) []domain.Notification {
+	query := `SELECT id, userid, type, actorid, entitytype, entityid, title, read, emailed, created 
+	FROM notifications 
+	WHERE userid = $1 AND (NOT $2 OR NOT read) 
+	ORDER BY created DESC 
+	LIMIT $3 OFFSET $4;`
+	tr := ctx.StartTrace("NotificationRepository.GetList")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId, unreadOnly, count, page*count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Notification{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows, query)
+}
+
+func (r *notificationRepo) UnreadCount(ctx core.ReqContext, userId string) (count int, ok bool) {
+	query := `SELECT count(id) FROM notifications WHERE userid = $1 AND NOT read;`
+	tr := ctx.StartTrace("NotificationRepository.UnreadCount")
+	defer ctx.StopTrace(tr)
+
+	if err := r.Db.Conn.QueryRow(context.Background(), query, userId).Scan(&count); err != nil {
+		r.Db.LogError(err, query)
+		return 0, false
+	}
+	return count, true
+}
+
+func (r *notificationRepo) MarkRead(ctx core.ReqContext, userId string, ids []int64) (int64, *core.AppError) {
+	query := `UPDATE notifications SET read = true WHERE userid = $1 AND NOT read AND ($2 OR id = ANY($3));`
+	tr := ctx.StartTrace("NotificationRepository.MarkRead")
+	defer ctx.StopTrace(tr)
+
+	if ids == nil {
+		ids = []int64{}
+	}
+	tag, err := r.Db.Conn.Exec(context.Background(), query, userId, len(ids) == 0, ids)
+	if err != nil {
+		return 0, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *notificationRepo) GetPreferences(ctx core.ReqContext, userId string) []domain.NotificationPreference {
+	query := `SELECT type, enabled, email FROM notification_preferences WHERE userid = $1;`
+	tr := ctx.StartTrace("NotificationRepository.GetPreferences")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.NotificationPreference{}
+	}
+	defer rows.Close()
+
+	preferences := make([]domain.NotificationPreference, 0)
+	for rows.Next() {
+		var t int
+		p := domain.NotificationPreference{}
+		if err := rows.Scan(&t, &p.Enabled, &p.Email); err != nil {
+			r.Db.LogError(err, query)
+			return []domain.NotificationPreference{}
+		}
+		p.Type = domain.NotificationType(t)
+		preferences = append(preferences, p)
+	}
+	return preferences
+}
+
+func (r *notificationRepo) SavePreferences(ctx core.ReqContext, userId string, preferences []domain.NotificationPreference) (bool, *core.AppError) {
+	query := `INSERT INTO notification_preferences (userid, type, enabled, email) VALUES ($1, $2, $3, $4) 
+	ON CONFLICT (userid, type) DO UPDATE SET enabled = EXCLUDED.enabled, email = EXCLUDED.email;`
+	tr := ctx.StartTrace("NotificationRepository.SavePreferences")
+	defer ctx.StopTrace(tr)
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	for _, p := range preferences {
+		if _, err := tx.Exec(context.Background(), query, userId, int(p.Type), p.Enabled, p.Email); err != nil {
+			return false, r.Db.LogError(err, query)
+		}
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	return true, nil
+}
+
+func (r *notificationRepo) GetForDigest(count int) []domain.Notification {
+	query := `SELECT n.id, n.userid, n.type, n.actorid, n.entitytype, n.entityid, n.title, n.read, n.emailed, n.created 
+	FROM notifications n 
+		INNER JOIN notification_preferences p ON p.userid = n.userid AND p.type = n.type 
+	WHERE NOT n.read AND NOT n.emailed AND p.enabled AND p.email 
+	ORDER BY n.userid, n.created 
+	LIMIT $1;`
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Notification{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows, query)
+}
+
+func (r *notificationRepo) SaveDigest(email *domain.Email, ids []int64) (bool, *core.AppError) {
+	query := `UPDATE notifications SET emailed = true WHERE id = ANY($1);`
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), query, ids); err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	if email != nil {
+		if query, err := insertEmail(tx, email); err != nil {
+			return false, r.Db.LogError(err, query)
+		}
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	return true, nil
+}
+
+func (r *notificationRepo) scanRows(rows pgx.Rows, query string) []domain.Notification {
+	notifications := make([]domain.Notification, 0)
+	for rows.Next() {
+		dbo := NotificationDBO{}
+		err := rows.Scan(&dbo.Id, &dbo.UserId, &dbo.Type, &dbo.ActorId, &dbo.EntityType, &dbo.EntityId, &dbo.Title, &dbo.Read, &dbo.Emailed, &dbo.Created)
+		if err != nil {
+			r.Db.LogError(err, query)
+			return []domain.Notification{}
+		}
+		notifications = append(notifications, *dbo.ToNotification())
+	}
+	return notifications
+}
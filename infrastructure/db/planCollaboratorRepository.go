@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type planCollaboratorRepo struct {
+	Db *DbConnection
+}
+
+func NewPlanCollaboratorRepository(db *DbConnection) core.PlanCollaboratorRepository {
+	return &planCollaboratorRepo{Db: db}
+}
+
+func (r *planCollaboratorRepo) Save(ctx core.ReqContext, collaborator *domain.PlanCollaborator) (bool, *core.AppError) {
+	query := `INSERT INTO plan_collaborators (planid, userid, role, invitedby, accepted, date)
+	VALUES ($1, $2, $3, $4, false, now())
+	ON CONFLICT (planid, userid) DO UPDATE SET role = EXCLUDED.role
+	RETURNING accepted, date;`
+	tr := ctx.StartTrace("PlanCollaboratorRepository.Save")
+	defer ctx.StopTrace(tr)
+
+	err := r.Db.Conn.QueryRow(context.Background(), query, collaborator.PlanId, collaborator.UserId, int(collaborator.Role), collaborator.InvitedBy).Scan(&collaborator.Accepted, &collaborator.Date)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return true, nil
+}
+
+func (r *planCollaboratorRepo) Accept(ctx core.ReqContext, planId int, userId string) (bool, *core.AppError) {
+	query := `UPDATE plan_collaborators SET accepted = true WHERE planid = $1 AND userid = $2;`
+	tr := ctx.StartTrace("PlanCollaboratorRepository.Accept")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, planId, userId)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *planCollaboratorRepo) Remove(ctx core.ReqContext, planId int, userId string) (bool, *core.AppError) {
+	query := `DELETE FROM plan_collaborators WHERE planid = $1 AND userid = $2;`
+	tr := ctx.StartTrace("PlanCollaboratorRepository.Remove")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, planId, userId)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *planCollaboratorRepo) Get(ctx core.ReqContext, planId int, userId string) *domain.PlanCollaborator {
+	query := `SELECT c.planid, c.userid, c.role, c.invitedby, c.accepted, c.date, NULL, NULL
+	FROM plan_collaborators c WHERE c.planid = $1 AND c.userid = $2;`
+	tr := ctx.StartTrace("PlanCollaboratorRepository.Get")
+	defer ctx.StopTrace(tr)
+
+	row := r.Db.Conn.QueryRow(context.Background(), query, planId, userId)
+	dbo, err := r.scanRow(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return nil
+	}
+	return dbo.ToPlanCollaborator()
+}
+
+func (r *planCollaboratorRepo) GetByPlan(ctx core.ReqContext, planId int) []domain.PlanCollaborator {
+	query := `SELECT c.planid, c.userid, c.role, c.invitedby, c.accepted, c.date, u.name, NULL
+	FROM plan_collaborators c
+		INNER JOIN users u ON u.id = c.userid
+	WHERE c.planid = $1
+	ORDER BY c.date;`
+	tr := ctx.StartTrace("PlanCollaboratorRepository.GetByPlan")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, planId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.PlanCollaborator{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *planCollaboratorRepo) GetInvitations(ctx core.ReqContext, userId string) []domain.PlanCollaborator {
+	query := `SELECT c.planid, c.userid, c.role, c.invitedby, c.accepted, c.date, NULL, p.title
+	FROM plan_collaborators c
+		INNER JOIN plans p ON p.id = c.planid
+	WHERE c.userid = $1
+		AND c.accepted = false
+		AND p.deletedat IS NULL
+	ORDER BY c.date DESC;`
+	tr := ctx.StartTrace("PlanCollaboratorRepository.GetInvitations")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.PlanCollaborator{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *planCollaboratorRepo) scanRows(rows pgx.Rows) []domain.PlanCollaborator {
+	collaborators := make([]domain.PlanCollaborator, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			return []domain.PlanCollaborator{}
+		}
+		collaborators = append(collaborators, *dbo.ToPlanCollaborator())
+	}
+	return collaborators
+}
+
+func (r *planCollaboratorRepo) scanRow(row pgx.Row) (*PlanCollaboratorDBO, error) {
+	dbo := PlanCollaboratorDBO{}
+	err := row.Scan(&dbo.PlanId, &dbo.UserId, &dbo.Role, &dbo.InvitedBy, &dbo.Accepted, &dbo.Date, &dbo.UserName, &dbo.PlanTitle)
+	if err != nil && err.Error() == "no rows in result set" {
+		return &dbo, sql.ErrNoRows
+	}
+	return &dbo, err
+}
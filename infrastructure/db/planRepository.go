@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
@@ -26,51 +27,57 @@ func (r *planRepo) SaveWithSteps(ctx core.ReqContext, plan *domain.Plan) (bool,
 	if len(plan.Steps) == 0 {
 		return false, core.NewError(core.InvalidRequest)
 	}
-	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
-		IsoLevel:       pgx.ReadCommitted,
-		AccessMode:     pgx.ReadWrite,
-		DeferrableMode: pgx.NotDeferrable,
-	})
-	insertPlanQuery := "INSERT INTO plans(title, topic, owner, isdraft) VALUES ($1, $2, $3, $4) RETURNING id;"
 
-	err = r.Db.Conn.QueryRow(context.Background(), insertPlanQuery, plan.Title, plan.TopicName, plan.OwnerId, plan.IsDraft).Scan(&plan.Id)
-	tr.Point("insert plan")
-	if err != nil {
-		if e := tx.Rollback(context.Background()); e != nil {
-			r.Db.Log.Errorw("Tx not rolled back", "err", e.Error())
+	dbo := PlanDBO{}
+	dbo.FromPlan(plan)
+	query := "INSERT INTO plans(title, topic, owner, isdraft, parentid) VALUES ($1, $2, $3, $4, $5) RETURNING id, version;"
+	err := r.Db.InTx(func(tx pgx.Tx) error {
+		err := tx.QueryRow(context.Background(), query, dbo.Title, dbo.TopicName, dbo.OwnerId, dbo.IsDraft, dbo.ParentId).Scan(&dbo.Id, &dbo.Version)
+		tr.Point("insert plan")
+		if err != nil {
+			return err
 		}
-		return false, r.Db.LogError(err, insertPlanQuery)
+		return r.insertSteps(tx, dbo.Id, plan.Steps)
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, query)
 	}
 
-	for i := 0; i < len(plan.Steps); i++ {
+	plan.Id = dbo.Id
+	plan.Version = dbo.Version
+	for i := range plan.Steps {
 		plan.Steps[i].PlanId = plan.Id
-		query := "INSERT INTO steps( planid, referenceid, referencetype, position, title) VALUES ($1, $2, $3, $4, $5) RETURNING id;"
-		err := r.Db.Conn.QueryRow(context.Background(),
+	}
+	return true, nil
+}
+
+// insertSteps saves steps of plan in tx and sets their ids
+func (r *planRepo) insertSteps(tx pgx.Tx, planId int, steps []domain.Step) error {
+	query := `INSERT INTO steps( planid, referenceid, referencetype, position, title, titlehtml, duration, difficulty, optional, notes, noteshtml)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id;`
+	for i := 0; i < len(steps); i++ {
+		dbo := StepDBO{}
+		dbo.FromStep(&steps[i])
+		err := tx.QueryRow(context.Background(),
 			query,
-			plan.Steps[i].PlanId,
-			plan.Steps[i].ReferenceId,
-			plan.Steps[i].ReferenceType,
-			plan.Steps[i].Position,
-			plan.Steps[i].Title).
-			Scan(&plan.Steps[i].Id)
-
-		tr.Point("insert steps")
+			planId,
+			dbo.ReferenceId,
+			dbo.ReferenceType,
+			dbo.Position,
+			dbo.Title,
+			dbo.TitleHtml,
+			dbo.Duration,
+			dbo.Difficulty,
+			dbo.Optional,
+			dbo.Notes,
+			dbo.NotesHtml).
+			Scan(&steps[i].Id)
 		if err != nil {
-			if e := tx.Rollback(context.Background()); e != nil {
-				r.Db.Log.Errorw("Tx not rolled back", "err", e.Error())
-			}
-			return false, r.Db.LogError(err, query)
+			return err
 		}
 	}
-
-	err = tx.Commit(context.Background())
-	// if err is serialization error, we should repeat transaction
-	if err != nil {
-		return false, r.Db.LogError(err, "")
-	}
-	return true, nil
+	return nil
 }
-
 func (r *planRepo) Update(ctx core.ReqContext, plan *domain.Plan) (bool, *core.AppError) {
 	tr := ctx.StartTrace("PlanRepository.Update")
 	defer ctx.StopTrace(tr)
@@ -79,80 +86,123 @@ func (r *planRepo) Update(ctx core.ReqContext, plan *domain.Plan) (bool, *core.A
 		return false, core.NewError(core.InvalidRequest)
 	}
 
-	updatePlanQuery := `UPDATE plans SET title = $1, topic = $2, isdraft = $4 WHERE id = $3`
-	_, err := r.Db.Conn.Exec(context.Background(), updatePlanQuery, plan.Title, plan.TopicName, plan.Id, plan.IsDraft)
-	tr.Point("update plans")
-	if err != nil {
-		return false, r.Db.LogError(err, updatePlanQuery)
-	}
+	var version int
+	query := `UPDATE plans SET title = $1, topic = $2, isdraft = $4, version = version + 1 WHERE id = $3 AND version = $5 AND deletedat IS NULL RETURNING version`
+	err := r.Db.InTx(func(tx pgx.Tx) error {
+		err := tx.QueryRow(context.Background(), query, plan.Title, plan.TopicName, plan.Id, plan.IsDraft, plan.Version).Scan(&version)
+		tr.Point("update plans")
+		if err == pgx.ErrNoRows {
+			return errVersionConflict
+		}
+		if err != nil {
+			return err
+		}
 
-	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
-		IsoLevel:       pgx.ReadCommitted,
-		AccessMode:     pgx.ReadWrite,
-		DeferrableMode: pgx.NotDeferrable,
+		if _, err := tx.Exec(context.Background(), `DELETE FROM steps WHERE planid = $1`, plan.Id); err != nil {
+			return err
+		}
+		tr.Point("delete steps")
+		return r.insertSteps(tx, plan.Id, plan.Steps)
 	})
-	deleteStepsQuery := `DELETE FROM steps WHERE planid = $1`
-	_, err = r.Db.Conn.Exec(context.Background(), deleteStepsQuery, plan.Id)
-	tr.Point("delete steps")
+	if err == errVersionConflict {
+		return false, core.NewError(core.Conflict)
+	}
 	if err != nil {
-		if e := tx.Rollback(context.Background()); e != nil {
-			r.Db.Log.Errorw("Tx not rolled back", "err", e.Error())
-		}
-		return false, r.Db.LogError(err, updatePlanQuery)
+		return false, r.Db.LogError(err, query)
 	}
 
-	for i := 0; i < len(plan.Steps); i++ {
+	plan.Version = version
+	for i := range plan.Steps {
 		plan.Steps[i].PlanId = plan.Id
-		query := "INSERT INTO steps( planid, referenceid, referencetype, position, title) VALUES ($1, $2, $3, $4, $5) RETURNING id;"
-		err := r.Db.Conn.QueryRow(context.Background(),
-			query,
-			plan.Steps[i].PlanId,
-			plan.Steps[i].ReferenceId,
-			plan.Steps[i].ReferenceType,
-			plan.Steps[i].Position,
-			plan.Steps[i].Title).
-			Scan(&plan.Steps[i].Id)
-
-		tr.Point("insert steps")
-		if err != nil {
-			if e := tx.Rollback(context.Background()); e != nil {
-				r.Db.Log.Errorw("Tx not rolled back", "err", e.Error())
-			}
-			return false, r.Db.LogError(err, query)
-		}
 	}
+	return true, nil
+}
+func (r *planRepo) Delete(ctx core.ReqContext, planId int) (bool, *core.AppError) {
+	tr := ctx.StartTrace("PlanRepository.Delete")
+	defer ctx.StopTrace(tr)
 
-	err = tx.Commit(context.Background())
-	// if err is serialization error, we should repeat transaction
+	query := `UPDATE plans SET deletedat = now() WHERE id = $1 AND deletedat IS NULL`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, planId)
 	if err != nil {
-		return false, r.Db.LogError(err, "")
+		return false, r.Db.LogError(err, query)
 	}
-
-	return true, nil
+	return tag.RowsAffected() > 0, nil
 }
 
-func (r *planRepo) Delete(ctx core.ReqContext, planId int) (bool, *core.AppError) {
-	tr := ctx.StartTrace("PlanRepository.Delete")
+func (r *planRepo) Restore(ctx core.ReqContext, planId int) (bool, *core.AppError) {
+	tr := ctx.StartTrace("PlanRepository.Restore")
 	defer ctx.StopTrace(tr)
 
-	deletePlanQuery := `DELETE FROM plans WHERE id = $1`
-	_, err := r.Db.Conn.Exec(context.Background(), deletePlanQuery, planId)
+	query := `UPDATE plans SET deletedat = NULL WHERE id = $1 AND deletedat IS NOT NULL`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, planId)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *planRepo) Purge(before time.Time) (int64, *core.AppError) {
+	var purged int64
+	query := ""
+	err := r.Db.InTx(func(tx pgx.Tx) error {
+		purged = 0
+		query = `SELECT COALESCE(array_agg(id), '{}') FROM plans WHERE deletedat < $1`
+		ids := make([]int32, 0)
+		if err := tx.QueryRow(context.Background(), query, before).Scan(&ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		queries := []string{
+			`DELETE FROM usersplans WHERE planid = ANY($1)`,
+			`DELETE FROM steps_sources WHERE stepid IN (SELECT id FROM steps WHERE planid = ANY($1))`,
+			`DELETE FROM steps WHERE planid = ANY($1)`,
+			`DELETE FROM points_aggregated_plans WHERE entityid = ANY($1)`,
+		}
+		for _, q := range queries {
+			query = q
+			if _, err := tx.Exec(context.Background(), query, ids); err != nil {
+				return err
+			}
+		}
+
+		query = `DELETE FROM comments WHERE entitytype = $1 AND entityid = ANY($2)`
+		if _, err := tx.Exec(context.Background(), query, int(domain.PlanEntity), ids); err != nil {
+			return err
+		}
+
+		query = `DELETE FROM plans WHERE id = ANY($1)`
+		tag, err := tx.Exec(context.Background(), query, ids)
+		if err != nil {
+			return err
+		}
+		purged = tag.RowsAffected()
+		return nil
+	})
 	if err != nil {
-		return false, r.Db.LogError(err, deletePlanQuery)
+		return 0, r.Db.LogError(err, query)
 	}
-	deleteStepsQuery := `DELETE FROM steps WHERE planid = $1`
-	_, err = r.Db.Conn.Exec(context.Background(), deleteStepsQuery, planId)
+	return purged, nil
+}
+func (r *planRepo) SetHidden(ctx core.ReqContext, planId int, hidden bool) (bool, *core.AppError) {
+	tr := ctx.StartTrace("PlanRepository.SetHidden")
+	defer ctx.StopTrace(tr)
+
+	query := `UPDATE plans SET hidden = $2 WHERE id = $1`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, planId, hidden)
 	if err != nil {
-		return false, r.Db.LogError(err, deletePlanQuery)
+		return false, r.Db.LogError(err, query)
 	}
-	return true, nil
+	return tag.RowsAffected() > 0, nil
 }
 
 func (r *planRepo) Get(ctx core.ReqContext, id int) *domain.Plan {
 	tr := ctx.StartTrace("PlanRepository.Get")
 	defer ctx.StopTrace(tr)
 
-	query := `SELECT id, title, topic, owner, isdraft FROM plans WHERE id=$1 AND isdraft=false ;`
+	query := `SELECT id, title, topic, owner, isdraft, hidden, parentid, version FROM plans WHERE id=$1 AND isdraft=false AND hidden=false AND deletedat IS NULL;`
 	row := r.Db.Conn.QueryRow(context.Background(), query, id)
 	p, err := r.scanRow(row)
 	if err == sql.ErrNoRows {
@@ -165,11 +215,29 @@ func (r *planRepo) Get(ctx core.ReqContext, id int) *domain.Plan {
 	return p.ToPlan()
 }
 
+func (r *planRepo) GetHidden(ctx core.ReqContext, planId int) *domain.Plan {
+	tr := ctx.StartTrace("PlanRepository.GetHidden")
+	defer ctx.StopTrace(tr)
+
+	query := `SELECT id, title, topic, owner, isdraft, hidden, parentid, version FROM plans WHERE id=$1 AND hidden=true AND deletedat IS NULL;`
+	row := r.Db.Conn.QueryRow(context.Background(), query, planId)
+	p, err := r.scanRow(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return nil
+	}
+	return p.ToPlan()
+}
+
 func (r *planRepo) GetWithDraft(ctx core.ReqContext, id int, userid string) *domain.Plan {
 	tr := ctx.StartTrace("PlanRepository.Get")
 	defer ctx.StopTrace(tr)
 
-	query := `SELECT id, title, topic, owner, isdraft FROM plans WHERE id=$1 AND ( isdraft=false OR owner=$2 );`
+	query := `SELECT id, title, topic, owner, isdraft, hidden, parentid, version FROM plans WHERE id=$1 AND deletedat IS NULL AND ( (isdraft=false AND hidden=false) OR owner=$2
+		OR EXISTS (SELECT 1 FROM plan_collaborators c WHERE c.planid = plans.id AND c.userid = $2 AND c.accepted) );`
 	row := r.Db.Conn.QueryRow(context.Background(), query, id, userid)
 	p, err := r.scanRow(row)
 	if err == sql.ErrNoRows {
@@ -186,7 +254,7 @@ func (r *planRepo) GetList(ctx core.ReqContext, id []int) []domain.Plan {
 	tr := ctx.StartTrace("PlanRepository.GetList")
 	defer ctx.StopTrace(tr)
 
-	query := "SELECT id, title, topic, owner, isdraft FROM plans WHERE id IN (%s) AND isdraft=false;"
+	query := "SELECT id, title, topic, owner, isdraft, hidden, parentid, version FROM plans WHERE id IN (%s) AND isdraft=false AND hidden=false AND deletedat IS NULL;"
 	query = fmt.Sprintf(query, strings.Trim(strings.Join(strings.Fields(fmt.Sprint(id)), ","), "[]"))
 	rows, err := r.Db.Conn.Query(context.Background(), query)
 	if err != nil {
@@ -201,9 +269,9 @@ func (r *planRepo) GetByUser(ctx core.ReqContext, userid string, count int, page
 	tr := ctx.StartTrace("PlanRepository.GetByUser")
 	defer ctx.StopTrace(tr)
 
-	query := "SELECT id, title, topic, owner, isdraft " +
+	query := "SELECT id, title, topic, owner, isdraft, hidden, parentid, version " +
 		"FROM plans " +
-		"WHERE owner =$1 ORDER BY id DESC LIMIT $2 OFFSET $3;"
+		"WHERE owner =$1 AND deletedat IS NULL ORDER BY id DESC LIMIT $2 OFFSET $3;"
 	rows, err := r.Db.Conn.Query(context.Background(), query, userid, count, page*count)
 	if err != nil {
 		r.Db.LogError(err, query)
@@ -213,12 +281,17 @@ func (r *planRepo) GetByUser(ctx core.ReqContext, userid string, count int, page
 	return r.scanRows(rows)
 }
 
-func (r *planRepo) GetPopularByTopic(ctx core.ReqContext, topic string, count int) []domain.Plan {
+func (r *planRepo) GetPopularByTopic(ctx core.ReqContext, topic string, sort domain.PlanSort, count int) []domain.Plan {
 	tr := ctx.StartTrace("PlanRepository.GetPopularByTopic")
 	defer ctx.StopTrace(tr)
 
-	query := "SELECT p.id, p.title, p.topic, p.owner, p.isdraft FROM plans p LEFT JOIN points_aggregated_plans ps ON p.id=ps.entityid WHERE p.topic=$1 AND p.isdraft=false ORDER BY ps.value DESC LIMIT $2"
-	rows, err := r.Db.Conn.Query(context.Background(), query, topic, count)
+	// plans added after last run of ranking job have no score yet
+	query := "SELECT p.id, p.title, p.topic, p.owner, p.isdraft, p.hidden, p.parentid, p.version FROM plans p " +
+		"LEFT JOIN plan_scores s ON p.id=s.planid AND s.sort=$2 " +
+		"LEFT JOIN points_aggregated_plans ps ON p.id=ps.entityid " +
+		"WHERE p.topic=$1 AND p.isdraft=false AND p.hidden=false AND p.deletedat IS NULL " +
+		"ORDER BY s.score DESC NULLS LAST, ps.value DESC NULLS LAST, p.id DESC LIMIT $3"
+	rows, err := r.Db.Conn.Query(context.Background(), query, topic, string(sort), count)
 	if err != nil {
 		r.Db.LogError(err, query)
 		return []domain.Plan{}
@@ -227,6 +300,66 @@ func (r *planRepo) GetPopularByTopic(ctx core.ReqContext, topic string, count in
 	return r.scanRows(rows)
 }
 
+func (r *planRepo) SaveScores(sort domain.PlanSort, scores map[int64]float64) *core.AppError {
+	ids := make([]int32, 0, len(scores))
+	values := make([]float64, 0, len(scores))
+	for id, score := range scores {
+		ids = append(ids, int32(id))
+		values = append(values, score)
+	}
+
+	query := ""
+	err := r.Db.InTx(func(tx pgx.Tx) error {
+		query = `DELETE FROM plan_scores WHERE sort = $1`
+		if _, err := tx.Exec(context.Background(), query, string(sort)); err != nil {
+			return err
+		}
+
+		// scores of plans purged while job was running are skipped
+		query = `INSERT INTO plan_scores (planid, sort, score, updatedate)
+		SELECT s.planid, $1, s.score, now() FROM unnest($2::integer[], $3::double precision[]) AS s(planid, score)
+		WHERE EXISTS (SELECT 1 FROM plans WHERE id = s.planid)`
+		_, err := tx.Exec(context.Background(), query, string(sort), ids, values)
+		return err
+	})
+	if err != nil {
+		return r.Db.LogError(err, query)
+	}
+	return nil
+}
+
+func (r *planRepo) GetListedIds() ([]int64, *core.AppError) {
+	query := `SELECT id FROM plans WHERE isdraft=false AND hidden=false AND deletedat IS NULL;`
+	rows, err := r.Db.Conn.Query(context.Background(), query)
+	if err != nil {
+		return nil, r.Db.LogError(err, query)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, r.Db.LogError(err, query)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *planRepo) CountForks(ctx core.ReqContext, planId int) int {
+	tr := ctx.StartTrace("PlanRepository.CountForks")
+	defer ctx.StopTrace(tr)
+
+	query := `SELECT count(*) FROM plans WHERE parentid=$1 AND deletedat IS NULL;`
+	var count int
+	if err := r.Db.Conn.QueryRow(context.Background(), query, planId).Scan(&count); err != nil {
+		r.Db.LogError(err, query)
+		return 0
+	}
+	return count
+}
+
 func (r *planRepo) scanRows(rows pgx.Rows) []domain.Plan {
 	plans := make([]domain.Plan, 0)
 	for rows.Next() {
@@ -240,7 +373,7 @@ func (r *planRepo) scanRows(rows pgx.Rows) []domain.Plan {
 }
 
 func (r *planRepo) All() []domain.Plan {
-	query := "SELECT id, title, topic, owner, isdraft FROM plans"
+	query := "SELECT id, title, topic, owner, isdraft, hidden, parentid, version FROM plans WHERE deletedat IS NULL"
 	rows, err := r.Db.Conn.Query(context.Background(), query)
 	if err != nil {
 		r.Db.LogError(err, query)
@@ -252,7 +385,7 @@ func (r *planRepo) All() []domain.Plan {
 
 func (r *planRepo) scanRow(row pgx.Row) (*PlanDBO, error) {
 	dbo := PlanDBO{}
-	err := row.Scan(&dbo.Id, &dbo.Title, &dbo.TopicName, &dbo.OwnerId, &dbo.IsDraft)
+	err := row.Scan(&dbo.Id, &dbo.Title, &dbo.TopicName, &dbo.OwnerId, &dbo.IsDraft, &dbo.Hidden, &dbo.ParentId, &dbo.Version)
 	if err != nil && err.Error() == "no rows in result set" {
 		return &dbo, sql.ErrNoRows
 	}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type planSuggestionRepo struct {
+	Db *DbConnection
+}
+
+func NewPlanSuggestionRepository(db *DbConnection) core.PlanSuggestionRepository {
+	return &planSuggestionRepo{Db: db}
+}
+
+func (r *planSuggestionRepo) Add(ctx core.ReqContext, suggestion *domain.PlanSuggestion) (bool, *core.AppError) {
+	tr := ctx.StartTrace("PlanSuggestionRepository.Add")
+	defer ctx.StopTrace(tr)
+
+	dbo := &PlanSuggestionDBO{}
+	if err := dbo.FromPlanSuggestion(suggestion); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+
+	query := `INSERT INTO plan_suggestions (planid, userid, title, steps, message, status, date, planversion) 
+	VALUES ($1, $2, $3, $4::jsonb, $5, $6, now(), $7) 
+	RETURNING id, date;`
+	err := r.Db.Conn.QueryRow(context.Background(), query, dbo.PlanId, dbo.UserId, dbo.Title, string(dbo.Steps), dbo.Message, int(domain.SuggestionPending), dbo.PlanVersion).Scan(&suggestion.Id, &suggestion.Date)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return true, nil
+}
+
+func (r *planSuggestionRepo) Get(ctx core.ReqContext, id int64) *domain.PlanSuggestion {
+	query := `SELECT id, planid, userid, title, steps, message, status, date, reviewedby, reviewedat, comment, planversion 
+	FROM plan_suggestions WHERE id = $1;`
+	tr := ctx.StartTrace("PlanSuggestionRepository.Get")
+	defer ctx.StopTrace(tr)
+
+	row := r.Db.Conn.QueryRow(context.Background(), query, id)
+	dbo, err := r.scanRow(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return nil
+	}
+	return dbo.ToPlanSuggestion()
+}
+
+func (r *planSuggestionRepo) GetPending(ctx core.ReqContext, ownerId string, planId int, count int, page int) []domain.PlanSuggestion {
+	query := `SELECT s.id, s.planid, s.userid, s.title, s.steps, s.message, s.status, s.date, s.reviewedby, s.reviewedat, s.comment, s.planversion 
+	FROM plan_suggestions s 
+		INNER JOIN plans p ON p.id = s.planid 
+	WHERE s.status = 0 
+		AND p.owner = $1 
+		AND p.deletedat IS NULL 
+		AND ($2 = 0 OR s.planid = $2) 
+	ORDER BY s.date DESC 
+	LIMIT $3 OFFSET $4;`
+	tr := ctx.StartTrace("PlanSuggestionRepository.GetPending")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, ownerId, planId, count, page*count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.PlanSuggestion{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *planSuggestionRepo) Review(ctx core.ReqContext, suggestion *domain.PlanSuggestion) (bool, *core.AppError) {
+	query := `UPDATE plan_suggestions SET status = $2, reviewedby = $3, reviewedat = now(), comment = $4 
+	WHERE id = $1 AND status = 0;`
+	tr := ctx.StartTrace("PlanSuggestionRepository.Review")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, suggestion.Id, int(suggestion.Status), suggestion.ReviewedBy, ToNullString(suggestion.Comment))
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *planSuggestionRepo) scanRows(rows pgx.Rows) []domain.PlanSuggestion {
+	suggestions := make([]domain.PlanSuggestion, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			return []domain.PlanSuggestion{}
+		}
+		suggestions = append(suggestions, *dbo.ToPlanSuggestion())
+	}
+	return suggestions
+}
+
+func (r *planSuggestionRepo) scanRow(row pgx.Row) (*PlanSuggestionDBO, error) {
+	dbo := PlanSuggestionDBO{}
+	err := row.Scan(&dbo.Id, &dbo.PlanId, &dbo.UserId, &dbo.Title, &dbo.Steps, &dbo.Message, &dbo.Status, &dbo.Date, &dbo.ReviewedBy, &dbo.ReviewedAt, &dbo.Comment, &dbo.PlanVersion)
+	if err != nil && err.Error() == "no rows in result set" {
+		return &dbo, sql.ErrNoRows
+	}
+	return &dbo, err
+}
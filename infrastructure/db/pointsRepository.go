@@ -1,12 +1,14 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
 	"github.com/jackc/pgx/v4"
 	"strings"
+	"time"
 )
 
 type pointsRepo struct {
@@ -17,16 +19,155 @@ func NewPointsRepository(db *DbConnection) core.PointsRepository {
 	return &pointsRepo{Db: db}
 }
 
-func (r *pointsRepo) Add(ctx core.ReqContext, entityType domain.EntityType, entityId int64, userId string, value int) bool {
+func (r *pointsRepo) Add(ctx core.ReqContext, vote *domain.VoteRecord) (int, bool) {
 	tr := ctx.StartTrace("PointsRepository.Add")
 	defer ctx.StopTrace(tr)
-	query := fmt.Sprintf("INSERT INTO points_%ss (entityid,userid,date,value) VALUES ($1,$2,now(),$3 )", domain.EntityTypeToString(entityType))
-	_, err := r.Db.Conn.Exec(ctx, query, entityId, userId, value)
+	entityName := domain.EntityTypeToString(vote.EntityType)
+	// previous vote is locked until it is replaced, so concurrent votes of user read values of each other.
+	// vote flagged by fraud detection stays flagged after change
+	query := fmt.Sprintf("WITH old AS (SELECT value FROM points_%ss WHERE entityid=$1 AND userid=$2 FOR UPDATE) "+
+		"INSERT INTO points_%ss (entityid,userid,date,value,ip,weight,flagged) VALUES ($1,$2,now(),$3,$4,$5,$6) "+
+		"ON CONFLICT (entityid,userid) DO UPDATE SET value=EXCLUDED.value, date=EXCLUDED.date, ip=EXCLUDED.ip, weight=EXCLUDED.weight, flagged=points_%ss.flagged OR EXCLUDED.flagged "+
+		"RETURNING xmax = 0, (SELECT value FROM old)", entityName, entityName, entityName)
+	var inserted bool
+	var previous *int
+	err := r.Db.Conn.QueryRow(ctx, query, vote.EntityId, vote.UserId, vote.Value, ToNullString(vote.IP), vote.Weight, vote.Flagged).Scan(&inserted, &previous)
 	if err != nil {
 		r.Db.LogError(err, query)
-		return false
+		return 0, false
 	}
-	return true
+	if inserted {
+		return 0, true
+	}
+	// the first vote was inserted by concurrent request, it is treated as not changed
+	if previous == nil {
+		return vote.Value, true
+	}
+	return *previous, true
+}
+
+func (r *pointsRepo) Remove(ctx core.ReqContext, entityType domain.EntityType, entityId int64, userId string) (int, bool) {
+	tr := ctx.StartTrace("PointsRepository.Remove")
+	defer ctx.StopTrace(tr)
+	query := fmt.Sprintf("DELETE FROM points_%ss WHERE entityid=$1 AND userid=$2 RETURNING value", domain.EntityTypeToString(entityType))
+	var previous int
+	err := r.Db.Conn.QueryRow(ctx, query, entityId, userId).Scan(&previous)
+	if err == pgx.ErrNoRows {
+		return 0, false
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return 0, false
+	}
+	return previous, true
+}
+
+func (r *pointsRepo) CountRecent(ctx core.ReqContext, userId string, ip string, since time.Time) (int, int) {
+	tr := ctx.StartTrace("PointsRepository.CountRecent")
+	defer ctx.StopTrace(tr)
+	// points_base includes votes of all inherited tables
+	query := `SELECT count(*) FILTER (WHERE userid = $1), count(*) FILTER (WHERE ip = $2) FROM points_base WHERE date > $3 AND (userid = $1 OR ip = $2)`
+	var byUser, byIP int
+	if err := r.Db.Conn.QueryRow(ctx, query, userId, ip, since).Scan(&byUser, &byIP); err != nil {
+		r.Db.LogError(err, query)
+		return 0, 0
+	}
+	return byUser, byIP
+}
+
+func (r *pointsRepo) GetVotes(entityType domain.EntityType) ([]domain.EntityVotes, *core.AppError) {
+	query := fmt.Sprintf("SELECT entityid, value, date, weight FROM points_%ss WHERE flagged = false ORDER BY entityid", domain.EntityTypeToString(entityType))
+	rows, err := r.Db.Conn.Query(context.Background(), query)
+	if err != nil {
+		return nil, r.Db.LogError(err, query)
+	}
+	defer rows.Close()
+
+	result := make([]domain.EntityVotes, 0)
+	for rows.Next() {
+		var id int64
+		var vote domain.Vote
+		if err := rows.Scan(&id, &vote.Value, &vote.Date, &vote.Weight); err != nil {
+			return nil, r.Db.LogError(err, query)
+		}
+		if len(result) == 0 || result[len(result)-1].EntityId != id {
+			result = append(result, domain.EntityVotes{EntityId: id})
+		}
+		last := &result[len(result)-1]
+		last.Votes = append(last.Votes, vote)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.Db.LogError(err, query)
+	}
+	return result, nil
+}
+
+func (r *pointsRepo) GetRecent(since time.Time) ([]domain.VoteRecord, *core.AppError) {
+	selects := make([]string, 0)
+	for et := domain.PlanEntity; et <= domain.UserEntity; et++ {
+		if et.IsVotable() {
+			selects = append(selects, fmt.Sprintf("SELECT %d, entityid, userid, COALESCE(ip, ''), value, weight, date FROM points_%ss WHERE flagged = false AND date > $1", et, domain.EntityTypeToString(et)))
+		}
+	}
+	query := strings.Join(selects, " UNION ALL ")
+	rows, err := r.Db.Conn.Query(context.Background(), query, since)
+	if err != nil {
+		return nil, r.Db.LogError(err, query)
+	}
+	defer rows.Close()
+
+	result := make([]domain.VoteRecord, 0)
+	for rows.Next() {
+		var vote domain.VoteRecord
+		var entityType int
+		if err := rows.Scan(&entityType, &vote.EntityId, &vote.UserId, &vote.IP, &vote.Value, &vote.Weight, &vote.Date); err != nil {
+			return nil, r.Db.LogError(err, query)
+		}
+		vote.EntityType = domain.EntityType(entityType)
+		result = append(result, vote)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.Db.LogError(err, query)
+	}
+	return result, nil
+}
+
+func (r *pointsRepo) Flag(votes []domain.VoteRecord) *core.AppError {
+	byType := make(map[domain.EntityType][]domain.VoteRecord)
+	for _, v := range votes {
+		byType[v.EntityType] = append(byType[v.EntityType], v)
+	}
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	for entityType, list := range byType {
+		ids := make([]int64, len(list))
+		users := make([]string, len(list))
+		for i, v := range list {
+			ids[i] = v.EntityId
+			users[i] = v.UserId
+		}
+		// aggregates are recalculated by trigger
+		query := fmt.Sprintf(`UPDATE points_%ss p SET flagged = true
+			FROM unnest($1::bigint[], $2::varchar[]) AS f(entityid, userid)
+			WHERE p.entityid = f.entityid AND p.userid = f.userid AND p.flagged = false`, domain.EntityTypeToString(entityType))
+		if _, err := tx.Exec(context.Background(), query, ids, users); err != nil {
+			return r.Db.LogError(err, query)
+		}
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return r.Db.LogError(err, "")
+	}
+	return nil
 }
 
 func (r *pointsRepo) Get(ctx core.ReqContext, userid string, entityType domain.EntityType, entityId int64) *domain.Points {
@@ -36,10 +177,10 @@ func (r *pointsRepo) Get(ctx core.ReqContext, userid string, entityType domain.E
 	var row pgx.Row
 	entityName := domain.EntityTypeToString(entityType)
 	if userid == "" {
-		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, false FROM points_aggregated_%ss WHERE entityid=$1", entityName)
+		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, 0 FROM points_aggregated_%ss WHERE entityid=$1", entityName)
 		row = r.Db.Conn.QueryRow(ctx, query, entityId)
 	} else {
-		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, EXISTS (SELECT entityid FROM points_%ss WHERE entityid=ps.entityid AND userid=$2) FROM points_aggregated_%ss ps WHERE entityid=$1", entityName, entityName)
+		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, COALESCE((SELECT value FROM points_%ss WHERE entityid=ps.entityid AND userid=$2), 0) FROM points_aggregated_%ss ps WHERE entityid=$1", entityName, entityName)
 		row = r.Db.Conn.QueryRow(ctx, query, entityId, userid)
 	}
 
@@ -64,10 +205,10 @@ func (r *pointsRepo) GetList(ctx core.ReqContext, userid string, entityType doma
 	var err error
 	entityName := domain.EntityTypeToString(entityType)
 	if userid == "" {
-		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, false FROM points_aggregated_%ss WHERE entityid IN (%s)", entityName, idList)
+		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, 0 FROM points_aggregated_%ss WHERE entityid IN (%s)", entityName, idList)
 		rows, err = r.Db.Conn.Query(ctx, query)
 	} else {
-		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, EXISTS (SELECT entityid FROM points_%ss WHERE entityid=ps.entityid AND userid=$1) FROM points_aggregated_%ss ps WHERE entityid IN (%s)", entityName, entityName, idList)
+		query = fmt.Sprintf("SELECT entityid, updatedate, count, value, avg, COALESCE((SELECT value FROM points_%ss WHERE entityid=ps.entityid AND userid=$1), 0) FROM points_aggregated_%ss ps WHERE entityid IN (%s)", entityName, entityName, idList)
 		rows, err = r.Db.Conn.Query(ctx, query, userid)
 	}
 
@@ -0,0 +1,215 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type reportRepo struct {
+	Db *DbConnection
+}
+
+func NewReportRepository(db *DbConnection) core.ReportRepository {
+	return &reportRepo{Db: db}
+}
+
+func (r *reportRepo) Add(ctx core.ReqContext, entityType domain.EntityType, entityId int64, complaint *domain.Complaint) (bool, *core.AppError) {
+	tr := ctx.StartTrace("ReportRepository.Add")
+	defer ctx.StopTrace(tr)
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	reportQuery := `INSERT INTO reports (entitytype, entityid, status, count, created, updated) 
+	VALUES ($1, $2, $3, 0, now(), now()) 
+	ON CONFLICT (entitytype, entityid) WHERE status = 0 
+		DO UPDATE SET updated = now() 
+	RETURNING id;`
+	err = tx.QueryRow(context.Background(), reportQuery, int(entityType), entityId, int(domain.ReportOpen)).Scan(&complaint.ReportId)
+	if err != nil {
+		return false, r.Db.LogError(err, reportQuery)
+	}
+
+	complaintQuery := `INSERT INTO reports_complaints (reportid, userid, reason, text, date) 
+	VALUES ($1, $2, $3, $4, now()) 
+	ON CONFLICT DO NOTHING;`
+	tag, err := tx.Exec(context.Background(), complaintQuery, complaint.ReportId, complaint.UserId, int(complaint.Reason), ToNullString(complaint.Text))
+	if err != nil {
+		return false, r.Db.LogError(err, complaintQuery)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	countQuery := `UPDATE reports SET count = count + 1 WHERE id = $1;`
+	if _, err = tx.Exec(context.Background(), countQuery, complaint.ReportId); err != nil {
+		return false, r.Db.LogError(err, countQuery)
+	}
+
+	if err = tx.Commit(context.Background()); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	return true, nil
+}
+
+func (r *reportRepo) Get(ctx core.ReqContext, id int64) *domain.Report {
+	query := `SELECT id, entitytype, entityid, status, count, created, updated, resolvedby, resolvedat, comment 
+	FROM reports WHERE id = $1;`
+	tr := ctx.StartTrace("ReportRepository.Get")
+	defer ctx.StopTrace(tr)
+
+	row := r.Db.Conn.QueryRow(context.Background(), query, id)
+	dbo, err := r.scanRow(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return nil
+	}
+
+	reports := []domain.Report{*dbo.ToReport()}
+	r.fillComplaints(reports, "")
+	return &reports[0]
+}
+
+func (r *reportRepo) GetOpen(ctx core.ReqContext, entityType domain.EntityType, count int, page int) []domain.Report {
+	query := `SELECT id, entitytype, entityid, status, count, created, updated, resolvedby, resolvedat, comment 
+	FROM reports 
+	WHERE status = 0 
+		AND ($1 = 0 OR entitytype = $1) 
+	ORDER BY count DESC, updated 
+	LIMIT $2 OFFSET $3;`
+	tr := ctx.StartTrace("ReportRepository.GetOpen")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, int(entityType), count, page*count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Report{}
+	}
+	reports := r.scanRows(rows)
+	rows.Close()
+
+	r.fillComplaints(reports, "")
+	return reports
+}
+
+func (r *reportRepo) GetByUser(ctx core.ReqContext, userId string, count int, page int) []domain.Report {
+	query := `SELECT r.id, r.entitytype, r.entityid, r.status, r.count, r.created, r.updated, r.resolvedby, r.resolvedat, r.comment 
+	FROM reports r 
+		INNER JOIN reports_complaints c ON c.reportid = r.id 
+	WHERE c.userid = $1 
+	ORDER BY c.date DESC 
+	LIMIT $2 OFFSET $3;`
+	tr := ctx.StartTrace("ReportRepository.GetByUser")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId, count, page*count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Report{}
+	}
+	reports := r.scanRows(rows)
+	rows.Close()
+
+	r.fillComplaints(reports, userId)
+	return reports
+}
+
+func (r *reportRepo) Resolve(ctx core.ReqContext, report *domain.Report) (bool, *core.AppError) {
+	query := `UPDATE reports SET status = $2, resolvedby = $3, resolvedat = now(), comment = $4, updated = now() 
+	WHERE id = $1 AND status = 0;`
+	tr := ctx.StartTrace("ReportRepository.Resolve")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, report.Id, int(report.Status), report.ResolvedBy, ToNullString(report.Comment))
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *reportRepo) Reopen(ctx core.ReqContext, report *domain.Report) (bool, *core.AppError) {
+	query := `UPDATE reports SET status = 0, resolvedby = NULL, resolvedat = NULL, comment = NULL, updated = now() 
+	WHERE id = $1 AND status = $2;`
+	tr := ctx.StartTrace("ReportRepository.Reopen")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, report.Id, int(report.Status))
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// fillComplaints loads complaints of reports, if userId is not empty only complaints of this user are loaded
+func (r *reportRepo) fillComplaints(reports []domain.Report, userId string) {
+	if len(reports) == 0 {
+		return
+	}
+
+	ids := make([]string, len(reports))
+	idx := make(map[int64]int, len(reports))
+	for i, v := range reports {
+		ids[i] = fmt.Sprint(v.Id)
+		idx[v.Id] = i
+	}
+
+	query := fmt.Sprintf(`SELECT reportid, userid, reason, text, date 
+	FROM reports_complaints 
+	WHERE reportid IN (%s) 
+		AND ($1 = '' OR userid = $1) 
+	ORDER BY date;`, strings.Join(ids, ","))
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		dbo := ComplaintDBO{}
+		if err := rows.Scan(&dbo.ReportId, &dbo.UserId, &dbo.Reason, &dbo.Text, &dbo.Date); err != nil {
+			r.Db.LogError(err, query)
+			return
+		}
+		i := idx[dbo.ReportId]
+		reports[i].Complaints = append(reports[i].Complaints, *dbo.ToComplaint())
+	}
+}
+
+func (r *reportRepo) scanRows(rows pgx.Rows) []domain.Report {
+	reports := make([]domain.Report, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			return []domain.Report{}
+		}
+		reports = append(reports, *dbo.ToReport())
+	}
+	return reports
+}
+
+func (r *reportRepo) scanRow(row pgx.Row) (*ReportDBO, error) {
+	dbo := ReportDBO{}
+	err := row.Scan(&dbo.Id, &dbo.EntityType, &dbo.EntityId, &dbo.Status, &dbo.Count, &dbo.Created, &dbo.Updated, &dbo.ResolvedBy, &dbo.ResolvedAt, &dbo.Comment)
+	if err != nil && err.Error() == "no rows in result set" {
+		return &dbo, sql.ErrNoRows
+	}
+	return &dbo, err
+}
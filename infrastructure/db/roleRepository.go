@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type roleRepo struct {
+	Db *DbConnection
+}
+
+func NewRoleRepository(db *DbConnection) core.RoleRepository {
+	return &roleRepo{Db: db}
+}
+
+func (r *roleRepo) Get(ctx core.ReqContext, id int) *domain.Role {
+	query := "SELECT id, name, permissions FROM roles WHERE id=$1;"
+	tr := ctx.StartTrace("RoleRepository.Get")
+	defer ctx.StopTrace(tr)
+	row := r.Db.Conn.QueryRow(context.Background(), query, id)
+	dbo, err := r.scanRow(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return nil
+	}
+	return dbo.ToRole()
+}
+
+func (r *roleRepo) GetByName(ctx core.ReqContext, name string) *domain.Role {
+	query := "SELECT id, name, permissions FROM roles WHERE name=$1;"
+	tr := ctx.StartTrace("RoleRepository.GetByName")
+	defer ctx.StopTrace(tr)
+	row := r.Db.Conn.QueryRow(context.Background(), query, name)
+	dbo, err := r.scanRow(row)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		r.Db.LogError(err, query)
+		return nil
+	}
+	return dbo.ToRole()
+}
+
+func (r *roleRepo) GetList(ctx core.ReqContext) []domain.Role {
+	query := "SELECT id, name, permissions FROM roles ORDER BY id;"
+	tr := ctx.StartTrace("RoleRepository.GetList")
+	defer ctx.StopTrace(tr)
+	rows, err := r.Db.Conn.Query(context.Background(), query)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Role{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *roleRepo) GetByUser(ctx core.ReqContext, userId string) []domain.Role {
+	query := `SELECT r.id, r.name, r.permissions 
+	FROM roles r 
+		INNER JOIN users_roles ur ON ur.roleid = r.id 
+	WHERE ur.userid=$1 
+	ORDER BY r.id;`
+	tr := ctx.StartTrace("RoleRepository.GetByUser")
+	defer ctx.StopTrace(tr)
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Role{}
+	}
+	defer rows.Close()
+	return r.scanRows(rows)
+}
+
+func (r *roleRepo) Save(ctx core.ReqContext, role *domain.Role) (bool, *core.AppError) {
+	dbo := &RoleDBO{}
+	dbo.FromRole(role)
+	query := "INSERT INTO roles (name, permissions) VALUES ($1, $2) RETURNING id;"
+	tr := ctx.StartTrace("RoleRepository.Save")
+	defer ctx.StopTrace(tr)
+	err := r.Db.Conn.QueryRow(context.Background(), query, dbo.Name, dbo.Permissions).Scan(&role.Id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return true, nil
+}
+
+func (r *roleRepo) Update(ctx core.ReqContext, role *domain.Role) (bool, *core.AppError) {
+	dbo := &RoleDBO{}
+	dbo.FromRole(role)
+	query := "UPDATE roles SET name=$2, permissions=$3 WHERE id=$1;"
+	tr := ctx.StartTrace("RoleRepository.Update")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, dbo.Id, dbo.Name, dbo.Permissions)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *roleRepo) Delete(ctx core.ReqContext, id int) (bool, *core.AppError) {
+	query := "DELETE FROM roles WHERE id=$1;"
+	tr := ctx.StartTrace("RoleRepository.Delete")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *roleRepo) AddToUser(ctx core.ReqContext, userId string, roleId int) (bool, *core.AppError) {
+	query := "INSERT INTO users_roles (userid, roleid) VALUES ($1, $2) ON CONFLICT DO NOTHING;"
+	tr := ctx.StartTrace("RoleRepository.AddToUser")
+	defer ctx.StopTrace(tr)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, userId, roleId)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *roleRepo) RemoveFromUser(ctx core.ReqContext, userId string, roleId int, keepLast bool) (bool, *core.AppError) {
+	tr := ctx.StartTrace("RoleRepository.RemoveFromUser")
+	defer ctx.StopTrace(tr)
+
+	// serializable transaction, two users could not revoke role of each other at the same time
+	removed, last := false, false
+	query := ""
+	err := r.Db.InTx(func(tx pgx.Tx) error {
+		removed, last = false, false
+		if keepLast {
+			query = "SELECT count(*) FILTER (WHERE userid <> $2), count(*) FILTER (WHERE userid = $2) FROM users_roles WHERE roleid=$1;"
+			var others, own int
+			if err := tx.QueryRow(context.Background(), query, roleId, userId).Scan(&others, &own); err != nil {
+				return err
+			}
+			if others == 0 && own > 0 {
+				last = true
+				return nil
+			}
+		}
+
+		query = "DELETE FROM users_roles WHERE userid=$1 AND roleid=$2;"
+		tag, err := tx.Exec(context.Background(), query, userId, roleId)
+		if err != nil {
+			return err
+		}
+		removed = tag.RowsAffected() > 0
+		return nil
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	if last {
+		return false, core.NewError(core.InUse)
+	}
+	return removed, nil
+}
+
+func (r *roleRepo) scanRows(rows pgx.Rows) []domain.Role {
+	roles := make([]domain.Role, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			return []domain.Role{}
+		}
+		roles = append(roles, *dbo.ToRole())
+	}
+	return roles
+}
+
+func (r *roleRepo) scanRow(row pgx.Row) (*RoleDBO, error) {
+	dbo := RoleDBO{}
+	err := row.Scan(&dbo.Id, &dbo.Name, &dbo.Permissions)
+	if err != nil && err.Error() == "no rows in result set" {
+		return &dbo, sql.ErrNoRows
+	}
+	return &dbo, err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
 	"github.com/jackc/pgx/v4"
+	"time"
 )
 
 type sourceRepo struct {
@@ -20,7 +21,7 @@ func NewSourceRepository(db *DbConnection) core.SourceRepository {
 func (repo *sourceRepo) Get(ctx core.ReqContext, id int64) *domain.Source {
 	tr := ctx.StartTrace("SourceRepository.Get")
 	defer ctx.StopTrace(tr)
-	query := "SELECT id, title, identifier, normalizedidentifier, type, properties, img, description FROM sources WHERE id=$1;"
+	query := "SELECT id, title, identifier, normalizedidentifier, type, properties, img, description FROM sources WHERE id=$1 AND deletedat IS NULL;"
 	row := repo.Db.Conn.QueryRow(context.Background(), query, id)
 	dbo, err := repo.scanRow(row)
 	if err == sql.ErrNoRows {
@@ -36,7 +37,7 @@ func (repo *sourceRepo) Get(ctx core.ReqContext, id int64) *domain.Source {
 func (repo *sourceRepo) FindByIdentifier(ctx core.ReqContext, nIdentifier string) *domain.Source {
 	tr := ctx.StartTrace("SourceRepository.FindByIdentifier")
 	defer ctx.StopTrace(tr)
-	query := "SELECT id, title, identifier, normalizedidentifier, type, properties, img, description FROM sources WHERE normalizedidentifier=$1;"
+	query := "SELECT id, title, identifier, normalizedidentifier, type, properties, img, description FROM sources WHERE normalizedidentifier=$1 AND deletedat IS NULL;"
 	row := repo.Db.Conn.QueryRow(context.Background(), query, nIdentifier)
 	dbo, err := repo.scanRow(row)
 
@@ -73,7 +74,7 @@ func (repo *sourceRepo) Update(ctx core.ReqContext, source *domain.Source) (bool
 	dbo.FromSource(source)
 	query := `UPDATE sources
 		SET title=$2, identifier=$3, normalizedidentifier=$4, type=$5, properties=$6, img=$7, description=$8
-		WHERE id=$1;`
+		WHERE id=$1 AND deletedat IS NULL;`
 	tag, err := repo.Db.Conn.Exec(context.Background(), query, dbo.Id, dbo.Title, dbo.Identifier, dbo.NormalizedIdentifier, dbo.Type, dbo.Properties, dbo.Img, dbo.Desc)
 	if err != nil {
 		return false, repo.Db.LogError(err, query)
@@ -81,7 +82,7 @@ func (repo *sourceRepo) Update(ctx core.ReqContext, source *domain.Source) (bool
 	return tag.RowsAffected() > 0, nil
 }
 
-func (repo *sourceRepo) GetOrAddByIdentifier(ctx core.ReqContext, source *domain.Source) *domain.Source {
+func (repo *sourceRepo) GetOrAddByIdentifier(ctx core.ReqContext, source *domain.Source) (*domain.Source, bool) {
 	tr := ctx.StartTrace("SourceRepository.GetOrAddByIdentifier")
 	defer ctx.StopTrace(tr)
 	dbo := &SourceDBO{}
@@ -90,18 +91,61 @@ func (repo *sourceRepo) GetOrAddByIdentifier(ctx core.ReqContext, source *domain
 INSERT INTO sources(
 	title, identifier, normalizedidentifier, type, properties, img, description)
 	VALUES ($1, $2, $3, $4, $5, $6, $7) 
-ON CONFLICT (normalizedidentifier) DO NOTHING;`
+ON CONFLICT (normalizedidentifier) WHERE deletedat IS NULL DO NOTHING;`
 
-	_, err := repo.Db.Conn.Exec(context.Background(), query, dbo.Title, dbo.Identifier, dbo.NormalizedIdentifier, dbo.Type, dbo.Properties, dbo.Img, dbo.Desc)
+	tag, err := repo.Db.Conn.Exec(context.Background(), query, dbo.Title, dbo.Identifier, dbo.NormalizedIdentifier, dbo.Type, dbo.Properties, dbo.Img, dbo.Desc)
 	if err != nil {
 		repo.Db.LogError(err, query)
-		return nil
+		return nil, false
+	}
+	s := repo.FindByIdentifier(ctx, dbo.NormalizedIdentifier)
+	if s == nil {
+		repo.Db.Log.Errorw("Source not found after insert", "reqid", ctx.ReqId(), "identifier", dbo.NormalizedIdentifier)
+	}
+	return s, tag.RowsAffected() > 0
+}
+
+func (repo *sourceRepo) Delete(ctx core.ReqContext, id int64) (bool, *core.AppError) {
+	tr := ctx.StartTrace("SourceRepository.Delete")
+	defer ctx.StopTrace(tr)
+
+	query := `UPDATE sources SET deletedat = now() WHERE id = $1 AND deletedat IS NULL`
+	tag, err := repo.Db.Conn.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, repo.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (repo *sourceRepo) Restore(ctx core.ReqContext, id int64) (bool, *core.AppError) {
+	tr := ctx.StartTrace("SourceRepository.Restore")
+	defer ctx.StopTrace(tr)
+
+	// the same resource could be added again after delete
+	query := `UPDATE sources s SET deletedat = NULL WHERE s.id = $1 AND s.deletedat IS NOT NULL 
+	AND NOT EXISTS (SELECT id FROM sources WHERE normalizedidentifier = s.normalizedidentifier AND deletedat IS NULL)`
+	tag, err := repo.Db.Conn.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, repo.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (repo *sourceRepo) Purge(before time.Time) (int64, *core.AppError) {
+	// sources still used by steps wait until those plans are purged
+	query := `DELETE FROM sources s 
+WHERE s.deletedat < $1 
+	AND NOT EXISTS (SELECT id FROM steps WHERE referencetype = $2 AND referenceid = s.id) 
+	AND NOT EXISTS (SELECT stepid FROM steps_sources WHERE sourceid = s.id)`
+	tag, err := repo.Db.Conn.Exec(context.Background(), query, before, string(domain.ResourceReference))
+	if err != nil {
+		return 0, repo.Db.LogError(err, query)
 	}
-	return repo.FindByIdentifier(ctx, dbo.NormalizedIdentifier)
+	return tag.RowsAffected(), nil
 }
 
 func (repo *sourceRepo) All() []domain.Source {
-	query := "SELECT id, title, identifier, normalizedidentifier, type, properties, img, description FROM sources;"
+	query := "SELECT id, title, identifier, normalizedidentifier, type, properties, img, description FROM sources WHERE deletedat IS NULL;"
 	rows, err := repo.Db.Conn.Query(context.Background(), query)
 	if err != nil {
 		return []domain.Source{}
@@ -8,6 +8,7 @@ import (
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
 	"github.com/jackc/pgx/v4"
+	"time"
 )
 
 type topicRepo struct {
@@ -21,7 +22,7 @@ func NewTopicRepository(db *DbConnection) core.TopicRepository {
 func (repo *topicRepo) Get(ctx core.ReqContext, name string) *domain.Topic {
 	tr := ctx.StartTrace("TopicRepository.Get")
 	defer ctx.StopTrace(tr)
-	row := repo.Db.Conn.QueryRow(context.Background(), "SELECT id, name, title, description, creator, tags, istag FROM topics WHERE name=$1", name)
+	row := repo.Db.Conn.QueryRow(context.Background(), "SELECT id, name, title, description, descriptionhtml, creator, tags, istag, version FROM topics WHERE name=$1 AND deletedat IS NULL", name)
 	dbo, err := repo.scanRow(row)
 	if err == sql.ErrNoRows {
 		return nil
@@ -37,7 +38,7 @@ func (repo *topicRepo) Get(ctx core.ReqContext, name string) *domain.Topic {
 func (repo *topicRepo) GetById(ctx core.ReqContext, id int) *domain.Topic {
 	tr := ctx.StartTrace("TopicRepository.GetById")
 	defer ctx.StopTrace(tr)
-	row := repo.Db.Conn.QueryRow(context.Background(), "SELECT id, name, title, description, creator, tags, istag FROM topics WHERE id=$1", id)
+	row := repo.Db.Conn.QueryRow(context.Background(), "SELECT id, name, title, description, descriptionhtml, creator, tags, istag, version FROM topics WHERE id=$1 AND deletedat IS NULL", id)
 	dbo, err := repo.scanRow(row)
 
 	if err == sql.ErrNoRows {
@@ -57,9 +58,9 @@ func (repo *topicRepo) Save(ctx core.ReqContext, topic *domain.Topic) (bool, *co
 	defer ctx.StopTrace(tr)
 	dbo := TopicDBO{}
 	dbo.FromTopic(topic)
-	query := "INSERT INTO topics( name, title, description, creator, tags, istag) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;"
-	row := repo.Db.Conn.QueryRow(context.Background(), query, dbo.Name, dbo.Title, dbo.Description, dbo.Creator, dbo.Tags, dbo.IsTag)
-	err := row.Scan(&topic.Id)
+	query := "INSERT INTO topics( name, title, description, descriptionhtml, creator, tags, istag) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, version;"
+	row := repo.Db.Conn.QueryRow(context.Background(), query, dbo.Name, dbo.Title, dbo.Description, dbo.DescriptionHtml, dbo.Creator, dbo.Tags, dbo.IsTag)
+	err := row.Scan(&topic.Id, &topic.Version)
 	if err != nil {
 		return false, repo.Db.LogError(err, query)
 	}
@@ -77,52 +78,47 @@ func (repo *topicRepo) Update(ctx core.ReqContext, topic *domain.Topic) (bool, *
 	defer ctx.StopTrace(tr)
 	dbo := TopicDBO{}
 	dbo.FromTopic(topic)
-	oldTopic := repo.GetById(ctx, topic.Id)
-	if oldTopic == nil {
-		return false, core.NewError(core.InternalError)
-	}
 
-	tx, err := repo.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
-		IsoLevel:       pgx.ReadCommitted,
-		AccessMode:     pgx.ReadWrite,
-		DeferrableMode: pgx.NotDeferrable,
-	})
-	query := "UPDATE topics SET name=$2, title=$3, description=$4, istag=$5 WHERE id=$1;"
-	tag, err := repo.Db.Conn.Exec(context.Background(), query, dbo.Id, dbo.Name, dbo.Title, dbo.Description, dbo.IsTag)
-	if err != nil {
-		if e := tx.Rollback(context.Background()); e != nil {
-			repo.Db.Log.Errorw("Tx not rolled back", "err", e.Error())
+	var version int
+	query := ""
+	err := repo.Db.InTx(func(tx pgx.Tx) error {
+		var oldName string
+		query = `SELECT name FROM topics WHERE id=$1 AND version=$2 AND deletedat IS NULL;`
+		err := tx.QueryRow(context.Background(), query, dbo.Id, dbo.Version).Scan(&oldName)
+		if err == pgx.ErrNoRows {
+			return errVersionConflict
 		}
-		return false, repo.Db.LogError(err, query)
-	}
-
-	if tag.RowsAffected() > 0 {
-		_, err := repo.updateTags(oldTopic.Name, dbo.Name)
 		if err != nil {
-			if e := tx.Rollback(context.Background()); e != nil {
-				repo.Db.Log.Errorw("Tx not rolled back", "err", e.Error())
+			return err
+		}
+
+		query = "UPDATE topics SET name=$2, title=$3, description=$4, descriptionhtml=$5, istag=$6, version=version+1 WHERE id=$1 RETURNING version;"
+		if err := tx.QueryRow(context.Background(), query, dbo.Id, dbo.Name, dbo.Title, dbo.Description, dbo.DescriptionHtml, dbo.IsTag).Scan(&version); err != nil {
+			return err
+		}
+
+		// name of topic is used as tag of other topics
+		if oldName != dbo.Name {
+			query = `UPDATE topics SET tags = array_replace(tags,$1,$2)`
+			if _, err := tx.Exec(context.Background(), query, oldName, dbo.Name); err != nil {
+				return err
 			}
-			return false, repo.Db.LogError(err, "")
 		}
+		return nil
+	})
+	if err == errVersionConflict {
+		return false, core.NewError(core.Conflict)
 	}
-	err = tx.Commit(context.Background())
-	if err != nil {
-		return false, core.NewError(core.InternalError)
-	}
-	return true, nil
-}
-
-func (repo *topicRepo) updateTags(oldname, newname string) (bool, error) {
-	query := `UPDATE topics SET tags = array_replace(tags,$1,$2)`
-	_, err := repo.Db.Conn.Exec(context.Background(), query, oldname, newname)
 	if err != nil {
 		return false, repo.Db.LogError(err, query)
 	}
+
+	topic.Version = version
 	return true, nil
 }
 
 func (repo *topicRepo) All() []domain.Topic {
-	query := "SELECT id, name, title, description, creator, tags, istag FROM topics"
+	query := "SELECT id, name, title, description, descriptionhtml, creator, tags, istag, version FROM topics WHERE deletedat IS NULL"
 	rows, err := repo.Db.Conn.Query(context.Background(), query)
 	if err != nil {
 		repo.Db.LogError(err, query)
@@ -147,7 +143,7 @@ func (repo *topicRepo) Search(ctx core.ReqContext, str string, tags []string, co
 	defer ctx.StopTrace(tr)
 
 	var buffer bytes.Buffer
-	buffer.WriteString("SELECT id, name, title, description, creator, tags, istag FROM topics WHERE title ILIKE $1 ")
+	buffer.WriteString("SELECT id, name, title, description, descriptionhtml, creator, tags, istag, version FROM topics WHERE title ILIKE $1 AND deletedat IS NULL ")
 	params := make([]interface{}, len(tags)+3)
 	params[0] = "%" + str + "%"
 	params[1] = str
@@ -193,7 +189,7 @@ func (repo *topicRepo) GetTags(ctx core.ReqContext, topicNames []string) []domai
 		return []domain.TopicTag{}
 	}
 
-	query := `SELECT name,title FROM topics WHERE name=ANY($1) AND istag = true`
+	query := `SELECT name,title FROM topics WHERE name=ANY($1) AND istag = true AND deletedat IS NULL`
 
 	rows, err := repo.Db.Conn.Query(context.Background(), query, topicNames)
 	if err != nil {
@@ -223,7 +219,8 @@ func (repo *topicRepo) AddTag(ctx core.ReqContext, tagname, topicname string) bo
 SET tags = array_cat(tags, $1) 
 WHERE name=$2 
 	AND array_position(tags, $3) IS NULL
-	AND EXISTS( SELECT id FROM topics WHERE name=$3 AND istag = true );`
+	AND deletedat IS NULL
+	AND EXISTS( SELECT id FROM topics WHERE name=$3 AND istag = true AND deletedat IS NULL );`
 	t, err := repo.Db.Conn.Exec(context.Background(), query, []string{tagname}, topicname, tagname)
 	if err != nil {
 		repo.Db.LogError(err, query)
@@ -245,9 +242,85 @@ func (repo *topicRepo) DeleteTag(ctx core.ReqContext, tagname, topicname string)
 	return true
 }
 
+func (repo *topicRepo) HasPlans(ctx core.ReqContext, name string) bool {
+	tr := ctx.StartTrace("TopicRepository.HasPlans")
+	defer ctx.StopTrace(tr)
+
+	query := `SELECT EXISTS (SELECT id FROM plans WHERE topic=$1 AND deletedat IS NULL)`
+	var exists bool
+	if err := repo.Db.Conn.QueryRow(context.Background(), query, name).Scan(&exists); err != nil {
+		repo.Db.LogError(err, query)
+		return true
+	}
+	return exists
+}
+
+func (repo *topicRepo) Delete(ctx core.ReqContext, id int) (bool, *core.AppError) {
+	tr := ctx.StartTrace("TopicRepository.Delete")
+	defer ctx.StopTrace(tr)
+
+	query := `UPDATE topics SET deletedat = now() WHERE id = $1 AND deletedat IS NULL`
+	tag, err := repo.Db.Conn.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, repo.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (repo *topicRepo) Restore(ctx core.ReqContext, id int) (bool, *core.AppError) {
+	tr := ctx.StartTrace("TopicRepository.Restore")
+	defer ctx.StopTrace(tr)
+
+	query := `UPDATE topics SET deletedat = NULL WHERE id = $1 AND deletedat IS NOT NULL`
+	tag, err := repo.Db.Conn.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, repo.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (repo *topicRepo) Purge(before time.Time) (int64, *core.AppError) {
+	tx, err := repo.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return 0, repo.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	// topics still referenced by plans, steps or favorites wait until those are purged
+	deleteQuery := `WITH deleted AS (
+	DELETE FROM topics t 
+	WHERE t.deletedat < $1 
+		AND NOT EXISTS (SELECT id FROM plans WHERE topic = t.name) 
+		AND NOT EXISTS (SELECT planid FROM usersplans WHERE topic = t.name)
+		AND NOT EXISTS (SELECT id FROM steps WHERE referencetype = $2 AND referenceid = t.id)
+	RETURNING t.name
+) SELECT COALESCE(array_agg(name), '{}') FROM deleted`
+	names := make([]string, 0)
+	if err := tx.QueryRow(context.Background(), deleteQuery, before, string(domain.TopicReference)).Scan(&names); err != nil {
+		return 0, repo.Db.LogError(err, deleteQuery)
+	}
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	tagsQuery := `UPDATE topics SET tags = ARRAY(SELECT unnest(tags) EXCEPT SELECT unnest($1::character varying[])) WHERE tags && $1::character varying[]`
+	if _, err := tx.Exec(context.Background(), tagsQuery, names); err != nil {
+		return 0, repo.Db.LogError(err, tagsQuery)
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return 0, repo.Db.LogError(err, "")
+	}
+	return int64(len(names)), nil
+}
+
 func (repo *topicRepo) scanRow(row pgx.Row) (*TopicDBO, error) {
 	dbo := TopicDBO{}
-	err := row.Scan(&dbo.Id, &dbo.Name, &dbo.Title, &dbo.Description, &dbo.Creator, &dbo.Tags, &dbo.IsTag)
+	err := row.Scan(&dbo.Id, &dbo.Name, &dbo.Title, &dbo.Description, &dbo.DescriptionHtml, &dbo.Creator, &dbo.Tags, &dbo.IsTag, &dbo.Version)
 	if err != nil && err.Error() == "no rows in result set" {
 		return &dbo, sql.ErrNoRows
 	}
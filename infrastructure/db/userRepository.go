@@ -9,9 +9,13 @@ import (
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/domain"
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 )
 
+// likeEscaper escapes wildcards of LIKE pattern, query uses backslash as ESCAPE character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type userRepository struct {
 	Db *DbConnection
 }
@@ -21,7 +25,7 @@ func NewUserRepository(db *DbConnection) core.UserRepository {
 }
 
 func (r *userRepository) Get(ctx core.ReqContext, id string) *domain.User {
-	query := "SELECT id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt FROM users where id=$1"
+	query := "SELECT id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail, reputation, created FROM users where id=$1"
 	tr := ctx.StartTrace("UserRepository.Get")
 	defer ctx.StopTrace(tr)
 	row := r.Db.Conn.QueryRow(context.Background(), query, id)
@@ -37,38 +41,63 @@ func (r *userRepository) Get(ctx core.ReqContext, id string) *domain.User {
 }
 
 func (r *userRepository) Save(ctx core.ReqContext, user *domain.User) (bool, *core.AppError) {
-	dbo := UserDBO{}
-	dbo.FromUser(user)
-	dbo.Id = uuid.New().String()
-	query := "INSERT INTO users " +
-		"	(id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt) " +
-		"VALUES " +
-		"	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) " +
-		"RETURNING id;"
-
 	tr := ctx.StartTrace("UserRepository.Save")
 	defer ctx.StopTrace(tr)
 
-	row := r.Db.Conn.QueryRow(context.Background(), query, dbo.Id, dbo.Name, dbo.NormalizedName, dbo.Email, dbo.EmailConfirmed, dbo.EmailConfirmation, dbo.Img, dbo.Tokens, dbo.Rights, dbo.Pass, dbo.Salt)
-	err := row.Scan(&user.Id)
+	if query, err := insertUser(r.Db.Conn, user); err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return true, nil
+}
 
+func (r *userRepository) SaveWithEmail(ctx core.ReqContext, user *domain.User, email *domain.Email) (bool, *core.AppError) {
+	tr := ctx.StartTrace("UserRepository.SaveWithEmail")
+	defer ctx.StopTrace(tr)
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
 	if err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	if query, err := insertUser(tx, user); err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	email.UserId = user.Id
+	if query, err := insertEmail(tx, email); err != nil {
 		return false, r.Db.LogError(err, query)
 	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
 	return true, nil
 }
 
-func (r *userRepository) Update(ctx core.ReqContext, user *domain.User) (bool, *core.AppError) {
-	dbo := &UserDBO{}
+func insertUser(conn queryRower, user *domain.User) (string, error) {
+	dbo := UserDBO{}
 	dbo.FromUser(user)
-	query := "UPDATE users " +
-		"SET name=$1, normalizedname=$2, email=$3, emailconfirmed=$4, emailconfirmation=$5, img=$6, tokens=$7, rights=$8, password=$9, salt=$10 " +
-		"WHERE id = $11;"
+	dbo.Id = uuid.New().String()
+	query := "INSERT INTO users " +
+		"	(id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail) " +
+		"VALUES " +
+		"	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) " +
+		"RETURNING id;"
+
+	row := conn.QueryRow(context.Background(), query, dbo.Id, dbo.Name, dbo.NormalizedName, dbo.Email, dbo.EmailConfirmed, dbo.EmailConfirmation, dbo.Img, dbo.Tokens, dbo.Rights, dbo.Pass, dbo.Salt, dbo.Banned, dbo.BanReason, dbo.BannedUntil, dbo.ConfirmationExp, dbo.ConfirmationSent, dbo.PendingEmail)
+	return query, row.Scan(&user.Id)
+}
 
+func (r *userRepository) Update(ctx core.ReqContext, user *domain.User) (bool, *core.AppError) {
 	tr := ctx.StartTrace("UserRepository.Update")
 	defer ctx.StopTrace(tr)
 
-	tag, err := r.Db.Conn.Exec(context.Background(), query, dbo.Name, dbo.NormalizedName, dbo.Email, dbo.EmailConfirmed, dbo.EmailConfirmation, dbo.Img, dbo.Tokens, dbo.Rights, dbo.Pass, dbo.Salt, dbo.Id)
+	tag, query, err := updateUser(r.Db.Conn, user)
 	if err != nil {
 		return false, r.Db.LogError(err, query)
 	}
@@ -76,6 +105,55 @@ func (r *userRepository) Update(ctx core.ReqContext, user *domain.User) (bool, *
 	return tag.RowsAffected() > 0, nil
 }
 
+func (r *userRepository) UpdateWithEmail(ctx core.ReqContext, user *domain.User, email *domain.Email) (bool, *core.AppError) {
+	tr := ctx.StartTrace("UserRepository.UpdateWithEmail")
+	defer ctx.StopTrace(tr)
+
+	tx, err := r.Db.Conn.BeginTx(context.Background(), pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+	})
+	if err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	defer tx.Rollback(context.Background())
+
+	tag, query, err := updateUser(tx, user)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	email.UserId = user.Id
+	if query, err := insertEmail(tx, email); err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return false, r.Db.LogError(err, "")
+	}
+	return true, nil
+}
+
+// execer is implemented by both pool and transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+func updateUser(conn execer, user *domain.User) (pgconn.CommandTag, string, error) {
+	dbo := &UserDBO{}
+	dbo.FromUser(user)
+	query := "UPDATE users " +
+		"SET name=$1, normalizedname=$2, email=$3, emailconfirmed=$4, emailconfirmation=$5, img=$6, tokens=$7, rights=$8, password=$9, salt=$10, banned=$11, banreason=$12, banneduntil=$13, emailconfirmationexpires=$14, emailconfirmationsent=$15, pendingemail=$16 " +
+		"WHERE id = $17;"
+
+	tag, err := conn.Exec(context.Background(), query, dbo.Name, dbo.NormalizedName, dbo.Email, dbo.EmailConfirmed, dbo.EmailConfirmation, dbo.Img, dbo.Tokens, dbo.Rights, dbo.Pass, dbo.Salt, dbo.Banned, dbo.BanReason, dbo.BannedUntil, dbo.ConfirmationExp, dbo.ConfirmationSent, dbo.PendingEmail, dbo.Id)
+	return tag, query, err
+}
+
 func (r *userRepository) ExistsName(ctx core.ReqContext, name string) (exists bool, ok bool) {
 	query := "select exists(select 1 from users where normalizedname=$1)"
 	tr := ctx.StartTrace("UserRepository.ExistsName")
@@ -109,7 +187,7 @@ func (r *userRepository) ExistsEmail(ctx core.ReqContext, email string) (exists
 }
 
 func (r *userRepository) FindByEmail(ctx core.ReqContext, email string) *domain.User {
-	query := "SELECT id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt " +
+	query := "SELECT id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail, reputation, created " +
 		"FROM users where email=$1"
 
 	tr := ctx.StartTrace("UserRepository.FindByEmail")
@@ -127,6 +205,35 @@ func (r *userRepository) FindByEmail(ctx core.ReqContext, email string) *domain.
 	return dbo.ToUser()
 }
 
+func (r *userRepository) FindByNames(ctx core.ReqContext, names []string) []domain.User {
+	query := "SELECT id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail, reputation, created " +
+		"FROM users WHERE normalizedname = ANY($1)"
+	tr := ctx.StartTrace("UserRepository.FindByNames")
+	defer ctx.StopTrace(tr)
+
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		normalized[i] = strings.ToUpper(name)
+	}
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, normalized)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.User{}
+	}
+	defer rows.Close()
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			r.Db.LogError(err, query)
+			return []domain.User{}
+		}
+		users = append(users, *dbo.ToUser())
+	}
+	return users
+}
+
 func (r *userRepository) Count(ctx core.ReqContext) (count int, ok bool) {
 	query := "select count(id) from users;"
 	tr := ctx.StartTrace("UserRepository.Count")
@@ -140,7 +247,7 @@ func (r *userRepository) Count(ctx core.ReqContext) (count int, ok bool) {
 }
 
 func (r *userRepository) All() []domain.User {
-	query := "select id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt " +
+	query := "select id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail, reputation, created " +
 		"FROM users"
 
 	rows, err := r.Db.Conn.Query(context.Background(), query)
@@ -166,7 +273,7 @@ func (r *userRepository) All() []domain.User {
 }
 
 func (r *userRepository) GetList(ctx core.ReqContext, id []string) []domain.User {
-	query := "select id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt " +
+	query := "select id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail, reputation, created " +
 		"FROM users WHERE Id IN ('%s')"
 	tr := ctx.StartTrace("UserRepository.GetList")
 	defer ctx.StopTrace(tr)
@@ -194,6 +301,40 @@ func (r *userRepository) GetList(ctx core.ReqContext, id []string) []domain.User
 	return users
 }
 
+func (r *userRepository) Search(ctx core.ReqContext, filter domain.UserFilter, count int, page int) []domain.User {
+	query := `SELECT id, name, normalizedname, email, emailconfirmed, emailconfirmation, img, tokens, rights, password, salt, banned, banreason, banneduntil, emailconfirmationexpires, emailconfirmationsent, pendingemail, reputation, created 
+	FROM users 
+	WHERE ($1 = '' OR normalizedname LIKE $2 ESCAPE '\' OR email ILIKE $3 ESCAPE '\') 
+		AND (NOT $4 OR banned) 
+		AND (NOT $5 OR NOT emailconfirmed) 
+		AND ($6 = 0 OR id IN (SELECT userid FROM users_roles WHERE roleid = $6)) 
+	ORDER BY normalizedname 
+	LIMIT $7 OFFSET $8;`
+	tr := ctx.StartTrace("UserRepository.Search")
+	defer ctx.StopTrace(tr)
+
+	// wildcards typed by user are searched as is
+	like := "%" + likeEscaper.Replace(filter.Query) + "%"
+	rows, err := r.Db.Conn.Query(context.Background(), query, filter.Query, strings.ToUpper(like), like,
+		filter.Banned, filter.Unconfirmed, filter.RoleId, count, page*count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.User{}
+	}
+	defer rows.Close()
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		dbo, err := r.scanRow(rows)
+		if err != nil {
+			r.Db.LogError(err, query)
+			return []domain.User{}
+		}
+		users = append(users, *dbo.ToUser())
+	}
+
+	return users
+}
+
 func (r *userRepository) AddOauth(ctx core.ReqContext, userid, provider, openid string) (bool, *core.AppError) {
 	query := "INSERT INTO users_oauth( userid, provider, id, date) VALUES ($1, $2, $3, now());"
 	tr := ctx.StartTrace("UserRepository.AddOauth")
@@ -221,7 +362,7 @@ func (r *userRepository) Delete(ctx core.ReqContext, id string) (bool, *core.App
 }
 
 func (r *userRepository) FindByOauth(ctx core.ReqContext, provider, id string) *domain.User {
-	query := "SELECT u.id, u.name, u.normalizedname, u.email, u.emailconfirmed, u.emailconfirmation, u.img, u.tokens, u.rights, u.password, u.salt " +
+	query := "SELECT u.id, u.name, u.normalizedname, u.email, u.emailconfirmed, u.emailconfirmation, u.img, u.tokens, u.rights, u.password, u.salt, u.banned, u.banreason, u.banneduntil, u.emailconfirmationexpires, u.emailconfirmationsent, u.pendingemail, u.reputation, u.created " +
 		"FROM users u INNER JOIN users_oauth ua ON ua.userid = u.id " +
 		"WHERE ua.provider=$1 AND ua.id=$2"
 	tr := ctx.StartTrace("UserRepository.FindByOauth")
@@ -244,7 +385,7 @@ func (r *userRepository) FindByOauth(ctx core.ReqContext, provider, id string) *
 
 func (r *userRepository) scanRow(row pgx.Row) (*UserDBO, error) {
 	dbo := UserDBO{}
-	err := row.Scan(&dbo.Id, &dbo.Name, &dbo.NormalizedName, &dbo.Email, &dbo.EmailConfirmed, &dbo.EmailConfirmation, &dbo.Img, &dbo.Tokens, &dbo.Rights, &dbo.Pass, &dbo.Salt)
+	err := row.Scan(&dbo.Id, &dbo.Name, &dbo.NormalizedName, &dbo.Email, &dbo.EmailConfirmed, &dbo.EmailConfirmation, &dbo.Img, &dbo.Tokens, &dbo.Rights, &dbo.Pass, &dbo.Salt, &dbo.Banned, &dbo.BanReason, &dbo.BannedUntil, &dbo.ConfirmationExp, &dbo.ConfirmationSent, &dbo.PendingEmail, &dbo.Reputation, &dbo.Created)
 	if err != nil && err.Error() == "no rows in result set" {
 		return &dbo, sql.ErrNoRows
 	}
@@ -45,7 +45,8 @@ func (repo *usersPlanRepo) Remove(ctx core.ReqContext, userId string, planId int
 func (repo *usersPlanRepo) GetByTopic(ctx core.ReqContext, userId, topicName string) *domain.UsersPlan {
 	tr := ctx.StartTrace("UsersPlanRepository.GetByTopic")
 	defer ctx.StopTrace(tr)
-	query := `SELECT userid, topic, planid FROM usersplans WHERE userid=$1 AND topic=$2`
+	query := `SELECT userid, topic, planid FROM usersplans up WHERE userid=$1 AND topic=$2 
+	AND EXISTS (SELECT id FROM plans WHERE id = up.planid AND deletedat IS NULL)`
 	row := repo.Db.Conn.QueryRow(context.Background(), query, userId, topicName)
 	dbo, err := repo.scanRow(row)
 	if err == sql.ErrNoRows {
@@ -62,7 +63,8 @@ func (repo *usersPlanRepo) GetByUser(ctx core.ReqContext, userId string) []domai
 	tr := ctx.StartTrace("UsersPlanRepository.GetByUser")
 	defer ctx.StopTrace(tr)
 
-	query := `SELECT userid, topic, planid FROM usersplans WHERE userid=$1`
+	query := `SELECT userid, topic, planid FROM usersplans up WHERE userid=$1 
+	AND EXISTS (SELECT id FROM plans WHERE id = up.planid AND deletedat IS NULL)`
 	rows, err := repo.Db.Conn.Query(context.Background(), query, userId)
 	if err == sql.ErrNoRows {
 		return []domain.UsersPlan{}
@@ -83,6 +85,29 @@ func (repo *usersPlanRepo) GetByUser(ctx core.ReqContext, userId string) []domai
 	return usersPlans
 }
 
+func (repo *usersPlanRepo) GetUsers(ctx core.ReqContext, planId int) []string {
+	tr := ctx.StartTrace("UsersPlanRepository.GetUsers")
+	defer ctx.StopTrace(tr)
+
+	query := `SELECT userid FROM usersplans WHERE planid=$1`
+	rows, err := repo.Db.Conn.Query(context.Background(), query, planId)
+	if err != nil {
+		repo.Db.LogError(err, query)
+		return []string{}
+	}
+	defer rows.Close()
+	users := make([]string, 0)
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			repo.Db.LogError(err, query)
+			return []string{}
+		}
+		users = append(users, userId)
+	}
+	return users
+}
+
 func (repo *usersPlanRepo) scanRow(row pgx.Row) (*UsersPlanDBO, error) {
 	dbo := UsersPlanDBO{}
 	err := row.Scan(&dbo.UserId, &dbo.TopicName, &dbo.PlanId)
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/jackc/pgx/v4"
+)
+
+type webhookRepo struct {
+	Db *DbConnection
+}
+
+func NewWebhookRepository(db *DbConnection) core.WebhookRepository {
+	return &webhookRepo{Db: db}
+}
+
+const webhookDeliveryColumns = `d.id, d.webhookid, d.event, d.payload, d.status, d.attempts, d.nextattempt, d.responsecode, d.lasterror, d.created, d.delivered, d.redeliveryof`
+
+func (r *webhookRepo) Add(ctx core.ReqContext, webhook *domain.Webhook) (bool, *core.AppError) {
+	query := `INSERT INTO webhooks (userid, url, secret, events, active, ownchanges, created) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7) 
+	RETURNING id;`
+	tr := ctx.StartTrace("WebhookRepository.Add")
+	defer ctx.StopTrace(tr)
+
+	dbo := &WebhookDBO{}
+	dbo.FromWebhook(webhook)
+	err := r.Db.Conn.QueryRow(context.Background(), query, dbo.UserId, dbo.Url, dbo.Secret, dbo.Events, dbo.Active, dbo.OwnChanges, dbo.Created).Scan(&webhook.Id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return true, nil
+}
+
+func (r *webhookRepo) Update(ctx core.ReqContext, webhook *domain.Webhook) (bool, *core.AppError) {
+	query := `UPDATE webhooks SET url = $2, events = $3, active = $4, ownchanges = $5 WHERE id = $1;`
+	tr := ctx.StartTrace("WebhookRepository.Update")
+	defer ctx.StopTrace(tr)
+
+	dbo := &WebhookDBO{}
+	dbo.FromWebhook(webhook)
+	tag, err := r.Db.Conn.Exec(context.Background(), query, dbo.Id, dbo.Url, dbo.Events, dbo.Active, dbo.OwnChanges)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *webhookRepo) Get(ctx core.ReqContext, id int64) *domain.Webhook {
+	query := `SELECT id, userid, url, secret, events, active, ownchanges, created FROM webhooks WHERE id = $1;`
+	tr := ctx.StartTrace("WebhookRepository.Get")
+	defer ctx.StopTrace(tr)
+
+	dbo := WebhookDBO{}
+	err := r.Db.Conn.QueryRow(context.Background(), query, id).Scan(&dbo.Id, &dbo.UserId, &dbo.Url, &dbo.Secret, &dbo.Events, &dbo.Active, &dbo.OwnChanges, &dbo.Created)
+	if err != nil {
+		if err != pgx.ErrNoRows {
+			r.Db.LogError(err, query)
+		}
+		return nil
+	}
+	return dbo.ToWebhook()
+}
+
+func (r *webhookRepo) GetByUser(ctx core.ReqContext, userId string) []domain.Webhook {
+	query := `SELECT id, userid, url, secret, events, active, ownchanges, created FROM webhooks WHERE userid = $1 ORDER BY id;`
+	tr := ctx.StartTrace("WebhookRepository.GetByUser")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, userId)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.Webhook{}
+	}
+	defer rows.Close()
+
+	webhooks := make([]domain.Webhook, 0)
+	for rows.Next() {
+		dbo := WebhookDBO{}
+		if err := rows.Scan(&dbo.Id, &dbo.UserId, &dbo.Url, &dbo.Secret, &dbo.Events, &dbo.Active, &dbo.OwnChanges, &dbo.Created); err != nil {
+			r.Db.LogError(err, query)
+			return []domain.Webhook{}
+		}
+		webhooks = append(webhooks, *dbo.ToWebhook())
+	}
+	return webhooks
+}
+
+func (r *webhookRepo) Delete(ctx core.ReqContext, id int64) (bool, *core.AppError) {
+	// deliveries are deleted by cascade
+	query := `DELETE FROM webhooks WHERE id = $1;`
+	tr := ctx.StartTrace("WebhookRepository.Delete")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, id)
+	if err != nil {
+		return false, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *webhookRepo) Enqueue(ctx core.ReqContext, event domain.ChangeType, userId string, payload string) (int64, *core.AppError) {
+	query := `INSERT INTO webhook_deliveries (webhookid, event, payload, status, attempts, nextattempt, created) 
+	SELECT id, $1, $2, $3, 0, now(), now() 
+	FROM webhooks 
+	WHERE active AND $1 = ANY(events) AND (NOT ownchanges OR userid = $4);`
+	tr := ctx.StartTrace("WebhookRepository.Enqueue")
+	defer ctx.StopTrace(tr)
+
+	tag, err := r.Db.Conn.Exec(context.Background(), query, int(event), payload, int(domain.WebhookDeliveryPending), userId)
+	if err != nil {
+		return 0, r.Db.LogError(err, query)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *webhookRepo) GetDeliveries(ctx core.ReqContext, webhookId int64, count int, page int) []domain.WebhookDelivery {
+	query := `SELECT ` + webhookDeliveryColumns + ` 
+	FROM webhook_deliveries d 
+	WHERE d.webhookid = $1 
+	ORDER BY d.created DESC, d.id DESC 
+	LIMIT $2 OFFSET $3;`
+	tr := ctx.StartTrace("WebhookRepository.GetDeliveries")
+	defer ctx.StopTrace(tr)
+
+	rows, err := r.Db.Conn.Query(context.Background(), query, webhookId, count, page*count)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.WebhookDelivery{}
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		dbo := WebhookDeliveryDBO{}
+		if err := scanWebhookDelivery(rows, &dbo); err != nil {
+			r.Db.LogError(err, query)
+			return []domain.WebhookDelivery{}
+		}
+		deliveries = append(deliveries, *dbo.ToWebhookDelivery())
+	}
+	return deliveries
+}
+
+func (r *webhookRepo) GetDelivery(ctx core.ReqContext, id int64) *domain.WebhookDelivery {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries d WHERE d.id = $1;`
+	tr := ctx.StartTrace("WebhookRepository.GetDelivery")
+	defer ctx.StopTrace(tr)
+
+	dbo := WebhookDeliveryDBO{}
+	if err := scanWebhookDelivery(r.Db.Conn.QueryRow(context.Background(), query, id), &dbo); err != nil {
+		if err != pgx.ErrNoRows {
+			r.Db.LogError(err, query)
+		}
+		return nil
+	}
+	return dbo.ToWebhookDelivery()
+}
+
+func (r *webhookRepo) Redeliver(ctx core.ReqContext, id int64) (*domain.WebhookDelivery, *core.AppError) {
+	query := `INSERT INTO webhook_deliveries AS d (webhookid, event, payload, status, attempts, nextattempt, created, redeliveryof) 
+	SELECT webhookid, event, payload, $2, 0, now(), now(), id 
+	FROM webhook_deliveries 
+	WHERE id = $1 
+	RETURNING ` + webhookDeliveryColumns + `;`
+	tr := ctx.StartTrace("WebhookRepository.Redeliver")
+	defer ctx.StopTrace(tr)
+
+	dbo := WebhookDeliveryDBO{}
+	if err := scanWebhookDelivery(r.Db.Conn.QueryRow(context.Background(), query, id, int(domain.WebhookDeliveryPending)), &dbo); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.NewError(core.NotExists)
+		}
+		return nil, r.Db.LogError(err, query)
+	}
+	return dbo.ToWebhookDelivery(), nil
+}
+
+func (r *webhookRepo) Take(count int, lease time.Duration) []domain.WebhookDelivery {
+	// deliveries of disabled webhooks wait until webhook is enabled again
+	query := `UPDATE webhook_deliveries d SET nextattempt = $2 
+FROM webhooks w 
+WHERE w.id = d.webhookid AND d.id IN (
+	SELECT pd.id FROM webhook_deliveries pd 
+	JOIN webhooks pw ON pw.id = pd.webhookid 
+	WHERE pd.status = $3 AND pd.nextattempt <= now() AND pw.active 
+	ORDER BY pd.nextattempt 
+	LIMIT $1 
+	FOR UPDATE OF pd SKIP LOCKED)
+RETURNING ` + webhookDeliveryColumns + `, w.url, w.secret;`
+	rows, err := r.Db.Conn.Query(context.Background(), query, count, time.Now().Add(lease), int(domain.WebhookDeliveryPending))
+	if err != nil {
+		r.Db.LogError(err, query)
+		return []domain.WebhookDelivery{}
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		dbo := WebhookDeliveryDBO{}
+		err := rows.Scan(&dbo.Id, &dbo.WebhookId, &dbo.Event, &dbo.Payload, &dbo.Status, &dbo.Attempts, &dbo.NextAttempt, &dbo.ResponseCode, &dbo.LastError, &dbo.Created, &dbo.Delivered, &dbo.RedeliveryOf, &dbo.Url, &dbo.Secret)
+		if err != nil {
+			r.Db.LogError(err, query)
+			return []domain.WebhookDelivery{}
+		}
+		deliveries = append(deliveries, *dbo.ToWebhookDelivery())
+	}
+	return deliveries
+}
+
+func (r *webhookRepo) MarkDelivered(id int64, responseCode int) bool {
+	query := `UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, responsecode = $3, delivered = now(), lasterror = NULL WHERE id = $1;`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, id, int(domain.WebhookDeliveryDelivered), responseCode)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return false
+	}
+	return tag.RowsAffected() > 0
+}
+
+func (r *webhookRepo) MarkFailed(id int64, responseCode int, lastError string, nextAttempt time.Time, dead bool) bool {
+	status := domain.WebhookDeliveryPending
+	if dead {
+		status = domain.WebhookDeliveryDead
+	}
+	query := `UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, nextattempt = $3, responsecode = $4, lasterror = $5 WHERE id = $1;`
+	tag, err := r.Db.Conn.Exec(context.Background(), query, id, int(status), nextAttempt, ToNullInt64(int64(responseCode)), lastError)
+	if err != nil {
+		r.Db.LogError(err, query)
+		return false
+	}
+	return tag.RowsAffected() > 0
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhookDelivery(row rowScanner, dbo *WebhookDeliveryDBO) error {
+	return row.Scan(&dbo.Id, &dbo.WebhookId, &dbo.Event, &dbo.Payload, &dbo.Status, &dbo.Attempts, &dbo.NextAttempt, &dbo.ResponseCode, &dbo.LastError, &dbo.Created, &dbo.Delivered, &dbo.RedeliveryOf)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/NeekUP/roadmaps/core"
 	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
 	"os"
 	"strings"
 )
@@ -13,16 +14,18 @@ type DbSeed interface {
 	Seed()
 }
 
-func NewDbSeed(regUser usecases.RegisterUser, userRepo core.UserRepository) DbSeed {
+func NewDbSeed(regUser usecases.RegisterUser, userRepo core.UserRepository, roleRepo core.RoleRepository) DbSeed {
 	return &dbSeedProd{
 		RegUser:  regUser,
 		UserRepo: userRepo,
+		RoleRepo: roleRepo,
 	}
 }
 
 type dbSeedProd struct {
 	RegUser  usecases.RegisterUser
 	UserRepo core.UserRepository
+	RoleRepo core.RoleRepository
 }
 
 func (seed *dbSeedProd) Seed() {
@@ -43,8 +46,21 @@ func (seed *dbSeedProd) seedUsers() {
 
 			exists, ok := seed.UserRepo.ExistsEmail(ctx, email)
 			if !exists && ok && name != "" && email != "" && pass != "" {
-				seed.RegUser.Do(NewContext(context.Background()), name, email, pass)
+				user, err := seed.RegUser.Do(NewContext(context.Background()), name, email, pass)
+				if err == nil {
+					// admins are not restricted as users with unconfirmed email
+					user.ConfirmEmail()
+					seed.UserRepo.Update(ctx, user)
+					seed.grantAdmin(ctx, user.Id)
+				}
 			}
 		}
 	}
 }
+
+func (seed *dbSeedProd) grantAdmin(ctx core.ReqContext, userId string) {
+	role := seed.RoleRepo.GetByName(ctx, domain.AdminRole)
+	if role != nil {
+		seed.RoleRepo.AddToUser(ctx, userId, role.Id)
+	}
+}
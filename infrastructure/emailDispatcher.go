@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// EmailDispatcher sends emails from outbox in background.
+// Failed email is retried with growing delay and marked as dead after MaxAttempts.
+type EmailDispatcher struct {
+	outbox      core.EmailOutbox
+	templates   *EmailTemplates
+	transport   MailTransport
+	siteHost    string
+	fromEmail   string
+	fromName    string
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	log         core.AppLogger
+}
+
+type EmailDispatcherConf struct {
+	SiteHost    string
+	FromEmail   string
+	FromName    string
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+}
+
+// time to send one batch, after it emails become available for other senders again
+const emailLease = 5 * time.Minute
+
+func NewEmailDispatcher(outbox core.EmailOutbox, templates *EmailTemplates, transport MailTransport, conf EmailDispatcherConf, log core.AppLogger) *EmailDispatcher {
+	return &EmailDispatcher{
+		outbox:      outbox,
+		templates:   templates,
+		transport:   transport,
+		siteHost:    conf.SiteHost,
+		fromEmail:   conf.FromEmail,
+		fromName:    conf.FromName,
+		interval:    conf.Interval,
+		batchSize:   conf.BatchSize,
+		maxAttempts: conf.MaxAttempts,
+		log:         log,
+	}
+}
+
+func (d *EmailDispatcher) Start() {
+	if d.interval <= 0 || d.batchSize <= 0 {
+		d.log.Infow("Email dispatcher disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			// send next batch immediately if this one was full
+			if d.Run() < d.batchSize {
+				<-ticker.C
+			}
+		}
+	}()
+}
+
+// Run sends one batch of emails and returns count of processed emails
+func (d *EmailDispatcher) Run() int {
+	emails := d.outbox.Take(d.batchSize, emailLease)
+	for i := 0; i < len(emails); i++ {
+		d.send(&emails[i])
+	}
+	return len(emails)
+}
+
+func (d *EmailDispatcher) send(email *domain.Email) {
+	err := d.deliver(email)
+	if err == nil {
+		d.outbox.MarkSent(email.Id)
+		d.log.Infow("Send email", "id", email.Id, "to", email.Recipient, "type", email.Template, "status", true)
+		return
+	}
+
+	attempts := email.Attempts + 1
+	dead := attempts >= d.maxAttempts
+	d.outbox.MarkFailed(email.Id, err.Error(), time.Now().Add(retryDelay(attempts)), dead)
+	d.log.Errorw("Send email",
+		"id", email.Id,
+		"to", email.Recipient,
+		"type", email.Template,
+		"status", false,
+		"attempts", attempts,
+		"dead", dead,
+		"err", err.Error(),
+	)
+}
+
+func (d *EmailDispatcher) deliver(email *domain.Email) error {
+	data := make(map[string]string, len(email.Data)+3)
+	for k, v := range email.Data {
+		data[k] = v
+	}
+	data["Host"] = d.siteHost
+	data["UserId"] = email.UserId
+	data["Recipient"] = email.Recipient
+
+	rendered, err := d.templates.Render(email.Template, email.Lang, data)
+	if err != nil {
+		return err
+	}
+
+	msg, err := BuildMessage(d.fromName, d.fromEmail, email.Recipient, rendered)
+	if err != nil {
+		return err
+	}
+	return d.transport.Send(d.fromEmail, email.Recipient, msg)
+}
+
+// retryDelay grows quadratically: 1, 4, 9, 16... minutes, but not more than a day
+func retryDelay(attempts int) time.Duration {
+	delay := time.Duration(attempts*attempts) * time.Minute
+	if delay > 24*time.Hour {
+		delay = 24 * time.Hour
+	}
+	return delay
+}
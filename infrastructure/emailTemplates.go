@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+)
+
+// EmailTemplates keeps parsed email templates in memory.
+// Templates are stored in folder per language: {dir}/{lang}/{name}.tpl
+// Every file is json with subject, html and text templates.
+type EmailTemplates struct {
+	defaultLang string
+	templates   map[string]*emailTemplate
+}
+
+type emailTemplate struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+type emailTemplateSource struct {
+	Subject string `json:"subject"`
+	Html    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+type RenderedEmail struct {
+	Subject string
+	Html    string
+	Text    string
+}
+
+func NewEmailTemplates(dir, defaultLang string) (*EmailTemplates, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.tpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := &EmailTemplates{
+		defaultLang: defaultLang,
+		templates:   make(map[string]*emailTemplate, len(files)),
+	}
+
+	for _, file := range files {
+		lang := filepath.Base(filepath.Dir(file))
+		name := strings.TrimSuffix(filepath.Base(file), ".tpl")
+		tpl, err := loadEmailTemplate(file)
+		if err != nil {
+			return nil, fmt.Errorf("email template %s: %v", file, err)
+		}
+		templates.templates[templateKey(name, lang)] = tpl
+	}
+
+	return templates, nil
+}
+
+func loadEmailTemplate(file string) (*emailTemplate, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &emailTemplateSource{}
+	if err := json.Unmarshal(b, src); err != nil {
+		return nil, err
+	}
+	if src.Subject == "" || src.Html == "" || src.Text == "" {
+		return nil, fmt.Errorf("subject, html and text are required")
+	}
+
+	tpl := &emailTemplate{}
+	if tpl.subject, err = texttemplate.New("subject").Parse(src.Subject); err != nil {
+		return nil, err
+	}
+	if tpl.html, err = htmltemplate.New("html").Parse(src.Html); err != nil {
+		return nil, err
+	}
+	if tpl.text, err = texttemplate.New("text").Parse(src.Text); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+func templateKey(name, lang string) string {
+	return lang + "/" + name
+}
+
+// Render executes template in the language or in default language if template is not translated
+func (t *EmailTemplates) Render(name, lang string, data map[string]string) (*RenderedEmail, error) {
+	tpl, ok := t.templates[templateKey(name, lang)]
+	if !ok {
+		tpl, ok = t.templates[templateKey(name, t.defaultLang)]
+	}
+	if !ok {
+		return nil, fmt.Errorf("email template %s not found", name)
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := tpl.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := tpl.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := tpl.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	return &RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Html:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
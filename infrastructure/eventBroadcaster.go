@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// EventBroadcaster publishes events to channels of entity and comment thread
+type EventBroadcaster struct {
+	bus          core.EventBus
+	pointsRepo   core.PointsRepository
+	commentsRepo core.CommentsRepository
+	log          core.AppLogger
+}
+
+func NewEventBroadcaster(bus core.EventBus, pointsRepo core.PointsRepository, commentsRepo core.CommentsRepository, log core.AppLogger) core.Broadcaster {
+	return &EventBroadcaster{bus: bus, pointsRepo: pointsRepo, commentsRepo: commentsRepo, log: log}
+}
+
+func (b *EventBroadcaster) CommentAdded(ctx core.ReqContext, comment *domain.Comment) {
+	b.publishComment(domain.CommentAddedEvent, comment)
+}
+
+func (b *EventBroadcaster) CommentEdited(ctx core.ReqContext, comment *domain.Comment) {
+	b.publishComment(domain.CommentEditedEvent, comment)
+}
+
+func (b *EventBroadcaster) CommentDeleted(ctx core.ReqContext, comment *domain.Comment) {
+	deleted := *comment
+	deleted.Deleted = true
+	deleted.Text = ""
+	deleted.Html = ""
+	deleted.Title = ""
+	b.publishComment(domain.CommentDeletedEvent, &deleted)
+}
+
+func (b *EventBroadcaster) ReactionsChanged(ctx core.ReqContext, comment *domain.Comment) {
+	// aggregate only, reactions of current user are not broadcast
+	reactions := b.commentsRepo.GetReactions(ctx, "", []int64{comment.Id})[comment.Id]
+	b.bus.Publish(&domain.Event{
+		Type:       domain.ReactionsChangedEvent,
+		Channels:   commentChannels(comment),
+		EntityType: domain.CommentEntity,
+		EntityId:   comment.Id,
+		Reactions:  reactions,
+	})
+}
+
+func (b *EventBroadcaster) PointsChanged(ctx core.ReqContext, entityType domain.EntityType, entityId int64) {
+	// aggregate only, vote of current user is not broadcast
+	points := b.pointsRepo.Get(ctx, "", entityType, entityId)
+	if points == nil {
+		return
+	}
+
+	channels := []string{domain.EntityChannel(entityType, entityId)}
+	if entityType == domain.CommentEntity {
+		if comment := b.commentsRepo.Get(ctx, entityId); comment != nil {
+			channels = append(channels, commentChannels(comment)...)
+		}
+	}
+
+	b.bus.Publish(&domain.Event{
+		Type:       domain.PointsChangedEvent,
+		Channels:   channels,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Points:     points,
+	})
+}
+
+func (b *EventBroadcaster) publishComment(eventType domain.EventType, comment *domain.Comment) {
+	// user, votes and replies are loaded by client if needed
+	c := *comment
+	c.User = nil
+	c.Points = nil
+	c.Reactions = nil
+	c.Childs = nil
+
+	b.bus.Publish(&domain.Event{
+		Type:       eventType,
+		Channels:   commentChannels(&c),
+		EntityType: domain.CommentEntity,
+		EntityId:   c.Id,
+		Comment:    &c,
+	})
+}
+
+func commentChannels(comment *domain.Comment) []string {
+	channels := []string{domain.EntityChannel(comment.EntityType, comment.EntityId)}
+	if comment.ThreadId > 0 {
+		channels = append(channels, domain.ThreadChannel(comment.ThreadId))
+	} else {
+		channels = append(channels, domain.ThreadChannel(comment.Id))
+	}
+	return channels
+}
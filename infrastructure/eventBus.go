@@ -0,0 +1,116 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// events are dropped for subscribers which don't read them fast enough
+const subscriptionBufferSize = 32
+
+type subscription struct {
+	events chan domain.Event
+}
+
+// EventBus delivers events to subscribers in memory.
+// When backend is set events go through it, so subscribers of all instances get them.
+type EventBus struct {
+	backend       core.EventBackend
+	mu            sync.RWMutex
+	subscriptions map[string]map[*subscription]bool
+	log           core.AppLogger
+}
+
+func NewEventBus(backend core.EventBackend, log core.AppLogger) core.EventBus {
+	bus := &EventBus{
+		backend:       backend,
+		subscriptions: make(map[string]map[*subscription]bool),
+		log:           log,
+	}
+	if backend != nil {
+		backend.Listen(bus.receive)
+	}
+	return bus
+}
+
+func (bus *EventBus) Publish(event *domain.Event) {
+	if bus.backend == nil {
+		bus.deliver(event)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		bus.log.Errorw("Fail to serialize event", "type", event.Type, "error", err.Error())
+		return
+	}
+
+	if err := bus.backend.Publish(payload); err != nil {
+		// subscribers of this instance should get event anyway
+		bus.log.Errorw("Event not published", "type", event.Type, "error", err.Error())
+		bus.deliver(event)
+	}
+}
+
+func (bus *EventBus) Subscribe(channels []string) (<-chan domain.Event, func()) {
+	sub := &subscription{events: make(chan domain.Event, subscriptionBufferSize)}
+
+	bus.mu.Lock()
+	for _, ch := range channels {
+		if bus.subscriptions[ch] == nil {
+			bus.subscriptions[ch] = make(map[*subscription]bool)
+		}
+		bus.subscriptions[ch][sub] = true
+	}
+	bus.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			bus.mu.Lock()
+			defer bus.mu.Unlock()
+			for _, ch := range channels {
+				delete(bus.subscriptions[ch], sub)
+				if len(bus.subscriptions[ch]) == 0 {
+					delete(bus.subscriptions, ch)
+				}
+			}
+			close(sub.events)
+		})
+	}
+	return sub.events, cancel
+}
+
+func (bus *EventBus) receive(payload []byte) {
+	event := &domain.Event{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		bus.log.Errorw("Fail to deserialize event", "error", err.Error())
+		return
+	}
+	bus.deliver(event)
+}
+
+// deliver sends event once to every subscriber of any event channel
+func (bus *EventBus) deliver(event *domain.Event) {
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	delivered := make(map[*subscription]bool)
+	for _, ch := range event.Channels {
+		for sub := range bus.subscriptions[ch] {
+			if delivered[sub] {
+				continue
+			}
+			delivered[sub] = true
+
+			select {
+			case sub.events <- *event:
+			default:
+				bus.log.Infow("Event dropped for slow subscriber", "type", event.Type, "channel", ch)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// MailTransport delivers ready to send message
+type MailTransport interface {
+	Send(from string, to string, msg []byte) error
+}
+
+type smtpTransport struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSmtpTransport creates transport without authentication if password is empty
+func NewSmtpTransport(host string, port int, user, pass string) MailTransport {
+	transport := &smtpTransport{addr: fmt.Sprintf("%s:%d", host, port)}
+	if pass != "" {
+		transport.auth = smtp.PlainAuth("", user, pass, host)
+	}
+	return transport
+}
+
+func (t *smtpTransport) Send(from string, to string, msg []byte) error {
+	return smtp.SendMail(t.addr, t.auth, from, []string{to}, msg)
+}
+
+// BuildMessage creates multipart/alternative message with plain text and html bodies
+func BuildMessage(fromName, fromEmail, to string, email *RenderedEmail) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=\"UTF-8\"", email.Text},
+		{"text/html; charset=\"UTF-8\"", email.Html},
+	}
+	for _, p := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", p.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		pw, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s <%s>\r\n", mime.QEncoding.Encode("UTF-8", fromName), fromEmail)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", email.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n", writer.Boundary())
+	fmt.Fprintf(&msg, "\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
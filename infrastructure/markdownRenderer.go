@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"bytes"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday/v2"
+)
+
+const markdownExtensions = blackfriday.NoIntraEmphasis |
+	blackfriday.Tables |
+	blackfriday.FencedCode |
+	blackfriday.Autolink |
+	blackfriday.Strikethrough |
+	blackfriday.SpaceHeadings |
+	blackfriday.BackslashLineBreak
+
+// mentions more than this are rendered as text
+const maxMentions = 20
+
+// @name at the start of text or after a character which could not be a part of name or email
+var mentionPattern = regexp.MustCompile(`(^|[^a-zA-Z0-9_@.-])@([a-zA-Z0-9_-]{2,32})`)
+
+// MarkdownRenderer renders markdown of comments, step titles and topic descriptions.
+// Html in markdown source is not trusted, so result is sanitized after rendering.
+// MentionUrl is a link to the user profile, {id} and {name} are replaced with id and name of the user.
+type MarkdownRenderer struct {
+	userRepo   core.UserRepository
+	mentionUrl string
+	policy     *bluemonday.Policy
+	log        core.AppLogger
+}
+
+func NewMarkdownRenderer(userRepo core.UserRepository, mentionUrl string, log core.AppLogger) core.MarkdownRenderer {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile("^language-[a-zA-Z0-9]+$")).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile("^mention$")).OnElements("a")
+	return &MarkdownRenderer{userRepo: userRepo, mentionUrl: mentionUrl, policy: policy, log: log}
+}
+
+func (r *MarkdownRenderer) Render(ctx core.ReqContext, source string) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+	return r.policy.Sanitize(r.render(ctx, source))
+}
+
+func (r *MarkdownRenderer) RenderInline(ctx core.ReqContext, source string) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+
+	rendered := strings.TrimSpace(r.render(ctx, source))
+	if strings.HasPrefix(rendered, "<p>") && strings.HasSuffix(rendered, "</p>") && strings.Count(rendered, "<p>") == 1 {
+		rendered = strings.TrimSuffix(strings.TrimPrefix(rendered, "<p>"), "</p>")
+	}
+	return r.policy.Sanitize(rendered)
+}
+
+func (r *MarkdownRenderer) render(ctx core.ReqContext, source string) string {
+	parser := blackfriday.New(blackfriday.WithExtensions(markdownExtensions))
+	ast := parser.Parse([]byte(source))
+
+	renderer := &mentionRenderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+			Flags: blackfriday.Safelink | blackfriday.NofollowLinks | blackfriday.NoopenerLinks | blackfriday.HrefTargetBlank,
+		}),
+		users:      r.resolveMentions(ctx, ast),
+		mentionUrl: r.mentionUrl,
+	}
+
+	var buf bytes.Buffer
+	renderer.RenderHeader(&buf, ast)
+	ast.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		return renderer.RenderNode(&buf, node, entering)
+	})
+	renderer.RenderFooter(&buf, ast)
+	return buf.String()
+}
+
+// resolveMentions returns mentioned users by upper case name
+func (r *MarkdownRenderer) resolveMentions(ctx core.ReqContext, ast *blackfriday.Node) map[string]*domain.User {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	ast.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || node.Type != blackfriday.Text || insideLink(node) {
+			return blackfriday.GoToNext
+		}
+		for _, m := range mentionPattern.FindAllStringSubmatch(string(node.Literal), -1) {
+			name := strings.ToUpper(m[2])
+			if !seen[name] && len(names) < maxMentions {
+				seen[name] = true
+				names = append(names, m[2])
+			}
+		}
+		return blackfriday.GoToNext
+	})
+
+	users := make(map[string]*domain.User, len(names))
+	if len(names) == 0 {
+		return users
+	}
+
+	found := r.userRepo.FindByNames(ctx, names)
+	for i := range found {
+		users[strings.ToUpper(found[i].Name)] = &found[i]
+	}
+	return users
+}
+
+// mentionRenderer renders mentions of existing users as links, other nodes are rendered by HTMLRenderer
+type mentionRenderer struct {
+	*blackfriday.HTMLRenderer
+	users      map[string]*domain.User
+	mentionUrl string
+}
+
+func (r *mentionRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	if node.Type != blackfriday.Text || len(r.users) == 0 || insideLink(node) {
+		return r.HTMLRenderer.RenderNode(w, node, entering)
+	}
+
+	text := string(node.Literal)
+	last := 0
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		// m[4]:m[5] is a name, @ is right before it
+		user, ok := r.users[strings.ToUpper(text[m[4]:m[5]])]
+		if !ok {
+			continue
+		}
+		io.WriteString(w, html.EscapeString(text[last:m[4]-1]))
+		io.WriteString(w, `<a href="`+html.EscapeString(r.userUrl(user))+`" class="mention">@`+html.EscapeString(user.Name)+`</a>`)
+		last = m[5]
+	}
+	io.WriteString(w, html.EscapeString(text[last:]))
+	return blackfriday.GoToNext
+}
+
+func (r *mentionRenderer) userUrl(user *domain.User) string {
+	return strings.NewReplacer("{id}", url.PathEscape(user.Id), "{name}", url.PathEscape(user.Name)).Replace(r.mentionUrl)
+}
+
+func insideLink(node *blackfriday.Node) bool {
+	for p := node.Parent; p != nil; p = p.Parent {
+		if p.Type == blackfriday.Link || p.Type == blackfriday.Image {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+const notificationTitleLength = 100
+
+// plainTextPolicy removes all markup of rendered comments, titles of notifications are plain text
+var plainTextPolicy = bluemonday.StrictPolicy()
+
+// NotificationCenter finds users interested in the event and saves notifications for them.
+// User never gets notification about own action.
+type NotificationCenter struct {
+	notificationRepo core.NotificationRepository
+	commentsRepo     core.CommentsRepository
+	planRepo         core.PlanRepository
+	usersPlanRepo    core.UsersPlanRepository
+	log              core.AppLogger
+}
+
+func NewNotificationCenter(notificationRepo core.NotificationRepository, commentsRepo core.CommentsRepository, planRepo core.PlanRepository, usersPlanRepo core.UsersPlanRepository, log core.AppLogger) core.Notifier {
+	return &NotificationCenter{
+		notificationRepo: notificationRepo,
+		commentsRepo:     commentsRepo,
+		planRepo:         planRepo,
+		usersPlanRepo:    usersPlanRepo,
+		log:              log,
+	}
+}
+
+func (center *NotificationCenter) CommentAdded(ctx core.ReqContext, comment *domain.Comment) {
+	var plan *domain.Plan
+	title := truncate(plainText(comment.Html), notificationTitleLength)
+	if comment.EntityType == domain.PlanEntity {
+		if plan = center.planRepo.Get(ctx, int(comment.EntityId)); plan != nil {
+			title = plan.Title
+		}
+	}
+
+	notifications := make([]domain.Notification, 0, 2)
+	replyTo := ""
+	if comment.ParentId > 0 {
+		if parent := center.commentsRepo.Get(ctx, comment.ParentId); parent != nil && !parent.Deleted {
+			replyTo = parent.UserId
+			notifications = center.append(ctx, notifications, parent.UserId, domain.CommentReplyNotification, comment.EntityType, comment.EntityId, title)
+		}
+	}
+
+	// author of parent comment gets reply notification only
+	if plan != nil && plan.OwnerId != replyTo {
+		notifications = center.append(ctx, notifications, plan.OwnerId, domain.PlanCommentNotification, comment.EntityType, comment.EntityId, title)
+	}
+
+	center.save(ctx, notifications)
+}
+
+func (center *NotificationCenter) VoteAdded(ctx core.ReqContext, entityType domain.EntityType, entityId int64, value int) {
+	if entityType != domain.PlanEntity {
+		return
+	}
+
+	plan := center.planRepo.Get(ctx, int(entityId))
+	if plan == nil {
+		return
+	}
+	center.save(ctx, center.append(ctx, nil, plan.OwnerId, domain.PlanVoteNotification, entityType, entityId, plan.Title))
+}
+
+func (center *NotificationCenter) PlanChanged(ctx core.ReqContext, planId int) {
+	plan := center.planRepo.Get(ctx, planId)
+	if plan == nil {
+		return
+	}
+
+	var notifications []domain.Notification
+	for _, userId := range center.usersPlanRepo.GetUsers(ctx, planId) {
+		notifications = center.append(ctx, notifications, userId, domain.FavoritePlanChangedNotification, domain.PlanEntity, int64(planId), plan.Title)
+	}
+	center.save(ctx, notifications)
+}
+
+func (center *NotificationCenter) append(ctx core.ReqContext, notifications []domain.Notification, userId string, notificationType domain.NotificationType, entityType domain.EntityType, entityId int64, title string) []domain.Notification {
+	if userId == "" || userId == ctx.UserId() {
+		return notifications
+	}
+
+	return append(notifications, domain.Notification{
+		UserId:     userId,
+		Type:       notificationType,
+		ActorId:    ctx.UserId(),
+		EntityType: entityType,
+		EntityId:   entityId,
+		Title:      title,
+		Created:    time.Now().UTC(),
+	})
+}
+
+func (center *NotificationCenter) save(ctx core.ReqContext, notifications []domain.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+
+	if _, err := center.notificationRepo.Add(ctx, notifications); err != nil {
+		center.log.Errorw("Notifications not saved",
+			"reqid", ctx.ReqId(),
+			"type", notifications[0].Type,
+			"count", len(notifications),
+			"error", err.Error(),
+		)
+	}
+}
+
+func plainText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(plainTextPolicy.Sanitize(s))), " ")
+}
+
+func truncate(s string, length int) string {
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+	return string([]rune(s)[:length]) + "…"
+}
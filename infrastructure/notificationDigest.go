@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// NotificationDigest periodically emails unread notifications to users who enabled it in preferences.
+// Every notification is emailed once, users with unconfirmed email get nothing.
+type NotificationDigest struct {
+	notificationRepo core.NotificationRepository
+	userRepo         core.UserRepository
+	interval         time.Duration
+	batchSize        int
+	log              core.AppLogger
+}
+
+func NewNotificationDigest(notificationRepo core.NotificationRepository, userRepo core.UserRepository, interval time.Duration, batchSize int, log core.AppLogger) *NotificationDigest {
+	return &NotificationDigest{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		interval:         interval,
+		batchSize:        batchSize,
+		log:              log,
+	}
+}
+
+// Start runs digest in background every interval. Zero interval disables digest.
+func (digest *NotificationDigest) Start() {
+	if digest.interval <= 0 || digest.batchSize <= 0 {
+		digest.log.Infow("Notification digest disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(digest.interval)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			digest.Run()
+		}
+	}()
+}
+
+// Run puts digest emails into outbox and returns count of them
+func (digest *NotificationDigest) Run() int {
+	notifications := digest.notificationRepo.GetForDigest(digest.batchSize)
+	if len(notifications) == 0 {
+		return 0
+	}
+
+	byUser := make(map[string][]domain.Notification)
+	userIds := make([]string, 0)
+	for _, n := range notifications {
+		if _, ok := byUser[n.UserId]; !ok {
+			userIds = append(userIds, n.UserId)
+		}
+		byUser[n.UserId] = append(byUser[n.UserId], n)
+	}
+
+	users := make(map[string]*domain.User, len(userIds))
+	list := digest.userRepo.GetList(NewContext(context.Background()), userIds)
+	for i := range list {
+		users[list[i].Id] = &list[i]
+	}
+
+	sent := 0
+	for _, userId := range userIds {
+		var email *domain.Email
+		if user, ok := users[userId]; ok && user.EmailConfirmed && !user.IsBanned() {
+			email = domain.NewEmail(user.Email, domain.DigestEmail, "", digestData(byUser[userId]))
+		}
+
+		ids := make([]int64, len(byUser[userId]))
+		for i, n := range byUser[userId] {
+			ids[i] = n.Id
+		}
+		if _, err := digest.notificationRepo.SaveDigest(email, ids); err != nil {
+			digest.log.Errorw("Digest not saved", "userId", userId, "error", err.Error())
+			continue
+		}
+		if email != nil {
+			sent++
+		}
+	}
+
+	digest.log.Infow("Notification digests created", "notifications", len(notifications), "emails", sent)
+	return sent
+}
+
+// digestData counts notifications by types, texts of digest are in template
+func digestData(notifications []domain.Notification) map[string]string {
+	counts := make(map[domain.NotificationType]int)
+	titles := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, n := range notifications {
+		counts[n.Type]++
+		if n.Title != "" && !seen[n.Title] {
+			seen[n.Title] = true
+			titles = append(titles, n.Title)
+		}
+	}
+
+	return map[string]string{
+		"Count":        strconv.Itoa(len(notifications)),
+		"Replies":      strconv.Itoa(counts[domain.CommentReplyNotification]),
+		"Comments":     strconv.Itoa(counts[domain.PlanCommentNotification]),
+		"Votes":        strconv.Itoa(counts[domain.PlanVoteNotification]),
+		"ChangedPlans": strconv.Itoa(counts[domain.FavoritePlanChangedNotification]),
+		"Titles":       strings.Join(titles, ", "),
+	}
+}
@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// PlanDiffer compares plans the same way as change log does.
+// Only content is compared, so fork differs from its upstream by edits of its owner only
+type PlanDiffer struct{}
+
+func NewPlanDiffer() core.PlanDiffer {
+	return &PlanDiffer{}
+}
+
+func (differ *PlanDiffer) Diff(before *domain.Plan, after *domain.Plan) (string, error) {
+	difference, err := diffPlans(planContent(before), planContent(after))
+	if err != nil {
+		return "", err
+	}
+	return string(difference), nil
+}
+
+// planContent returns copy of plan without ids, owner and moderation state
+func planContent(plan *domain.Plan) *domain.Plan {
+	steps := make([]domain.Step, len(plan.Steps))
+	for i, step := range plan.Steps {
+		steps[i] = domain.Step{
+			ReferenceId:   step.ReferenceId,
+			ReferenceType: step.ReferenceType,
+			Position:      step.Position,
+			Title:         step.Title,
+			Duration:      step.Duration,
+			Difficulty:    step.Difficulty,
+			Optional:      step.Optional,
+			Notes:         step.Notes,
+		}
+	}
+	return &domain.Plan{
+		Title:     plan.Title,
+		TopicName: plan.TopicName,
+		Steps:     steps,
+	}
+}
@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+)
+
+// PurgeJob removes soft deleted plans, topics and sources from db when retention period is over.
+// Plans are purged first because topics and sources can't be removed while plans use them.
+type PurgeJob struct {
+	planRepo   core.PlanRepository
+	topicRepo  core.TopicRepository
+	sourceRepo core.SourceRepository
+	retention  time.Duration
+	interval   time.Duration
+	log        core.AppLogger
+}
+
+func NewPurgeJob(planRepo core.PlanRepository, topicRepo core.TopicRepository, sourceRepo core.SourceRepository, retention time.Duration, interval time.Duration, log core.AppLogger) *PurgeJob {
+	return &PurgeJob{
+		planRepo:   planRepo,
+		topicRepo:  topicRepo,
+		sourceRepo: sourceRepo,
+		retention:  retention,
+		interval:   interval,
+		log:        log,
+	}
+}
+
+// Start runs purge in background every interval. Zero retention or interval disables the job.
+func (job *PurgeJob) Start() {
+	if job.retention <= 0 || job.interval <= 0 {
+		job.log.Infow("Purge job disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(job.interval)
+		defer ticker.Stop()
+		for {
+			job.Run()
+			<-ticker.C
+		}
+	}()
+}
+
+func (job *PurgeJob) Run() {
+	before := time.Now().Add(-job.retention)
+
+	plans, err := job.planRepo.Purge(before)
+	if err != nil {
+		job.log.Errorw("Plans not purged", "error", err.Error())
+		return
+	}
+
+	topics, err := job.topicRepo.Purge(before)
+	if err != nil {
+		job.log.Errorw("Topics not purged", "error", err.Error())
+	}
+
+	sources, err := job.sourceRepo.Purge(before)
+	if err != nil {
+		job.log.Errorw("Sources not purged", "error", err.Error())
+	}
+
+	job.log.Infow("Deleted entities purged",
+		"before", before,
+		"plans", plans,
+		"topics", topics,
+		"sources", sources,
+	)
+}
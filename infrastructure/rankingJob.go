@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// RankingJob recomputes scores of plans by every ranker from all votes.
+// Plans are listed in order of stored scores, so changes of votes affect lists after next run.
+// Plans without votes get score of empty list of votes, so they are ranked above plans with bad votes.
+type RankingJob struct {
+	pointsRepo core.PointsRepository
+	planRepo   core.PlanRepository
+	rankers    []core.Ranker
+	interval   time.Duration
+	log        core.AppLogger
+}
+
+func NewRankingJob(pointsRepo core.PointsRepository, planRepo core.PlanRepository, rankers []core.Ranker, interval time.Duration, log core.AppLogger) *RankingJob {
+	return &RankingJob{
+		pointsRepo: pointsRepo,
+		planRepo:   planRepo,
+		rankers:    rankers,
+		interval:   interval,
+		log:        log,
+	}
+}
+
+// Start runs ranking in background every interval. Zero interval disables the job.
+func (job *RankingJob) Start() {
+	if job.interval <= 0 {
+		job.log.Infow("Ranking job disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(job.interval)
+		defer ticker.Stop()
+		for {
+			job.Run(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+func (job *RankingJob) Run(now time.Time) {
+	votes, err := job.pointsRepo.GetVotes(domain.PlanEntity)
+	if err != nil {
+		job.log.Errorw("Votes not loaded", "error", err.Error())
+		return
+	}
+
+	listed, err := job.planRepo.GetListedIds()
+	if err != nil {
+		job.log.Errorw("Plans not loaded", "error", err.Error())
+		return
+	}
+
+	for _, ranker := range job.rankers {
+		scores := make(map[int64]float64, len(listed))
+		neutral := ranker.Score(nil, now)
+		for _, id := range listed {
+			scores[id] = neutral
+		}
+		for _, v := range votes {
+			scores[v.EntityId] = ranker.Score(v.Votes, now)
+		}
+
+		if err := job.planRepo.SaveScores(ranker.Sort(), scores); err != nil {
+			job.log.Errorw("Scores not saved",
+				"sort", ranker.Sort(),
+				"error", err.Error(),
+			)
+			continue
+		}
+	}
+
+	job.log.Infow("Plans ranked",
+		"plans", len(listed),
+		"voted", len(votes),
+		"rankers", len(job.rankers),
+	)
+}
@@ -0,0 +1,110 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// ReputationConf sets points awarded for contributions.
+// Vote with value not less than UpvoteValue is an upvote, other votes are not awarded.
+// Only SourcesPerDay added sources are awarded a day, zero means no limit
+type ReputationConf struct {
+	UpvoteValue   int
+	PlanUpvote    int
+	CommentUpvote int
+	SourceAdded   int
+	SourcesPerDay int
+}
+
+// ReputationEngine records awarded points in change log and adds them to reputation of user.
+// Votes for own plans and comments are not awarded
+type ReputationEngine struct {
+	changeLogRepo core.ChangeLogRepository
+	planRepo      core.PlanRepository
+	commentsRepo  core.CommentsRepository
+	policy        core.AccessPolicy
+	conf          ReputationConf
+	log           core.AppLogger
+}
+
+func NewReputationEngine(changeLogRepo core.ChangeLogRepository, planRepo core.PlanRepository, commentsRepo core.CommentsRepository, policy core.AccessPolicy, conf ReputationConf, log core.AppLogger) core.Reputation {
+	return &ReputationEngine{
+		changeLogRepo: changeLogRepo,
+		planRepo:      planRepo,
+		commentsRepo:  commentsRepo,
+		policy:        policy,
+		conf:          conf,
+		log:           log,
+	}
+}
+
+func (engine *ReputationEngine) VoteChanged(ctx core.ReqContext, entityType domain.EntityType, entityId int64, before int, after int) {
+	var upvote int
+	var authorId string
+	switch entityType {
+	case domain.PlanEntity:
+		upvote = engine.conf.PlanUpvote
+		if upvote != 0 {
+			if plan := engine.planRepo.Get(ctx, int(entityId)); plan != nil {
+				authorId = plan.OwnerId
+			}
+		}
+	case domain.CommentEntity:
+		upvote = engine.conf.CommentUpvote
+		if upvote != 0 {
+			if comment := engine.commentsRepo.Get(ctx, entityId); comment != nil {
+				authorId = comment.UserId
+			}
+		}
+	default:
+		return
+	}
+
+	points := upvote*engine.upvotes(after) - upvote*engine.upvotes(before)
+	if points == 0 || authorId == "" || authorId == ctx.UserId() {
+		return
+	}
+	engine.award(ctx, authorId, entityType, entityId, points)
+}
+
+func (engine *ReputationEngine) SourceAdded(ctx core.ReqContext, sourceId int64) {
+	if engine.conf.SourceAdded == 0 || ctx.UserId() == "" {
+		return
+	}
+	if engine.conf.SourcesPerDay > 0 &&
+		engine.changeLogRepo.CountAwards(ctx.UserId(), domain.ResourceEntity, time.Now().Add(-24*time.Hour)) >= engine.conf.SourcesPerDay {
+		engine.log.Infow("Daily limit of awards for sources reached", "reqid", ctx.ReqId(), "userId", ctx.UserId(), "sourceId", sourceId)
+		return
+	}
+	engine.award(ctx, ctx.UserId(), domain.ResourceEntity, sourceId, engine.conf.SourceAdded)
+}
+
+// upvotes returns 1 if vote is upvote
+func (engine *ReputationEngine) upvotes(value int) int {
+	if value > 0 && value >= engine.conf.UpvoteValue {
+		return 1
+	}
+	return 0
+}
+
+func (engine *ReputationEngine) award(ctx core.ReqContext, userId string, entityType domain.EntityType, entityId int64, points int) {
+	record := &domain.ChangeLogRecord{
+		Action:     domain.AwardReputation,
+		UserId:     userId,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Points:     points,
+		ReqId:      ctx.ReqId(),
+		IP:         ctx.ClientIP(),
+		UserAgent:  ctx.UserAgent(),
+	}
+
+	if !engine.changeLogRepo.Award(record) {
+		engine.log.Errorw("Reputation not awarded", "reqid", ctx.ReqId(), "entityType", entityType, "entityId", entityId, "userId", userId, "points", points)
+		return
+	}
+	// permissions unlocked by reputation are cached with user state
+	engine.policy.ResetUser(userId)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/NeekUP/nptrace"
 	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
 	"time"
 )
 
@@ -73,6 +74,46 @@ func (reqCtx *requestContext) UserName() string {
 	return ""
 }
 
+func (reqCtx *requestContext) ClientIP() string {
+	if reqCtx.ctx == nil {
+		return ""
+	}
+	if ip, ok := reqCtx.ctx.Value(ReqClientIP).(string); ok {
+		return ip
+	}
+	return ""
+}
+
+func (reqCtx *requestContext) UserAgent() string {
+	if reqCtx.ctx == nil {
+		return ""
+	}
+	if userAgent, ok := reqCtx.ctx.Value(ReqUserAgent).(string); ok {
+		return userAgent
+	}
+	return ""
+}
+
+func (reqCtx *requestContext) Language() string {
+	if reqCtx.ctx == nil {
+		return ""
+	}
+	if lang, ok := reqCtx.ctx.Value(ReqLanguage).(string); ok {
+		return lang
+	}
+	return ""
+}
+
+func (reqCtx *requestContext) HasPermission(permission domain.Permission) bool {
+	if reqCtx.ctx == nil {
+		return false
+	}
+	if permissions, ok := reqCtx.ctx.Value(ReqPermissions).(domain.PermissionSet); ok {
+		return permissions.Has(permission)
+	}
+	return false
+}
+
 func (reqCtx *requestContext) StartTrace(name string, args ...interface{}) *nptrace.Trace {
 	tr, ok := reqCtx.Value(Tracer).(*nptrace.Task)
 	if ok {
@@ -0,0 +1,144 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"sync"
+)
+
+// SmtpSink is a local smtp server which accepts all messages and keeps them in memory.
+// It is used in tests and in development instead of real smtp server.
+type SmtpSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []SinkMessage
+}
+
+type SinkMessage struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// NewSmtpSink starts listening on addr, use "127.0.0.1:0" to get random port
+func NewSmtpSink(addr string) (*SmtpSink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sink := &SmtpSink{listener: listener}
+	go sink.serve()
+	return sink, nil
+}
+
+func (sink *SmtpSink) Addr() string {
+	return sink.listener.Addr().String()
+}
+
+func (sink *SmtpSink) Close() error {
+	return sink.listener.Close()
+}
+
+func (sink *SmtpSink) Messages() []SinkMessage {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	result := make([]SinkMessage, len(sink.messages))
+	copy(result, sink.messages)
+	return result
+}
+
+func (sink *SmtpSink) serve() {
+	for {
+		conn, err := sink.listener.Accept()
+		if err != nil {
+			return
+		}
+		go sink.handle(conn)
+	}
+}
+
+func (sink *SmtpSink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		w.WriteString(line + "\r\n")
+		w.Flush()
+	}
+
+	msg := SinkMessage{}
+	reply("220 localhost smtp sink")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			w.WriteString("250-localhost\r\n")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "AUTH"):
+			reply("235 Authentication succeeded")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg = SinkMessage{From: trimAddress(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.To = append(msg.To, trimAddress(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			sink.mu.Lock()
+			sink.messages = append(sink.messages, msg)
+			sink.mu.Unlock()
+			reply("250 OK")
+		case cmd == "RSET":
+			msg = SinkMessage{}
+			reply("250 OK")
+		case cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func readData(r *bufio.Reader) ([]byte, error) {
+	var data bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return data.Bytes(), nil
+		}
+		// dot stuffing
+		if strings.HasPrefix(line, "..") {
+			line = line[1:]
+		}
+		data.WriteString(line)
+	}
+}
+
+func trimAddress(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, " "); i > 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "<>")
+}
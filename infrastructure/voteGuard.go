@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// VoteGuardConf limits number of votes made by user and from ip within Window, zero limit is not checked.
+// Votes of accounts younger than NewAccountAge and of accounts with unconfirmed email are multiplied by weights
+type VoteGuardConf struct {
+	Window            time.Duration
+	MaxPerUser        int
+	MaxPerIP          int
+	NewAccountAge     time.Duration
+	NewAccountWeight  float64
+	UnconfirmedWeight float64
+}
+
+// VoteGuard rejects votes made too often and lowers weight of votes of suspicious accounts.
+// Votes for own content are saved flagged, so user sees own vote but aggregates ignore it
+type VoteGuard struct {
+	pointsRepo   core.PointsRepository
+	userRepo     core.UserRepository
+	planRepo     core.PlanRepository
+	commentsRepo core.CommentsRepository
+	topicRepo    core.TopicRepository
+	conf         VoteGuardConf
+	log          core.AppLogger
+}
+
+func NewVoteGuard(pointsRepo core.PointsRepository, userRepo core.UserRepository, planRepo core.PlanRepository, commentsRepo core.CommentsRepository, topicRepo core.TopicRepository, conf VoteGuardConf, log core.AppLogger) core.VoteGuard {
+	return &VoteGuard{
+		pointsRepo:   pointsRepo,
+		userRepo:     userRepo,
+		planRepo:     planRepo,
+		commentsRepo: commentsRepo,
+		topicRepo:    topicRepo,
+		conf:         conf,
+		log:          log,
+	}
+}
+
+func (guard *VoteGuard) Check(ctx core.ReqContext, vote *domain.VoteRecord) *core.AppError {
+	if guard.conf.Window > 0 && (guard.conf.MaxPerUser > 0 || guard.conf.MaxPerIP > 0) {
+		byUser, byIP := guard.pointsRepo.CountRecent(ctx, vote.UserId, vote.IP, time.Now().Add(-guard.conf.Window))
+		if (guard.conf.MaxPerUser > 0 && byUser >= guard.conf.MaxPerUser) ||
+			(guard.conf.MaxPerIP > 0 && vote.IP != "" && byIP >= guard.conf.MaxPerIP) {
+			guard.log.Errorw("too many votes",
+				"reqid", ctx.ReqId(),
+				"UserId", vote.UserId,
+				"ip", vote.IP,
+				"byUser", byUser,
+				"byIP", byIP,
+			)
+			return core.NewError(core.TooManyRequests)
+		}
+	}
+
+	vote.Weight = 1
+	if guard.author(ctx, vote.EntityType, vote.EntityId) == vote.UserId {
+		vote.Flagged = true
+		return nil
+	}
+
+	user := guard.userRepo.Get(ctx, vote.UserId)
+	if user == nil {
+		return nil
+	}
+	if !user.EmailConfirmed {
+		vote.Weight *= guard.conf.UnconfirmedWeight
+	}
+	if !user.Created.IsZero() && time.Since(user.Created) < guard.conf.NewAccountAge {
+		vote.Weight *= guard.conf.NewAccountWeight
+	}
+	return nil
+}
+
+// author returns id of user created the entity, empty if unknown
+func (guard *VoteGuard) author(ctx core.ReqContext, entityType domain.EntityType, entityId int64) string {
+	switch entityType {
+	case domain.PlanEntity:
+		if plan := guard.planRepo.Get(ctx, int(entityId)); plan != nil {
+			return plan.OwnerId
+		}
+	case domain.CommentEntity:
+		if comment := guard.commentsRepo.Get(ctx, entityId); comment != nil {
+			return comment.UserId
+		}
+	case domain.TopicEntity:
+		if topic := guard.topicRepo.GetById(ctx, int(entityId)); topic != nil {
+			return topic.Creator
+		}
+	}
+	// projects are not stored yet, sources have no author
+	return ""
+}
@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+)
+
+// VoteRingConf sets detection of accounts voting together. Votes of Lookback period are checked,
+// accounts are linked if they voted alike for MinShared entities within Window of each other
+type VoteRingConf struct {
+	Interval  time.Duration
+	Lookback  time.Duration
+	Window    time.Duration
+	MinShared int
+	MinSize   int
+}
+
+// VoteRingJob flags votes of rings of accounts voting together, flagged votes are excluded from aggregates
+type VoteRingJob struct {
+	pointsRepo core.PointsRepository
+	conf       VoteRingConf
+	log        core.AppLogger
+}
+
+func NewVoteRingJob(pointsRepo core.PointsRepository, conf VoteRingConf, log core.AppLogger) *VoteRingJob {
+	return &VoteRingJob{
+		pointsRepo: pointsRepo,
+		conf:       conf,
+		log:        log,
+	}
+}
+
+// Start runs detection in background every interval. Zero interval disables the job.
+func (job *VoteRingJob) Start() {
+	if job.conf.Interval <= 0 {
+		job.log.Infow("Vote ring job disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(job.conf.Interval)
+		defer ticker.Stop()
+		for {
+			job.Run(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+func (job *VoteRingJob) Run(now time.Time) {
+	votes, err := job.pointsRepo.GetRecent(now.Add(-job.conf.Lookback))
+	if err != nil {
+		job.log.Errorw("Votes not loaded", "error", err.Error())
+		return
+	}
+
+	flagged := core.FindVoteRings(votes, job.conf.Window, job.conf.MinShared, job.conf.MinSize)
+	if len(flagged) == 0 {
+		return
+	}
+
+	if err := job.pointsRepo.Flag(flagged); err != nil {
+		job.log.Errorw("Votes not flagged", "error", err.Error())
+		return
+	}
+
+	job.log.Infow("Vote rings detected",
+		"votes", len(votes),
+		"flagged", len(flagged),
+	)
+}
@@ -0,0 +1,174 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+const (
+	WebhookSignatureHeader = "X-Roadmaps-Signature"
+	WebhookEventHeader     = "X-Roadmaps-Event"
+	WebhookDeliveryHeader  = "X-Roadmaps-Delivery"
+)
+
+// time to send one batch, after it deliveries become available for other dispatchers again
+const webhookLease = 5 * time.Minute
+
+// WebhookDispatcher sends queued deliveries to webhooks in background.
+// Failed delivery is retried with growing delay and marked as dead after MaxAttempts.
+type WebhookDispatcher struct {
+	webhookRepo core.WebhookRepository
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	log         core.AppLogger
+}
+
+type WebhookDispatcherConf struct {
+	Interval    time.Duration
+	Timeout     time.Duration
+	BatchSize   int
+	MaxAttempts int
+	// webhooks are not sent to local and private networks unless allowed, for development only
+	AllowPrivateNetworks bool
+}
+
+func NewWebhookDispatcher(webhookRepo core.WebhookRepository, conf WebhookDispatcherConf, log core.AppLogger) *WebhookDispatcher {
+	dialer := &net.Dialer{Timeout: conf.Timeout}
+	if !conf.AllowPrivateNetworks {
+		dialer.Control = publicAddressOnly
+	}
+
+	return &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout: conf.Timeout,
+			// proxy is not used, address is checked when connection is opened
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			// redirect could lead request to unexpected host
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		interval:    conf.Interval,
+		batchSize:   conf.BatchSize,
+		maxAttempts: conf.MaxAttempts,
+		log:         log,
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	if d.interval <= 0 || d.batchSize <= 0 {
+		d.log.Infow("Webhook dispatcher disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			// send next batch immediately if this one was full
+			if d.Run() < d.batchSize {
+				<-ticker.C
+			}
+		}
+	}()
+}
+
+// Run sends one batch of deliveries and returns count of processed deliveries
+func (d *WebhookDispatcher) Run() int {
+	deliveries := d.webhookRepo.Take(d.batchSize, webhookLease)
+	for i := 0; i < len(deliveries); i++ {
+		d.send(&deliveries[i])
+	}
+	return len(deliveries)
+}
+
+func (d *WebhookDispatcher) send(delivery *domain.WebhookDelivery) {
+	code, err := d.deliver(delivery)
+	if err == nil {
+		d.webhookRepo.MarkDelivered(delivery.Id, code)
+		d.log.Infow("Send webhook", "id", delivery.Id, "webhookId", delivery.WebhookId, "event", delivery.Event, "code", code, "status", true)
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	dead := attempts >= d.maxAttempts
+	d.webhookRepo.MarkFailed(delivery.Id, code, err.Error(), time.Now().Add(retryDelay(attempts)), dead)
+	d.log.Errorw("Send webhook",
+		"id", delivery.Id,
+		"webhookId", delivery.WebhookId,
+		"event", delivery.Event,
+		"code", code,
+		"status", false,
+		"attempts", attempts,
+		"dead", dead,
+		"err", err.Error(),
+	)
+}
+
+// deliver returns response code, it is zero when request was not sent
+func (d *WebhookDispatcher) deliver(delivery *domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, strconv.Itoa(int(delivery.Event)))
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(delivery.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// connection is reused only when body is read
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// publicAddressOnly rejects connection to resolved address,
+// so host which resolves to local network after validation could not be reached
+func publicAddressOnly(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !core.IsPublicIP(ip) {
+		return fmt.Errorf("webhook address is not allowed: %s", host)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns HMAC-SHA256 of payload, receiver checks it with the secret of webhook
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
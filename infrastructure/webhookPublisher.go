@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+// WebhookPublisher puts changes of plans, topics and sources into queue of webhook deliveries
+type WebhookPublisher struct {
+	webhookRepo core.WebhookRepository
+	log         core.AppLogger
+}
+
+func NewWebhookPublisher(webhookRepo core.WebhookRepository, log core.AppLogger) core.WebhookPublisher {
+	return &WebhookPublisher{webhookRepo: webhookRepo, log: log}
+}
+
+// WebhookPayload is a body of request sent to webhook.
+// Ids of plans are encoded the same way as in api.
+type WebhookPayload struct {
+	Event      domain.ChangeType `json:"event"`
+	EntityType string            `json:"entityType"`
+	EntityId   string            `json:"entityId"`
+	UserId     string            `json:"userId"`
+	Date       time.Time         `json:"date"`
+	Diff       json.RawMessage   `json:"diff,omitempty"`
+	Snapshot   json.RawMessage   `json:"snapshot,omitempty"`
+}
+
+func NewWebhookPayload(record *domain.ChangeLogRecord, date time.Time) *WebhookPayload {
+	entityId := strconv.FormatInt(record.EntityId, 10)
+	if record.EntityType == domain.PlanEntity {
+		entityId = core.EncodeNumToString(int(record.EntityId))
+	}
+
+	payload := &WebhookPayload{
+		Event:      record.Action,
+		EntityType: domain.EntityTypeToString(record.EntityType),
+		EntityId:   entityId,
+		UserId:     record.UserId,
+		Date:       date.UTC(),
+	}
+	if record.Diff != "" {
+		payload.Diff = json.RawMessage(record.Diff)
+	}
+	if record.Snapshot != "" {
+		payload.Snapshot = json.RawMessage(record.Snapshot)
+	}
+	return payload
+}
+
+func (p *WebhookPublisher) Publish(ctx core.ReqContext, record *domain.ChangeLogRecord) {
+	if !record.Action.IsWebhookEvent() {
+		return
+	}
+
+	payload, err := json.Marshal(NewWebhookPayload(record, time.Now()))
+	if err != nil {
+		p.log.Errorw("Fail to serialize webhook payload", "reqid", ctx.ReqId(), "event", record.Action, "entityId", record.EntityId, "error", err.Error())
+		return
+	}
+
+	if _, appErr := p.webhookRepo.Enqueue(ctx, record.Action, record.UserId, string(payload)); appErr != nil {
+		p.log.Errorw("Webhook deliveries not queued", "reqid", ctx.ReqId(), "event", record.Action, "entityId", record.EntityId, "error", appErr.Error())
+	}
+}
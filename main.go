@@ -14,6 +14,7 @@ import (
 	"github.com/NeekUP/roadmaps/infrastructure/db"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"runtime"
@@ -72,6 +73,7 @@ func main() {
 	}
 
 	r.Use(middleware.RealIP)
+	r.Use(infrastructure.ClientInfo)
 	r.Use(httpLogger(newLogger("http")))
 	r.Use(recoverer(newLogger("recoverer")))
 
@@ -95,9 +97,30 @@ func main() {
 	commentsRepo := db.NewCommentsRepository(dbConnection)
 	pointsRepo := db.NewPointsRepository(dbConnection)
 	changesRepository := db.NewChangeLogRepository(dbConnection)
+	roleRepo := db.NewRoleRepository(dbConnection)
+	reportRepo := db.NewReportRepository(dbConnection)
+	suggestionRepo := db.NewPlanSuggestionRepository(dbConnection)
+	collaboratorRepo := db.NewPlanCollaboratorRepository(dbConnection)
+	accessPolicy := infrastructure.NewRolePolicy(roleRepo, userRepo, cache, initUnconfirmedRestrictions(), initReputationUnlocks(), newLogger("accessPolicy"))
 	projectsRepo := db.NewProjectsRepository(dbConnection)
-	changeLog := infrastructure.NewChangesCollector(changesRepository, newLogger("changeLog"))
-	emailService := infrastructure.NewEmailSender(Cfg.SiteHost, Cfg.SMTP.SenderEmail, Cfg.SMTP.SenderName, Cfg.SMTP.Host, Cfg.SMTP.Pass, Cfg.SMTP.Port, newLogger("emails"))
+	notificationRepo := db.NewNotificationRepository(dbConnection)
+	notifier := infrastructure.NewNotificationCenter(notificationRepo, commentsRepo, planRepo, usersPlanRepo, newLogger("notifications"))
+	webhookRepo := db.NewWebhookRepository(dbConnection)
+	webhookPublisher := infrastructure.NewWebhookPublisher(webhookRepo, newLogger("webhooks"))
+	changeLog := infrastructure.NewChangesCollector(changesRepository, notifier, webhookPublisher, newLogger("changeLog"))
+	eventBus := infrastructure.NewEventBus(initEventBackend(dbConnection), newLogger("events"))
+	broadcaster := infrastructure.NewEventBroadcaster(eventBus, pointsRepo, commentsRepo, newLogger("events"))
+	reputation := infrastructure.NewReputationEngine(changesRepository, planRepo, commentsRepo, accessPolicy,
+		infrastructure.ReputationConf{
+			UpvoteValue:   Cfg.Reputation.UpvoteValue,
+			PlanUpvote:    Cfg.Reputation.PlanUpvote,
+			CommentUpvote: Cfg.Reputation.CommentUpvote,
+			SourceAdded:   Cfg.Reputation.SourceAdded,
+			SourcesPerDay: Cfg.Reputation.SourcesPerDay,
+		},
+		newLogger("reputation"))
+	markdown := infrastructure.NewMarkdownRenderer(userRepo, Cfg.Markdown.MentionUrl, newLogger("markdown"))
+	emailOutbox := db.NewEmailOutboxRepository(dbConnection)
 
 	api.ImgManager = imageManager
 
@@ -109,30 +132,56 @@ func main() {
 	**************************************/
 
 	// Users
-	regUser := usecases.NewRegisterUser(userRepo, emailService, hashProvider, imageManager, newLogger("registerUser"))
+	confirmationLifetime := time.Duration(Cfg.Confirmation.LifetimeHours) * time.Hour
+	confirmationInterval := time.Duration(Cfg.Confirmation.ResendIntervalSec) * time.Second
+	regUser := usecases.NewRegisterUser(userRepo, hashProvider, imageManager, confirmationLifetime, newLogger("registerUser"))
 	loginUser := usecases.NewLoginUser(userRepo, newLogger("loginUser"), hashProvider, tokenService)
 	refreshToken := usecases.NewRefreshToken(userRepo, newLogger("refreshToken"), tokenService, JwtSecret)
-	emailConfirmation := usecases.NewEmailConfirmation(userRepo, newLogger("emailConfirmation"))
+	emailConfirmation := usecases.NewEmailConfirmation(userRepo, accessPolicy, newLogger("emailConfirmation"))
+	resendConfirmation := usecases.NewResendConfirmation(userRepo, confirmationLifetime, confirmationInterval, newLogger("resendConfirmation"))
+	changeEmail := usecases.NewChangeEmail(userRepo, confirmationLifetime, confirmationInterval, newLogger("changeEmail"))
 	checkUser := usecases.NewCheckUser(userRepo, newLogger("checkUser"))
 	registerUserOauth := usecases.NewRegisterUserOauth(userRepo, hashProvider, imageManager, newLogger("registerUserOauth"))
 	loginUserOauth := usecases.NewLoginUserOauth(userRepo, tokenService, newLogger("loginUserOauth"))
 	// Sources
-	addSource := usecases.NewAddSource(sourceRepo, newLogger("addSource"), imageManager, changeLog)
+	addSource := usecases.NewAddSource(sourceRepo, newLogger("addSource"), imageManager, changeLog, reputation)
+	removeSource := usecases.NewRemoveSource(sourceRepo, changeLog, newLogger("removeSource"))
+	restoreSource := usecases.NewRestoreSource(sourceRepo, changeLog, newLogger("restoreSource"))
 
 	// Topics
-	addTopic := usecases.NewAddTopic(topicRepo, changeLog, newLogger("addTopic"))
-	getTopic := usecases.NewGetTopic(topicRepo, planRepo, usersPlanRepo, newLogger("getTopic"))
+	addTopic := usecases.NewAddTopic(topicRepo, changeLog, markdown, newLogger("addTopic"))
+	getTopic := usecases.NewGetTopic(topicRepo, planRepo, usersPlanRepo, commentsRepo, pointsRepo, newLogger("getTopic"))
 	searchTopic := usecases.NewSearchTopic(topicRepo, newLogger("getUsersPlans"))
-	editTopic := usecases.NewEditTopic(topicRepo, changeLog, newLogger("editTopic"))
+	editTopic := usecases.NewEditTopic(topicRepo, changeLog, markdown, newLogger("editTopic"))
+	removeTopic := usecases.NewRemoveTopic(topicRepo, changeLog, newLogger("removeTopic"))
+	restoreTopic := usecases.NewRestoreTopic(topicRepo, changeLog, newLogger("restoreTopic"))
 
 	// Plans
-	addPlan := usecases.NewAddPlan(planRepo, sourceRepo, topicRepo, projectsRepo, changeLog, newLogger("addPlan"))
+	addPlan := usecases.NewAddPlan(planRepo, sourceRepo, topicRepo, projectsRepo, changeLog, markdown, newLogger("addPlan"))
 	getPlanTree := usecases.NewGetPlanTree(planRepo, topicRepo, stepRepo, usersPlanRepo, newLogger("getPlanTree"))
-	getPlan := usecases.NewGetPlan(planRepo, userRepo, stepRepo, sourceRepo, topicRepo, newLogger("getPlan"))
+	getPlan := usecases.NewGetPlan(planRepo, userRepo, stepRepo, sourceRepo, topicRepo, commentsRepo, pointsRepo, newLogger("getPlan"))
 	getPlanList := usecases.NewGetPlanList(planRepo, userRepo, newLogger("getPlanList"))
-	editPlan := usecases.NewEditPlan(planRepo, sourceRepo, topicRepo, projectsRepo, changeLog, newLogger("editPlan"))
-	removePlan := usecases.NewRemovePlan(planRepo, changeLog, newLogger("removePlan"))
+	editPlan := usecases.NewEditPlan(planRepo, collaboratorRepo, stepRepo, sourceRepo, topicRepo, projectsRepo, changeLog, markdown, newLogger("editPlan"))
+	removePlan := usecases.NewRemovePlan(planRepo, collaboratorRepo, stepRepo, changeLog, newLogger("removePlan"))
+	restorePlan := usecases.NewRestorePlan(planRepo, changeLog, newLogger("restorePlan"))
 	getListByUser := usecases.NewGetPlanListByUser(planRepo, userRepo, newLogger("getListByUser"))
+	forkPlan := usecases.NewForkPlan(planRepo, stepRepo, changeLog, newLogger("forkPlan"))
+	planDiffer := infrastructure.NewPlanDiffer()
+	getPlanDiff := usecases.NewGetPlanDiff(planRepo, stepRepo, planDiffer, newLogger("getPlanDiff"))
+
+	// Plan Suggestions
+	suggestPlanEdit := usecases.NewSuggestPlanEdit(suggestionRepo, planRepo, sourceRepo, topicRepo, projectsRepo, newLogger("suggestPlanEdit"))
+	getPlanSuggestions := usecases.NewGetPlanSuggestions(suggestionRepo, newLogger("getPlanSuggestions"))
+	getPlanSuggestion := usecases.NewGetPlanSuggestion(suggestionRepo, planRepo, stepRepo, planDiffer, newLogger("getPlanSuggestion"))
+	acceptPlanSuggestion := usecases.NewAcceptPlanSuggestion(suggestionRepo, planRepo, editPlan, newLogger("acceptPlanSuggestion"))
+	rejectPlanSuggestion := usecases.NewRejectPlanSuggestion(suggestionRepo, planRepo, newLogger("rejectPlanSuggestion"))
+
+	// Plan Collaborators
+	invitePlanCollaborator := usecases.NewInvitePlanCollaborator(collaboratorRepo, planRepo, userRepo, newLogger("invitePlanCollaborator"))
+	getPlanCollaborators := usecases.NewGetPlanCollaborators(collaboratorRepo, planRepo, newLogger("getPlanCollaborators"))
+	removePlanCollaborator := usecases.NewRemovePlanCollaborator(collaboratorRepo, planRepo, newLogger("removePlanCollaborator"))
+	getPlanInvitations := usecases.NewGetPlanInvitations(collaboratorRepo, newLogger("getPlanInvitations"))
+	acceptPlanInvitation := usecases.NewAcceptPlanInvitation(collaboratorRepo, newLogger("acceptPlanInvitation"))
 
 	// Users Plans
 	addUserPlan := usecases.NewAddUserPlan(planRepo, usersPlanRepo, newLogger("addUserPlan"))
@@ -144,16 +193,74 @@ func main() {
 	removeTopicTag := usecases.NewRemoveTopicTag(topicRepo, changeLog, newLogger("removeTopicTag"))
 
 	// Comments
-	addComment := usecases.NewAddComment(commentsRepo, planRepo, changeLog, newLogger("addComment"))
-	editComment := usecases.NewEditComment(commentsRepo, changeLog, newLogger("editComment"))
-	removeComment := usecases.NewRemoveComments(commentsRepo, changeLog, newLogger("removeComment"))
+	addComment := usecases.NewAddComment(commentsRepo, planRepo, topicRepo, sourceRepo, changeLog, notifier, broadcaster, markdown, newLogger("addComment"))
+	editComment := usecases.NewEditComment(commentsRepo, changeLog, broadcaster, markdown, newLogger("editComment"))
+	removeComment := usecases.NewRemoveComments(commentsRepo, changeLog, broadcaster, newLogger("removeComment"))
 	getCommentsThreads := usecases.NewGetCommentsThreads(commentsRepo, userRepo, newLogger("getCommentsThreads"))
 	getCommentsThread := usecases.NewGetCommentsThread(commentsRepo, userRepo, newLogger("getCommentsThread"))
+	getCommentRevisions := usecases.NewGetCommentRevisions(commentsRepo, userRepo, newLogger("getCommentRevisions"))
+	toggleReaction := usecases.NewToggleReaction(commentsRepo, broadcaster, newLogger("toggleReaction"))
+	pinComment := usecases.NewPinComment(commentsRepo, planRepo, changeLog, broadcaster, newLogger("pinComment"))
+	acceptAnswer := usecases.NewAcceptAnswer(commentsRepo, planRepo, changeLog, broadcaster, newLogger("acceptAnswer"))
 
 	// Vote
-	addPoints := usecases.NewAddPoints(pointsRepo, newLogger("addPoints"))
+	voteGuard := infrastructure.NewVoteGuard(pointsRepo, userRepo, planRepo, commentsRepo, topicRepo,
+		infrastructure.VoteGuardConf{
+			Window:            time.Duration(Cfg.AntiFraud.WindowMin) * time.Minute,
+			MaxPerUser:        Cfg.AntiFraud.MaxVotesPerUser,
+			MaxPerIP:          Cfg.AntiFraud.MaxVotesPerIP,
+			NewAccountAge:     time.Duration(Cfg.AntiFraud.NewAccountDays) * 24 * time.Hour,
+			NewAccountWeight:  Cfg.AntiFraud.NewAccountWeight,
+			UnconfirmedWeight: Cfg.AntiFraud.UnconfirmedWeight,
+		},
+		newLogger("voteGuard"))
+	addPoints := usecases.NewAddPoints(pointsRepo, notifier, broadcaster, reputation, voteGuard, newLogger("addPoints"))
+	changePoints := usecases.NewChangePoints(pointsRepo, broadcaster, reputation, voteGuard, newLogger("changePoints"))
+	removePoints := usecases.NewRemovePoints(pointsRepo, broadcaster, reputation, newLogger("removePoints"))
 	getPoints := usecases.NewGetPoints(pointsRepo, newLogger("getPoints"))
 	getPointsList := usecases.NewGetPointsList(pointsRepo, newLogger("getPointsList"))
+
+	// Reports
+	addReport := usecases.NewAddReport(reportRepo, planRepo, topicRepo, sourceRepo, commentsRepo, newLogger("addReport"))
+	getReports := usecases.NewGetReports(reportRepo, newLogger("getReports"))
+	getUserReports := usecases.NewGetUserReports(reportRepo, newLogger("getUserReports"))
+	resolveReport := usecases.NewResolveReport(reportRepo, planRepo, commentsRepo, removePlan, removeComment, removeTopic, removeSource, changeLog, newLogger("resolveReport"))
+	unhideContent := usecases.NewUnhideContent(planRepo, commentsRepo, changeLog, newLogger("unhideContent"))
+
+	// Roles
+	listRoles := usecases.NewListRoles(roleRepo, newLogger("listRoles"))
+	saveRole := usecases.NewSaveRole(roleRepo, accessPolicy, newLogger("saveRole"))
+	removeRole := usecases.NewRemoveRole(roleRepo, accessPolicy, newLogger("removeRole"))
+	grantRole := usecases.NewGrantRole(roleRepo, userRepo, accessPolicy, changeLog, newLogger("grantRole"))
+	revokeRole := usecases.NewRevokeRole(roleRepo, userRepo, accessPolicy, changeLog, newLogger("revokeRole"))
+
+	// Users management
+	searchUsers := usecases.NewSearchUsers(userRepo, roleRepo, newLogger("searchUsers"))
+	banUser := usecases.NewBanUser(userRepo, accessPolicy, changeLog, newLogger("banUser"))
+	unbanUser := usecases.NewUnbanUser(userRepo, accessPolicy, changeLog, newLogger("unbanUser"))
+	confirmUserEmail := usecases.NewConfirmUserEmail(userRepo, accessPolicy, changeLog, newLogger("confirmUserEmail"))
+	logoutUser := usecases.NewLogoutUser(userRepo, accessPolicy, changeLog, newLogger("logoutUser"))
+
+	// Change log
+	getChangeLog := usecases.NewGetChangeLog(changesRepository, newLogger("getChangeLog"))
+
+	// Events
+	subscribeEvents := usecases.NewSubscribeEvents(eventBus, planRepo, commentsRepo, topicRepo, sourceRepo, newLogger("subscribeEvents"))
+
+	// Webhooks
+	addWebhook := usecases.NewAddWebhook(webhookRepo, newLogger("addWebhook"))
+	editWebhook := usecases.NewEditWebhook(webhookRepo, newLogger("editWebhook"))
+	removeWebhook := usecases.NewRemoveWebhook(webhookRepo, newLogger("removeWebhook"))
+	getWebhooks := usecases.NewGetWebhooks(webhookRepo, newLogger("getWebhooks"))
+	getWebhookDeliveries := usecases.NewGetWebhookDeliveries(webhookRepo, newLogger("getWebhookDeliveries"))
+	redeliverWebhook := usecases.NewRedeliverWebhook(webhookRepo, newLogger("redeliverWebhook"))
+
+	// Notifications
+	getNotifications := usecases.NewGetNotifications(notificationRepo, newLogger("getNotifications"))
+	getUnreadCount := usecases.NewGetUnreadCount(notificationRepo, newLogger("getUnreadCount"))
+	markNotificationsRead := usecases.NewMarkNotificationsRead(notificationRepo, newLogger("markNotificationsRead"))
+	getNotificationPreferences := usecases.NewGetNotificationPreferences(notificationRepo, newLogger("getNotificationPreferences"))
+	saveNotificationPreferences := usecases.NewSaveNotificationPreferences(notificationRepo, newLogger("saveNotificationPreferences"))
 	/*
 		Api methods
 	**************************************/
@@ -163,6 +270,8 @@ func main() {
 	apiLoginUser := api.Login(loginUser, newLogger("loginUser"), captcha)
 	apiRefreshToken := api.RefreshToken(refreshToken, newLogger("refreshToken"), captcha)
 	apiEmailConfirmation := api.EmailConfirmation(emailConfirmation, newLogger("emailConfirmation"))
+	apiResendConfirmation := api.ResendConfirmation(resendConfirmation, newLogger("resendConfirmation"))
+	apiChangeEmail := api.ChangeEmail(changeEmail, newLogger("changeEmail"))
 	apiCheckUser := api.CheckUser(checkUser, newLogger("checkUser"))
 	apiRegisterUserOauthLink := api.RegisterOAuthLink(checkUser, openAuthenticator, newLogger("registerUserOauth"))
 	apiRegisterUserOauth := api.RegisterOAuth(registerUserOauth, loginUserOauth, openAuthenticator, newLogger("registerUserOauth"))
@@ -171,6 +280,8 @@ func main() {
 
 	// Sources
 	apiAddSource := api.AddSource(addSource, newLogger("addSource"))
+	apiRemoveSource := api.RemoveSource(removeSource, newLogger("removeSource"))
+	apiRestoreSource := api.RestoreSource(restoreSource, newLogger("restoreSource"))
 
 	// Topics
 	apiAddTopic := api.AddTopic(addTopic, newLogger("addTopic"))
@@ -178,6 +289,8 @@ func main() {
 	apiGetTopic := api.GetTopic(getTopic, newLogger("getTopic"))
 	apiSearchTopic := api.SearchTopic(searchTopic, newLogger("searchTopic"))
 	apiEditTopic := api.EditTopic(editTopic, newLogger("editTopic"))
+	apiRemoveTopic := api.RemoveTopic(removeTopic, newLogger("removeTopic"))
+	apiRestoreTopic := api.RestoreTopic(restoreTopic, newLogger("restoreTopic"))
 
 	// Plans
 	apiAddPlan := api.AddPlan(addPlan, newLogger("addPlan"))
@@ -186,7 +299,22 @@ func main() {
 	apiGetPlanList := api.GetPlanList(getPlanList, getUsersPlans, getPointsList, newLogger("getPlanList"))
 	apiEditPlan := api.EditPlan(editPlan, newLogger("editPlan"))
 	apiRemovePlan := api.RemovePlan(removePlan, newLogger("removePlan"))
+	apiRestorePlan := api.RestorePlan(restorePlan, newLogger("restorePlan"))
 	apiGetListByUser := api.GetListByUser(getListByUser, getPointsList, newLogger("getListByUser "))
+	apiForkPlan := api.ForkPlan(forkPlan, newLogger("forkPlan"))
+	apiGetPlanDiff := api.GetPlanDiff(getPlanDiff, newLogger("getPlanDiff"))
+	// Plan Suggestions
+	apiSuggestPlanEdit := api.SuggestPlanEdit(suggestPlanEdit, newLogger("suggestPlanEdit"))
+	apiGetPlanSuggestions := api.GetPlanSuggestions(getPlanSuggestions, newLogger("getPlanSuggestions"))
+	apiGetPlanSuggestion := api.GetPlanSuggestion(getPlanSuggestion, newLogger("getPlanSuggestion"))
+	apiAcceptPlanSuggestion := api.AcceptPlanSuggestion(acceptPlanSuggestion, newLogger("acceptPlanSuggestion"))
+	apiRejectPlanSuggestion := api.RejectPlanSuggestion(rejectPlanSuggestion, newLogger("rejectPlanSuggestion"))
+	// Plan Collaborators
+	apiInvitePlanCollaborator := api.InvitePlanCollaborator(invitePlanCollaborator, newLogger("invitePlanCollaborator"))
+	apiGetPlanCollaborators := api.GetPlanCollaborators(getPlanCollaborators, newLogger("getPlanCollaborators"))
+	apiRemovePlanCollaborator := api.RemovePlanCollaborator(removePlanCollaborator, newLogger("removePlanCollaborator"))
+	apiGetPlanInvitations := api.GetPlanInvitations(getPlanInvitations, newLogger("getPlanInvitations"))
+	apiAcceptPlanInvitation := api.AcceptPlanInvitation(acceptPlanInvitation, newLogger("acceptPlanInvitation"))
 	// Users Plans
 	apiAddUserPlan := api.AddUserPlan(addUserPlan, newLogger("addUserPlan"))
 	apiRemoveAddUserPlan := api.RemoveUserPlan(removeUserPlan, newLogger("removeUserPlan"))
@@ -201,23 +329,128 @@ func main() {
 	apiRemoveComment := api.DeleteComment(removeComment, newLogger("removeComment"))
 	apiGetCommentsThreads := api.GetThreads(getCommentsThreads, getPointsList, newLogger("getCommentsThreads"))
 	apiGetCommentsThread := api.GetThread(getCommentsThread, getPointsList, newLogger("getCommentsThread"))
+	apiGetCommentRevisions := api.GetCommentRevisions(getCommentRevisions, newLogger("getCommentRevisions"))
+	apiToggleReaction := api.ToggleReaction(toggleReaction, newLogger("toggleReaction"))
+	apiPinComment := api.PinComment(pinComment, newLogger("pinComment"))
+	apiAcceptAnswer := api.AcceptAnswer(acceptAnswer, newLogger("acceptAnswer"))
 
 	// Vote
 	apiAddPoints := api.AddPoints(addPoints, newLogger("addPoints"))
+	apiChangePoints := api.ChangePoints(changePoints, newLogger("changePoints"))
+	apiRemovePoints := api.RemovePoints(removePoints, newLogger("removePoints"))
+
+	// Reports
+	apiAddReport := api.AddReport(addReport, newLogger("addReport"))
+	apiGetReports := api.GetReports(getReports, newLogger("getReports"))
+	apiGetUserReports := api.GetUserReports(getUserReports, newLogger("getUserReports"))
+	apiResolveReport := api.ResolveReport(resolveReport, newLogger("resolveReport"))
+	apiUnhideContent := api.UnhideContent(unhideContent, newLogger("unhideContent"))
+
+	// Roles
+	apiListRoles := api.ListRoles(listRoles, newLogger("listRoles"))
+	apiSaveRole := api.SaveRole(saveRole, newLogger("saveRole"))
+	apiRemoveRole := api.RemoveRole(removeRole, newLogger("removeRole"))
+	apiGrantRole := api.GrantRole(grantRole, newLogger("grantRole"))
+	apiRevokeRole := api.RevokeRole(revokeRole, newLogger("revokeRole"))
+
+	// Users management
+	apiSearchUsers := api.SearchUsers(searchUsers, newLogger("searchUsers"))
+	apiBanUser := api.BanUser(banUser, newLogger("banUser"))
+	apiUnbanUser := api.UnbanUser(unbanUser, newLogger("unbanUser"))
+	apiConfirmUserEmail := api.ConfirmUserEmail(confirmUserEmail, newLogger("confirmUserEmail"))
+	apiLogoutUser := api.LogoutUser(logoutUser, newLogger("logoutUser"))
+
+	// Change log
+	apiGetChangeLog := api.GetChangeLog(getChangeLog, newLogger("getChangeLog"))
+
+	// Events
+	apiEvents := api.Events(subscribeEvents, eventsStreamDuration(), newLogger("events"))
+
+	// Webhooks
+	apiAddWebhook := api.AddWebhook(addWebhook, newLogger("addWebhook"))
+	apiEditWebhook := api.EditWebhook(editWebhook, newLogger("editWebhook"))
+	apiRemoveWebhook := api.RemoveWebhook(removeWebhook, newLogger("removeWebhook"))
+	apiGetWebhooks := api.GetWebhooks(getWebhooks, newLogger("getWebhooks"))
+	apiGetWebhookDeliveries := api.GetWebhookDeliveries(getWebhookDeliveries, newLogger("getWebhookDeliveries"))
+	apiRedeliverWebhook := api.RedeliverWebhook(redeliverWebhook, newLogger("redeliverWebhook"))
+
+	// Notifications
+	apiGetNotifications := api.GetNotifications(getNotifications, newLogger("getNotifications"))
+	apiGetUnreadCount := api.GetUnreadCount(getUnreadCount, newLogger("getUnreadCount"))
+	apiMarkNotificationsRead := api.MarkNotificationsRead(markNotificationsRead, newLogger("markNotificationsRead"))
+	apiGetNotificationPreferences := api.GetNotificationPreferences(getNotificationPreferences, newLogger("getNotificationPreferences"))
+	apiSaveNotificationPreferences := api.SaveNotificationPreferences(saveNotificationPreferences, newLogger("saveNotificationPreferences"))
 
 	/*
 		Database
 	**************************************/
 
-	dbSeed := infrastructure.NewDbSeed(regUser, userRepo)
+	dbSeed := infrastructure.NewDbSeed(regUser, userRepo, roleRepo)
 	dbSeed.Seed()
 
+	purgeJob := infrastructure.NewPurgeJob(planRepo, topicRepo, sourceRepo,
+		time.Duration(Cfg.Retention.DeletedDays)*24*time.Hour,
+		time.Duration(Cfg.Retention.PurgeIntervalMin)*time.Minute,
+		newLogger("purgeJob"))
+	purgeJob.Start()
+
+	rankingJob := infrastructure.NewRankingJob(pointsRepo, planRepo,
+		[]core.Ranker{
+			core.NewBayesianRanker(Cfg.Ranking.Prior, Cfg.Ranking.MinVotes),
+			core.NewWilsonRanker(Cfg.Ranking.WilsonZ),
+			core.NewHotRanker(time.Duration(Cfg.Ranking.HotHalfLifeHours) * time.Hour),
+			core.NewTrendingRanker(Cfg.Ranking.TrendingDays),
+		},
+		time.Duration(Cfg.Ranking.IntervalMin)*time.Minute,
+		newLogger("rankingJob"))
+	rankingJob.Start()
+
+	voteRingJob := infrastructure.NewVoteRingJob(pointsRepo,
+		infrastructure.VoteRingConf{
+			Interval:  time.Duration(Cfg.AntiFraud.RingIntervalMin) * time.Minute,
+			Lookback:  time.Duration(Cfg.AntiFraud.RingLookbackDays) * 24 * time.Hour,
+			Window:    time.Duration(Cfg.AntiFraud.RingWindowMin) * time.Minute,
+			MinShared: Cfg.AntiFraud.RingMinShared,
+			MinSize:   Cfg.AntiFraud.RingMinSize,
+		},
+		newLogger("voteRingJob"))
+	voteRingJob.Start()
+
+	emailDispatcher := infrastructure.NewEmailDispatcher(emailOutbox, initEmailTemplates(), initMailTransport(),
+		infrastructure.EmailDispatcherConf{
+			SiteHost:    Cfg.SiteHost,
+			FromEmail:   Cfg.SMTP.SenderEmail,
+			FromName:    Cfg.SMTP.SenderName,
+			Interval:    time.Duration(Cfg.Email.SendIntervalSec) * time.Second,
+			BatchSize:   Cfg.Email.BatchSize,
+			MaxAttempts: Cfg.Email.MaxAttempts,
+		},
+		newLogger("emails"))
+	emailDispatcher.Start()
+
+	notificationDigest := infrastructure.NewNotificationDigest(notificationRepo, userRepo,
+		time.Duration(Cfg.Notifications.DigestIntervalMin)*time.Minute,
+		Cfg.Notifications.DigestBatchSize,
+		newLogger("notificationDigest"))
+	notificationDigest.Start()
+
+	webhookDispatcher := infrastructure.NewWebhookDispatcher(webhookRepo,
+		infrastructure.WebhookDispatcherConf{
+			Interval:             time.Duration(Cfg.Webhooks.SendIntervalSec) * time.Second,
+			Timeout:              time.Duration(Cfg.Webhooks.TimeoutSec) * time.Second,
+			BatchSize:            Cfg.Webhooks.BatchSize,
+			MaxAttempts:          Cfg.Webhooks.MaxAttempts,
+			AllowPrivateNetworks: Cfg.Webhooks.AllowPrivateNetworks,
+		},
+		newLogger("webhooks"))
+	webhookDispatcher.Start()
+
 	/*
 		Http server
 	**************************************/
 	// for all
 	r.Group(func(r chi.Router) {
-		r.Use(api.Auth(domain.All, tokenService, newLogger("auth")))
+		r.Use(api.Auth(domain.All, tokenService, accessPolicy, newLogger("auth")))
 		r.Post("/api/topic/tree", apiGetTopicTree)
 		r.Post("/api/topic/get", apiGetTopic)
 		r.Post("/api/search", apiSearchTopic)
@@ -225,6 +458,7 @@ func main() {
 		r.Post("/api/plan/get", apiGetPlan)
 		r.Post("/api/plan/list", apiGetPlanList)
 		r.Post("/api/plan/tree", apiGetPlanTree)
+		r.Post("/api/plan/diff", apiGetPlanDiff)
 
 		r.Post("/api/user/registration", apiReqUser)
 		r.Post("/api/user/login", apiLoginUser)
@@ -241,27 +475,127 @@ func main() {
 
 	// for users
 	r.Group(func(r chi.Router) {
-		r.Use(api.Auth(domain.U, tokenService, newLogger("auth")))
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
 		r.Post("/api/source/add", apiAddSource)
 		r.Post("/api/topic/add", apiAddTopic)
 		r.Post("/api/plan/add", apiAddPlan)
 		r.Post("/api/plan/edit", apiEditPlan)
 		r.Post("/api/plan/list/user", apiGetListByUser)
 		r.Post("/api/plan/remove", apiRemovePlan)
+		r.Post("/api/plan/fork", apiForkPlan)
+		r.Post("/api/plan/suggestion/add", apiSuggestPlanEdit)
+		r.Post("/api/plan/suggestion/list", apiGetPlanSuggestions)
+		r.Post("/api/plan/suggestion/get", apiGetPlanSuggestion)
+		r.Post("/api/plan/suggestion/accept", apiAcceptPlanSuggestion)
+		r.Post("/api/plan/suggestion/reject", apiRejectPlanSuggestion)
+		r.Post("/api/plan/collaborator/invite", apiInvitePlanCollaborator)
+		r.Post("/api/plan/collaborator/list", apiGetPlanCollaborators)
+		r.Post("/api/plan/collaborator/remove", apiRemovePlanCollaborator)
+		r.Post("/api/plan/invitation/list", apiGetPlanInvitations)
+		r.Post("/api/plan/invitation/accept", apiAcceptPlanInvitation)
 		r.Post("/api/user/plan/favorite", apiAddUserPlan)
 		r.Post("/api/user/plan/unfavorite", apiRemoveAddUserPlan)
+		r.Post("/api/user/confirm/resend", apiResendConfirmation)
+		r.Post("/api/user/email/change", apiChangeEmail)
+		r.Post("/api/notification/list", apiGetNotifications)
+		r.Post("/api/notification/unread", apiGetUnreadCount)
+		r.Post("/api/notification/read", apiMarkNotificationsRead)
+		r.Post("/api/notification/preferences", apiGetNotificationPreferences)
+		r.Post("/api/notification/preferences/save", apiSaveNotificationPreferences)
 		r.Post("/api/comment/add", apiAddComment)
 		r.Post("/api/comment/edit", apiEditComment)
 		r.Post("/api/comment/delete", apiRemoveComment)
+		r.Post("/api/comment/revisions", apiGetCommentRevisions)
+		r.Post("/api/comment/react", apiToggleReaction)
+		r.Post("/api/comment/pin", apiPinComment)
+		r.Post("/api/comment/accept", apiAcceptAnswer)
 		r.Post("/api/points/add", apiAddPoints)
+		r.Post("/api/points/change", apiChangePoints)
+		r.Post("/api/points/remove", apiRemovePoints)
+		r.Post("/api/topic/remove", apiRemoveTopic)
+		r.Post("/api/source/remove", apiRemoveSource)
+		r.Post("/api/report/add", apiAddReport)
+		r.Post("/api/report/my", apiGetUserReports)
+	})
+
+	// event stream, token could be passed in query
+	r.Group(func(r chi.Router) {
+		r.Use(api.QueryToken)
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Get("/api/events", apiEvents)
 	})
 
 	// for moderators
 	r.Group(func(r chi.Router) {
-		r.Use(api.Auth(domain.M, tokenService, newLogger("auth")))
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.ReportModerate, newLogger("auth")))
+		r.Post("/api/report/queue", apiGetReports)
+		r.Post("/api/report/resolve", apiResolveReport)
+	})
+
+	// for content restore
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.ContentRestore, newLogger("auth")))
+		r.Post("/api/plan/restore", apiRestorePlan)
+		r.Post("/api/topic/restore", apiRestoreTopic)
+		r.Post("/api/source/restore", apiRestoreSource)
+		r.Post("/api/report/unhide", apiUnhideContent)
+	})
+
+	// for change log viewers
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.ChangeLogView, newLogger("auth")))
+		r.Post("/api/changelog", apiGetChangeLog)
+	})
+
+	// for webhook managers
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.WebhookOwn, newLogger("auth")))
+		r.Post("/api/webhook/add", apiAddWebhook)
+		r.Post("/api/webhook/edit", apiEditWebhook)
+		r.Post("/api/webhook/remove", apiRemoveWebhook)
+		r.Post("/api/webhook/list", apiGetWebhooks)
+		r.Post("/api/webhook/deliveries", apiGetWebhookDeliveries)
+		r.Post("/api/webhook/redeliver", apiRedeliverWebhook)
+	})
+
+	// for moderators
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.TopicEdit, newLogger("auth")))
+		r.Post("/api/topic/edit", apiEditTopic)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.TagManage, newLogger("auth")))
 		r.Post("/api/topic/tag/add", apiAddTopicTag)
 		r.Post("/api/topic/tag/remove", apiRemoveTopicTag)
-		r.Post("/api/topic/edit", apiEditTopic)
+	})
+
+	// for role managers
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.RoleManage, newLogger("auth")))
+		r.Post("/api/admin/role/list", apiListRoles)
+		r.Post("/api/admin/role/save", apiSaveRole)
+		r.Post("/api/admin/role/remove", apiRemoveRole)
+		r.Post("/api/admin/user/role/grant", apiGrantRole)
+		r.Post("/api/admin/user/role/revoke", apiRevokeRole)
+	})
+
+	// for user managers
+	r.Group(func(r chi.Router) {
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.UserManage, newLogger("auth")))
+		r.Post("/api/admin/user/search", apiSearchUsers)
+		r.Post("/api/admin/user/ban", apiBanUser)
+		r.Post("/api/admin/user/unban", apiUnbanUser)
+		r.Post("/api/admin/user/confirm", apiConfirmUserEmail)
+		r.Post("/api/admin/user/logout", apiLogoutUser)
 	})
 
 	// for development only
@@ -278,7 +612,8 @@ func main() {
 	apiListUsersDev := api.ListUsers(listUsersDev)
 
 	r.Group(func(r chi.Router) {
-		r.Use(api.Auth(domain.A, tokenService, newLogger("auth")))
+		r.Use(api.Auth(domain.U, tokenService, accessPolicy, newLogger("auth")))
+		r.Use(api.Permit(domain.DevTools, newLogger("auth")))
 		r.Post("/api/dev/list/topics", apiListTopicsDev)
 		r.Post("/api/dev/list/plans", apiListPlansDev)
 		r.Post("/api/dev/list/steps", apiListStepsDev)
@@ -324,6 +659,74 @@ func initTracer() *nptrace.NPTrace {
 	return nil
 }
 
+func initUnconfirmedRestrictions() []domain.Permission {
+	permissions := make([]domain.Permission, 0, len(Cfg.Confirmation.Restrict))
+	for _, v := range Cfg.Confirmation.Restrict {
+		p := domain.Permission(v)
+		if !p.IsValid() {
+			panic(fmt.Sprintf("unknown permission in confirmation restrictions: %s", v))
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions
+}
+
+func initReputationUnlocks() map[domain.Permission]int {
+	unlocks := make(map[domain.Permission]int, len(Cfg.Reputation.Unlock))
+	for k, v := range Cfg.Reputation.Unlock {
+		p := domain.Permission(k)
+		if !p.IsValid() {
+			panic(fmt.Sprintf("unknown permission in reputation unlocks: %s", k))
+		}
+		unlocks[p] = v
+	}
+	return unlocks
+}
+
+func initEventBackend(dbConnection *db.DbConnection) core.EventBackend {
+	switch Cfg.Events.Backend {
+	case "":
+		return nil
+	case "postgres":
+		return db.NewEventBackend(dbConnection)
+	default:
+		panic(fmt.Sprintf("unknown events backend: %s", Cfg.Events.Backend))
+	}
+}
+
+// eventsStreamDuration limits event stream by server write timeout, otherwise stream is broken by server
+func eventsStreamDuration() time.Duration {
+	duration := time.Duration(Cfg.Events.StreamDurationSec) * time.Second
+	if Cfg.HTTPServer.WriteTimeoutSec > 0 {
+		limit := time.Duration(Cfg.HTTPServer.WriteTimeoutSec)*time.Second - time.Second
+		if duration <= 0 || duration > limit {
+			duration = limit
+		}
+	}
+	return duration
+}
+
+func initEmailTemplates() *infrastructure.EmailTemplates {
+	templates, err := infrastructure.NewEmailTemplates(Cfg.Email.TemplatesPath, Cfg.Email.DefaultLang)
+	panicError(err)
+	return templates
+}
+
+func initMailTransport() infrastructure.MailTransport {
+	if Cfg.Email.SinkAddr == "" {
+		return infrastructure.NewSmtpTransport(Cfg.SMTP.Host, Cfg.SMTP.Port, Cfg.SMTP.SenderEmail, Cfg.SMTP.Pass)
+	}
+
+	sink, err := infrastructure.NewSmtpSink(Cfg.Email.SinkAddr)
+	panicError(err)
+	AppLog.Infow("Emails are sent to local smtp sink", "addr", sink.Addr())
+	host, port, err := net.SplitHostPort(sink.Addr())
+	panicError(err)
+	p, err := strconv.Atoi(port)
+	panicError(err)
+	return infrastructure.NewSmtpTransport(host, p, "", "")
+}
+
 func initConfig(dat []byte) *infrastructure.Config {
 	var cfg infrastructure.Config
 	err := json.Unmarshal(dat, &cfg)
@@ -0,0 +1,10 @@
+ALTER TABLE users
+    ADD COLUMN banned boolean NOT NULL DEFAULT False;
+
+ALTER TABLE users
+    ADD COLUMN banreason character varying(512) COLLATE pg_catalog."default";
+
+ALTER TABLE users
+    ADD COLUMN banneduntil timestamp without time zone;
+
+UPDATE roles SET permissions = array_append(permissions, 'user.manage') WHERE name = 'admin';
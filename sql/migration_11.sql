@@ -0,0 +1,59 @@
+-- hidden by moderator
+ALTER TABLE plans
+    ADD COLUMN hidden boolean NOT NULL DEFAULT False;
+
+ALTER TABLE comments
+    ADD COLUMN hidden boolean NOT NULL DEFAULT False;
+
+-- reports
+CREATE TABLE reports
+(
+    id bigserial NOT NULL,
+    entitytype integer NOT NULL,
+    entityid bigint NOT NULL,
+    status integer NOT NULL DEFAULT 0,
+    count integer NOT NULL DEFAULT 0,
+    created timestamp without time zone NOT NULL,
+    updated timestamp without time zone NOT NULL,
+    resolvedby character varying(36),
+    resolvedat timestamp without time zone,
+    comment character varying(512) COLLATE pg_catalog."default",
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+-- only one open report per entity, complaints are aggregated into it
+CREATE UNIQUE INDEX u_reports_open_entity
+    ON reports USING btree
+    (entitytype, entityid)
+    WHERE status = 0;
+
+CREATE INDEX ix_reports_status
+    ON reports USING btree
+    (status ASC NULLS LAST, count DESC);
+
+-- reports_complaints
+CREATE TABLE reports_complaints
+(
+    reportid bigint NOT NULL,
+    userid character varying(36) NOT NULL,
+    reason integer NOT NULL,
+    text character varying(512) COLLATE pg_catalog."default",
+    date timestamp without time zone NOT NULL,
+    PRIMARY KEY (reportid, userid)
+)
+WITH (
+    OIDS = FALSE
+);
+
+ALTER TABLE reports_complaints
+    ADD CONSTRAINT fk_reports_complaints_reportid FOREIGN KEY (reportid) REFERENCES reports(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE INDEX ix_reports_complaints_userid
+    ON reports_complaints USING btree
+    (userid ASC NULLS LAST);
+
+UPDATE roles SET permissions = array_append(permissions, 'report.add') WHERE name IN ('user', 'admin');
+UPDATE roles SET permissions = array_append(permissions, 'report.moderate') WHERE name IN ('moderator', 'admin');
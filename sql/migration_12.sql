@@ -0,0 +1,25 @@
+ALTER TABLE plans
+    ADD COLUMN deletedat timestamp without time zone;
+
+ALTER TABLE topics
+    ADD COLUMN deletedat timestamp without time zone;
+
+ALTER TABLE sources
+    ADD COLUMN deletedat timestamp without time zone;
+
+CREATE INDEX ix_plans_deletedat
+    ON plans USING btree
+    (deletedat ASC NULLS LAST)
+    WHERE deletedat IS NOT NULL;
+
+CREATE INDEX ix_topics_deletedat
+    ON topics USING btree
+    (deletedat ASC NULLS LAST)
+    WHERE deletedat IS NOT NULL;
+
+CREATE INDEX ix_sources_deletedat
+    ON sources USING btree
+    (deletedat ASC NULLS LAST)
+    WHERE deletedat IS NOT NULL;
+
+UPDATE roles SET permissions = array_cat(permissions, ARRAY['topic.remove', 'source.remove', 'content.restore']::character varying[]) WHERE name IN ('moderator', 'admin');
@@ -0,0 +1,31 @@
+ALTER TABLE changelog
+    ADD COLUMN snapshot text;
+
+ALTER TABLE changelog
+    ADD COLUMN reqid character varying(64) COLLATE pg_catalog."default";
+
+ALTER TABLE changelog
+    ADD COLUMN ip character varying(45) COLLATE pg_catalog."default";
+
+ALTER TABLE changelog
+    ADD COLUMN useragent character varying(512) COLLATE pg_catalog."default";
+
+CREATE INDEX ix_changelog_userid
+    ON changelog USING btree
+    (userid ASC NULLS LAST);
+
+CREATE INDEX ix_changelog_entity
+    ON changelog USING btree
+    (entitytype ASC NULLS LAST, entityid ASC NULLS LAST);
+
+CREATE INDEX ix_changelog_action
+    ON changelog USING btree
+    (action ASC NULLS LAST);
+
+-- deletions were logged as additions, the first AddX record of an entity is the real one
+-- AddPlan, AddTopic, AddProject and AddResource are followed by their DeleteX action
+UPDATE changelog c SET action = c.action + 2
+WHERE c.action IN (1, 4, 7, 10)
+    AND EXISTS (SELECT id FROM changelog WHERE action = c.action AND entitytype = c.entitytype AND entityid = c.entityid AND id < c.id);
+
+UPDATE roles SET permissions = array_append(permissions, 'changelog.view') WHERE name IN ('moderator', 'admin');
@@ -0,0 +1,28 @@
+CREATE TABLE emails
+(
+    id bigserial NOT NULL,
+    userid character varying(36) COLLATE pg_catalog."default",
+    recipient character varying(256) COLLATE pg_catalog."default" NOT NULL,
+    template character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    lang character varying(8) COLLATE pg_catalog."default",
+    data jsonb NOT NULL DEFAULT '{}',
+    status integer NOT NULL DEFAULT 0,
+    attempts integer NOT NULL DEFAULT 0,
+    nextattempt timestamp without time zone NOT NULL,
+    lasterror text COLLATE pg_catalog."default",
+    created timestamp without time zone NOT NULL,
+    sent timestamp without time zone,
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_emails_pending
+    ON emails USING btree
+    (nextattempt ASC NULLS LAST)
+    WHERE status = 0;
+
+CREATE INDEX ix_emails_userid
+    ON emails USING btree
+    (userid ASC NULLS LAST);
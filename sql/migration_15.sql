@@ -0,0 +1,9 @@
+ALTER TABLE users
+    ADD COLUMN emailconfirmationexpires timestamp without time zone,
+    ADD COLUMN emailconfirmationsent timestamp without time zone,
+    ADD COLUMN pendingemail character varying(128) COLLATE pg_catalog."default";
+
+-- links sent before expiry was introduced stay valid for a week
+UPDATE users
+    SET emailconfirmationexpires = now() + interval '7 days'
+    WHERE NOT emailconfirmed AND emailconfirmation <> '';
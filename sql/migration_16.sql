@@ -0,0 +1,38 @@
+CREATE TABLE notifications
+(
+    id bigserial NOT NULL,
+    userid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    type integer NOT NULL,
+    actorid character varying(36) COLLATE pg_catalog."default",
+    entitytype integer NOT NULL,
+    entityid bigint NOT NULL,
+    title character varying(256) COLLATE pg_catalog."default",
+    read boolean NOT NULL DEFAULT false,
+    emailed boolean NOT NULL DEFAULT false,
+    created timestamp without time zone NOT NULL,
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_notifications_userid
+    ON notifications USING btree
+    (userid ASC NULLS LAST, created DESC);
+
+CREATE INDEX ix_notifications_unread
+    ON notifications USING btree
+    (userid ASC NULLS LAST)
+    WHERE NOT read;
+
+CREATE TABLE notification_preferences
+(
+    userid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    type integer NOT NULL,
+    enabled boolean NOT NULL,
+    email boolean NOT NULL,
+    PRIMARY KEY (userid, type)
+)
+WITH (
+    OIDS = FALSE
+);
@@ -0,0 +1,49 @@
+CREATE TABLE webhooks
+(
+    id bigserial NOT NULL,
+    userid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    url character varying(2048) COLLATE pg_catalog."default" NOT NULL,
+    secret character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    events integer[] NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created timestamp without time zone NOT NULL,
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_webhooks_userid
+    ON webhooks USING btree
+    (userid ASC NULLS LAST);
+
+CREATE TABLE webhook_deliveries
+(
+    id bigserial NOT NULL,
+    webhookid bigint NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event integer NOT NULL,
+    payload text COLLATE pg_catalog."default" NOT NULL,
+    status integer NOT NULL DEFAULT 0,
+    attempts integer NOT NULL DEFAULT 0,
+    nextattempt timestamp without time zone NOT NULL,
+    responsecode integer,
+    lasterror text COLLATE pg_catalog."default",
+    created timestamp without time zone NOT NULL,
+    delivered timestamp without time zone,
+    redeliveryof bigint,
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_webhook_deliveries_pending
+    ON webhook_deliveries USING btree
+    (nextattempt ASC NULLS LAST)
+    WHERE status = 0;
+
+CREATE INDEX ix_webhook_deliveries_webhookid
+    ON webhook_deliveries USING btree
+    (webhookid ASC NULLS LAST, created DESC);
+
+UPDATE roles SET permissions = array_append(permissions, 'webhook.manage') WHERE name = 'admin';
@@ -0,0 +1,13 @@
+ALTER TABLE comments
+    ADD COLUMN html text COLLATE pg_catalog."default";
+
+ALTER TABLE steps
+    ADD COLUMN titlehtml text COLLATE pg_catalog."default";
+
+ALTER TABLE topics
+    ADD COLUMN descriptionhtml text COLLATE pg_catalog."default";
+
+-- texts were sanitized html before markdown, so they are shown as is
+UPDATE comments SET html = text;
+UPDATE steps SET titlehtml = title;
+UPDATE topics SET descriptionhtml = description;
@@ -0,0 +1,20 @@
+ALTER TABLE comments
+    ADD COLUMN pinned boolean NOT NULL DEFAULT false,
+    ADD COLUMN answerid bigint;
+
+CREATE INDEX ix_comments_threads
+    ON comments USING btree
+    (entitytype ASC NULLS LAST, entityid ASC NULLS LAST, pinned DESC, id DESC)
+    WHERE threadid IS NULL;
+
+CREATE TABLE comment_reactions
+(
+    commentid bigint NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    userid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    reaction character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    date timestamp without time zone NOT NULL,
+    PRIMARY KEY (commentid, userid, reaction)
+)
+WITH (
+    OIDS = FALSE
+);
@@ -0,0 +1,58 @@
+-- aggregates of comment votes were inserted into missing table, comments of every entity type get them now
+CREATE OR REPLACE FUNCTION create_aggregate_comments_points()
+  RETURNS trigger AS
+$BODY$
+BEGIN
+	INSERT INTO points_aggregated_comments (entityid,updatedate,count,value,avg)
+	VALUES (NEW.id, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+CREATE OR REPLACE FUNCTION aggregate_comments_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _avg double precision := 0;
+   _count INTEGER := 0;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	INSERT INTO points_aggregated_comments (entityid,updatedate,count,value,avg)
+	VALUES (NEW.entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	SELECT avg, count + 1 INTO _avg, _count
+	FROM points_aggregated_comments WHERE entityid = NEW.entityid LIMIT 1 FOR UPDATE;
+
+	UPDATE points_aggregated_comments
+	SET updatedate = now(),
+		count = _count,
+		avg = _avg + (NEW.value - _avg) / _count,
+		value = ((_avg + (NEW.value - _avg) / _count) * _count + 7 * 10) /  (_count + 10)
+	WHERE entityid = NEW.entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+INSERT INTO points_aggregated_comments (entityid, updatedate, count, value, avg)
+SELECT id, now(), 0, 0, 0 FROM comments
+ON CONFLICT (entityid) DO NOTHING;
+
+UPDATE points_aggregated_comments pa
+SET updatedate = now(),
+	count = p.count,
+	avg = p.avg,
+	value = (p.avg * p.count + 7 * 10) / (p.count + 10)
+FROM (SELECT entityid, count(*) AS count, avg(value) AS avg FROM points_comments GROUP BY entityid) p
+WHERE pa.entityid = p.entityid;
+
+CREATE INDEX ix_comments_entity_visible
+    ON comments USING btree
+    (entitytype ASC NULLS LAST, entityid ASC NULLS LAST)
+    WHERE deleted = false AND hidden = false;
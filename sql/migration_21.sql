@@ -0,0 +1,23 @@
+ALTER TABLE comments
+    ADD COLUMN editedat timestamp without time zone,
+    ADD COLUMN editedby character varying(36) COLLATE pg_catalog."default",
+    ADD COLUMN deletedby character varying(36) COLLATE pg_catalog."default";
+
+CREATE TABLE comment_revisions
+(
+    id bigserial NOT NULL,
+    commentid bigint NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    text text COLLATE pg_catalog."default" NOT NULL,
+    html text COLLATE pg_catalog."default",
+    title text COLLATE pg_catalog."default",
+    editorid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    date timestamp without time zone NOT NULL,
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_comment_revisions_commentid
+    ON comment_revisions USING btree
+    (commentid ASC NULLS LAST);
@@ -0,0 +1,123 @@
+-- votes can be changed and retracted, aggregates are recalculated from votes of entity
+CREATE OR REPLACE FUNCTION aggregate_plans_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_plans (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_plans WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_plans pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.count + 7 * 10) / (p.count + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(avg(value), 0) AS avg FROM points_plans WHERE entityid = _entityid) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_plans_trigger ON points_plans;
+CREATE TRIGGER aggregate_points_plans_trigger
+  AFTER INSERT OR UPDATE OF value OR DELETE
+  ON points_plans
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_plans_points();
+
+CREATE OR REPLACE FUNCTION aggregate_comments_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_comments (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_comments WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_comments pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.count + 7 * 10) / (p.count + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(avg(value), 0) AS avg FROM points_comments WHERE entityid = _entityid) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_comments_trigger ON points_comments;
+CREATE TRIGGER aggregate_points_comments_trigger
+  AFTER INSERT OR UPDATE OF value OR DELETE
+  ON points_comments
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_comments_points();
+
+CREATE OR REPLACE FUNCTION aggregate_projects_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_projects (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_projects WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_projects pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.count + 7 * 10) / (p.count + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(avg(value), 0) AS avg FROM points_projects WHERE entityid = _entityid) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_projects_trigger ON points_projects;
+CREATE TRIGGER aggregate_points_projects_trigger
+  AFTER INSERT OR UPDATE OF value OR DELETE
+  ON points_projects
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_projects_points();
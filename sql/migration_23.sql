@@ -0,0 +1,147 @@
+-- votes for topics and resources, there is no table for users: points_base.entityid is a number and user id is not
+
+-- TOPICS
+CREATE TABLE points_topics (LIKE points_base INCLUDING ALL) INHERITS (points_base);
+CREATE TABLE points_aggregated_topics (LIKE points_aggregated_base INCLUDING ALL) INHERITS (points_aggregated_base);
+
+CREATE OR REPLACE FUNCTION create_aggregate_topics_points()
+  RETURNS trigger AS
+$BODY$
+BEGIN
+	INSERT INTO points_aggregated_topics (entityid,updatedate,count,value,avg)
+	VALUES (NEW.id, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+CREATE OR REPLACE FUNCTION aggregate_topics_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_topics (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_topics WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_topics pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.count + 7 * 10) / (p.count + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(avg(value), 0) AS avg FROM points_topics WHERE entityid = _entityid) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+CREATE TRIGGER create_aggregate_points_topics_trigger
+  AFTER INSERT
+  ON topics
+  FOR EACH ROW
+  EXECUTE PROCEDURE create_aggregate_topics_points();
+
+CREATE TRIGGER aggregate_points_topics_trigger
+  AFTER INSERT OR UPDATE OF value OR DELETE
+  ON points_topics
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_topics_points();
+
+ALTER TABLE points_topics
+    ADD CONSTRAINT points_topics_entityid_topics_id FOREIGN KEY (entityid)
+    REFERENCES topics (id) MATCH SIMPLE
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+INSERT INTO points_aggregated_topics (entityid, updatedate, count, value, avg)
+SELECT id, now(), 0, 0, 0 FROM topics
+ON CONFLICT (entityid) DO NOTHING;
+
+-- RESOURCES
+CREATE TABLE points_resources (LIKE points_base INCLUDING ALL) INHERITS (points_base);
+CREATE TABLE points_aggregated_resources (LIKE points_aggregated_base INCLUDING ALL) INHERITS (points_aggregated_base);
+
+CREATE OR REPLACE FUNCTION create_aggregate_resources_points()
+  RETURNS trigger AS
+$BODY$
+BEGIN
+	INSERT INTO points_aggregated_resources (entityid,updatedate,count,value,avg)
+	VALUES (NEW.id, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+CREATE OR REPLACE FUNCTION aggregate_resources_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_resources (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_resources WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_resources pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.count + 7 * 10) / (p.count + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(avg(value), 0) AS avg FROM points_resources WHERE entityid = _entityid) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+CREATE TRIGGER create_aggregate_points_resources_trigger
+  AFTER INSERT
+  ON sources
+  FOR EACH ROW
+  EXECUTE PROCEDURE create_aggregate_resources_points();
+
+CREATE TRIGGER aggregate_points_resources_trigger
+  AFTER INSERT OR UPDATE OF value OR DELETE
+  ON points_resources
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_resources_points();
+
+ALTER TABLE points_resources
+    ADD CONSTRAINT points_resources_entityid_sources_id FOREIGN KEY (entityid)
+    REFERENCES sources (id) MATCH SIMPLE
+    ON UPDATE CASCADE
+    ON DELETE CASCADE;
+
+INSERT INTO points_aggregated_resources (entityid, updatedate, count, value, avg)
+SELECT id, now(), 0, 0, 0 FROM sources
+ON CONFLICT (entityid) DO NOTHING;
@@ -0,0 +1,19 @@
+-- scores of plans computed by ranking job, one row per ranking algorithm
+CREATE TABLE plan_scores
+(
+    planid integer NOT NULL,
+    sort character varying(16) NOT NULL,
+    score double precision NOT NULL,
+    updatedate timestamp without time zone NOT NULL,
+    PRIMARY KEY (planid, sort)
+)
+WITH (
+    OIDS = FALSE
+);
+
+ALTER TABLE plan_scores
+    ADD CONSTRAINT fk_plan_scores_planid FOREIGN KEY (planid) REFERENCES plans(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE INDEX ix_plan_scores_sort
+    ON plan_scores USING btree
+    (sort, score DESC);
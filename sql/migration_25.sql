@@ -0,0 +1,8 @@
+-- reputation is sum of points of changelog records of user
+ALTER TABLE users
+    ADD COLUMN reputation integer NOT NULL DEFAULT 0;
+
+CREATE INDEX ix_changelog_userid_points
+    ON changelog USING btree
+    (userid ASC NULLS LAST)
+    WHERE points <> 0;
@@ -0,0 +1,267 @@
+-- anti-abuse data of votes: address of voter, weight of vote by account age and email confirmation,
+-- flag of vote excluded by fraud detection. Columns are added to all inherited points tables
+ALTER TABLE points_base
+    ADD COLUMN ip character varying(45),
+    ADD COLUMN weight double precision NOT NULL DEFAULT 1,
+    ADD COLUMN flagged boolean NOT NULL DEFAULT false;
+
+-- accounts registered before this migration have no creation date and are treated as old
+ALTER TABLE users
+    ADD COLUMN created timestamp(0) without time zone;
+ALTER TABLE users
+    ALTER COLUMN created SET DEFAULT now();
+
+CREATE INDEX ix_points_plans_date
+    ON points_plans USING btree
+    (date ASC NULLS LAST);
+
+CREATE INDEX ix_points_plans_ip
+    ON points_plans USING btree
+    (ip ASC NULLS LAST);
+
+-- flagged votes are excluded, average is weighted
+CREATE OR REPLACE FUNCTION aggregate_plans_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_plans (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_plans WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_plans pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.weight + 7 * 10) / (p.weight + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(sum(weight), 0) AS weight, COALESCE(sum(value * weight) / NULLIF(sum(weight), 0), 0) AS avg
+		FROM points_plans WHERE entityid = _entityid AND flagged = false AND weight > 0) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_plans_trigger ON points_plans;
+CREATE TRIGGER aggregate_points_plans_trigger
+  AFTER INSERT OR UPDATE OF value, weight, flagged OR DELETE
+  ON points_plans
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_plans_points();
+
+CREATE INDEX ix_points_comments_date
+    ON points_comments USING btree
+    (date ASC NULLS LAST);
+
+CREATE INDEX ix_points_comments_ip
+    ON points_comments USING btree
+    (ip ASC NULLS LAST);
+
+-- flagged votes are excluded, average is weighted
+CREATE OR REPLACE FUNCTION aggregate_comments_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_comments (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_comments WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_comments pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.weight + 7 * 10) / (p.weight + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(sum(weight), 0) AS weight, COALESCE(sum(value * weight) / NULLIF(sum(weight), 0), 0) AS avg
+		FROM points_comments WHERE entityid = _entityid AND flagged = false AND weight > 0) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_comments_trigger ON points_comments;
+CREATE TRIGGER aggregate_points_comments_trigger
+  AFTER INSERT OR UPDATE OF value, weight, flagged OR DELETE
+  ON points_comments
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_comments_points();
+
+CREATE INDEX ix_points_projects_date
+    ON points_projects USING btree
+    (date ASC NULLS LAST);
+
+CREATE INDEX ix_points_projects_ip
+    ON points_projects USING btree
+    (ip ASC NULLS LAST);
+
+-- flagged votes are excluded, average is weighted
+CREATE OR REPLACE FUNCTION aggregate_projects_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_projects (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_projects WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_projects pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.weight + 7 * 10) / (p.weight + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(sum(weight), 0) AS weight, COALESCE(sum(value * weight) / NULLIF(sum(weight), 0), 0) AS avg
+		FROM points_projects WHERE entityid = _entityid AND flagged = false AND weight > 0) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_projects_trigger ON points_projects;
+CREATE TRIGGER aggregate_points_projects_trigger
+  AFTER INSERT OR UPDATE OF value, weight, flagged OR DELETE
+  ON points_projects
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_projects_points();
+
+CREATE INDEX ix_points_topics_date
+    ON points_topics USING btree
+    (date ASC NULLS LAST);
+
+CREATE INDEX ix_points_topics_ip
+    ON points_topics USING btree
+    (ip ASC NULLS LAST);
+
+-- flagged votes are excluded, average is weighted
+CREATE OR REPLACE FUNCTION aggregate_topics_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_topics (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_topics WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_topics pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.weight + 7 * 10) / (p.weight + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(sum(weight), 0) AS weight, COALESCE(sum(value * weight) / NULLIF(sum(weight), 0), 0) AS avg
+		FROM points_topics WHERE entityid = _entityid AND flagged = false AND weight > 0) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_topics_trigger ON points_topics;
+CREATE TRIGGER aggregate_points_topics_trigger
+  AFTER INSERT OR UPDATE OF value, weight, flagged OR DELETE
+  ON points_topics
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_topics_points();
+
+CREATE INDEX ix_points_resources_date
+    ON points_resources USING btree
+    (date ASC NULLS LAST);
+
+CREATE INDEX ix_points_resources_ip
+    ON points_resources USING btree
+    (ip ASC NULLS LAST);
+
+-- flagged votes are excluded, average is weighted
+CREATE OR REPLACE FUNCTION aggregate_resources_points()
+  RETURNS trigger AS
+$BODY$
+DECLARE
+   _entityid bigint;
+BEGIN
+    -- minVote = 10
+    -- avgConst = 7
+	IF TG_OP = 'DELETE' THEN
+		_entityid := OLD.entityid;
+	ELSE
+		_entityid := NEW.entityid;
+	END IF;
+
+	INSERT INTO points_aggregated_resources (entityid,updatedate,count,value,avg)
+	VALUES (_entityid, now(), 0, 0, 0 )
+	ON CONFLICT (entityid)
+	DO NOTHING;
+
+	-- lock aggregate before reading votes, so concurrent votes are counted after commit
+	PERFORM entityid FROM points_aggregated_resources WHERE entityid = _entityid FOR UPDATE;
+
+	UPDATE points_aggregated_resources pa
+	SET updatedate = now(),
+		count = p.count,
+		avg = p.avg,
+		value = CASE WHEN p.count = 0 THEN 0 ELSE (p.avg * p.weight + 7 * 10) / (p.weight + 10) END
+	FROM (SELECT count(*) AS count, COALESCE(sum(weight), 0) AS weight, COALESCE(sum(value * weight) / NULLIF(sum(weight), 0), 0) AS avg
+		FROM points_resources WHERE entityid = _entityid AND flagged = false AND weight > 0) p
+	WHERE pa.entityid = _entityid;
+	RETURN NULL;
+END;
+$BODY$
+LANGUAGE plpgsql VOLATILE;
+
+DROP TRIGGER IF EXISTS aggregate_points_resources_trigger ON points_resources;
+CREATE TRIGGER aggregate_points_resources_trigger
+  AFTER INSERT OR UPDATE OF value, weight, flagged OR DELETE
+  ON points_resources
+  FOR EACH ROW
+  EXECUTE PROCEDURE aggregate_resources_points();
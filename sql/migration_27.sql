@@ -0,0 +1,8 @@
+-- plans can be forked, fork keeps id of plan it was copied from
+ALTER TABLE plans
+    ADD COLUMN parentid integer REFERENCES plans (id) ON DELETE SET NULL;
+
+CREATE INDEX ix_plans_parentid
+    ON plans USING btree
+    (parentid ASC NULLS LAST)
+    WHERE parentid IS NOT NULL;
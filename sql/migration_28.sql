@@ -0,0 +1,27 @@
+-- changes of plans proposed by other users, reviewed by owner of plan
+CREATE TABLE plan_suggestions
+(
+    id bigserial NOT NULL,
+    planid integer NOT NULL,
+    userid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    title character varying(256) COLLATE pg_catalog."default" NOT NULL,
+    steps jsonb NOT NULL,
+    message text COLLATE pg_catalog."default",
+    status smallint NOT NULL DEFAULT 0,
+    date timestamp without time zone NOT NULL,
+    reviewedby character varying(36) COLLATE pg_catalog."default",
+    reviewedat timestamp without time zone,
+    comment text COLLATE pg_catalog."default",
+    PRIMARY KEY (id),
+    CONSTRAINT fk_plan_suggestions_planid FOREIGN KEY (planid) REFERENCES plans (id) ON DELETE CASCADE
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_plan_suggestions_pending
+    ON plan_suggestions USING btree
+    (planid ASC NULLS LAST, date DESC)
+    WHERE status = 0;
+
+UPDATE roles SET permissions = array_append(permissions, 'plan.suggest') WHERE name IN ('user', 'admin');
@@ -0,0 +1,19 @@
+-- users invited by owner to edit plan or to see it while it is a draft
+CREATE TABLE plan_collaborators
+(
+    planid integer NOT NULL,
+    userid character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    role smallint NOT NULL,
+    invitedby character varying(36) COLLATE pg_catalog."default" NOT NULL,
+    accepted boolean NOT NULL DEFAULT false,
+    date timestamp without time zone NOT NULL,
+    PRIMARY KEY (planid, userid),
+    CONSTRAINT fk_plan_collaborators_planid FOREIGN KEY (planid) REFERENCES plans (id) ON DELETE CASCADE
+)
+WITH (
+    OIDS = FALSE
+);
+
+CREATE INDEX ix_plan_collaborators_userid
+    ON plan_collaborators USING btree
+    (userid COLLATE pg_catalog."default" ASC NULLS LAST);
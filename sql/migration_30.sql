@@ -0,0 +1,6 @@
+-- version is incremented by every update, edit is rejected when client changed stale version
+ALTER TABLE plans ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE topics ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE comments ADD COLUMN version integer NOT NULL DEFAULT 1;
+-- projects are not stored yet
+ALTER TABLE IF EXISTS projects ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- estimated time in minutes, difficulty and notes of plan steps, optional steps are not counted in required time of plan
+ALTER TABLE steps ADD COLUMN duration integer NOT NULL DEFAULT 0;
+ALTER TABLE steps ADD COLUMN difficulty character varying(16) COLLATE pg_catalog."default";
+ALTER TABLE steps ADD COLUMN optional boolean NOT NULL DEFAULT false;
+ALTER TABLE steps ADD COLUMN notes text COLLATE pg_catalog."default";
+ALTER TABLE steps ADD COLUMN noteshtml text COLLATE pg_catalog."default";
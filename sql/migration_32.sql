@@ -0,0 +1,2 @@
+-- id of changed entity which is not a number, like user id
+ALTER TABLE changelog ADD COLUMN entitykey character varying(36) COLLATE pg_catalog."default";
@@ -0,0 +1,7 @@
+-- deleted sources keep their identifier until purge, so the same resource could be added again
+ALTER TABLE sources DROP CONSTRAINT u_sources_normalizedidentifier;
+
+CREATE UNIQUE INDEX u_sources_normalizedidentifier
+    ON sources USING btree
+    (normalizedidentifier)
+    WHERE deletedat IS NULL;
@@ -0,0 +1,4 @@
+-- every user could add webhooks receiving their own changes, webhook.manage gives access to all changes
+ALTER TABLE webhooks ADD COLUMN ownchanges boolean NOT NULL DEFAULT false;
+
+UPDATE roles SET permissions = array_append(permissions, 'webhook.own') WHERE name = 'user';
@@ -0,0 +1,4 @@
+-- version of plan the changes were proposed to, accept of suggestion made before the plan was edited is a conflict
+ALTER TABLE plan_suggestions ADD COLUMN planversion integer NOT NULL DEFAULT 1;
+
+UPDATE plan_suggestions s SET planversion = p.version FROM plans p WHERE p.id = s.planid;
@@ -0,0 +1,2 @@
+-- featured plans are not implemented, permission is not checked anywhere
+UPDATE roles SET permissions = array_remove(permissions, 'plan.feature');
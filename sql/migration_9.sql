@@ -0,0 +1,44 @@
+-- roles
+CREATE TABLE roles
+(
+    id serial NOT NULL,
+    name character varying(64) NOT NULL,
+    permissions character varying(64)[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+);
+
+ALTER TABLE roles
+    ADD CONSTRAINT u_roles_name UNIQUE (name);
+
+-- users_roles
+CREATE TABLE users_roles
+(
+    userid character varying(36) NOT NULL,
+    roleid integer NOT NULL,
+    PRIMARY KEY (userid, roleid)
+)
+WITH (
+    OIDS = FALSE
+);
+
+ALTER TABLE users_roles
+    ADD CONSTRAINT fk_users_roles_userid FOREIGN KEY (userid) REFERENCES users(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE users_roles
+    ADD CONSTRAINT fk_users_roles_roleid FOREIGN KEY (roleid) REFERENCES roles(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+-- built-in roles
+INSERT INTO roles (name, permissions) VALUES
+    ('user', '{topic.add,source.add,plan.add,comment.add,points.add}'),
+    ('moderator', '{topic.edit,tag.manage,plan.edit,plan.remove,plan.feature,comment.moderate}'),
+    ('admin', '{topic.add,topic.edit,tag.manage,source.add,plan.add,plan.edit,plan.remove,plan.feature,comment.add,comment.moderate,points.add,role.manage,dev.tools}');
+
+-- move rights bitmask to roles: M = 4, A = 8
+INSERT INTO users_roles (userid, roleid)
+    SELECT u.id, r.id FROM users u, roles r WHERE u.rights & 4 > 0 AND r.name = 'moderator';
+
+INSERT INTO users_roles (userid, roleid)
+    SELECT u.id, r.id FROM users u, roles r WHERE u.rights & 8 > 0 AND r.name = 'admin';
@@ -0,0 +1,5 @@
+{
+  "subject": "Confirm your new email on Roadmaps",
+  "html": "<!DOCTYPE html><html><body>Hello!<p>This address was specified as the new email of your Roadmaps Account.<br/></p><p>To confirm the change, click the link below:<br/><a href=\"{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\">Confirm email</a></p><p>If you did not request the change, just ignore this email.</p><p><a href=\"{{.Host}}\">{{.Host}}</a></p></body>\n\t</html>",
+  "text": "Hello!\n\nThis address was specified as the new email of your Roadmaps Account.\n\nTo confirm the change, open the link below:\n{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\n\nIf you did not request the change, just ignore this email.\n\n{{.Host}}\n"
+}
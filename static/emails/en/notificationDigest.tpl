@@ -0,0 +1,5 @@
+{
+  "subject": "You have {{.Count}} new notifications on Roadmaps",
+  "html": "<!DOCTYPE html><html><body>Hello!<p>Here is what happened since the last digest:</p><ul>{{if ne .Replies \"0\"}}<li>Replies to your comments: {{.Replies}}</li>{{end}}{{if ne .Comments \"0\"}}<li>Comments on your plans: {{.Comments}}</li>{{end}}{{if ne .Votes \"0\"}}<li>Votes for your plans: {{.Votes}}</li>{{end}}{{if ne .ChangedPlans \"0\"}}<li>Changes in favorite plans: {{.ChangedPlans}}</li>{{end}}</ul>{{if .Titles}}<p>Plans: {{.Titles}}</p>{{end}}<p><a href=\"{{.Host}}\">{{.Host}}</a></p><p>You can turn off the digest in notification settings.</p></body>\n\t</html>",
+  "text": "Hello!\n\nHere is what happened since the last digest:\n{{if ne .Replies \"0\"}}- Replies to your comments: {{.Replies}}\n{{end}}{{if ne .Comments \"0\"}}- Comments on your plans: {{.Comments}}\n{{end}}{{if ne .Votes \"0\"}}- Votes for your plans: {{.Votes}}\n{{end}}{{if ne .ChangedPlans \"0\"}}- Changes in favorite plans: {{.ChangedPlans}}\n{{end}}{{if .Titles}}\nPlans: {{.Titles}}\n{{end}}\n{{.Host}}\n\nYou can turn off the digest in notification settings.\n"
+}
@@ -0,0 +1,5 @@
+{
+  "subject": "Registration on Roadmaps!",
+  "html": "<!DOCTYPE html><html><body>Hello!<p>Thank you for creating your Roadmaps Account.<br/></p><p>To complete your registration, click the link below:<br/><a href=\"{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\">Confirm your account</a></p><p><a href=\"{{.Host}}\">{{.Host}}</a></p></body>\n\t</html>",
+  "text": "Hello!\n\nThank you for creating your Roadmaps Account.\n\nTo complete your registration, open the link below:\n{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\n\n{{.Host}}\n"
+}
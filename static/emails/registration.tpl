@@ -1,4 +0,0 @@
-{
-  "subject": "Registration on Roadmaps!",
-  "body": "<!DOCTYPE html><html><body>Hello!<p>Thank you for creating your Roadmaps Account.<br/></p><p>To complete your registration, click the link below:<br/><a href=\"{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\">Confirm your account</a></p><p><a href=\"{{.Host}}\">{{.Host}}</a></p></body>\n\t</html>"
-}
\ No newline at end of file
@@ -0,0 +1,5 @@
+{
+  "subject": "Подтверждение нового адреса на Roadmaps",
+  "html": "<!DOCTYPE html><html><body>Здравствуйте!<p>Этот адрес был указан как новый email вашего аккаунта на Roadmaps.<br/></p><p>Чтобы подтвердить изменение, перейдите по ссылке:<br/><a href=\"{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\">Подтвердить email</a></p><p>Если вы не запрашивали изменение, просто проигнорируйте это письмо.</p><p><a href=\"{{.Host}}\">{{.Host}}</a></p></body>\n\t</html>",
+  "text": "Здравствуйте!\n\nЭтот адрес был указан как новый email вашего аккаунта на Roadmaps.\n\nЧтобы подтвердить изменение, перейдите по ссылке:\n{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\n\nЕсли вы не запрашивали изменение, просто проигнорируйте это письмо.\n\n{{.Host}}\n"
+}
@@ -0,0 +1,5 @@
+{
+  "subject": "Новых уведомлений на Roadmaps: {{.Count}}",
+  "html": "<!DOCTYPE html><html><body>Здравствуйте!<p>Вот что произошло с момента последней рассылки:</p><ul>{{if ne .Replies \"0\"}}<li>Ответы на ваши комментарии: {{.Replies}}</li>{{end}}{{if ne .Comments \"0\"}}<li>Комментарии к вашим планам: {{.Comments}}</li>{{end}}{{if ne .Votes \"0\"}}<li>Оценки ваших планов: {{.Votes}}</li>{{end}}{{if ne .ChangedPlans \"0\"}}<li>Изменения в избранных планах: {{.ChangedPlans}}</li>{{end}}</ul>{{if .Titles}}<p>Планы: {{.Titles}}</p>{{end}}<p><a href=\"{{.Host}}\">{{.Host}}</a></p><p>Рассылку можно отключить в настройках уведомлений.</p></body>\n\t</html>",
+  "text": "Здравствуйте!\n\nВот что произошло с момента последней рассылки:\n{{if ne .Replies \"0\"}}- Ответы на ваши комментарии: {{.Replies}}\n{{end}}{{if ne .Comments \"0\"}}- Комментарии к вашим планам: {{.Comments}}\n{{end}}{{if ne .Votes \"0\"}}- Оценки ваших планов: {{.Votes}}\n{{end}}{{if ne .ChangedPlans \"0\"}}- Изменения в избранных планах: {{.ChangedPlans}}\n{{end}}{{if .Titles}}\nПланы: {{.Titles}}\n{{end}}\n{{.Host}}\n\nРассылку можно отключить в настройках уведомлений.\n"
+}
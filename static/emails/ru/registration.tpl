@@ -0,0 +1,5 @@
+{
+  "subject": "Регистрация на Roadmaps!",
+  "html": "<!DOCTYPE html><html><body>Здравствуйте!<p>Спасибо за регистрацию на Roadmaps.<br/></p><p>Чтобы завершить регистрацию, перейдите по ссылке:<br/><a href=\"{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\">Подтвердить аккаунт</a></p><p><a href=\"{{.Host}}\">{{.Host}}</a></p></body>\n\t</html>",
+  "text": "Здравствуйте!\n\nСпасибо за регистрацию на Roadmaps.\n\nЧтобы завершить регистрацию, перейдите по ссылке:\n{{.Host}}/s/confirm?u={{.UserId}}&s={{.Secret}}\n\n{{.Host}}\n"
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type changeLogRepoForTests struct {
+	records []domain.ChangeLogRecord
+}
+
+func (r *changeLogRepoForTests) Add(record *domain.ChangeLogRecord) bool {
+	r.records = append(r.records, *record)
+	return true
+}
+
+func (r *changeLogRepoForTests) Award(record *domain.ChangeLogRecord) bool {
+	r.records = append(r.records, *record)
+	return true
+}
+
+func (r *changeLogRepoForTests) CountAwards(userId string, entityType domain.EntityType, since time.Time) int {
+	count := 0
+	for _, record := range r.records {
+		if record.Action == domain.AwardReputation && record.UserId == userId && record.EntityType == entityType {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *changeLogRepoForTests) Search(ctx core.ReqContext, filter domain.ChangeLogFilter, count int, page int) []domain.ChangeLogRecord {
+	return r.records
+}
+
+func newChangeLogContext() core.ReqContext {
+	ctx := context.WithValue(context.Background(), infrastructure.ReqUserId, "user")
+	ctx = context.WithValue(ctx, infrastructure.ReqId, "req-1")
+	ctx = context.WithValue(ctx, infrastructure.ReqClientIP, "127.0.0.1")
+	ctx = context.WithValue(ctx, infrastructure.ReqUserAgent, "tests")
+	return infrastructure.NewContext(ctx)
+}
+
+func TestChangesCollectorDeleted(t *testing.T) {
+	repo := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(repo, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+
+	plan := &domain.Plan{Id: 7, Title: "Go", TopicName: "golang", OwnerId: "user"}
+	changeLog.Deleted(newChangeLogContext(), domain.PlanEntity, 7, plan)
+
+	if len(repo.records) != 1 {
+		t.Fatalf("Expected one record, got %d", len(repo.records))
+	}
+	record := repo.records[0]
+	if record.Action != domain.DeletePlan {
+		t.Errorf("Expected action %v, got %v", domain.DeletePlan, record.Action)
+	}
+	if record.UserId != "user" || record.ReqId != "req-1" || record.IP != "127.0.0.1" || record.UserAgent != "tests" {
+		t.Errorf("Request context not saved: %+v", record)
+	}
+
+	snapshot := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(record.Snapshot), &snapshot); err != nil {
+		t.Fatalf("Snapshot is not json: %v", err)
+	}
+	if snapshot["Title"] != "Go" {
+		t.Errorf("Unexpected snapshot: %s", record.Snapshot)
+	}
+}
+
+func TestChangesCollectorRestored(t *testing.T) {
+	repo := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(repo, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+
+	changeLog.Restored(newChangeLogContext(), domain.TopicEntity, 3)
+
+	if len(repo.records) != 1 || repo.records[0].Action != domain.RestoreTopic {
+		t.Errorf("Unexpected records: %+v", repo.records)
+	}
+}
+
+func TestChangesCollectorNotifiesPlanChanged(t *testing.T) {
+	repo := &changeLogRepoForTests{}
+	notifier := &notifierForTests{}
+	changeLog := infrastructure.NewChangesCollector(repo, notifier, &webhookPublisherForTests{}, &appLoggerForTests{})
+
+	before := &domain.Plan{Id: 7, Title: "Go"}
+	after := &domain.Plan{Id: 7, Title: "Golang"}
+	changeLog.Edited(newChangeLogContext(), domain.PlanEntity, 7, before, after)
+
+	if len(notifier.changedPlans) != 1 || notifier.changedPlans[0] != 7 {
+		t.Errorf("Plan change not notified: %v", notifier.changedPlans)
+	}
+}
+
+func TestChangesCollectorUserEdited(t *testing.T) {
+	repo := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(repo, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+
+	before := &domain.User{Id: "u-1", Name: "Alice", Roles: []string{"user", "admin"}}
+	after := &domain.User{Id: "u-1", Name: "Alice", Roles: []string{"user", "moderator"}}
+	changeLog.Edited(newChangeLogContext(), domain.UserEntity, 0, before, after)
+
+	if len(repo.records) != 1 || repo.records[0].EntityKey != "u-1" {
+		t.Fatalf("Expected edit of user logged with its id, got %+v", repo.records)
+	}
+	if before.Roles[0] != "user" || after.Roles[1] != "moderator" {
+		t.Errorf("Expected roles of users not reordered, got %v and %v", before.Roles, after.Roles)
+	}
+}
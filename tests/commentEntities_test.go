@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type topicRepoForTests struct {
+	core.TopicRepository
+	topics map[int]*domain.Topic
+}
+
+func (r *topicRepoForTests) Get(ctx core.ReqContext, name string) *domain.Topic {
+	for _, t := range r.topics {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func (r *topicRepoForTests) GetById(ctx core.ReqContext, id int) *domain.Topic {
+	return r.topics[id]
+}
+
+type sourceRepoForTests struct {
+	core.SourceRepository
+	sources map[int64]*domain.Source
+}
+
+func (r *sourceRepoForTests) Get(ctx core.ReqContext, id int64) *domain.Source {
+	return r.sources[id]
+}
+
+type projectsRepoForTests struct {
+	core.ProjectsRepository
+	projects map[int]*domain.Project
+}
+
+func (r *projectsRepoForTests) Get(ctx core.ReqContext, id int) *domain.Project {
+	return r.projects[id]
+}
+
+func (r *threadsRepoForTests) Add(ctx core.ReqContext, comment *domain.Comment) (bool, error) {
+	comment.Id = int64(len(r.comments) + 100)
+	c := *comment
+	r.comments[comment.Id] = &c
+	return true, nil
+}
+
+func (r *threadsRepoForTests) Count(ctx core.ReqContext, entityType domain.EntityType, entityIds []int64) map[int64]int {
+	result := make(map[int64]int)
+	for _, id := range entityIds {
+		for _, c := range r.comments {
+			if c.EntityType == entityType && c.EntityId == id && !c.Deleted && !c.Hidden {
+				result[id]++
+			}
+		}
+	}
+	return result
+}
+
+func TestAddCommentToEntities(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	plans := &planRepoForTests{plans: map[int]*domain.Plan{1: {Id: 1, OwnerId: "owner"}}}
+	topics := &topicRepoForTests{topics: map[int]*domain.Topic{2: {Id: 2, Name: "golang"}}}
+	sources := &sourceRepoForTests{sources: map[int64]*domain.Source{3: {Id: 3}}}
+	usecase := usecases.NewAddComment(repo, plans, topics, sources, changeLog, &notifierForTests{}, broadcaster, newMarkdownRendererForTests(), appLoggerForTests{})
+	ctx := newPermissionsContext("author", domain.CommentAdd)
+
+	existing := map[domain.EntityType]int64{
+		domain.PlanEntity:     1,
+		domain.TopicEntity:    2,
+		domain.ResourceEntity: 3,
+	}
+	for entityType, id := range existing {
+		if _, err := usecase.Do(ctx, entityType, id, 0, "text", "title"); err != nil {
+			t.Errorf("Expected comment added to %s, got %v", domain.EntityTypeToString(entityType), err)
+		}
+		if _, err := usecase.Do(ctx, entityType, id+10, 0, "text", "title"); err == nil {
+			t.Errorf("Expected error for missing %s", domain.EntityTypeToString(entityType))
+		}
+	}
+
+	if _, err := usecase.Do(ctx, domain.UserEntity, 1, 0, "text", "title"); err == nil {
+		t.Error("Expected error for comment to user")
+	}
+	_, err := usecase.Do(ctx, domain.ProjectEntity, 4, 0, "text", "title")
+	if appErr, ok := err.(*core.AppError); !ok || appErr.Validation["entityId"] != core.InvalidValue.String() {
+		t.Errorf("Expected validation error for comment to project, got %v", err)
+	}
+}
+
+func TestGetTopicCommentsCount(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	repo.comments[30] = &domain.Comment{Id: 30, EntityType: domain.TopicEntity, EntityId: 2}
+	repo.comments[31] = &domain.Comment{Id: 31, EntityType: domain.TopicEntity, EntityId: 2, ThreadId: 30, ParentId: 30}
+	repo.comments[32] = &domain.Comment{Id: 32, EntityType: domain.TopicEntity, EntityId: 2, ThreadId: 30, ParentId: 30, Deleted: true}
+	topics := &topicRepoForTests{topics: map[int]*domain.Topic{2: {Id: 2, Name: "golang"}}}
+	usecase := usecases.NewGetTopic(topics, &planRepoForTests{}, &usersPlanRepoForTests{}, repo, &pointsRepoForTests{}, appLoggerForTests{})
+
+	topic, err := usecase.Do(newUserContext("reader"), "golang", 10)
+	if err != nil || topic.Comments != 2 {
+		t.Errorf("Expected count of visible comments, got %+v %v", topic, err)
+	}
+}
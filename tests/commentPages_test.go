@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+type pagesRepoForTests struct {
+	threadsRepoForTests
+	page  []domain.Comment
+	next  *domain.CommentCursor
+	sort  domain.CommentSort
+	after *domain.CommentCursor
+	count int
+}
+
+func (r *pagesRepoForTests) GetThreadList(ctx core.ReqContext, entityType int, entityId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor) {
+	r.sort, r.after, r.count = sort, after, count
+	return r.page, r.next
+}
+
+func (r *pagesRepoForTests) GetThread(ctx core.ReqContext, entityType int, entityId int64, threadId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor) {
+	r.sort, r.after, r.count = sort, after, count
+	return r.page, r.next
+}
+
+type usersListRepoForTests struct {
+	core.UserRepository
+}
+
+func (r *usersListRepoForTests) GetList(ctx core.ReqContext, id []string) []domain.User {
+	return []domain.User{{Id: "author", Name: "Author"}, {Id: "replier", Name: "Replier"}}
+}
+
+func TestCommentCursorRoundTrip(t *testing.T) {
+	cursor := &domain.CommentCursor{Sort: domain.CommentSortTop, Pinned: true, Key: 42, Id: 7}
+	decoded, err := core.DecodeCommentCursor(core.EncodeCommentCursor(cursor))
+	if err != nil || *decoded != *cursor {
+		t.Errorf("Expected same cursor after decoding, got %+v %v", decoded, err)
+	}
+
+	for _, s := range []string{"", "abc", "e30", core.EncodeCommentCursor(&domain.CommentCursor{Sort: "best", Id: 1})} {
+		if _, err := core.DecodeCommentCursor(s); err == nil {
+			t.Errorf("Expected error for cursor %q", s)
+		}
+	}
+
+	if core.EncodeCommentCursor(nil) != "" {
+		t.Error("Expected empty string for last page")
+	}
+}
+
+func TestGetCommentsThreadsPage(t *testing.T) {
+	repo := &pagesRepoForTests{
+		page: []domain.Comment{{Id: 20, UserId: "author", Pinned: true}, {Id: 10, UserId: "replier", Replies: 2}},
+		next: &domain.CommentCursor{Sort: domain.CommentSortReplies, Key: 2, Id: 10},
+	}
+	usecase := usecases.NewGetCommentsThreads(repo, &usersListRepoForTests{}, appLoggerForTests{})
+	ctx := newUserContext("reader")
+
+	comments, next, err := usecase.Do(ctx, domain.PlanEntity, 1, domain.CommentSortReplies, "", 2)
+	if err != nil || len(comments) != 2 || next == "" {
+		t.Fatalf("Expected page with cursor, got %d %q %v", len(comments), next, err)
+	}
+	if comments[0].User == nil || comments[0].User.Name != "Author" || comments[1].User.Name != "Replier" {
+		t.Errorf("Expected users attached to comments, got %+v %+v", comments[0].User, comments[1].User)
+	}
+
+	if _, _, err := usecase.Do(ctx, domain.PlanEntity, 1, domain.CommentSortReplies, next, 2); err != nil || repo.after == nil || repo.after.Id != 10 {
+		t.Errorf("Expected next page after cursor, got %+v %v", repo.after, err)
+	}
+
+	if _, _, err := usecase.Do(ctx, domain.PlanEntity, 1, domain.CommentSortTop, next, 2); err == nil {
+		t.Error("Expected error for cursor of other sort mode")
+	}
+	if _, _, err := usecase.Do(ctx, domain.PlanEntity, 1, domain.CommentSort("best"), "", 2); err == nil {
+		t.Error("Expected error for unknown sort mode")
+	}
+	if _, _, err := usecase.Do(ctx, domain.PlanEntity, 1, "", "", 2); err != nil || repo.sort != domain.CommentSortNewest {
+		t.Errorf("Expected newest threads by default, got %q %v", repo.sort, err)
+	}
+}
+
+func TestGetCommentsThreadNestsPage(t *testing.T) {
+	repo := &pagesRepoForTests{page: []domain.Comment{
+		{Id: 14, ParentId: 10, ThreadId: 10, UserId: "replier"},
+		{Id: 15, ParentId: 14, ThreadId: 10, UserId: "author"},
+		{Id: 11, ParentId: 10, ThreadId: 10, UserId: "replier"},
+		{Id: 16, ParentId: 12, ThreadId: 10, UserId: "author"},
+		{Id: 17, ParentId: 14, ThreadId: 10, UserId: "replier"},
+	}}
+	usecase := usecases.NewGetCommentsThread(repo, &usersListRepoForTests{}, appLoggerForTests{})
+
+	comments, next, err := usecase.Do(newUserContext("reader"), domain.PlanEntity, 1, 10, domain.CommentSortNewest, "", 0)
+	if err != nil || next != "" {
+		t.Fatalf("Expected last page, got %q %v", next, err)
+	}
+
+	if len(comments) != 3 || comments[0].Id != 14 || comments[1].Id != 11 || comments[2].Id != 16 {
+		t.Fatalf("Expected top level replies in order of page, got %+v", comments)
+	}
+	if len(comments[0].Childs) != 2 || comments[0].Childs[0].Id != 15 || comments[0].Childs[1].Id != 17 {
+		t.Errorf("Expected nested replies in order of page, got %+v", comments[0].Childs)
+	}
+	if comments[0].Childs[0].User == nil || comments[0].Childs[0].User.Name != "Author" {
+		t.Errorf("Expected users attached to nested replies, got %+v", comments[0].Childs[0].User)
+	}
+	if repo.count != 500 {
+		t.Errorf("Expected max page of replies requested by default, got %d", repo.count)
+	}
+}
+
+// threadRepliesRepoForTests returns replies of thread by pages, oldest first
+type threadRepliesRepoForTests struct {
+	threadsRepoForTests
+	replies []domain.Comment
+}
+
+func (r *threadRepliesRepoForTests) GetThread(ctx core.ReqContext, entityType int, entityId int64, threadId int64, sort domain.CommentSort, after *domain.CommentCursor, count int) ([]domain.Comment, *domain.CommentCursor) {
+	start := 0
+	if after != nil {
+		for start < len(r.replies) && r.replies[start].Id <= after.Id {
+			start++
+		}
+	}
+	end := start + count
+	if end >= len(r.replies) {
+		return r.replies[start:], nil
+	}
+	return r.replies[start:end], &domain.CommentCursor{Sort: sort, Key: r.replies[end-1].Id, Id: r.replies[end-1].Id}
+}
+
+func TestGetCommentsThreadReadsAllPages(t *testing.T) {
+	repo := &threadRepliesRepoForTests{}
+	for id := int64(11); id <= 17; id++ {
+		repo.replies = append(repo.replies, domain.Comment{Id: id, ParentId: 10, ThreadId: 10, UserId: "replier"})
+	}
+	usecase := usecases.NewGetCommentsThread(repo, &usersListRepoForTests{}, appLoggerForTests{})
+
+	var ids []int64
+	pages := 0
+	cursor := ""
+	for {
+		comments, next, err := usecase.Do(newUserContext("reader"), domain.PlanEntity, 1, 10, "", cursor, 3)
+		if err != nil {
+			t.Fatalf("Page not returned: %v", err)
+		}
+		if len(comments) > 3 {
+			t.Fatalf("Expected page of 3 replies, got %d", len(comments))
+		}
+		for _, c := range comments {
+			ids = append(ids, c.Id)
+		}
+		pages++
+		if next == "" || pages > 3 {
+			break
+		}
+		cursor = next
+	}
+
+	if pages != 3 || len(ids) != 7 || ids[0] != 11 || ids[6] != 17 {
+		t.Errorf("Expected 7 replies on 3 pages, got %v on %d pages", ids, pages)
+	}
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type reactionKey struct {
+	commentId int64
+	userId    string
+	reaction  domain.Reaction
+}
+
+type threadsRepoForTests struct {
+	commentsRepoForTests
+	reactions []reactionKey
+}
+
+func newThreadsRepoForTests() *threadsRepoForTests {
+	return &threadsRepoForTests{commentsRepoForTests: commentsRepoForTests{comments: map[int64]*domain.Comment{
+		10: {Id: 10, EntityType: domain.PlanEntity, EntityId: 1, UserId: "author"},
+		11: {Id: 11, EntityType: domain.PlanEntity, EntityId: 1, ThreadId: 10, ParentId: 10, UserId: "replier"},
+		12: {Id: 12, EntityType: domain.PlanEntity, EntityId: 1, ThreadId: 10, ParentId: 10, UserId: "replier", Deleted: true},
+		20: {Id: 20, EntityType: domain.PlanEntity, EntityId: 1, UserId: "author"},
+		21: {Id: 21, EntityType: domain.PlanEntity, EntityId: 1, ThreadId: 20, ParentId: 20, UserId: "replier"},
+	}}}
+}
+
+func (r *threadsRepoForTests) Delete(ctx core.ReqContext, id int64, deletedBy string) (bool, error) {
+	r.comments[id].Deleted = true
+	r.comments[id].DeletedBy = deletedBy
+	return true, nil
+}
+
+func (r *threadsRepoForTests) SetPinned(ctx core.ReqContext, id int64, pinned bool) (bool, error) {
+	r.comments[id].Pinned = pinned
+	return true, nil
+}
+
+func (r *threadsRepoForTests) SetAnswer(ctx core.ReqContext, threadId int64, answerId int64) (bool, error) {
+	r.comments[threadId].AnswerId = answerId
+	return true, nil
+}
+
+func (r *threadsRepoForTests) ToggleReaction(ctx core.ReqContext, commentId int64, userId string, reaction domain.Reaction) (bool, error) {
+	key := reactionKey{commentId: commentId, userId: userId, reaction: reaction}
+	for i, v := range r.reactions {
+		if v == key {
+			r.reactions = append(r.reactions[:i], r.reactions[i+1:]...)
+			return false, nil
+		}
+	}
+	r.reactions = append(r.reactions, key)
+	return true, nil
+}
+
+func (r *threadsRepoForTests) GetReactions(ctx core.ReqContext, userId string, commentIds []int64) map[int64][]domain.ReactionSummary {
+	result := make(map[int64][]domain.ReactionSummary)
+	for _, id := range commentIds {
+		for _, v := range r.reactions {
+			if v.commentId != id {
+				continue
+			}
+			found := false
+			for i := range result[id] {
+				if result[id][i].Reaction == v.reaction {
+					result[id][i].Count++
+					result[id][i].Reacted = result[id][i].Reacted || v.userId == userId
+					found = true
+				}
+			}
+			if !found {
+				result[id] = append(result[id], domain.ReactionSummary{Reaction: v.reaction, Count: 1, Reacted: v.userId == userId})
+			}
+		}
+	}
+	return result
+}
+
+func newThreadBroadcasterForTests(repo core.CommentsRepository) (core.Broadcaster, core.EventBus) {
+	bus := infrastructure.NewEventBus(nil, appLoggerForTests{})
+	return infrastructure.NewEventBroadcaster(bus, &pointsRepoForTests{}, repo, appLoggerForTests{}), bus
+}
+
+func TestToggleReaction(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	broadcaster, bus := newThreadBroadcasterForTests(repo)
+	usecase := usecases.NewToggleReaction(repo, broadcaster, appLoggerForTests{})
+	events, cancel := bus.Subscribe([]string{domain.ThreadChannel(10)})
+	defer cancel()
+
+	reacted, reactions, err := usecase.Do(newPermissionsContext("reader", domain.CommentAdd), 11, domain.ReactionHeart)
+	if err != nil || !reacted || len(reactions) != 1 || reactions[0].Count != 1 || !reactions[0].Reacted {
+		t.Fatalf("Expected reaction added, got %v %+v %v", reacted, reactions, err)
+	}
+
+	reacted, reactions, err = usecase.Do(newPermissionsContext("other", domain.CommentAdd), 11, domain.ReactionHeart)
+	if err != nil || !reacted || len(reactions) != 1 || reactions[0].Count != 2 {
+		t.Fatalf("Expected second reaction counted, got %v %+v %v", reacted, reactions, err)
+	}
+
+	reacted, reactions, err = usecase.Do(newPermissionsContext("reader", domain.CommentAdd), 11, domain.ReactionHeart)
+	if err != nil || reacted || len(reactions) != 1 || reactions[0].Count != 1 || reactions[0].Reacted {
+		t.Fatalf("Expected reaction removed on second toggle, got %v %+v %v", reacted, reactions, err)
+	}
+
+	got := receiveEvents(events)
+	if len(got) != 3 || got[2].Type != domain.ReactionsChangedEvent || got[2].Reactions[0].Count != 1 || got[2].Reactions[0].Reacted {
+		t.Errorf("Expected aggregated reactions broadcast, got %+v", got)
+	}
+}
+
+func TestToggleReactionValidation(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	usecase := usecases.NewToggleReaction(repo, broadcaster, appLoggerForTests{})
+
+	if _, _, err := usecase.Do(newPermissionsContext("reader"), 11, domain.ReactionHeart); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied without permission, got %v", err)
+	}
+	if _, _, err := usecase.Do(newPermissionsContext("reader", domain.CommentAdd), 11, domain.Reaction("poop")); err == nil {
+		t.Error("Expected error for unknown reaction")
+	}
+	if _, _, err := usecase.Do(newPermissionsContext("reader", domain.CommentAdd), 12, domain.ReactionHeart); err == nil {
+		t.Error("Expected error for deleted comment")
+	}
+	if len(repo.reactions) != 0 {
+		t.Errorf("Expected no reactions saved, got %+v", repo.reactions)
+	}
+}
+
+func TestPinCommentByPlanOwner(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	plans := &planRepoForTests{plans: map[int]*domain.Plan{1: {Id: 1, OwnerId: "owner"}}}
+	changes := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(changes, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	usecase := usecases.NewPinComment(repo, plans, changeLog, broadcaster, appLoggerForTests{})
+
+	if _, err := usecase.Do(newPermissionsContext("author"), 10, true); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied for thread author, got %v", err)
+	}
+	if _, err := usecase.Do(newPermissionsContext("owner"), 11, true); err == nil {
+		t.Error("Expected error for pinning reply")
+	}
+	if ok, err := usecase.Do(newPermissionsContext("owner"), 10, true); !ok || err != nil || !repo.comments[10].Pinned {
+		t.Errorf("Expected thread pinned by plan owner, got %v %v", ok, err)
+	}
+	if ok, err := usecase.Do(newPermissionsContext("moderator", domain.CommentModerate), 10, false); !ok || err != nil || repo.comments[10].Pinned {
+		t.Errorf("Expected thread unpinned by moderator, got %v %v", ok, err)
+	}
+	if len(changes.records) != 2 {
+		t.Errorf("Expected changes recorded, got %d", len(changes.records))
+	}
+}
+
+func TestAcceptAnswer(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	plans := &planRepoForTests{plans: map[int]*domain.Plan{1: {Id: 1, OwnerId: "owner"}}}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	usecase := usecases.NewAcceptAnswer(repo, plans, changeLog, broadcaster, appLoggerForTests{})
+	ctx := newPermissionsContext("owner")
+
+	if _, err := usecase.Do(newPermissionsContext("replier"), 10, 11); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied for replier, got %v", err)
+	}
+	if _, err := usecase.Do(ctx, 10, 21); err == nil {
+		t.Error("Expected error for reply of other thread")
+	}
+	if _, err := usecase.Do(ctx, 10, 12); err == nil {
+		t.Error("Expected error for deleted reply")
+	}
+	if ok, err := usecase.Do(ctx, 10, 11); !ok || err != nil || repo.comments[10].AnswerId != 11 {
+		t.Fatalf("Expected answer accepted, got %v %v", ok, err)
+	}
+
+	if ok, err := usecase.Do(ctx, 10, 0); !ok || err != nil || repo.comments[10].AnswerId != 0 {
+		t.Errorf("Expected answer removed, got %v %v", ok, err)
+	}
+}
+
+func TestRemoveAcceptedAnswer(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	repo.comments[10].AnswerId = 11
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	usecase := usecases.NewRemoveComments(repo, changeLog, broadcaster, appLoggerForTests{})
+
+	if ok, err := usecase.Do(newPermissionsContext("replier"), 11); !ok || err != nil {
+		t.Fatalf("Expected reply removed, got %v %v", ok, err)
+	}
+	if repo.comments[10].AnswerId != 0 {
+		t.Errorf("Expected accepted answer cleared after removal, got %d", repo.comments[10].AnswerId)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type revisionsRepoForTests struct {
+	threadsRepoForTests
+	revisions []domain.CommentRevision
+}
+
+func (r *revisionsRepoForTests) Update(ctx core.ReqContext, id int64, version int, text, html, title string, editorId string) (bool, *core.AppError) {
+	c := r.comments[id]
+	if c.Version != version {
+		return false, core.NewError(core.Conflict)
+	}
+	editor := c.UserId
+	if c.EditedBy != "" {
+		editor = c.EditedBy
+	}
+	r.revisions = append(r.revisions, domain.CommentRevision{Id: int64(len(r.revisions) + 1), CommentId: id, Text: c.Text, Html: c.Html, Title: c.Title, EditorId: editor})
+	c.Text, c.Html, c.Title, c.EditedBy, c.EditedAt = text, html, title, editorId, time.Now()
+	c.Version++
+	return true, nil
+}
+
+func (r *revisionsRepoForTests) GetRevisions(ctx core.ReqContext, commentId int64) []domain.CommentRevision {
+	var result []domain.CommentRevision
+	for _, v := range r.revisions {
+		if v.CommentId == commentId {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func TestCommentRevisions(t *testing.T) {
+	repo := &revisionsRepoForTests{threadsRepoForTests: *newThreadsRepoForTests()}
+	repo.comments[10].Text = "first"
+	repo.comments[10].Version = 1
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	edit := usecases.NewEditComment(repo, changeLog, broadcaster, newMarkdownRendererForTests(), appLoggerForTests{})
+	getRevisions := usecases.NewGetCommentRevisions(repo, &usersListRepoForTests{}, appLoggerForTests{})
+
+	if ok, err := edit.Do(newPermissionsContext("author"), 10, 1, "second", ""); !ok || err != nil {
+		t.Fatalf("Expected comment edited by author, got %v %v", ok, err)
+	}
+	if ok, err := edit.Do(newPermissionsContext("moderator", domain.CommentModerate), 10, 2, "third", ""); !ok || err != nil {
+		t.Fatalf("Expected comment edited by moderator, got %v %v", ok, err)
+	}
+	if !repo.comments[10].EditedByModerator() || repo.comments[10].EditedAt.IsZero() {
+		t.Errorf("Expected comment marked as edited by moderator, got %+v", repo.comments[10])
+	}
+
+	revisions, err := getRevisions.Do(newPermissionsContext("author"), 10)
+	if err != nil || len(revisions) != 2 {
+		t.Fatalf("Expected two revisions for author, got %+v %v", revisions, err)
+	}
+	if revisions[0].Text != "first" || revisions[0].ByModerator || revisions[1].Text != "second" || revisions[1].ByModerator {
+		t.Errorf("Expected author revisions oldest first, got %+v", revisions)
+	}
+	if revisions[0].Editor == nil || revisions[0].Editor.Name != "Author" {
+		t.Errorf("Expected editor attached to revision, got %+v", revisions[0].Editor)
+	}
+
+	if _, err := getRevisions.Do(newPermissionsContext("moderator", domain.CommentModerate), 10); err != nil {
+		t.Errorf("Expected revisions for moderator, got %v", err)
+	}
+	if _, err := getRevisions.Do(newPermissionsContext("reader"), 10); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied for other user, got %v", err)
+	}
+}
+
+func TestRemoveCommentByModerator(t *testing.T) {
+	repo := newThreadsRepoForTests()
+	repo.comments[11].Text = "reply"
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	remove := usecases.NewRemoveComments(repo, changeLog, broadcaster, appLoggerForTests{})
+
+	if ok, err := remove.Do(newPermissionsContext("moderator", domain.CommentModerate), 11); !ok || err != nil {
+		t.Fatalf("Expected reply removed by moderator, got %v %v", ok, err)
+	}
+	c := repo.comments[11]
+	if !c.Deleted || c.DeletedBy != "moderator" || !c.DeletedByModerator() {
+		t.Errorf("Expected comment marked as removed by moderator, got %+v", c)
+	}
+}
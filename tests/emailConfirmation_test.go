@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+func TestEmailConfirmationExpires(t *testing.T) {
+	user := &domain.User{Email: "old@example.com"}
+	user.NewEmailConfirmation("secret", time.Hour)
+	if user.IsConfirmationExpired() {
+		t.Error("New confirmation should not be expired")
+	}
+	if user.EmailConfirmationSent.IsZero() {
+		t.Error("Time of sending should be saved")
+	}
+
+	user.NewEmailConfirmation("secret", -time.Minute)
+	if !user.IsConfirmationExpired() {
+		t.Error("Confirmation should be expired")
+	}
+
+	// confirmations without expiry are not accepted
+	user.EmailConfirmationExpires = time.Time{}
+	if !user.IsConfirmationExpired() {
+		t.Error("Confirmation without expiry should be expired")
+	}
+}
+
+func TestConfirmPendingEmail(t *testing.T) {
+	user := &domain.User{Email: "old@example.com", EmailConfirmed: true, PendingEmail: "new@example.com"}
+	user.NewEmailConfirmation("secret", time.Hour)
+	user.ConfirmEmail()
+
+	if user.Email != "new@example.com" || user.PendingEmail != "" {
+		t.Errorf("Pending email should replace current one, got %s, pending %s", user.Email, user.PendingEmail)
+	}
+	if !user.EmailConfirmed || user.EmailConfirmation != "" {
+		t.Error("Email should be confirmed and secret removed")
+	}
+}
+
+func TestRemovePermissions(t *testing.T) {
+	set := domain.NewPermissionSet(domain.PlanAdd, domain.CommentAdd, domain.ReportAdd)
+	set.Remove(domain.PlanAdd, domain.CommentAdd, domain.PointsAdd)
+	if set.Has(domain.PlanAdd) || set.Has(domain.CommentAdd) {
+		t.Error("Permissions should be removed")
+	}
+	if !set.Has(domain.ReportAdd) {
+		t.Error("Other permissions should stay")
+	}
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type emailOutboxForTests struct {
+	emails []domain.Email
+}
+
+func (o *emailOutboxForTests) Add(ctx core.ReqContext, email *domain.Email) (bool, *core.AppError) {
+	email.Id = int64(len(o.emails) + 1)
+	o.emails = append(o.emails, *email)
+	return true, nil
+}
+
+func (o *emailOutboxForTests) Take(count int, lease time.Duration) []domain.Email {
+	result := make([]domain.Email, 0)
+	for i := range o.emails {
+		if o.emails[i].Status == domain.EmailPending && len(result) < count {
+			result = append(result, o.emails[i])
+		}
+	}
+	return result
+}
+
+func (o *emailOutboxForTests) MarkSent(id int64) bool {
+	o.emails[id-1].Status = domain.EmailSent
+	o.emails[id-1].Attempts++
+	return true
+}
+
+func (o *emailOutboxForTests) MarkFailed(id int64, lastError string, nextAttempt time.Time, dead bool) bool {
+	o.emails[id-1].Attempts++
+	o.emails[id-1].LastError = lastError
+	if dead {
+		o.emails[id-1].Status = domain.EmailDead
+	}
+	return true
+}
+
+type failingTransport struct{}
+
+func (t *failingTransport) Send(from string, to string, msg []byte) error {
+	return errors.New("smtp is down")
+}
+
+func newTestEmailTemplates(t *testing.T) *infrastructure.EmailTemplates {
+	dir, err := ioutil.TempDir("", "emails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"en/hello.tpl": `{"subject": "Hello {{.Name}}", "html": "<b>Hello {{.Name}}</b> {{.Host}}", "text": "Hello {{.Name}} {{.Host}}"}`,
+		"ru/hello.tpl": `{"subject": "Привет {{.Name}}", "html": "<b>Привет {{.Name}}</b>", "text": "Привет {{.Name}}"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := infrastructure.NewEmailTemplates(dir, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return templates
+}
+
+func TestEmailTemplatesRender(t *testing.T) {
+	templates := newTestEmailTemplates(t)
+
+	ru, err := templates.Render("hello", "ru", map[string]string{"Name": "<Ann>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ru.Subject != "Привет <Ann>" || ru.Html != "<b>Привет &lt;Ann&gt;</b>" {
+		t.Errorf("Unexpected ru email: %+v", ru)
+	}
+
+	// not translated language falls back to default one
+	de, err := templates.Render("hello", "de", map[string]string{"Name": "Ann"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if de.Subject != "Hello Ann" {
+		t.Errorf("Unexpected fallback email: %+v", de)
+	}
+
+	if _, err := templates.Render("unknown", "en", nil); err == nil {
+		t.Error("Unknown template should return error")
+	}
+}
+
+func TestEmailDispatcherSendsToSink(t *testing.T) {
+	sink, err := infrastructure.NewSmtpSink("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	host, port := splitSinkAddr(t, sink.Addr())
+	outbox := &emailOutboxForTests{}
+	outbox.Add(nil, domain.NewEmail("ann@example.com", "hello", "en", map[string]string{"Name": "Ann"}))
+
+	dispatcher := infrastructure.NewEmailDispatcher(outbox, newTestEmailTemplates(t), infrastructure.NewSmtpTransport(host, port, "", ""),
+		infrastructure.EmailDispatcherConf{SiteHost: "http://localhost", FromEmail: "no-reply@example.com", FromName: "Roadmaps", BatchSize: 10, MaxAttempts: 3},
+		&appLoggerForTests{})
+
+	if sent := dispatcher.Run(); sent != 1 {
+		t.Fatalf("Expected one email processed, got %d", sent)
+	}
+	if outbox.emails[0].Status != domain.EmailSent {
+		t.Fatalf("Email not sent: %+v", outbox.emails[0])
+	}
+
+	messages := sink.Messages()
+	if len(messages) != 1 || messages[0].To[0] != "ann@example.com" {
+		t.Fatalf("Unexpected messages: %+v", messages)
+	}
+	data := string(messages[0].Data)
+	if !strings.Contains(data, "multipart/alternative") || !strings.Contains(data, "text/plain") || !strings.Contains(data, "text/html") {
+		t.Errorf("Message is not multipart: %s", data)
+	}
+	if !strings.Contains(data, "Hello Ann http://localhost") {
+		t.Errorf("Message body not rendered: %s", data)
+	}
+}
+
+func TestEmailDispatcherDeadLetter(t *testing.T) {
+	outbox := &emailOutboxForTests{}
+	outbox.Add(nil, domain.NewEmail("ann@example.com", "hello", "en", nil))
+
+	dispatcher := infrastructure.NewEmailDispatcher(outbox, newTestEmailTemplates(t), &failingTransport{},
+		infrastructure.EmailDispatcherConf{BatchSize: 10, MaxAttempts: 2},
+		&appLoggerForTests{})
+
+	dispatcher.Run()
+	if outbox.emails[0].Status != domain.EmailPending || outbox.emails[0].LastError != "smtp is down" {
+		t.Fatalf("Failed email should be retried: %+v", outbox.emails[0])
+	}
+
+	dispatcher.Run()
+	if outbox.emails[0].Status != domain.EmailDead {
+		t.Fatalf("Email should be dead after max attempts: %+v", outbox.emails[0])
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	cases := map[string]string{
+		"ru-RU,ru;q=0.9,en;q=0.8": "ru",
+		"en":                      "en",
+		"*":                       "",
+		"":                        "",
+	}
+	for header, expected := range cases {
+		if lang := infrastructure.ParseLanguage(header); lang != expected {
+			t.Errorf("Header %q: expected %q, got %q", header, expected, lang)
+		}
+	}
+}
+
+func splitSinkAddr(t *testing.T, addr string) (string, int) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host, p
+}
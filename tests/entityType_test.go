@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/domain"
+)
+
+func TestEntityTypeFromString(t *testing.T) {
+	types := []domain.EntityType{
+		domain.PlanEntity,
+		domain.TopicEntity,
+		domain.ProjectEntity,
+		domain.ResourceEntity,
+		domain.CommentEntity,
+		domain.UserEntity,
+	}
+
+	for _, et := range types {
+		name := domain.EntityTypeToString(et)
+		if ok, parsed := domain.EntityTypeFromString(name); !ok || parsed != et {
+			t.Errorf("Entity type %d converted to %s and parsed as %d", et, name, parsed)
+		}
+	}
+
+	if ok, _ := domain.EntityTypeFromString("unknown"); ok {
+		t.Error("Unknown entity type should not be parsed")
+	}
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type eventBackendForTests struct {
+	handlers []func([]byte)
+	payloads [][]byte
+}
+
+func (b *eventBackendForTests) Publish(payload []byte) error {
+	b.payloads = append(b.payloads, payload)
+	for _, h := range b.handlers {
+		h(payload)
+	}
+	return nil
+}
+
+func (b *eventBackendForTests) Listen(handler func([]byte)) {
+	b.handlers = append(b.handlers, handler)
+}
+
+type pointsRepoForTests struct {
+	core.PointsRepository
+	points map[int64]*domain.Points
+}
+
+func (r *pointsRepoForTests) Get(ctx core.ReqContext, userid string, entityType domain.EntityType, entityId int64) *domain.Points {
+	return r.points[entityId]
+}
+
+func receiveEvents(events <-chan domain.Event) []domain.Event {
+	var result []domain.Event
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return result
+			}
+			result = append(result, e)
+		default:
+			return result
+		}
+	}
+}
+
+func TestEventBusDeliversOncePerSubscriber(t *testing.T) {
+	bus := infrastructure.NewEventBus(nil, appLoggerForTests{})
+	events, cancel := bus.Subscribe([]string{"plan:1", "thread:10"})
+	other, cancelOther := bus.Subscribe([]string{"plan:2"})
+	defer cancelOther()
+
+	bus.Publish(&domain.Event{Type: domain.CommentAddedEvent, Channels: []string{"plan:1", "thread:10"}})
+
+	if got := receiveEvents(events); len(got) != 1 {
+		t.Errorf("Expected single event for subscriber of both channels, got %d", len(got))
+	}
+	if got := receiveEvents(other); len(got) != 0 {
+		t.Errorf("Expected no events for other channel, got %d", len(got))
+	}
+
+	cancel()
+	cancel()
+	bus.Publish(&domain.Event{Type: domain.CommentAddedEvent, Channels: []string{"plan:1"}})
+	if _, ok := <-events; ok {
+		t.Errorf("Expected closed channel after cancel")
+	}
+}
+
+func TestSubscribeEventsOfDraftPlan(t *testing.T) {
+	bus := infrastructure.NewEventBus(nil, appLoggerForTests{})
+	usecase := newSubscribeEventsForTests(bus)
+
+	_, _, err := usecase.Do(newPermissionsContext("stranger"), []string{"plan:1", "plan:2"})
+	if appErr, ok := err.(*core.AppError); !ok || appErr.Message != core.NotExists.String() {
+		t.Errorf("Expected draft plan hidden from other users, got %v", err)
+	}
+
+	_, cancel, err := usecase.Do(newPermissionsContext("author"), []string{"plan:1", "plan:2", "thread:10"})
+	if err != nil {
+		t.Fatalf("Expected owner subscribed to draft plan, got %v", err)
+	}
+	cancel()
+}
+
+func TestSubscribeEventsOfThread(t *testing.T) {
+	bus := infrastructure.NewEventBus(nil, appLoggerForTests{})
+	usecase := newSubscribeEventsForTests(bus)
+
+	for _, ch := range []string{"thread:20", "thread:11", "thread:99", "topic:9", "resource:9"} {
+		if _, _, err := usecase.Do(newPermissionsContext("stranger"), []string{ch}); err == nil {
+			t.Errorf("Expected %s not subscribed by other users", ch)
+		}
+	}
+
+	_, cancel, err := usecase.Do(newPermissionsContext("stranger"), []string{"thread:10", "topic:3", "resource:4"})
+	if err != nil {
+		t.Fatalf("Expected public channels subscribed, got %v", err)
+	}
+	cancel()
+
+	_, cancel, err = usecase.Do(newPermissionsContext("author"), []string{"thread:20"})
+	if err != nil {
+		t.Fatalf("Expected owner subscribed to thread of draft plan, got %v", err)
+	}
+	cancel()
+}
+
+// newSubscribeEventsForTests has thread 10 on public plan 1, its reply 11 and thread 20 on draft plan 2
+func newSubscribeEventsForTests(bus core.EventBus) usecases.SubscribeEvents {
+	comments := &commentsRepoForTests{comments: map[int64]*domain.Comment{
+		10: {Id: 10, EntityType: domain.PlanEntity, EntityId: 1},
+		11: {Id: 11, EntityType: domain.PlanEntity, EntityId: 1, ThreadId: 10, ParentId: 10},
+		20: {Id: 20, EntityType: domain.PlanEntity, EntityId: 2},
+	}}
+	topics := &topicRepoForTests{topics: map[int]*domain.Topic{3: {Id: 3, Name: "golang"}}}
+	sources := &sourceRepoForTests{sources: map[int64]*domain.Source{4: {Id: 4}}}
+	return usecases.NewSubscribeEvents(bus, newForkPlanRepoForTests(), comments, topics, sources, appLoggerForTests{})
+}
+
+func TestEventBusPublishesThroughBackend(t *testing.T) {
+	backend := &eventBackendForTests{}
+	bus := infrastructure.NewEventBus(backend, appLoggerForTests{})
+	events, cancel := bus.Subscribe([]string{"plan:1"})
+	defer cancel()
+
+	bus.Publish(&domain.Event{Type: domain.PointsChangedEvent, Channels: []string{"plan:1"}, EntityType: domain.PlanEntity, EntityId: 1, Points: &domain.Points{Id: 1, Count: 3}})
+
+	if len(backend.payloads) != 1 {
+		t.Fatalf("Expected event published to backend, got %d", len(backend.payloads))
+	}
+	got := receiveEvents(events)
+	if len(got) != 1 || got[0].Points == nil || got[0].Points.Count != 3 || got[0].EntityId != 1 {
+		t.Errorf("Expected event received from backend, got %+v", got)
+	}
+}
+
+func TestIsValidChannel(t *testing.T) {
+	cases := map[string]bool{
+		"plan:1":      true,
+		"topic:5":     true,
+		"resource:3":  true,
+		"project:2":   true,
+		"thread:10":   true,
+		"plan:0":      false,
+		"plan:abc":    false,
+		"user:1":      false,
+		"plan":        false,
+		"thread:-1":   false,
+		"":            false,
+		"plan:1:2":    false,
+		"comment:100": false,
+	}
+	for channel, expected := range cases {
+		if domain.IsValidChannel(channel) != expected {
+			t.Errorf("IsValidChannel(%q) expected %v", channel, expected)
+		}
+	}
+}
+
+func TestBroadcasterChannels(t *testing.T) {
+	bus := infrastructure.NewEventBus(nil, appLoggerForTests{})
+	comments := &commentsRepoForTests{comments: map[int64]*domain.Comment{
+		11: {Id: 11, EntityType: domain.PlanEntity, EntityId: 1, ParentId: 10, ThreadId: 10},
+	}}
+	points := &pointsRepoForTests{points: map[int64]*domain.Points{11: {Id: 11, Count: 1, Avg: 1}}}
+	broadcaster := infrastructure.NewEventBroadcaster(bus, points, comments, appLoggerForTests{})
+
+	thread, cancel := bus.Subscribe([]string{domain.ThreadChannel(10)})
+	defer cancel()
+
+	broadcaster.CommentDeleted(newUserContext("author"), &domain.Comment{Id: 11, EntityType: domain.PlanEntity, EntityId: 1, ThreadId: 10, Text: "secret", User: &domain.User{Id: "author"}})
+	broadcaster.PointsChanged(newUserContext("voter"), domain.CommentEntity, 11)
+
+	got := receiveEvents(thread)
+	if len(got) != 2 {
+		t.Fatalf("Expected comment and points events in thread channel, got %+v", got)
+	}
+	if got[0].Type != domain.CommentDeletedEvent || got[0].Comment.Text != "" || got[0].Comment.User != nil {
+		t.Errorf("Expected deleted comment without text and user, got %+v", got[0].Comment)
+	}
+	if got[1].Type != domain.PointsChangedEvent || got[1].Points.Count != 1 {
+		t.Errorf("Expected points event, got %+v", got[1])
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type userRepoForTests struct {
+	core.UserRepository
+	users []domain.User
+}
+
+func (r *userRepoForTests) FindByNames(ctx core.ReqContext, names []string) []domain.User {
+	var result []domain.User
+	for _, u := range r.users {
+		for _, name := range names {
+			if strings.EqualFold(u.Name, name) {
+				result = append(result, u)
+			}
+		}
+	}
+	return result
+}
+
+func newMarkdownRendererForTests() core.MarkdownRenderer {
+	users := &userRepoForTests{users: []domain.User{{Id: "u1", Name: "Alice"}}}
+	return infrastructure.NewMarkdownRenderer(users, "/user/{name}", appLoggerForTests{})
+}
+
+func TestMarkdownRender(t *testing.T) {
+	markdown := newMarkdownRendererForTests()
+	ctx := newUserContext("author")
+
+	cases := map[string]string{
+		"```go\nfmt.Println(1)\n```":      `<code class="language-go">`,
+		"| a | b |\n|---|---|\n| 1 | 2 |": "<table>",
+		"see https://example.com/page":    `<a href="https://example.com/page"`,
+		"~~old~~ **new**":                 "<del>old</del> <strong>new</strong>",
+		"hello @alice!":                   `<a href="/user/Alice" class="mention" rel="nofollow">@Alice</a>`,
+		"text<script>alert(1)</script>":   "text",
+		"[click](javascript:alert(1))":    "click",
+		"<img src=x onerror=alert(1)>":    "",
+		"line with <b>bold</b> html":      "<b>bold</b>",
+	}
+	for source, expected := range cases {
+		html := markdown.Render(ctx, source)
+		if !strings.Contains(html, expected) {
+			t.Errorf("Render(%q) = %q, expected to contain %q", source, html, expected)
+		}
+		for _, unsafe := range []string{"<script", "javascript:", "onerror"} {
+			if strings.Contains(html, unsafe) {
+				t.Errorf("Render(%q) = %q contains %q", source, html, unsafe)
+			}
+		}
+	}
+}
+
+func TestMarkdownMentions(t *testing.T) {
+	markdown := newMarkdownRendererForTests()
+	ctx := newUserContext("author")
+
+	plain := []string{
+		"hello @bob",
+		"mail me alice@example.com",
+		"`@alice`",
+		"[@alice](https://example.com)",
+	}
+	for _, source := range plain {
+		html := markdown.Render(ctx, source)
+		if strings.Contains(html, `class="mention"`) {
+			t.Errorf("Render(%q) = %q, expected no mention", source, html)
+		}
+	}
+}
+
+func TestMarkdownRenderInline(t *testing.T) {
+	markdown := newMarkdownRendererForTests()
+	ctx := newUserContext("author")
+
+	html := markdown.RenderInline(ctx, "Learn *Go*")
+	if html != "Learn <em>Go</em>" {
+		t.Errorf("Expected inline html without paragraph, got %q", html)
+	}
+	if html := markdown.RenderInline(ctx, "   "); html != "" {
+		t.Errorf("Expected empty html for blank source, got %q", html)
+	}
+}
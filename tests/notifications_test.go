@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type notifierForTests struct {
+	comments     []*domain.Comment
+	changedPlans []int
+}
+
+func (n *notifierForTests) CommentAdded(ctx core.ReqContext, comment *domain.Comment) {
+	n.comments = append(n.comments, comment)
+}
+
+func (n *notifierForTests) VoteAdded(ctx core.ReqContext, entityType domain.EntityType, entityId int64, value int) {
+}
+
+func (n *notifierForTests) PlanChanged(ctx core.ReqContext, planId int) {
+	n.changedPlans = append(n.changedPlans, planId)
+}
+
+type notificationRepoForTests struct {
+	core.NotificationRepository
+	saved []domain.Notification
+}
+
+func (r *notificationRepoForTests) Add(ctx core.ReqContext, notifications []domain.Notification) (int64, *core.AppError) {
+	r.saved = append(r.saved, notifications...)
+	return int64(len(notifications)), nil
+}
+
+// only methods used by notification center are implemented by fakes below
+
+type planRepoForTests struct {
+	core.PlanRepository
+	plans map[int]*domain.Plan
+}
+
+func (r *planRepoForTests) Get(ctx core.ReqContext, id int) *domain.Plan {
+	return r.plans[id]
+}
+
+type commentsRepoForTests struct {
+	core.CommentsRepository
+	comments map[int64]*domain.Comment
+}
+
+func (r *commentsRepoForTests) Get(ctx core.ReqContext, id int64) *domain.Comment {
+	return r.comments[id]
+}
+
+type usersPlanRepoForTests struct {
+	core.UsersPlanRepository
+	users map[int][]string
+}
+
+func (r *usersPlanRepoForTests) GetUsers(ctx core.ReqContext, planId int) []string {
+	return r.users[planId]
+}
+
+func newNotificationCenterForTests(repo core.NotificationRepository) core.Notifier {
+	plans := &planRepoForTests{plans: map[int]*domain.Plan{
+		1: {Id: 1, Title: "Go", OwnerId: "owner"},
+	}}
+	comments := &commentsRepoForTests{comments: map[int64]*domain.Comment{
+		10: {Id: 10, EntityType: domain.PlanEntity, EntityId: 1, UserId: "author"},
+	}}
+	favorites := &usersPlanRepoForTests{users: map[int][]string{
+		1: {"reader", "editor"},
+	}}
+	return infrastructure.NewNotificationCenter(repo, comments, plans, favorites, &appLoggerForTests{})
+}
+
+func newUserContext(userId string) core.ReqContext {
+	return infrastructure.NewContext(context.WithValue(context.Background(), infrastructure.ReqUserId, userId))
+}
+
+func TestNotifyCommentReply(t *testing.T) {
+	repo := &notificationRepoForTests{}
+	center := newNotificationCenterForTests(repo)
+
+	center.CommentAdded(newUserContext("replier"), &domain.Comment{Id: 11, EntityType: domain.PlanEntity, EntityId: 1, ParentId: 10, ThreadId: 10, UserId: "replier"})
+
+	if len(repo.saved) != 2 {
+		t.Fatalf("Expected notifications for comment author and plan owner, got %+v", repo.saved)
+	}
+	if repo.saved[0].UserId != "author" || repo.saved[0].Type != domain.CommentReplyNotification {
+		t.Errorf("Unexpected reply notification: %+v", repo.saved[0])
+	}
+	if repo.saved[1].UserId != "owner" || repo.saved[1].Type != domain.PlanCommentNotification || repo.saved[1].Title != "Go" {
+		t.Errorf("Unexpected plan comment notification: %+v", repo.saved[1])
+	}
+	if repo.saved[0].ActorId != "replier" {
+		t.Errorf("Actor not saved: %+v", repo.saved[0])
+	}
+}
+
+func TestNotifyCommentReplyTitleIsPlainText(t *testing.T) {
+	repo := &notificationRepoForTests{}
+	center := newNotificationCenterForTests(repo)
+
+	center.CommentAdded(newUserContext("replier"), &domain.Comment{Id: 13, EntityType: domain.TopicEntity, EntityId: 2, ParentId: 10, ThreadId: 10, UserId: "replier",
+		Text: "Use **go** & test\n\nnext", Html: "<p>Use <strong>go</strong> &amp; test</p>\n\n<p>next</p>\n"})
+
+	if len(repo.saved) != 1 || repo.saved[0].Title != "Use go & test next" {
+		t.Errorf("Expected title without markup, got %+v", repo.saved)
+	}
+}
+
+func TestNotifyNotAboutOwnActions(t *testing.T) {
+	repo := &notificationRepoForTests{}
+	center := newNotificationCenterForTests(repo)
+
+	center.CommentAdded(newUserContext("owner"), &domain.Comment{Id: 12, EntityType: domain.PlanEntity, EntityId: 1, UserId: "owner"})
+	center.VoteAdded(newUserContext("owner"), domain.PlanEntity, 1, 5)
+
+	if len(repo.saved) != 0 {
+		t.Errorf("User should not be notified about own actions: %+v", repo.saved)
+	}
+}
+
+func TestNotifyFavoritePlanChanged(t *testing.T) {
+	repo := &notificationRepoForTests{}
+	center := newNotificationCenterForTests(repo)
+
+	center.PlanChanged(newUserContext("editor"), 1)
+
+	if len(repo.saved) != 1 || repo.saved[0].UserId != "reader" || repo.saved[0].Type != domain.FavoritePlanChangedNotification {
+		t.Errorf("Unexpected notifications: %+v", repo.saved)
+	}
+}
+
+func TestDigestTemplates(t *testing.T) {
+	templates, err := infrastructure.NewEmailTemplates("../static/emails", "en")
+	if err != nil {
+		t.Fatalf("Templates not loaded: %v", err)
+	}
+
+	data := map[string]string{"Host": "https://example.com", "Count": "3", "Replies": "2", "Comments": "0", "Votes": "1", "ChangedPlans": "0", "Titles": "Go"}
+	for _, lang := range []string{"en", "ru"} {
+		email, err := templates.Render(domain.DigestEmail, lang, data)
+		if err != nil {
+			t.Fatalf("Digest not rendered: %v", err)
+		}
+		if !strings.Contains(email.Text, ": 2") || strings.Contains(email.Text, ": 0") {
+			t.Errorf("Unexpected digest text in %s: %s", lang, email.Text)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type collaboratorRepoForTests struct {
+	core.PlanCollaboratorRepository
+	collaborators map[string]*domain.PlanCollaborator
+}
+
+func newCollaboratorRepoForTests() *collaboratorRepoForTests {
+	return &collaboratorRepoForTests{collaborators: make(map[string]*domain.PlanCollaborator)}
+}
+
+func (r *collaboratorRepoForTests) key(planId int, userId string) string {
+	return fmt.Sprintf("%d:%s", planId, userId)
+}
+
+func (r *collaboratorRepoForTests) Save(ctx core.ReqContext, collaborator *domain.PlanCollaborator) (bool, *core.AppError) {
+	if c, ok := r.collaborators[r.key(collaborator.PlanId, collaborator.UserId)]; ok {
+		collaborator.Accepted = c.Accepted
+	}
+	c := *collaborator
+	r.collaborators[r.key(c.PlanId, c.UserId)] = &c
+	return true, nil
+}
+
+func (r *collaboratorRepoForTests) Accept(ctx core.ReqContext, planId int, userId string) (bool, *core.AppError) {
+	c, ok := r.collaborators[r.key(planId, userId)]
+	if !ok {
+		return false, nil
+	}
+	c.Accepted = true
+	return true, nil
+}
+
+func (r *collaboratorRepoForTests) Remove(ctx core.ReqContext, planId int, userId string) (bool, *core.AppError) {
+	if _, ok := r.collaborators[r.key(planId, userId)]; !ok {
+		return false, nil
+	}
+	delete(r.collaborators, r.key(planId, userId))
+	return true, nil
+}
+
+func (r *collaboratorRepoForTests) Get(ctx core.ReqContext, planId int, userId string) *domain.PlanCollaborator {
+	c, ok := r.collaborators[r.key(planId, userId)]
+	if !ok {
+		return nil
+	}
+	result := *c
+	return &result
+}
+
+func TestIsAllowedOnPlan(t *testing.T) {
+	plan := &domain.Plan{Id: 1, OwnerId: "author"}
+	editor := &domain.PlanCollaborator{PlanId: 1, UserId: "editor", Role: domain.CollaboratorEditor, Accepted: true}
+
+	if !core.IsAllowedOnPlan(newPermissionsContext("editor"), domain.PlanEdit, plan, editor) {
+		t.Error("Expected editor allowed to edit plan")
+	}
+	if core.IsAllowedOnPlan(newPermissionsContext("editor"), domain.TopicRemove, plan, editor) {
+		t.Error("Expected editor gets only owner permissions")
+	}
+	if core.IsAllowedOnPlan(newPermissionsContext("other"), domain.PlanEdit, plan, editor) {
+		t.Error("Expected record of other user ignored")
+	}
+
+	viewer := &domain.PlanCollaborator{PlanId: 1, UserId: "viewer", Role: domain.CollaboratorViewer, Accepted: true}
+	if core.IsAllowedOnPlan(newPermissionsContext("viewer"), domain.PlanEdit, plan, viewer) {
+		t.Error("Expected viewer not allowed to edit plan")
+	}
+	invited := &domain.PlanCollaborator{PlanId: 1, UserId: "invited", Role: domain.CollaboratorEditor}
+	if core.IsAllowedOnPlan(newPermissionsContext("invited"), domain.PlanEdit, plan, invited) {
+		t.Error("Expected rights granted only after invitation accepted")
+	}
+	if !core.IsAllowedOnPlan(newPermissionsContext("author"), domain.PlanEdit, plan, nil) {
+		t.Error("Expected owner allowed without collaborator record")
+	}
+}
+
+func TestEditPlanByCollaborator(t *testing.T) {
+	plans := &suggestionPlanRepoForTests{newForkPlanRepoForTests()}
+	collaborators := newCollaboratorRepoForTests()
+	collaborators.Save(nil, &domain.PlanCollaborator{PlanId: 1, UserId: "editor", Role: domain.CollaboratorEditor})
+	collaborators.Save(nil, &domain.PlanCollaborator{PlanId: 1, UserId: "viewer", Role: domain.CollaboratorViewer})
+	collaborators.Accept(nil, 1, "viewer")
+	sources := &sourceRepoForTests{sources: map[int64]*domain.Source{5: {Id: 5}}}
+	changes := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(changes, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	usecase := usecases.NewEditPlan(plans, collaborators, &stepRepoForTests{plans: plans.forkPlanRepoForTests}, sources, &topicRepoForTests{}, &projectsRepoForTests{}, changeLog, newMarkdownRendererForTests(), appLoggerForTests{})
+
+	req := usecases.EditPlanReq{
+		Id:        1,
+		TopicName: "golang",
+		Title:     "Go together",
+		Steps:     []usecases.PlanStep{{ReferenceId: 5, ReferenceType: domain.ResourceReference, Title: "tour"}},
+		Version:   1,
+	}
+
+	if _, err := usecase.Do(newPermissionsContext("editor"), req); err == nil {
+		t.Error("Expected editor not allowed to edit before invitation accepted")
+	}
+	if _, err := usecase.Do(newPermissionsContext("viewer"), req); err == nil {
+		t.Error("Expected viewer not allowed to edit plan")
+	}
+
+	collaborators.Accept(nil, 1, "editor")
+	if _, err := usecase.Do(newPermissionsContext("editor"), req); err != nil {
+		t.Fatalf("Expected editor allowed to edit plan, got %v", err)
+	}
+	if plans.plans[1].Title != "Go together" || plans.plans[1].OwnerId != "author" {
+		t.Errorf("Expected plan edited and owner kept, got %+v", plans.plans[1])
+	}
+	if len(changes.records) != 1 || changes.records[0].UserId != "editor" {
+		t.Errorf("Expected change attributed to editor, got %+v", changes.records)
+	}
+}
+
+func TestInvitePlanCollaborator(t *testing.T) {
+	plans := newForkPlanRepoForTests()
+	collaborators := newCollaboratorRepoForTests()
+	users := &userRepoForTests{users: []domain.User{{Id: "author", Name: "Author"}, {Id: "u2", Name: "Bob"}, {Id: "u3", Name: "Carol"}}}
+	invite := usecases.NewInvitePlanCollaborator(collaborators, plans, users, appLoggerForTests{})
+	remove := usecases.NewRemovePlanCollaborator(collaborators, plans, appLoggerForTests{})
+	accept := usecases.NewAcceptPlanInvitation(collaborators, appLoggerForTests{})
+
+	c, err := invite.Do(newPermissionsContext("author"), 2, "bob", domain.CollaboratorEditor)
+	if err != nil {
+		t.Fatalf("Expected user invited to draft, got %v", err)
+	}
+	if c.UserId != "u2" || c.Accepted || c.InvitedBy != "author" {
+		t.Errorf("Expected not accepted invitation of Bob, got %+v", c)
+	}
+
+	if _, err := invite.Do(newPermissionsContext("author"), 2, "Author", domain.CollaboratorEditor); err == nil {
+		t.Error("Expected owner not invited to own plan")
+	}
+	if _, err := invite.Do(newPermissionsContext("author"), 2, "nobody", domain.CollaboratorEditor); err == nil {
+		t.Error("Expected unknown user not invited")
+	}
+	if _, err := invite.Do(newPermissionsContext("author"), 2, "bob", domain.CollaboratorRole(7)); err == nil {
+		t.Error("Expected invalid role rejected")
+	}
+	if _, err := invite.Do(newPermissionsContext("u2"), 1, "Carol", domain.CollaboratorEditor); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected only owner invites collaborators, got %v", err)
+	}
+
+	if ok, err := accept.Do(newPermissionsContext("u2"), 2); err != nil || !ok {
+		t.Fatalf("Expected invitation accepted, got %v", err)
+	}
+	if _, err := accept.Do(newPermissionsContext("other"), 2); err == nil {
+		t.Error("Expected accepting without invitation fails")
+	}
+
+	if _, err := remove.Do(newPermissionsContext("other"), 1, "u2"); err == nil {
+		t.Error("Expected only owner removes collaborators")
+	}
+	if ok, err := remove.Do(newPermissionsContext("u2"), 2, "u2"); err != nil || !ok {
+		t.Errorf("Expected collaborator leaves plan, got %v", err)
+	}
+	if collaborators.Get(nil, 2, "u2") != nil {
+		t.Error("Expected collaborator removed")
+	}
+}
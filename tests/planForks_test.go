@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type forkPlanRepoForTests struct {
+	core.PlanRepository
+	plans map[int]*domain.Plan
+}
+
+func (r *forkPlanRepoForTests) GetWithDraft(ctx core.ReqContext, id int, userid string) *domain.Plan {
+	p, ok := r.plans[id]
+	if !ok || (p.IsDraft && p.OwnerId != userid) {
+		return nil
+	}
+	result := *p
+	return &result
+}
+
+func (r *forkPlanRepoForTests) SaveWithSteps(ctx core.ReqContext, plan *domain.Plan) (bool, *core.AppError) {
+	plan.Id = len(r.plans) + 100
+	r.plans[plan.Id] = plan
+	return true, nil
+}
+
+type stepRepoForTests struct {
+	core.StepRepository
+	plans *forkPlanRepoForTests
+}
+
+func (r *stepRepoForTests) GetByPlan(ctx core.ReqContext, planid int) []domain.Step {
+	if p, ok := r.plans.plans[planid]; ok {
+		return p.Steps
+	}
+	return nil
+}
+
+func newForkPlanRepoForTests() *forkPlanRepoForTests {
+	return &forkPlanRepoForTests{plans: map[int]*domain.Plan{
+		1: {Id: 1, Title: "Go", TopicName: "golang", OwnerId: "author", Version: 1, Steps: []domain.Step{
+			{Id: 10, PlanId: 1, ReferenceId: 5, ReferenceType: domain.ResourceReference, Position: 0, Title: "tour"},
+			{Id: 11, PlanId: 1, ReferenceId: 6, ReferenceType: domain.ResourceReference, Position: 1, Title: "book"},
+		}},
+		2: {Id: 2, Title: "Draft", TopicName: "golang", OwnerId: "author", IsDraft: true, Version: 1, Steps: []domain.Step{
+			{Id: 20, PlanId: 2, ReferenceId: 5, ReferenceType: domain.ResourceReference},
+		}},
+	}}
+}
+
+func TestForkPlan(t *testing.T) {
+	plans := newForkPlanRepoForTests()
+	changes := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(changes, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	usecase := usecases.NewForkPlan(plans, &stepRepoForTests{plans: plans}, changeLog, appLoggerForTests{})
+
+	fork, err := usecase.Do(newPermissionsContext("reader", domain.PlanAdd), 1)
+	if err != nil {
+		t.Fatalf("Expected plan forked, got %v", err)
+	}
+	if fork.ParentId != 1 || fork.OwnerId != "reader" || !fork.IsDraft || fork.TopicName != "golang" {
+		t.Errorf("Expected draft of reader forked from plan, got %+v", fork)
+	}
+	if len(fork.Steps) != 2 || fork.Steps[1].ReferenceId != 6 || fork.Steps[1].Title != "book" || fork.Steps[0].Id != 0 {
+		t.Errorf("Expected steps copied without ids, got %+v", fork.Steps)
+	}
+	if len(changes.records) != 1 || changes.records[0].EntityId != int64(fork.Id) {
+		t.Errorf("Expected fork recorded in change log, got %+v", changes.records)
+	}
+
+	if _, err := usecase.Do(newPermissionsContext("reader", domain.PlanAdd), 2); err == nil || err.Error() != core.NewError(core.NotExists).Error() {
+		t.Errorf("Expected draft of other user not forked, got %v", err)
+	}
+	if _, err := usecase.Do(newPermissionsContext("author", domain.PlanAdd), 2); err != nil {
+		t.Errorf("Expected own draft forked, got %v", err)
+	}
+	if _, err := usecase.Do(newPermissionsContext("reader"), 1); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied without permission, got %v", err)
+	}
+}
+
+func TestGetPlanDiff(t *testing.T) {
+	plans := newForkPlanRepoForTests()
+	steps := &stepRepoForTests{plans: plans}
+	fork, err := usecases.NewForkPlan(plans, steps, infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{}), appLoggerForTests{}).
+		Do(newPermissionsContext("reader", domain.PlanAdd), 1)
+	if err != nil {
+		t.Fatalf("Expected plan forked, got %v", err)
+	}
+	usecase := usecases.NewGetPlanDiff(plans, steps, infrastructure.NewPlanDiffer(), appLoggerForTests{})
+
+	diff, err := usecase.Do(newUserContext("reader"), fork.Id)
+	if err != nil || diff.Upstream.Id != 1 {
+		t.Fatalf("Expected diff with upstream, got %+v %v", diff, err)
+	}
+	var unchanged struct{ Steps []interface{} }
+	if err := json.Unmarshal([]byte(diff.Diff), &unchanged); err != nil || len(unchanged.Steps) != 0 {
+		t.Errorf("Expected no changes of steps in fresh fork, got %s", diff.Diff)
+	}
+
+	fork.Steps = fork.Steps[:1]
+	diff, err = usecase.Do(newUserContext("reader"), fork.Id)
+	var changed struct{ Steps []interface{} }
+	if err != nil || json.Unmarshal([]byte(diff.Diff), &changed) != nil || len(changed.Steps) == 0 {
+		t.Errorf("Expected removed step in diff, got %+v %v", diff, err)
+	}
+
+	if _, err := usecase.Do(newUserContext("reader"), 1); err == nil || err.Error() != core.ValidationError(map[string]string{"id": core.InvalidValue.String()}).Error() {
+		t.Errorf("Expected error for plan which is not a fork, got %v", err)
+	}
+	if _, err := usecase.Do(newUserContext("other"), fork.Id); err == nil || err.Error() != core.NewError(core.NotExists).Error() {
+		t.Errorf("Expected draft of other user not compared, got %v", err)
+	}
+}
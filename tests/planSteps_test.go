@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+func newAddPlanForTests() (*forkPlanRepoForTests, usecases.AddPlan) {
+	plans := newForkPlanRepoForTests()
+	sources := &sourceRepoForTests{sources: map[int64]*domain.Source{
+		5: {Id: 5, Type: domain.Video, Properties: `{"duration":"42"}`},
+		6: {Id: 6, Type: domain.Book, Properties: `{"isbn13":"9780134190440"}`},
+	}}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	usecase := usecases.NewAddPlan(plans, sources, &topicRepoForTests{}, &projectsRepoForTests{}, changeLog, newMarkdownRendererForTests(), appLoggerForTests{})
+	return plans, usecase
+}
+
+func TestAddPlanWithStepDetails(t *testing.T) {
+	_, usecase := newAddPlanForTests()
+
+	plan, err := usecase.Do(newPermissionsContext("author", domain.PlanAdd), usecases.AddPlanReq{
+		TopicName: "golang",
+		Title:     "Go",
+		Steps: []usecases.PlanStep{
+			{ReferenceId: 5, ReferenceType: domain.ResourceReference, Title: "talk", Difficulty: domain.Beginner},
+			{ReferenceId: 6, ReferenceType: domain.ResourceReference, Title: "book", Duration: 600, Difficulty: domain.Advanced, Notes: "Read chapters **1-3**"},
+			{ReferenceId: 5, ReferenceType: domain.ResourceReference, Title: "talk again", Duration: 30, Optional: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected plan added, got %v", err)
+	}
+
+	if plan.Steps[0].Duration != 42 || plan.Steps[0].Difficulty != domain.Beginner {
+		t.Errorf("Expected duration of video taken from source, got %+v", plan.Steps[0])
+	}
+	if plan.Steps[1].Duration != 600 || !strings.Contains(plan.Steps[1].NotesHtml, "<strong>1-3</strong>") {
+		t.Errorf("Expected duration and rendered notes kept, got %+v", plan.Steps[1])
+	}
+	if plan.Steps[2].Duration != 30 || !plan.Steps[2].Optional {
+		t.Errorf("Expected explicit duration of optional step kept, got %+v", plan.Steps[2])
+	}
+	if plan.EstimatedTime() != 672 || plan.RequiredTime() != 642 {
+		t.Errorf("Expected total 672 and required 642 minutes, got %v and %v", plan.EstimatedTime(), plan.RequiredTime())
+	}
+}
+
+func TestAddPlanValidatesStepDetails(t *testing.T) {
+	_, usecase := newAddPlanForTests()
+
+	_, err := usecase.Do(newPermissionsContext("author", domain.PlanAdd), usecases.AddPlanReq{
+		TopicName: "golang",
+		Title:     "Go",
+		Steps: []usecases.PlanStep{
+			{ReferenceId: 5, ReferenceType: domain.ResourceReference, Title: "talk", Duration: -1, Difficulty: "Expert", Notes: strings.Repeat("a", 2000)},
+		},
+	})
+	appErr, ok := err.(*core.AppError)
+	if !ok {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	for _, field := range []string{"duration", "difficulty", "notes"} {
+		if appErr.Validation[field] != core.InvalidValue.String() {
+			t.Errorf("Expected %s invalid, got %v", field, appErr.Validation)
+		}
+	}
+}
+
+func TestEditPlanKeepsStepDetails(t *testing.T) {
+	plans, usecase := newVersionedEditPlanForTests()
+	req := newEditPlanReqForTests(1)
+	req.Steps[0].Duration = 15
+	req.Steps[0].Difficulty = domain.Intermediate
+	req.Steps[0].Optional = true
+
+	if _, err := usecase.Do(newPermissionsContext("author"), req); err != nil {
+		t.Fatalf("Expected plan edited, got %v", err)
+	}
+	step := plans.plans[1].Steps[0]
+	if step.Duration != 15 || step.Difficulty != domain.Intermediate || !step.Optional {
+		t.Errorf("Expected step details saved, got %+v", step)
+	}
+	if plans.plans[1].RequiredTime() != 0 || plans.plans[1].EstimatedTime() != 15 {
+		t.Errorf("Expected optional step not required, got %+v", plans.plans[1])
+	}
+}
+
+func TestSourceDuration(t *testing.T) {
+	cases := map[string]int{
+		`{"duration":"90"}`:   90,
+		`{"duration":"-5"}`:   0,
+		`{"isbn10":"1"}`:      0,
+		``:                    0,
+		`{"duration":"long"}`: 0,
+	}
+	for props, expected := range cases {
+		source := &domain.Source{Properties: props}
+		if d := source.Duration(); d != expected {
+			t.Errorf("Expected duration %v for %q, got %v", expected, props, d)
+		}
+	}
+}
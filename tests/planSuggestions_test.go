@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type suggestionPlanRepoForTests struct {
+	*forkPlanRepoForTests
+}
+
+func (r *suggestionPlanRepoForTests) Get(ctx core.ReqContext, id int) *domain.Plan {
+	p, ok := r.plans[id]
+	if !ok || p.IsDraft {
+		return nil
+	}
+	result := *p
+	return &result
+}
+
+func (r *suggestionPlanRepoForTests) Update(ctx core.ReqContext, plan *domain.Plan) (bool, *core.AppError) {
+	if r.plans[plan.Id].Version != plan.Version {
+		return false, core.NewError(core.Conflict)
+	}
+	plan.Version++
+	r.plans[plan.Id] = plan
+	return true, nil
+}
+
+type suggestionRepoForTests struct {
+	core.PlanSuggestionRepository
+	suggestions map[int64]*domain.PlanSuggestion
+}
+
+func (r *suggestionRepoForTests) Add(ctx core.ReqContext, suggestion *domain.PlanSuggestion) (bool, *core.AppError) {
+	suggestion.Id = int64(len(r.suggestions) + 1)
+	s := *suggestion
+	r.suggestions[s.Id] = &s
+	return true, nil
+}
+
+func (r *suggestionRepoForTests) Get(ctx core.ReqContext, id int64) *domain.PlanSuggestion {
+	s, ok := r.suggestions[id]
+	if !ok {
+		return nil
+	}
+	result := *s
+	return &result
+}
+
+func (r *suggestionRepoForTests) Review(ctx core.ReqContext, suggestion *domain.PlanSuggestion) (bool, *core.AppError) {
+	s, ok := r.suggestions[suggestion.Id]
+	if !ok || s.Status != domain.SuggestionPending {
+		return false, nil
+	}
+	s.Status = suggestion.Status
+	s.ReviewedBy = suggestion.ReviewedBy
+	s.Comment = suggestion.Comment
+	return true, nil
+}
+
+func newSuggestionUsecasesForTests() (*suggestionPlanRepoForTests, *suggestionRepoForTests, *changeLogRepoForTests, usecases.SuggestPlanEdit, usecases.AcceptPlanSuggestion, usecases.RejectPlanSuggestion) {
+	plans := &suggestionPlanRepoForTests{newForkPlanRepoForTests()}
+	suggestions := &suggestionRepoForTests{suggestions: make(map[int64]*domain.PlanSuggestion)}
+	sources := &sourceRepoForTests{sources: map[int64]*domain.Source{5: {Id: 5}, 6: {Id: 6}, 7: {Id: 7}}}
+	topics := &topicRepoForTests{topics: map[int]*domain.Topic{}}
+	projects := &projectsRepoForTests{projects: map[int]*domain.Project{}}
+	changes := &changeLogRepoForTests{}
+	changeLog := infrastructure.NewChangesCollector(changes, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	editPlan := usecases.NewEditPlan(plans, newCollaboratorRepoForTests(), &stepRepoForTests{plans: plans.forkPlanRepoForTests}, sources, topics, projects, changeLog, newMarkdownRendererForTests(), appLoggerForTests{})
+
+	suggest := usecases.NewSuggestPlanEdit(suggestions, plans, sources, topics, projects, appLoggerForTests{})
+	accept := usecases.NewAcceptPlanSuggestion(suggestions, plans, editPlan, appLoggerForTests{})
+	reject := usecases.NewRejectPlanSuggestion(suggestions, plans, appLoggerForTests{})
+	return plans, suggestions, changes, suggest, accept, reject
+}
+
+func newSuggestPlanEditReqForTests(planId int) usecases.SuggestPlanEditReq {
+	return usecases.SuggestPlanEditReq{
+		PlanId: planId,
+		Title:  "Go in depth",
+		Steps: []usecases.PlanStep{
+			{ReferenceId: 5, ReferenceType: domain.ResourceReference, Title: "tour"},
+			{ReferenceId: 7, ReferenceType: domain.ResourceReference, Title: "spec"},
+		},
+		Message: "Spec is better than book",
+	}
+}
+
+func TestSuggestPlanEdit(t *testing.T) {
+	_, suggestions, _, suggest, _, _ := newSuggestionUsecasesForTests()
+
+	suggestion, err := suggest.Do(newPermissionsContext("reader", domain.PlanSuggest), newSuggestPlanEditReqForTests(1))
+	if err != nil {
+		t.Fatalf("Expected suggestion saved, got %v", err)
+	}
+	if suggestion.UserId != "reader" || suggestion.Status != domain.SuggestionPending || suggestion.PlanVersion != 1 || len(suggestions.suggestions) != 1 {
+		t.Errorf("Expected pending suggestion of reader, got %+v", suggestion)
+	}
+	if len(suggestion.Steps) != 2 || suggestion.Steps[1].ReferenceId != 7 || suggestion.Steps[1].Position != 1 {
+		t.Errorf("Expected proposed steps stored, got %+v", suggestion.Steps)
+	}
+
+	if _, err := suggest.Do(newPermissionsContext("author", domain.PlanSuggest), newSuggestPlanEditReqForTests(1)); err == nil {
+		t.Error("Expected owner not allowed to suggest changes of own plan")
+	}
+	if _, err := suggest.Do(newPermissionsContext("reader", domain.PlanSuggest), newSuggestPlanEditReqForTests(2)); err == nil {
+		t.Error("Expected changes of draft not suggested")
+	}
+	if _, err := suggest.Do(newPermissionsContext("reader"), newSuggestPlanEditReqForTests(1)); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied without permission, got %v", err)
+	}
+}
+
+func TestAcceptPlanSuggestion(t *testing.T) {
+	plans, suggestions, changes, suggest, accept, _ := newSuggestionUsecasesForTests()
+	suggestion, _ := suggest.Do(newPermissionsContext("reader", domain.PlanSuggest), newSuggestPlanEditReqForTests(1))
+
+	if _, err := accept.Do(newPermissionsContext("other"), suggestion.Id); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected only owner accepts suggestion, got %v", err)
+	}
+
+	accepted, err := accept.Do(newPermissionsContext("author"), suggestion.Id)
+	if err != nil || !accepted {
+		t.Fatalf("Expected suggestion accepted, got %v", err)
+	}
+
+	plan := plans.plans[1]
+	if plan.Title != "Go in depth" || len(plan.Steps) != 2 || plan.Steps[1].ReferenceId != 7 || plan.OwnerId != "author" {
+		t.Errorf("Expected proposed changes applied to plan, got %+v", plan)
+	}
+	if len(changes.records) != 1 || changes.records[0].UserId != "reader" {
+		t.Errorf("Expected change attributed to proposer, got %+v", changes.records)
+	}
+	if s := suggestions.suggestions[suggestion.Id]; s.Status != domain.SuggestionAccepted || s.ReviewedBy != "author" {
+		t.Errorf("Expected suggestion accepted by owner, got %+v", s)
+	}
+
+	if _, err := accept.Do(newPermissionsContext("author"), suggestion.Id); err == nil {
+		t.Error("Expected reviewed suggestion not accepted twice")
+	}
+}
+
+func TestAcceptPlanSuggestionToEditedPlan(t *testing.T) {
+	plans, suggestions, _, suggest, accept, _ := newSuggestionUsecasesForTests()
+	suggestion, _ := suggest.Do(newPermissionsContext("reader", domain.PlanSuggest), newSuggestPlanEditReqForTests(1))
+	plans.plans[1].Title = "Go by author"
+	plans.plans[1].Version = 2
+
+	_, err := accept.Do(newPermissionsContext("author"), suggestion.Id)
+	conflict, ok := err.(*core.ConflictError)
+	if !ok {
+		t.Fatalf("Expected conflict for plan edited after suggestion, got %v", err)
+	}
+	if current, ok := conflict.Current.(*domain.Plan); !ok || current.Version != 2 {
+		t.Errorf("Expected current plan in conflict, got %+v", conflict.Current)
+	}
+	if plans.plans[1].Title != "Go by author" || suggestions.suggestions[suggestion.Id].Status != domain.SuggestionPending {
+		t.Error("Expected plan unchanged and suggestion pending after conflict")
+	}
+}
+
+func TestRejectPlanSuggestion(t *testing.T) {
+	plans, suggestions, changes, suggest, _, reject := newSuggestionUsecasesForTests()
+	suggestion, _ := suggest.Do(newPermissionsContext("reader", domain.PlanSuggest), newSuggestPlanEditReqForTests(1))
+
+	if _, err := reject.Do(newPermissionsContext("reader"), suggestion.Id, ""); err == nil {
+		t.Error("Expected proposer not allowed to reject suggestion")
+	}
+
+	rejected, err := reject.Do(newPermissionsContext("author"), suggestion.Id, "Book is fine")
+	if err != nil || !rejected {
+		t.Fatalf("Expected suggestion rejected, got %v", err)
+	}
+	if s := suggestions.suggestions[suggestion.Id]; s.Status != domain.SuggestionRejected || s.Comment != "Book is fine" {
+		t.Errorf("Expected rejection with comment stored, got %+v", s)
+	}
+	if plans.plans[1].Title != "Go" || len(changes.records) != 0 {
+		t.Error("Expected plan unchanged after rejection")
+	}
+}
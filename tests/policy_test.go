@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+func newPermissionsContext(userId string, permissions ...domain.Permission) core.ReqContext {
+	ctx := context.WithValue(context.Background(), infrastructure.ReqUserId, userId)
+	ctx = context.WithValue(ctx, infrastructure.ReqPermissions, domain.NewPermissionSet(permissions...))
+	return infrastructure.NewContext(ctx)
+}
+
+func TestIsAllowedByPermission(t *testing.T) {
+	ctx := newPermissionsContext("moderator", domain.PlanEdit)
+	if !core.IsAllowed(ctx, domain.PlanEdit, "owner") {
+		t.Error("User with permission should be allowed")
+	}
+
+	if core.IsAllowed(ctx, domain.PlanRemove, "owner") {
+		t.Error("User without permission should not be allowed")
+	}
+}
+
+func TestIsAllowedForOwner(t *testing.T) {
+	ctx := newPermissionsContext("owner")
+	if !core.IsAllowed(ctx, domain.PlanEdit, "owner") {
+		t.Error("Owner should be allowed to edit own plan")
+	}
+
+	if core.IsAllowed(ctx, domain.TagManage, "owner") {
+		t.Error("Owner should get owner permissions only")
+	}
+
+	if core.IsAllowed(ctx, domain.TopicEdit, "owner") {
+		t.Error("Topics should be edited by moderators only")
+	}
+
+	if core.IsAllowed(ctx, domain.PlanEdit, "other") {
+		t.Error("Not owner should not be allowed")
+	}
+}
+
+func TestIsAllowedAnonymous(t *testing.T) {
+	ctx := infrastructure.NewContext(context.Background())
+	if core.IsAllowed(ctx, domain.PlanEdit, "") {
+		t.Error("Anonymous user should not be allowed")
+	}
+}
+
+func TestPermissionSetList(t *testing.T) {
+	set := domain.NewPermissionSet(domain.DevTools, domain.TopicAdd, domain.TopicAdd)
+	list := set.List()
+	if len(list) != 2 || list[0] != domain.TopicAdd || list[1] != domain.DevTools {
+		t.Errorf("Unexpected permissions list: %v", list)
+	}
+
+	if domain.Permission("unknown").IsValid() {
+		t.Error("Unknown permission should not be valid")
+	}
+}
+
+func TestUserIsBanned(t *testing.T) {
+	user := domain.User{Banned: true}
+	if !user.IsBanned() {
+		t.Error("User without ban expiry should be banned permanently")
+	}
+
+	user.BannedUntil = time.Now().Add(time.Hour)
+	if !user.IsBanned() {
+		t.Error("User should be banned until expiry")
+	}
+
+	user.BannedUntil = time.Now().Add(-time.Hour)
+	if user.IsBanned() {
+		t.Error("Ban should be expired")
+	}
+}
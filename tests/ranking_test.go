@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+func votesForTests(now time.Time, age time.Duration, values ...int) []domain.Vote {
+	votes := make([]domain.Vote, len(values))
+	for i, v := range values {
+		votes[i] = domain.Vote{Value: v, Date: now.Add(-age), Weight: 1}
+	}
+	return votes
+}
+
+func TestBayesianRanker(t *testing.T) {
+	now := time.Now()
+	ranker := core.NewBayesianRanker(7, 10)
+
+	if score := ranker.Score(nil, now); score != 7 {
+		t.Errorf("Expected prior for plan without votes, got %v", score)
+	}
+	few := ranker.Score(votesForTests(now, 0, 10, 10), now)
+	many := ranker.Score(votesForTests(now, 0, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9), now)
+	if few >= many {
+		t.Errorf("Expected many good votes outrank a few excellent ones, got %v >= %v", few, many)
+	}
+}
+
+func TestWilsonRanker(t *testing.T) {
+	now := time.Now()
+	ranker := core.NewWilsonRanker(1.96)
+
+	if score := ranker.Score(nil, now); score != 0 {
+		t.Errorf("Expected zero for plan without votes, got %v", score)
+	}
+	one := ranker.Score(votesForTests(now, 0, 10), now)
+	many := ranker.Score(votesForTests(now, 0, 10, 10, 10, 10, 10, 10, 10, 10, 10, 9), now)
+	bad := ranker.Score(votesForTests(now, 0, 1, 2, 1, 2, 1, 2, 1, 2, 1, 2), now)
+	if one >= many || bad >= one || bad < 0 || many > 1 {
+		t.Errorf("Expected confidence to grow with count of votes, got one=%v many=%v bad=%v", one, many, bad)
+	}
+}
+
+func TestHotAndTrendingRankers(t *testing.T) {
+	now := time.Now()
+	hot := core.NewHotRanker(72 * time.Hour)
+	trending := core.NewTrendingRanker(7)
+
+	fresh := votesForTests(now, time.Hour, 10, 10, 10)
+	old := votesForTests(now, 30*24*time.Hour, 10, 10, 10, 10, 10)
+	if hot.Score(fresh, now) <= hot.Score(old, now) {
+		t.Errorf("Expected fresh votes outrank old ones, got %v <= %v", hot.Score(fresh, now), hot.Score(old, now))
+	}
+	if hot.Score(votesForTests(now, time.Hour, 1), now) >= 0 {
+		t.Error("Expected bad vote decrease hot score")
+	}
+
+	if score := trending.Score(old, now); score != 0 {
+		t.Errorf("Expected votes out of window ignored, got %v", score)
+	}
+	if score := trending.Score(fresh, now); score != 3 {
+		t.Errorf("Expected votes of window counted, got %v", score)
+	}
+}
+
+type rankingVotesRepoForTests struct {
+	core.PointsRepository
+	votes []domain.EntityVotes
+}
+
+type rankingPlanRepoForTests struct {
+	core.PlanRepository
+	scores map[domain.PlanSort]map[int64]float64
+	sort   domain.PlanSort
+	listed []int64
+}
+
+func (r *rankingVotesRepoForTests) GetVotes(entityType domain.EntityType) ([]domain.EntityVotes, *core.AppError) {
+	return r.votes, nil
+}
+
+func (r *rankingPlanRepoForTests) SaveScores(sort domain.PlanSort, scores map[int64]float64) *core.AppError {
+	r.scores[sort] = scores
+	return nil
+}
+
+func (r *rankingPlanRepoForTests) GetListedIds() ([]int64, *core.AppError) {
+	return r.listed, nil
+}
+
+func (r *rankingPlanRepoForTests) GetPopularByTopic(ctx core.ReqContext, topic string, sort domain.PlanSort, count int) []domain.Plan {
+	r.sort = sort
+	return []domain.Plan{}
+}
+
+func TestRankingJob(t *testing.T) {
+	now := time.Now()
+	votes := &rankingVotesRepoForTests{votes: []domain.EntityVotes{
+		{EntityId: 1, Votes: votesForTests(now, time.Hour, 10, 9)},
+		{EntityId: 2, Votes: votesForTests(now, 40*24*time.Hour, 3)},
+	}}
+	repo := &rankingPlanRepoForTests{scores: make(map[domain.PlanSort]map[int64]float64), listed: []int64{1, 2}}
+	rankers := []core.Ranker{core.NewBayesianRanker(7, 10), core.NewTrendingRanker(7)}
+	job := infrastructure.NewRankingJob(votes, repo, rankers, time.Hour, appLoggerForTests{})
+
+	job.Run(now)
+	if len(repo.scores) != 2 || len(repo.scores[domain.PlanSortRating]) != 2 || len(repo.scores[domain.PlanSortTrending]) != 2 {
+		t.Fatalf("Expected scores saved for every ranker, got %+v", repo.scores)
+	}
+	if repo.scores[domain.PlanSortTrending][2] != 0 || repo.scores[domain.PlanSortRating][1] <= repo.scores[domain.PlanSortRating][2] {
+		t.Errorf("Expected scores computed by rankers, got %+v", repo.scores)
+	}
+}
+
+func TestRankingJobScoresUnvotedPlans(t *testing.T) {
+	now := time.Now()
+	votes := &rankingVotesRepoForTests{votes: []domain.EntityVotes{
+		{EntityId: 1, Votes: votesForTests(now, time.Hour, 10, 10, 9)},
+		{EntityId: 2, Votes: votesForTests(now, time.Hour, 1, 2, 1)},
+	}}
+	repo := &rankingPlanRepoForTests{scores: make(map[domain.PlanSort]map[int64]float64), listed: []int64{1, 2, 3}}
+	rankers := []core.Ranker{core.NewBayesianRanker(7, 10), core.NewHotRanker(72 * time.Hour)}
+	job := infrastructure.NewRankingJob(votes, repo, rankers, time.Hour, appLoggerForTests{})
+
+	job.Run(now)
+	for sort, scores := range repo.scores {
+		unvoted, ok := scores[3]
+		if !ok {
+			t.Fatalf("Expected score of unvoted plan saved for %s", sort)
+		}
+		if unvoted <= scores[2] || unvoted >= scores[1] {
+			t.Errorf("Expected unvoted plan between good and bad ones for %s, got %+v", sort, scores)
+		}
+	}
+}
+
+func TestGetPlanListSort(t *testing.T) {
+	repo := &rankingPlanRepoForTests{}
+	usecase := usecases.NewGetPlanList(repo, &usersListRepoForTests{}, appLoggerForTests{})
+	ctx := newUserContext("reader")
+
+	if _, err := usecase.Do(ctx, "golang", "", 10); err != nil || repo.sort != domain.PlanSortRating {
+		t.Errorf("Expected rating by default, got %q %v", repo.sort, err)
+	}
+	if _, err := usecase.Do(ctx, "golang", domain.PlanSortHot, 10); err != nil || repo.sort != domain.PlanSortHot {
+		t.Errorf("Expected hot plans, got %q %v", repo.sort, err)
+	}
+	if _, err := usecase.Do(ctx, "golang", domain.PlanSort("random"), 10); err == nil {
+		t.Error("Expected error for unknown sort")
+	}
+}
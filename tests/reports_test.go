@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type reportRepoForTests struct {
+	core.ReportRepository
+	reports map[int64]*domain.Report
+}
+
+func (r *reportRepoForTests) Get(ctx core.ReqContext, id int64) *domain.Report {
+	report, ok := r.reports[id]
+	if !ok {
+		return nil
+	}
+	copied := *report
+	return &copied
+}
+
+func (r *reportRepoForTests) Resolve(ctx core.ReqContext, report *domain.Report) (bool, *core.AppError) {
+	saved := r.reports[report.Id]
+	if saved.Status != domain.ReportOpen {
+		return false, nil
+	}
+	saved.Status = report.Status
+	saved.ResolvedBy = report.ResolvedBy
+	return true, nil
+}
+
+func (r *reportRepoForTests) Reopen(ctx core.ReqContext, report *domain.Report) (bool, *core.AppError) {
+	saved := r.reports[report.Id]
+	if saved.Status != report.Status {
+		return false, nil
+	}
+	saved.Status = domain.ReportOpen
+	saved.ResolvedBy = ""
+	return true, nil
+}
+
+type hiddenPlanRepoForTests struct {
+	core.PlanRepository
+	plans   map[int]*domain.Plan
+	updates int
+}
+
+func (r *hiddenPlanRepoForTests) Get(ctx core.ReqContext, id int) *domain.Plan {
+	plan, ok := r.plans[id]
+	if !ok || plan.Hidden {
+		return nil
+	}
+	return plan
+}
+
+func (r *hiddenPlanRepoForTests) GetHidden(ctx core.ReqContext, id int) *domain.Plan {
+	plan, ok := r.plans[id]
+	if !ok || !plan.Hidden {
+		return nil
+	}
+	return plan
+}
+
+func (r *hiddenPlanRepoForTests) SetHidden(ctx core.ReqContext, id int, hidden bool) (bool, *core.AppError) {
+	r.updates++
+	r.plans[id].Hidden = hidden
+	return true, nil
+}
+
+func newReportsForTests() (*reportRepoForTests, *hiddenPlanRepoForTests, core.ChangeLog) {
+	reports := &reportRepoForTests{reports: map[int64]*domain.Report{
+		1: {Id: 1, EntityType: domain.PlanEntity, EntityId: 5, Status: domain.ReportOpen},
+		2: {Id: 2, EntityType: domain.TopicEntity, EntityId: 3, Status: domain.ReportOpen},
+	}}
+	plans := &hiddenPlanRepoForTests{plans: map[int]*domain.Plan{5: {Id: 5, Title: "Go", OwnerId: "author"}}}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	return reports, plans, changeLog
+}
+
+func TestResolveReportHidesOnce(t *testing.T) {
+	reports, plans, changeLog := newReportsForTests()
+	usecase := usecases.NewResolveReport(reports, plans, &commentsRepoForTests{}, nil, nil, nil, nil, changeLog, &appLoggerForTests{})
+
+	resolved, err := usecase.Do(newPermissionsContext("moder", domain.ReportModerate), 1, domain.ReportHidden, "")
+	if err != nil || !resolved {
+		t.Fatalf("Report not resolved: %v", err)
+	}
+	if !plans.plans[5].Hidden || plans.updates != 1 {
+		t.Fatalf("Plan not hidden once: hidden %v, updates %d", plans.plans[5].Hidden, plans.updates)
+	}
+
+	resolved, err = usecase.Do(newPermissionsContext("other", domain.ReportModerate), 1, domain.ReportHidden, "")
+	if err == nil || resolved {
+		t.Error("Resolved report resolved twice")
+	}
+	if plans.updates != 1 {
+		t.Errorf("Content changed by second moderator, updates %d", plans.updates)
+	}
+}
+
+func TestResolveReportRejectsHidingTopic(t *testing.T) {
+	reports, plans, changeLog := newReportsForTests()
+	usecase := usecases.NewResolveReport(reports, plans, &commentsRepoForTests{}, nil, nil, nil, nil, changeLog, &appLoggerForTests{})
+
+	_, err := usecase.Do(newPermissionsContext("moder", domain.ReportModerate), 2, domain.ReportHidden, "")
+	if err == nil {
+		t.Fatal("Topic hidden")
+	}
+	if reports.reports[2].Status != domain.ReportOpen {
+		t.Errorf("Report closed on invalid resolution: %v", reports.reports[2].Status)
+	}
+}
+
+func TestUnhideContent(t *testing.T) {
+	reports, plans, changeLog := newReportsForTests()
+	resolve := usecases.NewResolveReport(reports, plans, &commentsRepoForTests{}, nil, nil, nil, nil, changeLog, &appLoggerForTests{})
+	if _, err := resolve.Do(newPermissionsContext("moder", domain.ReportModerate), 1, domain.ReportHidden, ""); err != nil {
+		t.Fatalf("Report not resolved: %v", err)
+	}
+
+	unhide := usecases.NewUnhideContent(plans, &commentsRepoForTests{}, changeLog, &appLoggerForTests{})
+	if _, err := unhide.Do(newPermissionsContext("moder", domain.ReportModerate), domain.PlanEntity, 5); err == nil {
+		t.Error("Plan shown without content.restore")
+	}
+
+	shown, err := unhide.Do(newPermissionsContext("admin", domain.ContentRestore), domain.PlanEntity, 5)
+	if err != nil || !shown {
+		t.Fatalf("Plan not shown: %v", err)
+	}
+	if plans.plans[5].Hidden {
+		t.Error("Plan still hidden")
+	}
+
+	if _, err := unhide.Do(newPermissionsContext("admin", domain.ContentRestore), domain.PlanEntity, 5); err == nil {
+		t.Error("Visible plan shown again")
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type policyForTests struct {
+	core.AccessPolicy
+	reset []string
+}
+
+func (p *policyForTests) ResetUser(userId string) {
+	p.reset = append(p.reset, userId)
+}
+
+type reputationForTests struct {
+	votes   [][2]int
+	sources []int64
+}
+
+func (r *reputationForTests) VoteChanged(ctx core.ReqContext, entityType domain.EntityType, entityId int64, before int, after int) {
+	r.votes = append(r.votes, [2]int{before, after})
+}
+
+func (r *reputationForTests) SourceAdded(ctx core.ReqContext, sourceId int64) {
+	r.sources = append(r.sources, sourceId)
+}
+
+func newReputationForTests() (core.Reputation, *changeLogRepoForTests, *policyForTests) {
+	changes := &changeLogRepoForTests{}
+	policy := &policyForTests{}
+	plans := &planRepoForTests{plans: map[int]*domain.Plan{1: {Id: 1, OwnerId: "owner"}}}
+	conf := infrastructure.ReputationConf{UpvoteValue: 6, PlanUpvote: 10, CommentUpvote: 2, SourceAdded: 3}
+	return infrastructure.NewReputationEngine(changes, plans, newThreadsRepoForTests(), policy, conf, appLoggerForTests{}), changes, policy
+}
+
+func TestReputationForVotes(t *testing.T) {
+	reputation, changes, policy := newReputationForTests()
+	ctx := newChangeLogContext()
+
+	reputation.VoteChanged(ctx, domain.PlanEntity, 1, 0, 8)
+	reputation.VoteChanged(ctx, domain.PlanEntity, 1, 8, 9)
+	reputation.VoteChanged(ctx, domain.PlanEntity, 1, 9, 3)
+	reputation.VoteChanged(ctx, domain.PlanEntity, 1, 3, 0)
+	reputation.VoteChanged(ctx, domain.CommentEntity, 11, 0, 10)
+	reputation.VoteChanged(ctx, domain.TopicEntity, 2, 0, 10)
+
+	expected := []int{10, -10, 2}
+	if len(changes.records) != len(expected) {
+		t.Fatalf("Expected %d awards, got %+v", len(expected), changes.records)
+	}
+	for i, points := range expected {
+		if changes.records[i].Points != points || changes.records[i].Action != domain.AwardReputation {
+			t.Errorf("Expected award of %d points, got %+v", points, changes.records[i])
+		}
+	}
+	if changes.records[0].UserId != "owner" || changes.records[0].EntityId != 1 || changes.records[2].UserId != "replier" {
+		t.Errorf("Expected authors awarded, got %+v", changes.records)
+	}
+	if len(policy.reset) != 3 || policy.reset[0] != "owner" {
+		t.Errorf("Expected cached permissions of awarded users reset, got %v", policy.reset)
+	}
+}
+
+func TestReputationNotAwardedForOwnContent(t *testing.T) {
+	reputation, changes, _ := newReputationForTests()
+
+	reputation.VoteChanged(newUserContext("owner"), domain.PlanEntity, 1, 0, 10)
+	reputation.VoteChanged(newUserContext("replier"), domain.CommentEntity, 11, 0, 10)
+	if len(changes.records) != 0 {
+		t.Errorf("Expected no awards for own content, got %+v", changes.records)
+	}
+
+	reputation.SourceAdded(newUserContext("author"), 5)
+	if len(changes.records) != 1 || changes.records[0].UserId != "author" || changes.records[0].Points != 3 || changes.records[0].EntityType != domain.ResourceEntity {
+		t.Errorf("Expected award for added source, got %+v", changes.records)
+	}
+}
+
+func TestReputationForSourcesLimitedPerDay(t *testing.T) {
+	changes := &changeLogRepoForTests{}
+	conf := infrastructure.ReputationConf{SourceAdded: 2, SourcesPerDay: 2}
+	reputation := infrastructure.NewReputationEngine(changes, &planRepoForTests{}, newThreadsRepoForTests(), &policyForTests{}, conf, appLoggerForTests{})
+
+	for id := int64(1); id <= 3; id++ {
+		reputation.SourceAdded(newUserContext("author"), id)
+	}
+	reputation.SourceAdded(newUserContext("other"), 4)
+
+	if len(changes.records) != 3 || changes.records[2].UserId != "other" {
+		t.Errorf("Expected 2 sources of user awarded a day, got %+v", changes.records)
+	}
+}
+
+type rolesRepoForTests struct {
+	core.RoleRepository
+}
+
+func (r *rolesRepoForTests) GetList(ctx core.ReqContext) []domain.Role {
+	return []domain.Role{{Id: 1, Name: domain.UserRole, Permissions: []domain.Permission{domain.CommentAdd}}}
+}
+
+func (r *rolesRepoForTests) GetByUser(ctx core.ReqContext, userId string) []domain.Role {
+	return []domain.Role{}
+}
+
+type stateRepoForTests struct {
+	core.UserRepository
+	users map[string]*domain.User
+}
+
+func (r *stateRepoForTests) Get(ctx core.ReqContext, id string) *domain.User {
+	return r.users[id]
+}
+
+func TestPermissionsUnlockedByReputation(t *testing.T) {
+	users := &stateRepoForTests{users: map[string]*domain.User{
+		"newbie":      {Id: "newbie", EmailConfirmed: true, Reputation: 10},
+		"expert":      {Id: "expert", EmailConfirmed: true, Reputation: 600},
+		"unconfirmed": {Id: "unconfirmed", Reputation: 2000},
+	}}
+	unlocks := map[domain.Permission]int{domain.TopicEdit: 500, domain.TagManage: 1000}
+	policy := infrastructure.NewRolePolicy(&rolesRepoForTests{}, users, infrastructure.NewInMemoryCache(), []domain.Permission{domain.TagManage}, unlocks, appLoggerForTests{})
+	ctx := newUserContext("admin")
+
+	if p := policy.Permissions(ctx, "newbie"); p.Has(domain.TopicEdit) || !p.Has(domain.CommentAdd) {
+		t.Errorf("Expected role permissions only, got %v", p.List())
+	}
+	if p := policy.Permissions(ctx, "expert"); !p.Has(domain.TopicEdit) || p.Has(domain.TagManage) {
+		t.Errorf("Expected topic editing unlocked, got %v", p.List())
+	}
+	if p := policy.Permissions(ctx, "unconfirmed"); !p.Has(domain.TopicEdit) || p.Has(domain.TagManage) {
+		t.Errorf("Expected unlocked permission restricted until email confirmed, got %v", p.List())
+	}
+
+	users.users["newbie"].Reputation = 550
+	policy.ResetUser("newbie")
+	if p := policy.Permissions(ctx, "newbie"); !p.Has(domain.TopicEdit) {
+		t.Errorf("Expected permission unlocked after reset, got %v", p.List())
+	}
+}
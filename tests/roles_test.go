@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type savedRolesRepoForTests struct {
+	core.RoleRepository
+	roles map[int]*domain.Role
+	users map[string][]int
+}
+
+func (r *savedRolesRepoForTests) GetByUser(ctx core.ReqContext, userId string) []domain.Role {
+	roles := []domain.Role{}
+	for _, id := range r.users[userId] {
+		roles = append(roles, *r.roles[id])
+	}
+	return roles
+}
+
+func (r *savedRolesRepoForTests) RemoveFromUser(ctx core.ReqContext, userId string, roleId int, keepLast bool) (bool, *core.AppError) {
+	holders := 0
+	for _, ids := range r.users {
+		for _, id := range ids {
+			if id == roleId {
+				holders++
+			}
+		}
+	}
+	for i, id := range r.users[userId] {
+		if id != roleId {
+			continue
+		}
+		if keepLast && holders == 1 {
+			return false, core.NewError(core.InUse)
+		}
+		r.users[userId] = append(r.users[userId][:i], r.users[userId][i+1:]...)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *savedRolesRepoForTests) Get(ctx core.ReqContext, id int) *domain.Role {
+	return r.roles[id]
+}
+
+func (r *savedRolesRepoForTests) Update(ctx core.ReqContext, role *domain.Role) (bool, *core.AppError) {
+	r.roles[role.Id] = role
+	return true, nil
+}
+
+type rolesPolicyForTests struct {
+	core.AccessPolicy
+}
+
+func (p *rolesPolicyForTests) ResetRoles() {}
+
+func (p *rolesPolicyForTests) ResetUser(userId string) {}
+
+type countingUsersRepoForTests struct {
+	stateRepoForTests
+	reads int
+}
+
+func (r *countingUsersRepoForTests) Get(ctx core.ReqContext, id string) *domain.User {
+	r.reads++
+	return r.stateRepoForTests.Get(ctx, id)
+}
+
+func TestSaveRoleKeepsRoleManagementOfAdmin(t *testing.T) {
+	roles := &savedRolesRepoForTests{roles: map[int]*domain.Role{
+		1: {Id: 1, Name: domain.ModeratorRole, Permissions: []domain.Permission{domain.TopicEdit}},
+		2: {Id: 2, Name: domain.AdminRole, Permissions: []domain.Permission{domain.RoleManage, domain.DevTools}},
+	}}
+	usecase := usecases.NewSaveRole(roles, &rolesPolicyForTests{}, appLoggerForTests{})
+	ctx := newPermissionsContext("admin", domain.RoleManage)
+
+	_, err := usecase.Do(ctx, 2, domain.AdminRole, []domain.Permission{domain.DevTools})
+	appErr, ok := err.(*core.AppError)
+	if !ok || appErr.Validation["permissions"] != core.AccessDenied.String() {
+		t.Errorf("Expected role management not removed from admin, got %v", err)
+	}
+	if !domain.NewPermissionSet(roles.roles[2].Permissions...).Has(domain.RoleManage) {
+		t.Error("Expected admin role unchanged")
+	}
+
+	if _, err := usecase.Do(ctx, 2, domain.AdminRole, []domain.Permission{domain.RoleManage}); err != nil {
+		t.Errorf("Expected other permissions of admin editable, got %v", err)
+	}
+	if _, err := usecase.Do(ctx, 1, domain.ModeratorRole, []domain.Permission{domain.TagManage}); err != nil {
+		t.Errorf("Expected moderator role edited, got %v", err)
+	}
+}
+
+func TestRevokeRoleKeepsLastAdmin(t *testing.T) {
+	roles := &savedRolesRepoForTests{
+		roles: map[int]*domain.Role{
+			1: {Id: 1, Name: domain.ModeratorRole},
+			2: {Id: 2, Name: domain.AdminRole},
+		},
+		users: map[string][]int{"admin": {1, 2}},
+	}
+	users := &stateRepoForTests{users: map[string]*domain.User{"admin": {Id: "admin"}, "other": {Id: "other"}}}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	usecase := usecases.NewRevokeRole(roles, users, &rolesPolicyForTests{}, changeLog, appLoggerForTests{})
+	ctx := newPermissionsContext("admin", domain.RoleManage)
+
+	_, err := usecase.Do(ctx, "admin", 2)
+	if appErr, ok := err.(*core.AppError); !ok || appErr.Validation["roleId"] != core.InUse.String() {
+		t.Errorf("Expected last admin kept, got %v", err)
+	}
+	if revoked, err := usecase.Do(ctx, "admin", 1); !revoked || err != nil {
+		t.Errorf("Expected other roles of last admin revoked, got %v", err)
+	}
+
+	roles.users["other"] = []int{2}
+	if revoked, err := usecase.Do(ctx, "admin", 2); !revoked || err != nil {
+		t.Errorf("Expected admin role revoked while other admin exists, got %v", err)
+	}
+}
+
+func TestUnknownSessionCached(t *testing.T) {
+	users := &countingUsersRepoForTests{stateRepoForTests: stateRepoForTests{users: map[string]*domain.User{
+		"user": {Id: "user", Tokens: []domain.UserToken{{Id: "session"}}},
+	}}}
+	policy := infrastructure.NewRolePolicy(&rolesRepoForTests{}, users, infrastructure.NewInMemoryCache(), nil, nil, appLoggerForTests{})
+	ctx := newUserContext("user")
+
+	for i := 0; i < 3; i++ {
+		if policy.IsActive(ctx, "user", "forged") {
+			t.Fatal("Expected unknown session not active")
+		}
+	}
+	if users.reads != 2 {
+		t.Errorf("Expected unknown session read from db once, got %d reads", users.reads)
+	}
+	if !policy.IsActive(ctx, "user", "session") || users.reads != 2 {
+		t.Errorf("Expected known session taken from cache, got %d reads", users.reads)
+	}
+}
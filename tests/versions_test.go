@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+func (r *topicRepoForTests) Update(ctx core.ReqContext, topic *domain.Topic) (bool, *core.AppError) {
+	if r.topics[topic.Id].Version != topic.Version {
+		return false, core.NewError(core.Conflict)
+	}
+	topic.Version++
+	r.topics[topic.Id] = topic
+	return true, nil
+}
+
+func newVersionedEditPlanForTests() (*suggestionPlanRepoForTests, usecases.EditPlan) {
+	plans := &suggestionPlanRepoForTests{newForkPlanRepoForTests()}
+	sources := &sourceRepoForTests{sources: map[int64]*domain.Source{5: {Id: 5}}}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	usecase := usecases.NewEditPlan(plans, newCollaboratorRepoForTests(), &stepRepoForTests{plans: plans.forkPlanRepoForTests}, sources, &topicRepoForTests{}, &projectsRepoForTests{}, changeLog, newMarkdownRendererForTests(), appLoggerForTests{})
+	return plans, usecase
+}
+
+func newEditPlanReqForTests(version int) usecases.EditPlanReq {
+	return usecases.EditPlanReq{
+		Id:        1,
+		TopicName: "golang",
+		Title:     "Go basics",
+		Steps:     []usecases.PlanStep{{ReferenceId: 5, ReferenceType: domain.ResourceReference, Title: "tour"}},
+		Version:   version,
+	}
+}
+
+func TestEditPlanIncrementsVersion(t *testing.T) {
+	plans, usecase := newVersionedEditPlanForTests()
+
+	if _, err := usecase.Do(newPermissionsContext("author"), newEditPlanReqForTests(1)); err != nil {
+		t.Fatalf("Expected plan edited, got %v", err)
+	}
+	if plans.plans[1].Version != 2 || plans.plans[1].Title != "Go basics" {
+		t.Errorf("Expected plan saved with next version, got %+v", plans.plans[1])
+	}
+}
+
+func TestEditPlanConflict(t *testing.T) {
+	plans, usecase := newVersionedEditPlanForTests()
+	plans.plans[1].Version = 3
+
+	_, err := usecase.Do(newPermissionsContext("author"), newEditPlanReqForTests(2))
+	conflict, ok := err.(*core.ConflictError)
+	if !ok {
+		t.Fatalf("Expected conflict for stale version, got %v", err)
+	}
+	current, ok := conflict.Current.(*domain.Plan)
+	if !ok || current.Version != 3 || current.Title != "Go" || len(current.Steps) != 2 {
+		t.Errorf("Expected current plan with steps in conflict, got %+v", conflict.Current)
+	}
+	if plans.plans[1].Title != "Go" {
+		t.Error("Expected plan unchanged after conflict")
+	}
+}
+
+func TestEditPlanWithoutVersion(t *testing.T) {
+	_, usecase := newVersionedEditPlanForTests()
+
+	_, err := usecase.Do(newPermissionsContext("author"), newEditPlanReqForTests(0))
+	appErr, ok := err.(*core.AppError)
+	if !ok || appErr.Validation["version"] != core.InvalidValue.String() {
+		t.Errorf("Expected version required, got %v", err)
+	}
+}
+
+func TestEditTopicConflict(t *testing.T) {
+	topics := &topicRepoForTests{topics: map[int]*domain.Topic{
+		1: {Id: 1, Name: "golang", Title: "Golang", Creator: "author", Version: 2},
+	}}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, &appLoggerForTests{})
+	usecase := usecases.NewEditTopic(topics, changeLog, newMarkdownRendererForTests(), appLoggerForTests{})
+	ctx := newPermissionsContext("author", domain.TopicEdit)
+
+	_, err := usecase.Do(ctx, 1, 1, "Golang", "Go language", false)
+	conflict, ok := err.(*core.ConflictError)
+	if !ok {
+		t.Fatalf("Expected conflict for stale version, got %v", err)
+	}
+	if current, ok := conflict.Current.(*domain.Topic); !ok || current.Version != 2 {
+		t.Errorf("Expected current topic in conflict, got %+v", conflict.Current)
+	}
+
+	if _, err := usecase.Do(ctx, 1, 2, "Golang", "Go language", false); err != nil {
+		t.Fatalf("Expected topic edited with current version, got %v", err)
+	}
+	if topics.topics[1].Version != 3 {
+		t.Errorf("Expected version incremented, got %v", topics.topics[1].Version)
+	}
+}
+
+func TestEditCommentConflict(t *testing.T) {
+	repo := &revisionsRepoForTests{threadsRepoForTests: *newThreadsRepoForTests()}
+	repo.comments[10].Version = 4
+	broadcaster, _ := newThreadBroadcasterForTests(repo)
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, &webhookPublisherForTests{}, appLoggerForTests{})
+	edit := usecases.NewEditComment(repo, changeLog, broadcaster, newMarkdownRendererForTests(), appLoggerForTests{})
+
+	_, err := edit.Do(newPermissionsContext("author"), 10, 3, "stale", "")
+	conflict, ok := err.(*core.ConflictError)
+	if !ok {
+		t.Fatalf("Expected conflict for stale version, got %v", err)
+	}
+	if current, ok := conflict.Current.(*domain.Comment); !ok || current.Version != 4 {
+		t.Errorf("Expected current comment in conflict, got %+v", conflict.Current)
+	}
+	if len(repo.revisions) != 0 {
+		t.Error("Expected no revision saved after conflict")
+	}
+}
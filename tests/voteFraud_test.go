@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type voteGuardForTests struct {
+	err  *core.AppError
+	flag bool
+}
+
+func (g *voteGuardForTests) Check(ctx core.ReqContext, vote *domain.VoteRecord) *core.AppError {
+	vote.Weight = 1
+	vote.Flagged = g.flag
+	return g.err
+}
+
+type recentVotesRepoForTests struct {
+	core.PointsRepository
+	byUser int
+	byIP   int
+}
+
+func (r *recentVotesRepoForTests) CountRecent(ctx core.ReqContext, userId string, ip string, since time.Time) (int, int) {
+	return r.byUser, r.byIP
+}
+
+func newVoteGuardForTests(points core.PointsRepository) core.VoteGuard {
+	users := &stateRepoForTests{users: map[string]*domain.User{
+		"owner":    {Id: "owner", EmailConfirmed: true},
+		"old":      {Id: "old", EmailConfirmed: true, Created: time.Now().AddDate(-1, 0, 0)},
+		"legacy":   {Id: "legacy", EmailConfirmed: true},
+		"newbie":   {Id: "newbie", EmailConfirmed: true, Created: time.Now().Add(-time.Hour)},
+		"stranger": {Id: "stranger", Created: time.Now().Add(-time.Hour)},
+	}}
+	plans := &planRepoForTests{plans: map[int]*domain.Plan{1: {Id: 1, OwnerId: "owner"}}}
+	return infrastructure.NewVoteGuard(points, users, plans, nil, nil,
+		infrastructure.VoteGuardConf{
+			Window:            time.Hour,
+			MaxPerUser:        10,
+			MaxPerIP:          20,
+			NewAccountAge:     7 * 24 * time.Hour,
+			NewAccountWeight:  0.5,
+			UnconfirmedWeight: 0.25,
+		}, appLoggerForTests{})
+}
+
+func TestVoteGuardWeights(t *testing.T) {
+	guard := newVoteGuardForTests(&recentVotesRepoForTests{})
+
+	weights := map[string]float64{"old": 1, "legacy": 1, "newbie": 0.5, "stranger": 0.125}
+	for user, expected := range weights {
+		vote := &domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: 1, UserId: user, Value: 9}
+		if err := guard.Check(newUserContext(user), vote); err != nil || vote.Weight != expected || vote.Flagged {
+			t.Errorf("Expected weight %v of vote of %s, got %+v %v", expected, user, vote, err)
+		}
+	}
+
+	own := &domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: 1, UserId: "owner", Value: 10}
+	if err := guard.Check(newUserContext("owner"), own); err != nil || !own.Flagged {
+		t.Errorf("Expected vote for own plan flagged, got %+v %v", own, err)
+	}
+}
+
+func TestVoteGuardProject(t *testing.T) {
+	guard := newVoteGuardForTests(&recentVotesRepoForTests{})
+
+	vote := &domain.VoteRecord{EntityType: domain.ProjectEntity, EntityId: 4, UserId: "old", Value: 7}
+	if err := guard.Check(newUserContext("old"), vote); err != nil || vote.Weight != 1 || vote.Flagged {
+		t.Errorf("Expected vote for project accepted, got %+v %v", vote, err)
+	}
+}
+
+func TestVoteGuardRateLimits(t *testing.T) {
+	tooMany := core.NewError(core.TooManyRequests).Error()
+
+	byUser := newVoteGuardForTests(&recentVotesRepoForTests{byUser: 10})
+	if err := byUser.Check(newUserContext("old"), &domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: 1, UserId: "old", Value: 9}); err == nil || err.Error() != tooMany {
+		t.Errorf("Expected user limit exceeded, got %v", err)
+	}
+
+	byIP := newVoteGuardForTests(&recentVotesRepoForTests{byUser: 1, byIP: 20})
+	if err := byIP.Check(newUserContext("old"), &domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: 1, UserId: "old", IP: "10.0.0.1", Value: 9}); err == nil || err.Error() != tooMany {
+		t.Errorf("Expected ip limit exceeded, got %v", err)
+	}
+	if err := byIP.Check(newUserContext("old"), &domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: 1, UserId: "old", Value: 9}); err != nil {
+		t.Errorf("Expected vote without ip not limited by ip, got %v", err)
+	}
+}
+
+func TestAddVoteGuarded(t *testing.T) {
+	repo := &votesRepoForTests{votes: map[string]int{}}
+	broadcaster := infrastructure.NewEventBroadcaster(infrastructure.NewEventBus(nil, appLoggerForTests{}), repo, newThreadsRepoForTests(), appLoggerForTests{})
+	ctx := newPermissionsContext("voter", domain.PointsAdd)
+
+	limited := usecases.NewAddPoints(repo, &notifierForTests{}, broadcaster, &reputationForTests{}, &voteGuardForTests{err: core.NewError(core.TooManyRequests)}, appLoggerForTests{})
+	if _, err := limited.Do(ctx, domain.PlanEntity, 1, 9); err == nil || err.Error() != core.NewError(core.TooManyRequests).Error() || len(repo.votes) != 0 {
+		t.Errorf("Expected vote rejected, got %v %v", err, repo.votes)
+	}
+
+	reputation := &reputationForTests{}
+	flagged := usecases.NewAddPoints(repo, &notifierForTests{}, broadcaster, reputation, &voteGuardForTests{flag: true}, appLoggerForTests{})
+	if ok, err := flagged.Do(ctx, domain.PlanEntity, 1, 9); !ok || err != nil || repo.votes["voter"] != 9 {
+		t.Errorf("Expected flagged vote saved, got %v %v", ok, err)
+	}
+	if len(reputation.votes) != 0 {
+		t.Errorf("Expected no reputation for flagged vote, got %v", reputation.votes)
+	}
+}
+
+func TestFindVoteRings(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var votes []domain.VoteRecord
+	for plan := int64(1); plan <= 5; plan++ {
+		at := start.Add(time.Duration(plan) * 24 * time.Hour)
+		for i, user := range []string{"ring1", "ring2", "ring3"} {
+			votes = append(votes, domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: plan, UserId: user, Value: 10, Date: at.Add(time.Duration(i) * time.Minute)})
+		}
+		// honest user votes for the same plans, but hours later
+		votes = append(votes, domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: plan, UserId: "honest", Value: 10, Date: at.Add(5 * time.Hour)})
+	}
+	// pair voting together is not a ring
+	for plan := int64(10); plan <= 15; plan++ {
+		at := start.Add(time.Duration(plan) * 24 * time.Hour)
+		votes = append(votes,
+			domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: plan, UserId: "friend1", Value: 9, Date: at},
+			domain.VoteRecord{EntityType: domain.PlanEntity, EntityId: plan, UserId: "friend2", Value: 9, Date: at})
+	}
+
+	flagged := core.FindVoteRings(votes, 10*time.Minute, 5, 3)
+	if len(flagged) != 15 {
+		t.Fatalf("Expected 15 votes of ring flagged, got %d", len(flagged))
+	}
+	for _, v := range flagged {
+		if v.UserId != "ring1" && v.UserId != "ring2" && v.UserId != "ring3" {
+			t.Errorf("Expected only ring members flagged, got %s", v.UserId)
+		}
+	}
+
+	if flagged := core.FindVoteRings(votes, 10*time.Minute, 6, 3); len(flagged) != 0 {
+		t.Errorf("Expected no rings with more shared votes required, got %d", len(flagged))
+	}
+}
+
+func TestRankersIgnoreZeroWeight(t *testing.T) {
+	now := time.Now()
+	ranker := core.NewBayesianRanker(7, 10)
+	votes := []domain.Vote{{Value: 10, Date: now, Weight: 0}}
+	if score := ranker.Score(votes, now); score != 7 {
+		t.Errorf("Expected vote with zero weight ignored, got %v", score)
+	}
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type votesRepoForTests struct {
+	core.PointsRepository
+	votes map[string]int
+}
+
+func (r *votesRepoForTests) Add(ctx core.ReqContext, vote *domain.VoteRecord) (int, bool) {
+	previous := r.votes[vote.UserId]
+	r.votes[vote.UserId] = vote.Value
+	return previous, true
+}
+
+func (r *votesRepoForTests) Remove(ctx core.ReqContext, entityType domain.EntityType, entityId int64, userId string) (int, bool) {
+	previous, ok := r.votes[userId]
+	if !ok {
+		return 0, false
+	}
+	delete(r.votes, userId)
+	return previous, true
+}
+
+func (r *votesRepoForTests) Get(ctx core.ReqContext, userid string, entityType domain.EntityType, entityId int64) *domain.Points {
+	return &domain.Points{Id: entityId, Count: len(r.votes), Voted: r.votes[userid]}
+}
+
+func TestChangeVote(t *testing.T) {
+	repo := &votesRepoForTests{votes: map[string]int{"voter": 3}}
+	reputation := &reputationForTests{}
+	bus := infrastructure.NewEventBus(nil, appLoggerForTests{})
+	broadcaster := infrastructure.NewEventBroadcaster(bus, repo, newThreadsRepoForTests(), appLoggerForTests{})
+	events, cancel := bus.Subscribe([]string{"plan:1"})
+	defer cancel()
+	usecase := usecases.NewChangePoints(repo, broadcaster, reputation, &voteGuardForTests{}, appLoggerForTests{})
+
+	if ok, err := usecase.Do(newPermissionsContext("voter", domain.PointsAdd), domain.PlanEntity, 1, 8); !ok || err != nil || repo.votes["voter"] != 8 {
+		t.Fatalf("Expected vote changed, got %v %v %d", ok, err, repo.votes["voter"])
+	}
+	if _, err := usecase.Do(newPermissionsContext("other", domain.PointsAdd), domain.PlanEntity, 1, 8); err == nil || err.Error() != core.NewError(core.NotExists).Error() {
+		t.Errorf("Expected error for user without vote, got %v", err)
+	}
+	if _, err := usecase.Do(newPermissionsContext("voter", domain.PointsAdd), domain.PlanEntity, 1, 11); err == nil {
+		t.Error("Expected error for invalid value")
+	}
+	if _, err := usecase.Do(newPermissionsContext("voter"), domain.PlanEntity, 1, 5); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied without permission, got %v", err)
+	}
+
+	if got := receiveEvents(events); len(got) != 1 || got[0].Type != domain.PointsChangedEvent {
+		t.Errorf("Expected points change broadcast once, got %+v", got)
+	}
+	if len(reputation.votes) != 1 || reputation.votes[0] != [2]int{3, 8} {
+		t.Errorf("Expected reputation changed by vote, got %v", reputation.votes)
+	}
+}
+
+func TestReVoteAwardedOnce(t *testing.T) {
+	repo := &votesRepoForTests{votes: map[string]int{}}
+	broadcaster := infrastructure.NewEventBroadcaster(infrastructure.NewEventBus(nil, appLoggerForTests{}), repo, newThreadsRepoForTests(), appLoggerForTests{})
+	reputation := &reputationForTests{}
+	usecase := usecases.NewAddPoints(repo, &notifierForTests{}, broadcaster, reputation, &voteGuardForTests{}, appLoggerForTests{})
+	ctx := newPermissionsContext("voter", domain.PointsAdd)
+
+	for _, value := range []int{9, 10} {
+		if ok, err := usecase.Do(ctx, domain.PlanEntity, 1, value); !ok || err != nil {
+			t.Fatalf("Vote not saved: %v %v", ok, err)
+		}
+	}
+	// reputation is changed from the value replaced by vote, not from the value read before
+	if len(reputation.votes) != 2 || reputation.votes[0] != [2]int{0, 9} || reputation.votes[1] != [2]int{9, 10} {
+		t.Errorf("Expected second vote changed from the first one, got %v", reputation.votes)
+	}
+}
+
+func TestRemoveVote(t *testing.T) {
+	repo := &votesRepoForTests{votes: map[string]int{"voter": 3}}
+	broadcaster := infrastructure.NewEventBroadcaster(infrastructure.NewEventBus(nil, appLoggerForTests{}), repo, newThreadsRepoForTests(), appLoggerForTests{})
+	reputation := &reputationForTests{}
+	usecase := usecases.NewRemovePoints(repo, broadcaster, reputation, appLoggerForTests{})
+
+	if ok, err := usecase.Do(newUserContext("voter"), domain.PlanEntity, 1); !ok || err != nil || len(repo.votes) != 0 {
+		t.Fatalf("Expected vote removed, got %v %v", ok, err)
+	}
+	if len(reputation.votes) != 1 || reputation.votes[0] != [2]int{3, 0} {
+		t.Errorf("Expected reputation changed by retracted vote, got %v", reputation.votes)
+	}
+	if _, err := usecase.Do(newUserContext("voter"), domain.PlanEntity, 1); err == nil || err.Error() != core.NewError(core.NotExists).Error() {
+		t.Errorf("Expected error for removed vote, got %v", err)
+	}
+	if _, err := usecase.Do(newUserContext("voter"), domain.EntityType(100), 1); err == nil {
+		t.Error("Expected error for invalid type")
+	}
+}
+
+func TestVoteEntityTypes(t *testing.T) {
+	votable := map[domain.EntityType]bool{
+		domain.PlanEntity:     true,
+		domain.TopicEntity:    true,
+		domain.ProjectEntity:  true,
+		domain.ResourceEntity: true,
+		domain.CommentEntity:  true,
+		domain.UserEntity:     false,
+		domain.EntityType(0):  false,
+	}
+	for et, expected := range votable {
+		if et.IsVotable() != expected {
+			t.Errorf("Expected IsVotable of %d to be %v", et, expected)
+		}
+	}
+
+	repo := &votesRepoForTests{votes: map[string]int{}}
+	usecase := usecases.NewAddPoints(repo, &notifierForTests{}, infrastructure.NewEventBroadcaster(infrastructure.NewEventBus(nil, appLoggerForTests{}), repo, newThreadsRepoForTests(), appLoggerForTests{}), &reputationForTests{}, &voteGuardForTests{}, appLoggerForTests{})
+	ctx := newPermissionsContext("voter", domain.PointsAdd)
+	if ok, err := usecase.Do(ctx, domain.ResourceEntity, 3, 9); !ok || err != nil || repo.votes["voter"] != 9 {
+		t.Errorf("Expected vote for resource saved, got %v %v", ok, err)
+	}
+	if _, err := usecase.Do(ctx, domain.UserEntity, 3, 9); err == nil {
+		t.Error("Expected error for vote for user")
+	}
+}
+
+func TestGetTopicPoints(t *testing.T) {
+	topics := &topicRepoForTests{topics: map[int]*domain.Topic{2: {Id: 2, Name: "golang"}}}
+	points := &pointsRepoForTests{points: map[int64]*domain.Points{2: {Id: 2, Count: 4, Voted: 7}}}
+	usecase := usecases.NewGetTopic(topics, &planRepoForTests{}, &usersPlanRepoForTests{}, newThreadsRepoForTests(), points, appLoggerForTests{})
+
+	topic, err := usecase.Do(newUserContext("reader"), "golang", 10)
+	if err != nil || topic.Points == nil || topic.Points.Count != 4 || topic.Points.Voted != 7 {
+		t.Errorf("Expected points attached to topic, got %+v %v", topic, err)
+	}
+}
@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NeekUP/roadmaps/core"
+	"github.com/NeekUP/roadmaps/core/usecases"
+	"github.com/NeekUP/roadmaps/domain"
+	"github.com/NeekUP/roadmaps/infrastructure"
+)
+
+type webhookPublisherForTests struct {
+	records []domain.ChangeLogRecord
+}
+
+func (p *webhookPublisherForTests) Publish(ctx core.ReqContext, record *domain.ChangeLogRecord) {
+	p.records = append(p.records, *record)
+}
+
+type webhookRepoForTests struct {
+	core.WebhookRepository
+	webhooks   map[int64]*domain.Webhook
+	queued     map[domain.ChangeType][]string
+	pending    []domain.WebhookDelivery
+	delivered  map[int64]int
+	failed     map[int64]bool
+	redelivers []int64
+}
+
+func newWebhookRepoForTests() *webhookRepoForTests {
+	return &webhookRepoForTests{
+		webhooks:  make(map[int64]*domain.Webhook),
+		queued:    make(map[domain.ChangeType][]string),
+		delivered: make(map[int64]int),
+		failed:    make(map[int64]bool),
+	}
+}
+
+func (r *webhookRepoForTests) Add(ctx core.ReqContext, webhook *domain.Webhook) (bool, *core.AppError) {
+	webhook.Id = int64(len(r.webhooks) + 1)
+	r.webhooks[webhook.Id] = webhook
+	return true, nil
+}
+
+func (r *webhookRepoForTests) Get(ctx core.ReqContext, id int64) *domain.Webhook {
+	return r.webhooks[id]
+}
+
+func (r *webhookRepoForTests) GetByUser(ctx core.ReqContext, userId string) []domain.Webhook {
+	list := []domain.Webhook{}
+	for _, w := range r.webhooks {
+		if w.UserId == userId {
+			list = append(list, *w)
+		}
+	}
+	return list
+}
+
+func (r *webhookRepoForTests) Enqueue(ctx core.ReqContext, event domain.ChangeType, userId string, payload string) (int64, *core.AppError) {
+	r.queued[event] = append(r.queued[event], payload)
+	return 1, nil
+}
+
+func (r *webhookRepoForTests) GetDelivery(ctx core.ReqContext, id int64) *domain.WebhookDelivery {
+	for i := range r.pending {
+		if r.pending[i].Id == id {
+			return &r.pending[i]
+		}
+	}
+	return nil
+}
+
+func (r *webhookRepoForTests) Redeliver(ctx core.ReqContext, id int64) (*domain.WebhookDelivery, *core.AppError) {
+	r.redelivers = append(r.redelivers, id)
+	return &domain.WebhookDelivery{Id: 100, RedeliveryOf: id}, nil
+}
+
+func (r *webhookRepoForTests) Take(count int, lease time.Duration) []domain.WebhookDelivery {
+	taken := r.pending
+	r.pending = nil
+	return taken
+}
+
+func (r *webhookRepoForTests) MarkDelivered(id int64, responseCode int) bool {
+	r.delivered[id] = responseCode
+	return true
+}
+
+func (r *webhookRepoForTests) MarkFailed(id int64, responseCode int, lastError string, nextAttempt time.Time, dead bool) bool {
+	r.failed[id] = dead
+	return true
+}
+
+func newWebhookManagerContext(userId string) core.ReqContext {
+	ctx := context.WithValue(context.Background(), infrastructure.ReqUserId, userId)
+	ctx = context.WithValue(ctx, infrastructure.ReqPermissions, domain.NewPermissionSet(domain.WebhookOwn, domain.WebhookManage))
+	return infrastructure.NewContext(ctx)
+}
+
+func TestWebhookPublisherQueuesOnlyContentChanges(t *testing.T) {
+	repo := newWebhookRepoForTests()
+	publisher := infrastructure.NewWebhookPublisher(repo, appLoggerForTests{})
+
+	publisher.Publish(newChangeLogContext(), &domain.ChangeLogRecord{Action: domain.EditPlan, UserId: "user", EntityType: domain.PlanEntity, EntityId: 7, Diff: `{"Title":"Go"}`})
+	publisher.Publish(newChangeLogContext(), &domain.ChangeLogRecord{Action: domain.AddComment, UserId: "user", EntityType: domain.CommentEntity, EntityId: 8})
+
+	if len(repo.queued) != 1 || len(repo.queued[domain.EditPlan]) != 1 {
+		t.Fatalf("Expected only plan change queued, got %+v", repo.queued)
+	}
+
+	payload := infrastructure.WebhookPayload{}
+	if err := json.Unmarshal([]byte(repo.queued[domain.EditPlan][0]), &payload); err != nil {
+		t.Fatalf("Payload is not json: %s", err.Error())
+	}
+	if payload.EntityId != core.EncodeNumToString(7) || payload.EntityType != "plan" || string(payload.Diff) != `{"Title":"Go"}` {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+}
+
+func TestChangesCollectorPublishesToWebhooks(t *testing.T) {
+	webhooks := &webhookPublisherForTests{}
+	changeLog := infrastructure.NewChangesCollector(&changeLogRepoForTests{}, &notifierForTests{}, webhooks, &appLoggerForTests{})
+
+	changeLog.Added(newChangeLogContext(), domain.TopicEntity, 3)
+
+	if len(webhooks.records) != 1 || webhooks.records[0].Action != domain.AddTopic {
+		t.Errorf("Expected added topic published, got %+v", webhooks.records)
+	}
+}
+
+func TestWebhookDispatcherSignsAndRetries(t *testing.T) {
+	var signature, event string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		signature = r.Header.Get(infrastructure.WebhookSignatureHeader)
+		event = r.Header.Get(infrastructure.WebhookEventHeader)
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	repo := newWebhookRepoForTests()
+	repo.pending = []domain.WebhookDelivery{
+		{Id: 1, WebhookId: 1, Url: server.URL + "/ok", Secret: "secret", Event: domain.AddTopic, Payload: `{"event":4}`},
+		{Id: 2, WebhookId: 1, Url: server.URL + "/fail", Secret: "secret", Event: domain.AddTopic, Payload: `{"event":4}`},
+		{Id: 3, WebhookId: 1, Url: server.URL + "/fail", Secret: "secret", Event: domain.AddTopic, Payload: `{"event":4}`, Attempts: 2},
+	}
+	dispatcher := infrastructure.NewWebhookDispatcher(repo, infrastructure.WebhookDispatcherConf{
+		Interval: time.Second, Timeout: time.Second, BatchSize: 10, MaxAttempts: 3, AllowPrivateNetworks: true,
+	}, appLoggerForTests{})
+
+	if n := dispatcher.Run(); n != 3 {
+		t.Fatalf("Expected 3 deliveries processed, got %d", n)
+	}
+
+	if repo.delivered[1] != http.StatusOK {
+		t.Errorf("Expected first delivery delivered, got %+v", repo.delivered)
+	}
+	if dead, ok := repo.failed[2]; !ok || dead {
+		t.Errorf("Expected second delivery to be retried")
+	}
+	if !repo.failed[3] {
+		t.Errorf("Expected third delivery to be dead after max attempts")
+	}
+	if string(body) != `{"event":4}` || event != "4" {
+		t.Errorf("Unexpected request: event %q body %q", event, body)
+	}
+	if signature != infrastructure.SignWebhookPayload("secret", body) {
+		t.Errorf("Invalid signature %q", signature)
+	}
+}
+
+func TestAddWebhookValidation(t *testing.T) {
+	repo := newWebhookRepoForTests()
+	addWebhook := usecases.NewAddWebhook(repo, appLoggerForTests{})
+
+	if _, err := addWebhook.Do(newUserContext("user"), "https://example.com/hook", []domain.ChangeType{domain.AddPlan}); err == nil || err.Error() != core.NewError(core.AccessDenied).Error() {
+		t.Errorf("Expected access denied without permission, got %v", err)
+	}
+
+	ctx := newWebhookManagerContext("user")
+	invalid := map[string][]domain.ChangeType{
+		"ftp://example.com/hook":   {domain.AddPlan},
+		"https://example.com/hook": {domain.AddComment},
+		"https://example.com/a":    {},
+		"http://localhost:8080/":   {domain.AddPlan},
+		"http://127.0.0.1/hook":    {domain.AddPlan},
+		"http://[::1]:5432/":       {domain.AddPlan},
+		"http://10.0.0.5/hook":     {domain.AddPlan},
+		"http://169.254.169.254/":  {domain.AddPlan},
+	}
+	for url, events := range invalid {
+		if _, err := addWebhook.Do(ctx, url, events); err == nil {
+			t.Errorf("Expected validation error for %s %v", url, events)
+		}
+	}
+
+	webhook, err := addWebhook.Do(ctx, "https://example.com/hook?a=1&b=2", []domain.ChangeType{domain.AddPlan, domain.EditResource})
+	if err != nil {
+		t.Fatalf("Webhook not added: %s", err.Error())
+	}
+	if len(webhook.Secret) != 64 || !webhook.Active || webhook.UserId != "user" || webhook.OwnChanges {
+		t.Errorf("Unexpected webhook: %+v", webhook)
+	}
+
+	own, err := addWebhook.Do(newPermissionsContext("user", domain.WebhookOwn), "https://example.com/own", []domain.ChangeType{domain.EditPlan})
+	if err != nil || !own.OwnChanges {
+		t.Errorf("Expected webhook of user limited to own changes, got %+v %v", own, err)
+	}
+}
+
+func TestRedeliverWebhookOnlyByOwner(t *testing.T) {
+	repo := newWebhookRepoForTests()
+	repo.webhooks[1] = &domain.Webhook{Id: 1, UserId: "owner"}
+	repo.pending = []domain.WebhookDelivery{{Id: 5, WebhookId: 1}}
+	redeliver := usecases.NewRedeliverWebhook(repo, appLoggerForTests{})
+
+	if _, err := redeliver.Do(newWebhookManagerContext("other"), 5); err == nil {
+		t.Errorf("Expected access denied for other user")
+	}
+
+	delivery, err := redeliver.Do(newWebhookManagerContext("owner"), 5)
+	if err != nil || delivery.RedeliveryOf != 5 || len(repo.redelivers) != 1 {
+		t.Errorf("Expected redelivery, got %+v %v", delivery, err)
+	}
+}
+
+func TestWebhookDispatcherSkipsLocalAddresses(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	repo := newWebhookRepoForTests()
+	// host name resolved to local address is rejected at connect time too
+	localName := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	repo.pending = []domain.WebhookDelivery{
+		{Id: 1, WebhookId: 1, Url: server.URL + "/hook", Secret: "secret", Event: domain.AddTopic, Payload: `{"event":4}`},
+		{Id: 2, WebhookId: 1, Url: localName + "/hook", Secret: "secret", Event: domain.AddTopic, Payload: `{"event":4}`},
+	}
+	dispatcher := infrastructure.NewWebhookDispatcher(repo, infrastructure.WebhookDispatcherConf{
+		Interval: time.Second, Timeout: time.Second, BatchSize: 10, MaxAttempts: 3,
+	}, appLoggerForTests{})
+
+	if n := dispatcher.Run(); n != 2 {
+		t.Fatalf("Expected 2 deliveries processed, got %d", n)
+	}
+	if requests != 0 || len(repo.delivered) != 0 || len(repo.failed) != 2 {
+		t.Errorf("Expected local deliveries failed, requests %d, delivered %+v", requests, repo.delivered)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":         true,
+		"2001:4860::8888": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"0.0.0.0":         false,
+		"10.1.2.3":        false,
+		"172.20.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"224.0.0.1":       false,
+	}
+	for ip, expected := range cases {
+		if got := core.IsPublicIP(net.ParseIP(ip)); got != expected {
+			t.Errorf("IsPublicIP(%s) = %v, expected %v", ip, got, expected)
+		}
+	}
+}